@@ -1,44 +1,230 @@
 package main
 
-import "fmt"
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+)
+
+// DefaultMaxMessageLength is the longest message DecodeE accepts unless told otherwise.
+const DefaultMaxMessageLength = 10000
+
+// ErrMessageTooLong is returned by DecodeE for a message longer than its limit.
+var ErrMessageTooLong = errors.New("message is too long")
 
 func main() {
-	var message string
+	maxLength := flag.Int("max-length", DefaultMaxMessageLength, "longest message, in digits, that will be decoded")
+	timeout := flag.Duration("timeout", 0, "how long to wait for the message on standard input; 0 waits forever")
+	flag.Parse()
+
+	ctx := context.Background()
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+
 	fmt.Print("Enter decoded message: ")
-	fmt.Scanln(&message)
-	fmt.Println("Decode ways:", decode(message))
+	message, err := readMessage(ctx, os.Stdin)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "\nNo message was read within %v; pass -timeout to wait longer.\n", *timeout)
+		os.Exit(1)
+	}
+	ways, err := DecodeE(message, *maxLength)
+	if errors.Is(err, ErrMessageTooLong) {
+		fmt.Fprintf(os.Stderr, "The message has %d digits but at most %d are decoded; pass -max-length to raise the limit.\n", len(message), *maxLength)
+		os.Exit(1)
+	}
+	fmt.Println("Decode ways:", ways)
+}
+
+// readMessage reads the message from the first line of r, as fmt.Scanln would, giving
+// up with ctx's error once ctx is done. The read itself cannot be interrupted, so it
+// is left running in its goroutine when that happens.
+func readMessage(ctx context.Context, r io.Reader) (string, error) {
+	read := make(chan string, 1)
+	go func() {
+		var message string
+		fmt.Fscanln(r, &message)
+		read <- message
+	}()
+
+	select {
+	case message := <-read:
+		return message, nil
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// DecodeE is decode for untrusted input: it refuses messages longer than maxLength
+// digits with ErrMessageTooLong instead of working through them.
+func DecodeE(message string, maxLength int) (int, error) {
+	if len(message) > maxLength {
+		return 0, fmt.Errorf("%w: %d digits, the limit is %d", ErrMessageTooLong, len(message), maxLength)
+	}
+	return decode(message), nil
 }
 
 func decode(message string) int {
 	if message == "" {
 		return 0
 	}
-	if message[0] == '0' {
+	if !validSingle(message[0]) {
 		return 0
 	}
 	current := 1
 	prev := 1
 	prev_prev := 1
-	var char byte
-	var prev_char byte
 
 	for i := 1; i < len(message); i++ {
-		char = message[i]
-		prev_char = message[i-1]
-		if char == '0' {
-			if prev_char == '0' || prev_char > '2' {
-				return 0
-			}
-			current = prev_prev
-		} else if prev_char == '0' {
-			current = prev
-		} else if string(prev_char)+string(char) > "26" {
-			current = prev
-		} else {
-			current = prev + prev_prev
+		single := validSingle(message[i])
+		pair := validPair(message[i-1], message[i])
+		if !single && !pair {
+			return 0
+		}
+		current = 0
+		if single {
+			current += prev
+		}
+		if pair {
+			current += prev_prev
 		}
 		prev_prev = prev
 		prev = current
 	}
 	return current
 }
+
+// DecodeMemo solves the same problem top-down: the number of ways to decode the
+// suffix starting at i is memoized so every suffix is solved only once.
+func DecodeMemo(message string) int {
+	if message == "" {
+		return 0
+	}
+	cache := make([]int, len(message))
+	for i := range cache {
+		cache[i] = -1
+	}
+	return decodeFrom(message, 0, cache)
+}
+
+func decodeFrom(message string, i int, cache []int) int {
+	if i == len(message) {
+		return 1
+	}
+	if cache[i] >= 0 {
+		return cache[i]
+	}
+
+	ways := 0
+	if validSingle(message[i]) {
+		ways += decodeFrom(message, i+1, cache)
+	}
+	if i+1 < len(message) && validPair(message[i], message[i+1]) {
+		ways += decodeFrom(message, i+2, cache)
+	}
+	cache[i] = ways
+	return ways
+}
+
+// validSingle reports whether char on its own encodes a letter (1-9).
+func validSingle(char byte) bool {
+	return char != '0'
+}
+
+// validPair reports whether the two digits together encode a letter (10-26).
+func validPair(first, second byte) bool {
+	return first != '0' && (first-'0')*10+(second-'0') <= 26
+}
+
+// SmallestForCount finds the shortest digit string that decode turns into exactly
+// target ways, preferring the numerically smallest among strings of that length.
+// It reports false when no string decodes to target: every digit either keeps the
+// count, adds the previous one or (for a 0) falls back to it, so counts are products
+// of Fibonacci numbers and a target such as 7 is never reached.
+//
+// The search runs breadth-first over decode's own state: the counts for the two
+// shortest prefixes and the last digit, which together fix every later count.
+func SmallestForCount(target int) (string, bool) {
+	switch {
+	case target < 0:
+		return "", false
+	case target == 0:
+		return "0", true
+	}
+
+	steps := make(map[decodeState]decodeStep)
+	var queue []decodeState
+	for digit := byte('1'); digit <= '9'; digit++ {
+		s := decodeState{prevPrev: 1, prev: 1, last: digit}
+		steps[s] = decodeStep{digit: digit, first: true}
+		queue = append(queue, s)
+	}
+
+	for len(queue) > 0 {
+		s := queue[0]
+		queue = queue[1:]
+		if s.prev == target {
+			return spellOut(s, steps), true
+		}
+
+		for digit := byte('0'); digit <= '9'; digit++ {
+			single := validSingle(digit)
+			pair := validPair(s.last, digit)
+			if !single && !pair {
+				continue
+			}
+			current := 0
+			if single {
+				current += s.prev
+			}
+			if pair {
+				current += s.prevPrev
+			}
+			// Later counts are sums of these two, so once both pass the target it is out of reach.
+			if min(s.prev, current) > target {
+				continue
+			}
+
+			next := decodeState{prevPrev: s.prev, prev: current, last: digit}
+			if _, seen := steps[next]; !seen {
+				steps[next] = decodeStep{from: s, digit: digit}
+				queue = append(queue, next)
+			}
+		}
+	}
+	return "", false
+}
+
+// decodeState is what decode carries from one digit to the next.
+type decodeState struct {
+	prevPrev, prev int
+	last           byte
+}
+
+// decodeStep records the digit that first led SmallestForCount to a state.
+type decodeStep struct {
+	from  decodeState
+	digit byte
+	first bool
+}
+
+// spellOut follows the recorded steps back from s to rebuild the digits that reach it.
+func spellOut(s decodeState, steps map[decodeState]decodeStep) string {
+	var digits []byte
+	for {
+		step := steps[s]
+		digits = append(digits, step.digit)
+		if step.first {
+			break
+		}
+		s = step.from
+	}
+	slices.Reverse(digits)
+	return string(digits)
+}
@@ -1,123 +1,132 @@
 package main
 
-import "testing"
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+)
 
-func Test_decode(t *testing.T) {
-	type args struct {
-		message string
-	}
-	tests := []struct {
-		name string
-		args args
-		want int
-	}{
-		{
-			name: "12",
-			args: args{
-				message: "12",
-			},
-			want: 2,
+type args struct {
+	message string
+}
+
+var decodeTests = []struct {
+	name string
+	args args
+	want int
+}{
+	{
+		name: "12",
+		args: args{
+			message: "12",
 		},
-		{
-			name: "226",
-			args: args{
-				message: "226",
-			},
-			want: 3,
+		want: 2,
+	},
+	{
+		name: "226",
+		args: args{
+			message: "226",
 		},
-		{
-			name: "06",
-			args: args{
-				message: "06",
-			},
-			want: 0,
+		want: 3,
+	},
+	{
+		name: "06",
+		args: args{
+			message: "06",
 		},
-		{
-			name: "0",
-			args: args{
-				message: "0",
-			},
-			want: 0,
+		want: 0,
+	},
+	{
+		name: "0",
+		args: args{
+			message: "0",
 		},
-		{
-			name: "106",
-			args: args{
-				message: "106",
-			},
-			want: 1,
+		want: 0,
+	},
+	{
+		name: "106",
+		args: args{
+			message: "106",
 		},
-		{
-			name: "1006",
-			args: args{
-				message: "1006",
-			},
-			want: 0,
+		want: 1,
+	},
+	{
+		name: "1006",
+		args: args{
+			message: "1006",
 		},
-		{
-			name: "2101",
-			args: args{
-				message: "2101",
-			},
-			want: 1,
+		want: 0,
+	},
+	{
+		name: "2101",
+		args: args{
+			message: "2101",
 		},
-		{
-			name: "2",
-			args: args{
-				message: "2",
-			},
-			want: 1,
+		want: 1,
+	},
+	{
+		name: "2",
+		args: args{
+			message: "2",
 		},
-		{
-			name: "22",
-			args: args{
-				message: "22",
-			},
-			want: 2,
+		want: 1,
+	},
+	{
+		name: "22",
+		args: args{
+			message: "22",
 		},
-		{
-			name: "221",
-			args: args{
-				message: "221",
-			},
-			want: 3,
+		want: 2,
+	},
+	{
+		name: "221",
+		args: args{
+			message: "221",
 		},
-		{
-			name: "2211",
-			args: args{
-				message: "2211",
-			},
-			want: 5,
+		want: 3,
+	},
+	{
+		name: "2211",
+		args: args{
+			message: "2211",
 		},
-		{
-			name: "22110",
-			args: args{
-				message: "22110",
-			},
-			want: 3,
+		want: 5,
+	},
+	{
+		name: "22110",
+		args: args{
+			message: "22110",
 		},
-		{
-			name: "221101",
-			args: args{
-				message: "221101",
-			},
-			want: 3,
+		want: 3,
+	},
+	{
+		name: "221101",
+		args: args{
+			message: "221101",
 		},
-		{
-			name: "2211011",
-			args: args{
-				message: "2211011",
-			},
-			want: 6,
+		want: 3,
+	},
+	{
+		name: "2211011",
+		args: args{
+			message: "2211011",
 		},
-		{
-			name: "230",
-			args: args{
-				message: "230",
-			},
-			want: 0,
+		want: 6,
+	},
+	{
+		name: "230",
+		args: args{
+			message: "230",
 		},
-	}
-	for _, tt := range tests {
+		want: 0,
+	},
+}
+
+func Test_decode(t *testing.T) {
+	for _, tt := range decodeTests {
 		t.Run(tt.name, func(t *testing.T) {
 			if got := decode(tt.args.message); got != tt.want {
 				t.Errorf("decode() = %v, want %v", got, tt.want)
@@ -125,3 +134,213 @@ func Test_decode(t *testing.T) {
 		})
 	}
 }
+
+func Test_DecodeMemoAgreesWithDecode(t *testing.T) {
+	decoders := []struct {
+		name string
+		fn   func(string) int
+	}{
+		{name: "decode", fn: decode},
+		{name: "DecodeMemo", fn: DecodeMemo},
+	}
+
+	for _, tt := range decodeTests {
+		for _, decoder := range decoders {
+			t.Run(decoder.name+"/"+tt.name, func(t *testing.T) {
+				if got := decoder.fn(tt.args.message); got != tt.want {
+					t.Errorf("%s() = %v, want %v", decoder.name, got, tt.want)
+				}
+			})
+		}
+	}
+}
+
+func Test_DecodeMemoAgreesOnRandomInputs(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	for i := 0; i < 1000; i++ {
+		message := make([]byte, 1+rng.Intn(30))
+		for j := range message {
+			message[j] = byte('0' + rng.Intn(10))
+		}
+		if got, want := DecodeMemo(string(message)), decode(string(message)); got != want {
+			t.Errorf("DecodeMemo(%q) = %v, decode() = %v", message, got, want)
+		}
+	}
+}
+
+func longDigitMessage(length int) string {
+	rng := rand.New(rand.NewSource(1))
+	message := make([]byte, length)
+	for i := range message {
+		// Zeros are left out so that the whole input is walked instead of bailing out early.
+		message[i] = byte('1' + rng.Intn(9))
+	}
+	return string(message)
+}
+
+func BenchmarkDecode(b *testing.B) {
+	message := longDigitMessage(100000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		decode(message)
+	}
+}
+
+func BenchmarkDecodeMemo(b *testing.B) {
+	message := longDigitMessage(100000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		DecodeMemo(message)
+	}
+}
+
+func Test_SmallestForCount(t *testing.T) {
+	tests := []struct {
+		name   string
+		target int
+		want   string
+	}{
+		{name: "1", target: 1, want: "1"},
+		{name: "2", target: 2, want: "11"},
+		{name: "3", target: 3, want: "111"},
+		{name: "4", target: 4, want: "1311"},
+		{name: "5", target: 5, want: "1111"},
+		{name: "0", target: 0, want: "0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := SmallestForCount(tt.target)
+			if !ok {
+				t.Fatalf("SmallestForCount(%v) reported no solution", tt.target)
+			}
+			if got != tt.want {
+				t.Errorf("SmallestForCount(%v) = %q, want %q", tt.target, got, tt.want)
+			}
+			if ways := decode(got); ways != tt.target {
+				t.Errorf("decode(%q) = %v, want %v", got, ways, tt.target)
+			}
+		})
+	}
+}
+
+func Test_SmallestForCountImpossible(t *testing.T) {
+	for _, target := range []int{-1, 7, 11} {
+		if got, ok := SmallestForCount(target); ok {
+			t.Errorf("SmallestForCount(%v) = %q, want no solution", target, got)
+		}
+	}
+}
+
+func Test_SmallestForCountIsShortest(t *testing.T) {
+	// The shortest strings for the reachable counts up to this bound are at most 5
+	// digits long, so every candidate can be enumerated.
+	const maxTarget = 8
+
+	shortest := make(map[int]int)
+	for length := 1; length <= 5; length++ {
+		limit := 1
+		for i := 0; i < length; i++ {
+			limit *= 10
+		}
+		message := make([]byte, length)
+		for n := 0; n < limit; n++ {
+			for i, rest := length-1, n; i >= 0; i, rest = i-1, rest/10 {
+				message[i] = byte('0' + rest%10)
+			}
+			ways := decode(string(message))
+			if _, seen := shortest[ways]; !seen {
+				shortest[ways] = length
+			}
+		}
+	}
+
+	for target := 1; target <= maxTarget; target++ {
+		got, ok := SmallestForCount(target)
+		if _, reachable := shortest[target]; !reachable {
+			if ok {
+				t.Errorf("SmallestForCount(%v) = %q, want no solution", target, got)
+			}
+			continue
+		}
+		if !ok {
+			t.Fatalf("SmallestForCount(%v) reported no solution", target)
+		}
+		if decode(got) != target {
+			t.Errorf("decode(%q) = %v, want %v", got, decode(got), target)
+		}
+		if len(got) != shortest[target] {
+			t.Errorf("SmallestForCount(%v) = %q, want length %v", target, got, shortest[target])
+		}
+	}
+}
+
+func Test_DecodeE(t *testing.T) {
+	tests := []struct {
+		name      string
+		message   string
+		maxLength int
+		want      int
+		wantErr   error
+	}{
+		{name: "Short", message: "226", maxLength: DefaultMaxMessageLength, want: 3},
+		{name: "At The Limit", message: "1111", maxLength: 4, want: 5},
+		{name: "Just Over The Limit", message: "11111", maxLength: 4, wantErr: ErrMessageTooLong},
+		{name: "At The Default Limit", message: strings.Repeat("9", DefaultMaxMessageLength), maxLength: DefaultMaxMessageLength, want: 1},
+		{name: "Just Over The Default Limit", message: strings.Repeat("9", DefaultMaxMessageLength+1), maxLength: DefaultMaxMessageLength, wantErr: ErrMessageTooLong},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeE(tt.message, tt.maxLength)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("DecodeE() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("DecodeE() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// slowReader hands out its data only after delay has passed.
+type slowReader struct {
+	delay time.Duration
+	data  *strings.Reader
+}
+
+func (r slowReader) Read(p []byte) (int, error) {
+	time.Sleep(r.delay)
+	return r.data.Read(p)
+}
+
+func Test_readMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		delay   time.Duration
+		timeout time.Duration
+		want    string
+		wantErr error
+	}{
+		{name: "In Time", delay: 0, timeout: time.Second, want: "226"},
+		{name: "Stalled Input", delay: time.Second, timeout: 20 * time.Millisecond, wantErr: context.DeadlineExceeded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), tt.timeout)
+			defer cancel()
+
+			start := time.Now()
+			got, err := readMessage(ctx, slowReader{delay: tt.delay, data: strings.NewReader("226\n")})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("readMessage() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("readMessage() = %q, want %q", got, tt.want)
+			}
+			if elapsed := time.Since(start); elapsed >= time.Second && tt.wantErr != nil {
+				t.Errorf("readMessage() returned after %v, want it to stop at the deadline", elapsed)
+			}
+		})
+	}
+}
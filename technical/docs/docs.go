@@ -15,27 +15,36 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/posts": {
-            "get": {
-                "description": "Get a list of all blog posts",
-                "consumes": [
-                    "application/json"
-                ],
+        "/admin/backup": {
+            "post": {
+                "description": "Return a snapshot of every post and the next ID to hand out, which POST /admin/restore accepts",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "posts"
+                    "admin"
+                ],
+                "summary": "Back up all posts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
                 ],
-                "summary": "Get all posts",
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Snapshot",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/posts.PostRead"
-                            }
+                            "type": "file"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "type": "string"
                         }
                     },
                     "500": {
@@ -45,9 +54,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "Create a new blog post",
+            }
+        },
+        "/admin/maintenance": {
+            "get": {
+                "description": "GET reports the current level. PUT switches to off, read-only (writes get 503) or closed (everything but /admin/ gets 503)",
                 "consumes": [
                     "application/json"
                 ],
@@ -55,39 +66,49 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "posts"
+                    "admin"
                 ],
-                "summary": "Create a new post",
+                "summary": "Get or set the maintenance level",
                 "parameters": [
                     {
-                        "description": "Post data",
-                        "name": "post",
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "New level, for PUT",
+                        "name": "level",
                         "in": "body",
-                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/posts.PostCreateUpdate"
+                            "$ref": "#/definitions/posts.MaintenanceStatus"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/posts.PostRead"
+                            "$ref": "#/definitions/posts.MaintenanceStatus"
                         }
                     },
                     "400": {
-                        "description": "Invalid request body or validation error",
+                        "description": "Invalid level",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
                         "schema": {
                             "type": "string"
                         }
                     }
                 }
-            }
-        },
-        "/posts/{id}": {
-            "get": {
-                "description": "Get a single blog post by its ID",
+            },
+            "put": {
+                "description": "GET reports the current level. PUT switches to off, read-only (writes get 503) or closed (everything but /admin/ gets 503)",
                 "consumes": [
                     "application/json"
                 ],
@@ -95,33 +116,70 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "posts"
+                    "admin"
                 ],
-                "summary": "Get a post by ID",
+                "summary": "Get or set the maintenance level",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Post ID",
-                        "name": "id",
-                        "in": "path",
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
                         "required": true
+                    },
+                    {
+                        "description": "New level, for PUT",
+                        "name": "level",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/posts.MaintenanceStatus"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/posts.PostRead"
+                            "$ref": "#/definitions/posts.MaintenanceStatus"
                         }
                     },
                     "400": {
-                        "description": "Invalid post ID",
+                        "description": "Invalid level",
                         "schema": {
                             "type": "string"
                         }
                     },
-                    "404": {
-                        "description": "Post not found",
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/reload": {
+            "post": {
+                "description": "Replace the in-memory posts with the contents of the data file, discarding changes made since it was loaded",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Reload posts from disk",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Reloaded"
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
                         "schema": {
                             "type": "string"
                         }
@@ -133,120 +191,1548 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "Update an existing blog post",
+            }
+        },
+        "/admin/restore": {
+            "post": {
+                "description": "Replace every post with those in a snapshot returned by POST /admin/backup, discarding the current ones",
                 "consumes": [
                     "application/json"
                 ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Restore posts from a backup",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Snapshot from POST /admin/backup",
+                        "name": "snapshot",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Restored"
+                    },
+                    "400": {
+                        "description": "Invalid snapshot",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid token",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/authors": {
+            "get": {
+                "description": "Get the distinct authors of all posts in alphabetical order",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "posts"
+                    "authors"
                 ],
-                "summary": "Update a post",
+                "summary": "List authors",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only authors whose name starts with this, ignoring case",
+                        "name": "prefix",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
-                        "description": "Post ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Page size; with limit or offset the response is an AuthorPage",
+                        "name": "limit",
+                        "in": "query"
                     },
                     {
-                        "description": "Updated post data",
-                        "name": "post",
-                        "in": "body",
-                        "required": true,
+                        "type": "integer",
+                        "description": "Number of authors to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/posts.PostCreateUpdate"
+                            "$ref": "#/definitions/posts.AuthorPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid pagination",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
                         }
                     }
+                }
+            }
+        },
+        "/posts": {
+            "get": {
+                "description": "Get a list of all blog posts",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/vnd.api+json"
+                ],
+                "tags": [
+                    "posts"
+                ],
+                "summary": "Get all posts",
+                "parameters": [
+                    {
+                        "enum": [
+                            "html"
+                        ],
+                        "type": "string",
+                        "description": "Set to html to include content rendered from Markdown",
+                        "name": "render",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to include, e.g. id,title",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "summary",
+                            "full"
+                        ],
+                        "type": "string",
+                        "description": "Set to summary to return PostSummary items with a content excerpt",
+                        "name": "view",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Set to true to include word_count and char_count computed from the content",
+                        "name": "stats",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20, max 100 unless configured otherwise); with limit or offset the response is a PostPage",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of posts to skip, in ID order",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Wrap the unpaginated list as {\\",
+                        "name": "envelope",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only posts created at or after this RFC 3339 time",
+                        "name": "createdAfter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only posts created before this RFC 3339 time",
+                        "name": "createdBefore",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Order by id, title, author, createdAt or updatedAt, descending with a leading -, e.g. -createdAt; ties are ordered by ID. Defaults to the configured sort, id unless set otherwise",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Return 304 if the collection has not changed since this time",
+                        "name": "If-Modified-Since",
+                        "in": "header"
+                    }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/posts.PostRead"
+                            "$ref": "#/definitions/posts.PostPage"
                         }
                     },
+                    "304": {
+                        "description": "Not Modified"
+                    },
                     "400": {
-                        "description": "Invalid post ID or request body",
+                        "description": "Unknown field or view, invalid pagination, a limit above the maximum when configured to reject it, invalid date range, unknown sort, or an unknown query parameter in strict mode",
                         "schema": {
                             "type": "string"
                         }
                     },
-                    "404": {
-                        "description": "Post not found",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "string"
                         }
                     }
                 }
             },
-            "delete": {
-                "description": "Delete a blog post by its ID",
+            "post": {
+                "description": "Create a new blog post",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/vnd.api+json"
                 ],
                 "tags": [
                     "posts"
                 ],
-                "summary": "Delete a post",
+                "summary": "Create a new post",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Post ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Post data",
+                        "name": "post",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/posts.PostCreateUpdate"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key making retried creates return the original post",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/posts.PostRead"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "URL of the new post"
+                            }
+                        }
                     },
                     "400": {
-                        "description": "Invalid post ID",
+                        "description": "Invalid request body or validation error",
+                        "schema": {
+                            "$ref": "#/definitions/posts.validationErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "A post with the same title and author exists",
                         "schema": {
                             "type": "string"
                         }
                     },
-                    "404": {
-                        "description": "Post not found",
+                    "422": {
+                        "description": "The idempotency key was already used with a different body",
                         "schema": {
                             "type": "string"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "507": {
+                        "description": "The repository is at capacity",
                         "schema": {
                             "type": "string"
                         }
                     }
                 }
-            }
-        }
-    },
-    "definitions": {
-        "posts.PostCreateUpdate": {
-            "type": "object",
-            "required": [
-                "author",
-                "content",
-                "title"
-            ],
-            "properties": {
-                "author": {
-                    "type": "string"
-                },
-                "content": {
+            },
+            "delete": {
+                "description": "Delete the posts listed in the body or in the ids query parameter and report which were not found",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "posts"
+                ],
+                "summary": "Delete several posts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma-separated post IDs, used when there is no body",
+                        "name": "ids",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Post IDs",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/posts.BulkDeleteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/posts.BulkDeleteResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid or missing post IDs",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Apply the same partial update to every listed post and report which were not found. If any patched post is invalid, none is changed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "posts"
+                ],
+                "summary": "Partially update several posts",
+                "parameters": [
+                    {
+                        "description": "Post IDs and the fields to change",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/posts.BulkPatchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/posts.BulkPatchResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid or missing post IDs, or invalid input",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts/batch-get": {
+            "post": {
+                "description": "Get the posts whose IDs are listed in the body, for lists too long for a query string. Posts are returned in the order their IDs are first listed; repeated IDs are returned once and missing ones are left out. At most 1000 distinct IDs may be given.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "posts"
+                ],
+                "summary": "Get several posts by ID",
+                "parameters": [
+                    {
+                        "description": "Post IDs",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/posts.BatchGetRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/posts.PostRead"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid, missing or too many post IDs",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts/export": {
+            "get": {
+                "description": "Download all blog posts as a CSV attachment, a JSON array or NDJSON streamed one post per line",
+                "produces": [
+                    "text/csv",
+                    "application/json",
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "posts"
+                ],
+                "summary": "Export all posts",
+                "parameters": [
+                    {
+                        "enum": [
+                            "csv",
+                            "json",
+                            "ndjson"
+                        ],
+                        "type": "string",
+                        "default": "csv",
+                        "description": "Export format",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/posts.PostRead"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Unsupported export format",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts/feed.xml": {
+            "get": {
+                "description": "Get the most recently created published posts, newest first, as an RSS 2.0 feed. The number of items and the feed's title and link are configured on the server.",
+                "produces": [
+                    "application/rss+xml"
+                ],
+                "tags": [
+                    "posts"
+                ],
+                "summary": "Get an RSS feed of recent posts",
+                "responses": {
+                    "200": {
+                        "description": "RSS 2.0 document",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts/import": {
+            "post": {
+                "description": "Create posts from a CSV upload (multipart field \"file\" or a raw text/csv body) with title, content and author columns",
+                "consumes": [
+                    "text/csv",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "posts"
+                ],
+                "summary": "Import posts from CSV",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "CSV file",
+                        "name": "file",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Create nothing if any row is invalid",
+                        "name": "atomic",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/posts.ImportResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid CSV upload",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "413": {
+                        "description": "The upload is larger than 10 MiB",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Atomic import rejected because of invalid rows",
+                        "schema": {
+                            "$ref": "#/definitions/posts.ImportResult"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "507": {
+                        "description": "The import would exceed the repository's capacity",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts/random": {
+            "get": {
+                "description": "Get one randomly selected blog post",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/vnd.api+json"
+                ],
+                "tags": [
+                    "posts"
+                ],
+                "summary": "Get a random post",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/posts.PostRead"
+                        }
+                    },
+                    "404": {
+                        "description": "No posts",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts/recent": {
+            "get": {
+                "description": "Get the n most recently created posts, newest first. n is clamped to at most 50.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/vnd.api+json"
+                ],
+                "tags": [
+                    "posts"
+                ],
+                "summary": "Get the most recent posts",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Number of posts to return (default 10, max 50)",
+                        "name": "n",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Wrap the list as {\\",
+                        "name": "envelope",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/posts.PostList"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid n",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts/search": {
+            "get": {
+                "description": "Get the posts whose title or content contains q, in ID order. Case is ignored, and so are accents unless strictAccents is set, so that \"cafe\" matches \"café\".",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/vnd.api+json"
+                ],
+                "tags": [
+                    "posts"
+                ],
+                "summary": "Search posts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Text to search for",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Set to true to match accented letters only with themselves",
+                        "name": "strictAccents",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Wrap the list as {\\",
+                        "name": "envelope",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/posts.PostList"
+                        }
+                    },
+                    "400": {
+                        "description": "Missing q or invalid strictAccents",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts/stats": {
+            "get": {
+                "description": "Get the total number of posts and the number of posts per author, sorted by count descending",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "posts"
+                ],
+                "summary": "Get post statistics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only count authors whose name starts with this, ignoring case",
+                        "name": "prefix",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of authors per page; with limit or offset the response includes author_count and links",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of authors to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/posts.PostStats"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid pagination",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts/validate": {
+            "post": {
+                "description": "Run the checks a create would run, after the same normalization, without storing anything",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "posts"
+                ],
+                "summary": "Validate a post without saving it",
+                "parameters": [
+                    {
+                        "description": "Post data",
+                        "name": "post",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/posts.PostCreateUpdate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/posts.ValidationResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or validation error",
+                        "schema": {
+                            "$ref": "#/definitions/posts.validationErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts/{id}": {
+            "get": {
+                "description": "Get a single blog post by its ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/vnd.api+json"
+                ],
+                "tags": [
+                    "posts"
+                ],
+                "summary": "Get a post by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Post ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "html"
+                        ],
+                        "type": "string",
+                        "description": "Set to html to include content rendered from Markdown",
+                        "name": "render",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to include, e.g. id,title",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Cut content to this many characters and set truncated when it was longer",
+                        "name": "excerpt",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "chars",
+                            "sentence"
+                        ],
+                        "type": "string",
+                        "description": "Set to sentence to end an excerpt at the last sentence end, or else the last whole word, within the limit",
+                        "name": "excerptMode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Set to true to include word_count and char_count computed from the content",
+                        "name": "stats",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Return 304 if the post's ETag is one of these; takes precedence over If-Modified-Since",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Return 304 if the post has not changed since this time",
+                        "name": "If-Modified-Since",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/posts.PostRead"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "The post's ETag, for If-None-Match and, unless weak, for If-Match on PATCH"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Invalid post ID, unknown field, invalid excerpt length or unknown excerpt mode",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Post not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replace the blog post with the given ID, creating it if it does not exist. With If-Unmodified-Since the post must already exist.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/vnd.api+json"
+                ],
+                "tags": [
+                    "posts"
+                ],
+                "summary": "Create or replace a post",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Post ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated post data",
+                        "name": "post",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/posts.PostCreateUpdate"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only update if the post has not changed since this time",
+                        "name": "If-Unmodified-Since",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Post replaced",
+                        "schema": {
+                            "$ref": "#/definitions/posts.PostRead"
+                        }
+                    },
+                    "201": {
+                        "description": "Post created",
+                        "schema": {
+                            "$ref": "#/definitions/posts.PostRead"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "URL of the new post"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid post ID, request body or validation error",
+                        "schema": {
+                            "$ref": "#/definitions/posts.validationErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Post not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "412": {
+                        "description": "Post modified since If-Unmodified-Since",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "507": {
+                        "description": "The repository is at capacity",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a blog post by its ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "posts"
+                ],
+                "summary": "Delete a post",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Post ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only delete if the post has not changed since this time",
+                        "name": "If-Unmodified-Since",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Invalid post ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Post not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "412": {
+                        "description": "Post modified since If-Unmodified-Since",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Set the fields present in the body on the post with the given ID. Without If-Match the patch is applied to the current version.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/vnd.api+json"
+                ],
+                "tags": [
+                    "posts"
+                ],
+                "summary": "Partially update a post",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Post ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "post",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/posts.PostPatch"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only update if the post's ETag is one of these, or * for any",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/posts.PostRead"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "The strong ETag of the updated post, for If-Match"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid post ID, request body or validation error",
+                        "schema": {
+                            "$ref": "#/definitions/posts.validationErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Post not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "412": {
+                        "description": "The post's ETag does not match If-Match",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts/{id}/neighbors": {
+            "get": {
+                "description": "Get the posts before and after a post in ID order, which is also creation order; prev or next is null at either end",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "posts"
+                ],
+                "summary": "Get the previous and next posts",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Post ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/posts.PostNeighbors"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid post ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Post not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts/{id}/view": {
+            "post": {
+                "description": "Increment the view counter of a blog post and return the new count",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "posts"
+                ],
+                "summary": "Register a post view",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Post ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/posts.PostViews"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid post ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Post not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/ws/posts": {
+            "get": {
+                "description": "Upgrade to a WebSocket that receives a PostEvent JSON frame for every created, updated or deleted post",
+                "tags": [
+                    "posts"
+                ],
+                "summary": "Stream post changes",
+                "responses": {
+                    "101": {
+                        "description": "Switching to the WebSocket protocol",
+                        "schema": {
+                            "$ref": "#/definitions/posts.PostEvent"
+                        }
+                    },
+                    "400": {
+                        "description": "Not a WebSocket handshake",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "posts.AuthorPage": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "links": {
+                    "$ref": "#/definitions/posts.PageLinks"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "posts.AuthorStats": {
+            "type": "object",
+            "properties": {
+                "author": {
+                    "type": "string"
+                },
+                "count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "posts.BatchGetRequest": {
+            "type": "object",
+            "properties": {
+                "ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "posts.BulkDeleteRequest": {
+            "type": "object",
+            "properties": {
+                "ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "posts.BulkDeleteResult": {
+            "type": "object",
+            "properties": {
+                "deleted": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "not_found": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "posts.BulkPatchRequest": {
+            "type": "object",
+            "properties": {
+                "ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "patch": {
+                    "$ref": "#/definitions/posts.PostPatch"
+                }
+            }
+        },
+        "posts.BulkPatchResult": {
+            "type": "object",
+            "properties": {
+                "not_found": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "updated": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/posts.PostRead"
+                    }
+                }
+            }
+        },
+        "posts.FieldError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "rule": {
+                    "type": "string"
+                }
+            }
+        },
+        "posts.ImportResult": {
+            "type": "object",
+            "properties": {
+                "created_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/posts.ImportRowError"
+                    }
+                }
+            }
+        },
+        "posts.ImportRowError": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "line": {
+                    "type": "integer"
+                }
+            }
+        },
+        "posts.MaintenanceStatus": {
+            "type": "object",
+            "properties": {
+                "level": {
+                    "type": "string",
+                    "enum": [
+                        "off",
+                        "read-only",
+                        "closed"
+                    ]
+                }
+            }
+        },
+        "posts.PageLinks": {
+            "type": "object",
+            "properties": {
+                "first": {
+                    "type": "string"
+                },
+                "last": {
+                    "type": "string"
+                },
+                "next": {
+                    "type": "string"
+                },
+                "prev": {
+                    "type": "string"
+                }
+            }
+        },
+        "posts.PostCreateUpdate": {
+            "type": "object",
+            "required": [
+                "author",
+                "title"
+            ],
+            "properties": {
+                "author": {
+                    "type": "string"
+                },
+                "content": {
+                    "type": "string"
+                },
+                "image_url": {
+                    "description": "ImageURL is the address of the post's cover image, if it has one.",
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "draft",
+                        "published"
+                    ]
+                },
+                "tags": {
+                    "description": "Tags are lowercased and de-duplicated before they are validated.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "posts.PostEvent": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "post": {
+                    "$ref": "#/definitions/posts.PostRead"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "posts.PostList": {
+            "type": "object",
+            "properties": {
+                "posts": {
+                    "type": "array",
+                    "items": {
+                        "type": "object"
+                    }
+                }
+            }
+        },
+        "posts.PostNeighbors": {
+            "type": "object",
+            "properties": {
+                "next": {
+                    "$ref": "#/definitions/posts.PostRead"
+                },
+                "prev": {
+                    "$ref": "#/definitions/posts.PostRead"
+                }
+            }
+        },
+        "posts.PostPage": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "type": "object"
+                    }
+                },
+                "links": {
+                    "$ref": "#/definitions/posts.PageLinks"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "posts.PostPatch": {
+            "type": "object",
+            "properties": {
+                "author": {
+                    "type": "string"
+                },
+                "content": {
+                    "type": "string"
+                },
+                "image_url": {
                     "type": "string"
                 },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "draft",
+                        "published"
+                    ]
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "title": {
                     "type": "string"
                 }
@@ -258,14 +1744,126 @@ const docTemplate = `{
                 "author": {
                     "type": "string"
                 },
+                "char_count": {
+                    "type": "integer"
+                },
                 "content": {
                     "type": "string"
                 },
+                "content_html": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "truncated": {
+                    "type": "boolean"
+                },
+                "uid": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "views": {
+                    "type": "integer"
+                },
+                "word_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "posts.PostStats": {
+            "type": "object",
+            "properties": {
+                "author_count": {
+                    "type": "integer"
+                },
+                "authors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/posts.AuthorStats"
+                    }
+                },
+                "links": {
+                    "$ref": "#/definitions/posts.PageLinks"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "posts.PostSummary": {
+            "type": "object",
+            "properties": {
+                "author": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "excerpt": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "integer"
                 },
                 "title": {
                     "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "posts.PostViews": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "views": {
+                    "type": "integer"
+                }
+            }
+        },
+        "posts.ValidationResult": {
+            "type": "object",
+            "properties": {
+                "valid": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "posts.validationErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/posts.FieldError"
+                    }
                 }
             }
         }
@@ -276,7 +1874,7 @@ const docTemplate = `{
 var SwaggerInfo = &swag.Spec{
 	Version:          "1.0",
 	Host:             "localhost:8000",
-	BasePath:         "/",
+	BasePath:         "/api/v1",
 	Schemes:          []string{},
 	Title:            "Blog API",
 	Description:      "A simple blog API for managing posts",
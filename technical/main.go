@@ -1,34 +1,210 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	httpSwagger "github.com/swaggo/http-swagger"
+	"google.golang.org/grpc"
+	"io"
 	"log"
+	"log/slog"
+	"net"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 	_ "technical/docs" // Import generated docs
 	"technical/posts"
+	"technical/postspb"
 )
 
 // @title Blog API
 // @version 1.0
 // @description A simple blog API for managing posts
 // @host localhost:8000
-// @BasePath /
+// @BasePath /api/v1
+
+// apiBasePath is the prefix every API route is mounted under; /metrics and /swagger/
+// stay at the root.
+const apiBasePath = "/api/v1"
 
 func main() {
-	mux := http.NewServeMux()
+	cfg := posts.LoadConfig()
+	logger := posts.NewLogger(cfg.LogLevel, cfg.LogFormat, os.Stdout)
+	slog.SetDefault(logger)
+	hub := posts.NewHub()
+
+	// Post routes are registered on postsMux once the repository has loaded; until
+	// then the gate answers them with 503 so that traffic can be accepted right away.
+	var gate posts.ReadinessGate
+	var maintenance posts.MaintenanceMode
+	var inFlight posts.InFlightTracker
+	postsMux := http.NewServeMux()
+	repos := make(chan posts.Repository, 1)
+	go func() {
+		repos <- startPosts(cfg, postsMux, hub, &maintenance, logger)
+		gate.MarkReady()
+	}()
+
+	root := newServerHandler(cfg, postsMux, &gate, &maintenance, &inFlight, hub, prometheus.DefaultRegisterer, logger)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	server := &http.Server{Addr: ":8000", Handler: root}
+	go func() {
+		fmt.Printf("Server starting on port %s...\n", server.Addr)
+		if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal(err)
+		}
+	}()
+	<-ctx.Done()
+
+	// Let in-flight requests finish, then close the repository so that an autosave
+	// writes its last changes.
+	logger.Info("shutting down", "in_flight", inFlight.Count(), "timeout", cfg.ShutdownTimeout)
+	if err := posts.GracefulShutdown(server, &inFlight, cfg.ShutdownTimeout); err != nil {
+		logger.Error("shutting down server", "error", err)
+	}
+	select {
+	case repo := <-repos:
+		if closer, ok := repo.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				logger.Error("closing repository", "error", err)
+			}
+		}
+	default:
+	}
+}
 
-	repo := posts.NewMapRepository()
-	service := posts.NewPostService(repo)
-	handler := posts.NewHandler(service)
+// newServerHandler serves the API routes registered on postsMux under apiBasePath, along
+// with /metrics, /swagger/, /healthz and the WebSocket feed, wrapped in the middleware
+// every request goes through.
+func newServerHandler(cfg posts.Config, postsMux *http.ServeMux, gate *posts.ReadinessGate, maintenance *posts.MaintenanceMode, inFlight *posts.InFlightTracker, hub *posts.Hub, reg prometheus.Registerer, logger *slog.Logger) http.Handler {
+	mux := http.NewServeMux()
+	// Maintenance mode leaves /admin/ reachable so that it can be switched off again.
+	// Read-only mode leaves /admin/ writable too, so that a mirror can still be reloaded.
+	var api http.Handler = maintenance.Middleware(gate.Middleware(postsMux))
+	if cfg.ReadOnly {
+		api = posts.ReadOnlyMiddleware(api)
+	}
+	cacheControl := posts.CacheControlMiddleware(cfg.CacheMaxAge)
+	for _, pattern := range []string{"/posts", "/posts/", "/authors"} {
+		mux.Handle(apiBasePath+pattern, cacheControl(api))
+	}
+	mux.Handle(apiBasePath+"/graphql", api)
+	mux.Handle(apiBasePath+"/admin/", gate.Middleware(http.StripPrefix(apiBasePath, postsMux)))
 
-	handler.RegisterRoutes(mux)
+	mux.Handle("/metrics", promhttp.Handler())
 
 	mux.HandleFunc("/swagger/", httpSwagger.Handler(
 		httpSwagger.URL("/swagger/doc.json"),
 	).ServeHTTP)
 
-	port := ":8000"
-	fmt.Printf("Server starting on port %s...\n", port)
-	log.Fatal(http.ListenAndServe(port, mux))
+	// The timeout middleware buffers whole responses, so exports, which are streamed and
+	// may take longer than any request timeout, bypass it. WebSocket connections outlive
+	// any request timeout and need the raw connection, so they bypass it as well. Neither
+	// they nor health checks count as in-flight requests, which shutdown waits for.
+	gzip := posts.GzipMiddleware(posts.DefaultGzipConfig())
+	var root http.Handler = posts.TimeoutMiddleware(cfg.RequestTimeout)(mux)
+	root = inFlight.Middleware(gzip(root))
+	outer := http.NewServeMux()
+	outer.Handle(apiBasePath+"/posts/export", inFlight.Middleware(gzip(mux)))
+	outer.Handle(apiBasePath+"/ws/posts", hub)
+	outer.Handle("/healthz", posts.HealthHandler(gate, inFlight))
+	outer.Handle("/", root)
+	root = outer
+	if len(cfg.CORSAllowedOrigins) > 0 {
+		cors := posts.DefaultCORSConfig()
+		cors.AllowedOrigins = cfg.CORSAllowedOrigins
+		cors.MaxAge = cfg.CORSMaxAge
+		cors.AllowCredentials = cfg.CORSAllowCredentials
+		if err := cors.Validate(); err != nil {
+			log.Fatal(err)
+		}
+		root = posts.CORSMiddleware(cors)(root)
+	}
+	root = posts.MetricsMiddleware(reg, apiBasePath)(root)
+	root = posts.TracingMiddleware(nil, apiBasePath)(root)
+	root = posts.LoggingMiddleware(logger)(root)
+	return posts.RequestIDMiddleware(root)
+}
+
+// startPosts loads the repository, registers the post routes on mux and starts the gRPC
+// server. It returns the repository so that it can be closed on shutdown.
+func startPosts(cfg posts.Config, mux *http.ServeMux, hub *posts.Hub, maintenance *posts.MaintenanceMode, logger *slog.Logger) posts.Repository {
+	repo, err := posts.NewRepository(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	// Only the service's calls are retried, timed and counted: the admin reload and backups, the posts
+	// gauge and Close use repo itself, since the wrappers do not pass on Reloader, Snapshotter or io.Closer.
+	// Retries sit innermost so that the slow query log and the metrics see each call once, retries included,
+	// and coalesced reads share their retries too.
+	var serviceRepo posts.Repository = repo
+	if cfg.RetryAttempts > 1 {
+		serviceRepo = posts.NewRetryingRepository(serviceRepo, cfg.RetryPolicy())
+	}
+	var coalescing posts.Repository
+	if cfg.CoalesceReads {
+		coalescing = posts.NewCoalescingRepository(serviceRepo)
+		serviceRepo = coalescing
+	}
+	if cfg.SlowQueryThreshold > 0 {
+		serviceRepo = posts.NewSlowQueryRepository(serviceRepo, cfg.SlowQueryThreshold, logger)
+	}
+	serviceRepo = posts.NewInstrumentedRepository(serviceRepo, posts.NewPrometheusRepositoryRecorder(prometheus.DefaultRegisterer))
+	service := posts.NewPostService(serviceRepo,
+		posts.WithEventPublisher(hub),
+		posts.WithReadOnly(cfg.ReadOnly),
+		posts.WithValidator(posts.NewValidator(
+			posts.WithDistinctTitleContent(cfg.RejectTitleAsContent),
+			posts.WithKnownAuthors(cfg.KnownAuthors...),
+		)),
+	)
+
+	defaultSort, err := posts.ParsePostSort(cfg.DefaultSort)
+	if err != nil {
+		log.Fatal(err)
+	}
+	posts.NewHandler(service,
+		posts.WithLogger(logger),
+		posts.WithBasePath(apiBasePath),
+		posts.WithDefaultSort(defaultSort),
+		posts.WithFeed(cfg.Feed()),
+		posts.WithPagination(cfg.Pagination()),
+		posts.WithStrictQuery(cfg.StrictQuery),
+		posts.WithStringIDs(cfg.StringIDs),
+		posts.WithWeakETags(cfg.WeakETags),
+	).RegisterRoutes(mux)
+	mux.Handle(apiBasePath+"/graphql", posts.NewGraphQLHandler(service))
+	if cfg.AdminToken != "" {
+		reloader, _ := repo.(posts.Reloader)
+		snapshotter, _ := repo.(posts.Snapshotter)
+		if coalescing != nil {
+			// Reloads and restores replace posts behind the coalescing repository's back.
+			reloader = posts.NewCoalescedReloader(reloader, coalescing)
+			snapshotter = posts.NewCoalescedSnapshotter(snapshotter, coalescing)
+		}
+		posts.NewAdminHandler(reloader, maintenance, snapshotter, cfg.AdminToken).RegisterRoutes(mux)
+	}
+
+	if err := posts.RegisterPostsGauge(prometheus.DefaultRegisterer, repo); err != nil {
+		log.Fatal(err)
+	}
+
+	grpcListener, err := net.Listen("tcp", cfg.GRPCAddr)
+	if err != nil {
+		log.Fatal(err)
+	}
+	grpcServer := grpc.NewServer()
+	postspb.RegisterPostServiceServer(grpcServer, posts.NewGRPCServer(service))
+	go func() {
+		fmt.Printf("gRPC server starting on %s...\n", cfg.GRPCAddr)
+		log.Fatal(grpcServer.Serve(grpcListener))
+	}()
+	return repo
 }
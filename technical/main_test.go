@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"github.com/prometheus/client_golang/prometheus"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"technical/posts"
+	"testing"
+	"time"
+)
+
+// gatedRepository holds Iterate up after the posts before the first flush have been
+// visited, until the test has received them.
+type gatedRepository struct {
+	posts.Repository
+	flushAfter int
+	received   chan struct{}
+}
+
+func (r *gatedRepository) Iterate(ctx context.Context, fn func(post posts.PostRead) error) error {
+	visited := 0
+	return r.Repository.Iterate(ctx, func(post posts.PostRead) error {
+		if visited == r.flushAfter {
+			select {
+			case <-r.received:
+			case <-time.After(5 * time.Second):
+			}
+		}
+		visited++
+		return fn(post)
+	})
+}
+
+func TestExportStreamsPastRequestTimeout(t *testing.T) {
+	const total = 600
+	const flushAfter = 500
+	stored := make([]posts.PostRead, total)
+	for i := range stored {
+		stored[i] = posts.PostRead{ID: i + 1, Title: "Title", Content: "Content", Author: "Jane Doe"}
+	}
+	mapRepo, err := posts.NewMapRepositoryWithPosts(stored)
+	if err != nil {
+		t.Fatalf("Failed to set up repository: %v", err)
+	}
+	repo := &gatedRepository{Repository: mapRepo, flushAfter: flushAfter, received: make(chan struct{})}
+
+	cfg := posts.LoadConfig()
+	cfg.RequestTimeout = 50 * time.Millisecond
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	var gate posts.ReadinessGate
+	var maintenance posts.MaintenanceMode
+	var inFlight posts.InFlightTracker
+	postsMux := http.NewServeMux()
+	posts.NewHandler(posts.NewPostService(repo), posts.WithLogger(logger), posts.WithBasePath(apiBasePath)).RegisterRoutes(postsMux)
+	gate.MarkReady()
+	server := httptest.NewServer(newServerHandler(cfg, postsMux, &gate, &maintenance, &inFlight, posts.NewHub(), prometheus.NewRegistry(), logger))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + apiBasePath + "/posts/export?format=ndjson")
+	if err != nil {
+		t.Fatalf("Failed to request the export: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, resp.StatusCode)
+	}
+
+	// The export is held up until the first flushed posts arrive, so reading them
+	// proves they were sent before the handler finished.
+	reader := bufio.NewReader(resp.Body)
+	lines := 0
+	for ; lines < flushAfter; lines++ {
+		if _, err := reader.ReadString('\n'); err != nil {
+			t.Fatalf("Expected the first %d posts to be streamed, got %d: %v", flushAfter, lines, err)
+		}
+	}
+	time.Sleep(2 * cfg.RequestTimeout)
+	close(repo.received)
+
+	rest, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("Failed to read the rest of the export: %v", err)
+	}
+	lines += strings.Count(string(rest), "\n")
+	if lines != total {
+		t.Errorf("Expected %d posts past the request timeout, got %d", total, lines)
+	}
+}
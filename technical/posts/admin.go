@@ -0,0 +1,174 @@
+package posts
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// Reloader is implemented by repositories that can reread their backing file.
+type Reloader interface {
+	Reload() error
+}
+
+// AdminHandler serves maintenance endpoints under /admin/. Every request must carry
+// the handler's token as "Authorization: Bearer <token>". Routes whose dependency is
+// nil are not registered.
+type AdminHandler struct {
+	reloader    Reloader
+	maintenance *MaintenanceMode
+	snapshotter Snapshotter
+	token       string
+}
+
+func NewAdminHandler(reloader Reloader, maintenance *MaintenanceMode, snapshotter Snapshotter, token string) *AdminHandler {
+	return &AdminHandler{
+		reloader:    reloader,
+		maintenance: maintenance,
+		snapshotter: snapshotter,
+		token:       token,
+	}
+}
+
+func (h *AdminHandler) RegisterRoutes(mux *http.ServeMux) {
+	auth := BearerAuthMiddleware(h.token)
+	if h.reloader != nil {
+		mux.Handle("/admin/reload", auth(http.HandlerFunc(h.Reload)))
+	}
+	if h.maintenance != nil {
+		mux.Handle("/admin/maintenance", auth(http.HandlerFunc(h.Maintenance)))
+	}
+	if h.snapshotter != nil {
+		mux.Handle("/admin/backup", auth(http.HandlerFunc(h.Backup)))
+		mux.Handle("/admin/restore", auth(http.HandlerFunc(h.Restore)))
+	}
+}
+
+// Reload handles POST /admin/reload
+// @Summary Reload posts from disk
+// @Description Replace the in-memory posts with the contents of the data file, discarding changes made since it was loaded
+// @Tags admin
+// @Param Authorization header string true "Bearer token"
+// @Success 204 "Reloaded"
+// @Failure 401 {object} string "Missing or invalid token"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /admin/reload [post]
+func (h *AdminHandler) Reload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+
+	if err := h.reloader.Reload(); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Backup handles POST /admin/backup
+// @Summary Back up all posts
+// @Description Return a snapshot of every post and the next ID to hand out, which POST /admin/restore accepts
+// @Tags admin
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Success 200 {file} file "Snapshot"
+// @Failure 401 {object} string "Missing or invalid token"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /admin/backup [post]
+func (h *AdminHandler) Backup(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", `attachment; filename="backup.json"`)
+	// Once the snapshot has started streaming the status can no longer change, so a
+	// failure part-way through leaves a truncated body that Restore will reject.
+	if err := h.snapshotter.Snapshot(w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// Restore handles POST /admin/restore
+// @Summary Restore posts from a backup
+// @Description Replace every post with those in a snapshot returned by POST /admin/backup, discarding the current ones
+// @Tags admin
+// @Accept json
+// @Param Authorization header string true "Bearer token"
+// @Param snapshot body object true "Snapshot from POST /admin/backup"
+// @Success 204 "Restored"
+// @Failure 400 {object} string "Invalid snapshot"
+// @Failure 401 {object} string "Missing or invalid token"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /admin/restore [post]
+func (h *AdminHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+
+	if err := h.snapshotter.Restore(r.Body); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, ErrInvalidSnapshot) {
+			status = http.StatusBadRequest
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Maintenance handles GET and PUT /admin/maintenance
+// @Summary Get or set the maintenance level
+// @Description GET reports the current level. PUT switches to off, read-only (writes get 503) or closed (everything but /admin/ gets 503)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param level body MaintenanceStatus false "New level, for PUT"
+// @Success 200 {object} MaintenanceStatus
+// @Failure 400 {object} string "Invalid level"
+// @Failure 401 {object} string "Missing or invalid token"
+// @Router /admin/maintenance [get]
+// @Router /admin/maintenance [put]
+func (h *AdminHandler) Maintenance(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		var req MaintenanceStatus
+		if err := decodeJSON(r, &req); err != nil {
+			respondWithBodyError(w, r, err)
+			return
+		}
+		level, err := ParseMaintenanceLevel(req.Level)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		h.maintenance.Set(level)
+	default:
+		methodNotAllowed(w, "GET, PUT")
+		return
+	}
+
+	respondWithJSON(w, r, http.StatusOK, MaintenanceStatus{Level: h.maintenance.Level().String()})
+}
+
+// BearerAuthMiddleware answers 401 unless the request carries token as a bearer token.
+// An empty token rejects every request.
+func BearerAuthMiddleware(token string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			given, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || token == "" || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="admin"`)
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package posts
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type mockReloader struct {
+	calls int
+	err   error
+}
+
+func (m *mockReloader) Reload() error {
+	m.calls++
+	return m.err
+}
+
+func TestAdminReload(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		authorization  string
+		reloadErr      error
+		expectedStatus int
+		expectedCalls  int
+	}{
+		{
+			name:           "Success",
+			method:         http.MethodPost,
+			authorization:  "Bearer secret",
+			expectedStatus: http.StatusNoContent,
+			expectedCalls:  1,
+		},
+		{
+			name:           "Missing Token",
+			method:         http.MethodPost,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "Wrong Token",
+			method:         http.MethodPost,
+			authorization:  "Bearer guess",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "Wrong Scheme",
+			method:         http.MethodPost,
+			authorization:  "Basic secret",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "Method Not Allowed",
+			method:         http.MethodGet,
+			authorization:  "Bearer secret",
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:           "Reload Error",
+			method:         http.MethodPost,
+			authorization:  "Bearer secret",
+			reloadErr:      errors.New("unreadable file"),
+			expectedStatus: http.StatusInternalServerError,
+			expectedCalls:  1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			reloader := &mockReloader{err: tc.reloadErr}
+			mux := http.NewServeMux()
+			NewAdminHandler(reloader, nil, nil, "secret").RegisterRoutes(mux)
+
+			req := httptest.NewRequest(tc.method, "/admin/reload", nil)
+			if tc.authorization != "" {
+				req.Header.Set("Authorization", tc.authorization)
+			}
+			rr := httptest.NewRecorder()
+			mux.ServeHTTP(rr, req)
+
+			if rr.Code != tc.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tc.expectedStatus, rr.Code)
+			}
+			if reloader.calls != tc.expectedCalls {
+				t.Errorf("Expected %d reloads, got %d", tc.expectedCalls, reloader.calls)
+			}
+		})
+	}
+}
+
+func TestAdminMaintenance(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		body           string
+		expectedStatus int
+		expectedLevel  MaintenanceLevel
+	}{
+		{name: "Get", method: http.MethodGet, expectedStatus: http.StatusOK, expectedLevel: MaintenanceOff},
+		{name: "Set Read-Only", method: http.MethodPut, body: `{"level": "read-only"}`, expectedStatus: http.StatusOK, expectedLevel: MaintenanceReadOnly},
+		{name: "Set Closed", method: http.MethodPut, body: `{"level": "closed"}`, expectedStatus: http.StatusOK, expectedLevel: MaintenanceClosed},
+		{name: "Unknown Level", method: http.MethodPut, body: `{"level": "paused"}`, expectedStatus: http.StatusBadRequest, expectedLevel: MaintenanceOff},
+		{name: "Missing Body", method: http.MethodPut, expectedStatus: http.StatusBadRequest, expectedLevel: MaintenanceOff},
+		{name: "Method Not Allowed", method: http.MethodPost, expectedStatus: http.StatusMethodNotAllowed, expectedLevel: MaintenanceOff},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			maintenance := &MaintenanceMode{}
+			mux := http.NewServeMux()
+			NewAdminHandler(nil, maintenance, nil, "secret").RegisterRoutes(mux)
+
+			req := httptest.NewRequest(tc.method, "/admin/maintenance", strings.NewReader(tc.body))
+			req.Header.Set("Authorization", "Bearer secret")
+			rr := httptest.NewRecorder()
+			mux.ServeHTTP(rr, req)
+
+			if rr.Code != tc.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tc.expectedStatus, rr.Code)
+			}
+			if maintenance.Level() != tc.expectedLevel {
+				t.Errorf("Expected level %s, got %s", tc.expectedLevel, maintenance.Level())
+			}
+			if tc.expectedStatus == http.StatusOK {
+				var status MaintenanceStatus
+				json.Unmarshal(rr.Body.Bytes(), &status)
+				if status.Level != tc.expectedLevel.String() {
+					t.Errorf("Expected reported level %s, got %q", tc.expectedLevel, status.Level)
+				}
+			}
+		})
+	}
+}
+
+func TestAdminRoutesRequireDependencies(t *testing.T) {
+	mux := http.NewServeMux()
+	NewAdminHandler(nil, nil, nil, "secret").RegisterRoutes(mux)
+
+	for _, path := range []string{"/admin/reload", "/admin/maintenance", "/admin/backup", "/admin/restore"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Authorization", "Bearer secret")
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("Expected %s to be unregistered, got status %d", path, rr.Code)
+		}
+	}
+}
+
+func TestBearerAuthMiddlewareRejectsEmptyToken(t *testing.T) {
+	handler := BearerAuthMiddleware("")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected the handler not to be called")
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/reload", nil)
+	req.Header.Set("Authorization", "Bearer ")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status %d, got %d", http.StatusUnauthorized, rr.Code)
+	}
+}
@@ -0,0 +1,85 @@
+package posts
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// FieldChange is one field that an update changed, with its values before and after.
+type FieldChange struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+// UpdateAudit records which fields of a post an update changed. Changes is empty when
+// the update rewrote the post with the same values.
+type UpdateAudit struct {
+	PostID    int           `json:"post_id"`
+	UpdatedAt time.Time     `json:"updated_at"`
+	Changes   []FieldChange `json:"changes"`
+}
+
+// AuditSink receives an UpdateAudit for every update applied by the repository. Record
+// is called while the repository holds its write lock, so it must not block or call
+// back into the repository.
+type AuditSink interface {
+	Record(audit UpdateAudit)
+}
+
+// NopAuditSink discards every record. It is the default sink.
+type NopAuditSink struct{}
+
+func (NopAuditSink) Record(UpdateAudit) {}
+
+// MemoryAuditSink keeps every record in memory, in the order they were made.
+type MemoryAuditSink struct {
+	mutex  sync.Mutex
+	audits []UpdateAudit
+}
+
+func (s *MemoryAuditSink) Record(audit UpdateAudit) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.audits = append(s.audits, audit)
+}
+
+// Audits returns a copy of the records made so far.
+func (s *MemoryAuditSink) Audits() []UpdateAudit {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	audits := make([]UpdateAudit, len(s.audits))
+	copy(audits, s.audits)
+	return audits
+}
+
+// diffPosts lists the user-editable fields that differ between before and after.
+// Views and timestamps are maintained by the repository and left out.
+func diffPosts(before, after PostRead) []FieldChange {
+	changes := []FieldChange{}
+	for _, field := range []struct {
+		name     string
+		old, new string
+	}{
+		{"title", before.Title, after.Title},
+		{"content", before.Content, after.Content},
+		{"author", before.Author, after.Author},
+		{"status", before.Status, after.Status},
+		{"tags", strings.Join(before.Tags, ","), strings.Join(after.Tags, ",")},
+		{"image_url", before.ImageURL, after.ImageURL},
+	} {
+		if field.old != field.new {
+			changes = append(changes, FieldChange{Field: field.name, Old: field.old, New: field.new})
+		}
+	}
+	return changes
+}
+
+func newUpdateAudit(before, after PostRead) UpdateAudit {
+	return UpdateAudit{
+		PostID:    after.ID,
+		UpdatedAt: after.UpdatedAt,
+		Changes:   diffPosts(before, after),
+	}
+}
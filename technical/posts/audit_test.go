@@ -0,0 +1,94 @@
+package posts
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMapRepositoryUpdateAudit(t *testing.T) {
+	repo := setupTestRepository()
+	sink := &MemoryAuditSink{}
+	repo.SetAuditSink(sink)
+
+	updatedAt := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	repo.now = func() time.Time { return updatedAt }
+
+	existing := repo.posts[1]
+	existing.Status = StatusPublished
+	repo.posts[1] = existing
+
+	_, err := repo.Update(1, PostCreateUpdate{
+		Title:   "Renamed Post",
+		Content: existing.Content,
+		Author:  existing.Author,
+		Status:  existing.Status,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	audits := sink.Audits()
+	if len(audits) != 1 {
+		t.Fatalf("Expected 1 audit record, got %d", len(audits))
+	}
+	expected := UpdateAudit{
+		PostID:    1,
+		UpdatedAt: updatedAt,
+		Changes:   []FieldChange{{Field: "title", Old: "Test Post 1", New: "Renamed Post"}},
+	}
+	if !reflect.DeepEqual(audits[0], expected) {
+		t.Errorf("Expected %+v, got %+v", expected, audits[0])
+	}
+}
+
+func TestMapRepositoryFailedUpdateIsNotAudited(t *testing.T) {
+	repo := setupTestRepository()
+	sink := &MemoryAuditSink{}
+	repo.SetAuditSink(sink)
+
+	if _, err := repo.Update(99, PostCreateUpdate{Title: "Title", Content: "Content", Author: "Author"}); err != ErrPostNotFound {
+		t.Fatalf("Expected ErrPostNotFound, got %v", err)
+	}
+	if len(sink.Audits()) != 0 {
+		t.Errorf("Expected no audit records, got %+v", sink.Audits())
+	}
+}
+
+func TestDiffPosts(t *testing.T) {
+	before := PostRead{ID: 1, Title: "Title", Content: "Content", Author: "Author", Status: StatusDraft, Views: 3}
+
+	tests := []struct {
+		name            string
+		after           PostRead
+		expectedChanges []FieldChange
+	}{
+		{
+			name:            "Unchanged",
+			after:           before,
+			expectedChanges: []FieldChange{},
+		},
+		{
+			name:            "Views Ignored",
+			after:           PostRead{ID: 1, Title: "Title", Content: "Content", Author: "Author", Status: StatusDraft, Views: 4},
+			expectedChanges: []FieldChange{},
+		},
+		{
+			name:  "Content And Status",
+			after: PostRead{ID: 1, Title: "Title", Content: "New Content", Author: "Author", Status: StatusPublished, Views: 3},
+			expectedChanges: []FieldChange{
+				{Field: "content", Old: "Content", New: "New Content"},
+				{Field: "status", Old: StatusDraft, New: StatusPublished},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			changes := diffPosts(before, tc.after)
+			if !reflect.DeepEqual(changes, tc.expectedChanges) {
+				t.Errorf("Expected %+v, got %+v", tc.expectedChanges, changes)
+			}
+		})
+	}
+}
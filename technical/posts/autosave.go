@@ -0,0 +1,73 @@
+package posts
+
+import (
+	"log/slog"
+	"time"
+)
+
+// autoSaver is the background goroutine started by StartAutoSave.
+type autoSaver struct {
+	stop chan struct{}
+	done chan struct{}
+}
+
+// Flush writes the snapshot file if anything has changed since it was last written.
+func (r *MapRepository) Flush() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.snapshotPath == "" {
+		return ErrNoSnapshotFile
+	}
+	if !r.dirty {
+		return nil
+	}
+	if err := r.writeSnapshot(); err != nil {
+		return err
+	}
+	r.dirty = false
+	return nil
+}
+
+// StartAutoSave flushes the repository every interval in the background, so that a
+// burst of mutations costs one write of the snapshot file rather than one per mutation.
+// Close stops it after a final flush. Starting it again replaces the previous interval.
+func (r *MapRepository) StartAutoSave(interval time.Duration) {
+	r.stopAutoSave()
+
+	saver := &autoSaver{stop: make(chan struct{}), done: make(chan struct{})}
+	r.mutex.Lock()
+	r.autoSave = saver
+	r.mutex.Unlock()
+
+	go func() {
+		defer close(saver.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := r.Flush(); err != nil {
+					slog.Error("autosave failed", "error", err)
+				}
+			case <-saver.stop:
+				return
+			}
+		}
+	}()
+}
+
+// stopAutoSave stops the background flushes, if running, and flushes one last time.
+func (r *MapRepository) stopAutoSave() error {
+	r.mutex.Lock()
+	saver := r.autoSave
+	r.autoSave = nil
+	r.mutex.Unlock()
+
+	if saver == nil {
+		return nil
+	}
+	close(saver.stop)
+	<-saver.done
+	return r.Flush()
+}
@@ -0,0 +1,99 @@
+package posts
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestMapRepositoryFlush(t *testing.T) {
+	snapshotPath, _ := setupWALFiles(t)
+	repo, err := LoadMapRepository(snapshotPath, 0)
+	if err != nil {
+		t.Fatalf("Failed to load repository: %v", err)
+	}
+
+	if err := repo.Flush(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if repo.snapshotWrites != 0 {
+		t.Errorf("Expected a clean repository not to be written, got %d writes", repo.snapshotWrites)
+	}
+
+	expected := mutateForRecovery(t, repo)
+	if err := repo.Flush(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	reloaded, err := LoadMapRepository(snapshotPath, 0)
+	if err != nil {
+		t.Fatalf("Failed to reload repository: %v", err)
+	}
+	assertRecovered(t, reloaded, expected)
+}
+
+func TestMapRepositoryFlushWithoutSnapshotFile(t *testing.T) {
+	repo := setupTestRepository()
+
+	if err := repo.Flush(); !errors.Is(err, ErrNoSnapshotFile) {
+		t.Errorf("Expected ErrNoSnapshotFile, got %v", err)
+	}
+}
+
+func TestMapRepositoryAutoSaveBatchesBursts(t *testing.T) {
+	snapshotPath, _ := setupWALFiles(t)
+	repo, err := LoadMapRepository(snapshotPath, 0)
+	if err != nil {
+		t.Fatalf("Failed to load repository: %v", err)
+	}
+
+	const interval = 20 * time.Millisecond
+	start := time.Now()
+	repo.StartAutoSave(interval)
+	for i := range 200 {
+		if _, err := repo.Create(PostCreateUpdate{Title: fmt.Sprintf("Title %d", i), Content: "Content", Author: "Author"}); err != nil {
+			t.Fatalf("Failed to create post: %v", err)
+		}
+	}
+	time.Sleep(3 * interval)
+	if err := repo.Close(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// One write per elapsed tick at most, plus the final flush on Close.
+	limit := int(time.Since(start)/interval) + 1
+	if repo.snapshotWrites < 1 || repo.snapshotWrites > limit {
+		t.Errorf("Expected between 1 and %d snapshot writes for 200 creates, got %d", limit, repo.snapshotWrites)
+	}
+
+	reloaded, err := LoadMapRepository(snapshotPath, 0)
+	if err != nil {
+		t.Fatalf("Failed to reload repository: %v", err)
+	}
+	if len(reloaded.posts) != 201 {
+		t.Errorf("Expected 201 posts on disk, got %d", len(reloaded.posts))
+	}
+}
+
+func TestMapRepositoryCloseFlushesAutoSave(t *testing.T) {
+	snapshotPath, _ := setupWALFiles(t)
+	repo, err := LoadMapRepository(snapshotPath, 0)
+	if err != nil {
+		t.Fatalf("Failed to load repository: %v", err)
+	}
+
+	repo.StartAutoSave(time.Hour)
+	created, _ := repo.Create(PostCreateUpdate{Title: "Unsaved", Content: "Content", Author: "Author"})
+	if err := repo.Close(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	reloaded, err := LoadMapRepository(snapshotPath, 0)
+	if err != nil {
+		t.Fatalf("Failed to reload repository: %v", err)
+	}
+	if _, err := reloaded.GetByID(created.ID); err != nil {
+		t.Errorf("Expected post %d to be saved on close, got %v", created.ID, err)
+	}
+}
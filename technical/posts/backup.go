@@ -0,0 +1,72 @@
+package posts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+)
+
+// ErrInvalidSnapshot is returned by Restore for data that is not a snapshot written by
+// Snapshot.
+var ErrInvalidSnapshot = errors.New("invalid snapshot")
+
+// Snapshotter is implemented by repositories that can copy their whole state out for a
+// backup and replace it with one.
+type Snapshotter interface {
+	Snapshot(w io.Writer) error
+	Restore(r io.Reader) error
+}
+
+// snapshot returns the current state in the snapshot file format; it must be called with
+// the lock held.
+func (r *MapRepository) snapshot() mapSnapshot {
+	return mapSnapshot{
+		Posts:  slices.SortedFunc(maps.Values(r.posts), func(a, b PostRead) int { return a.ID - b.ID }),
+		NextID: r.nextID,
+	}
+}
+
+// Snapshot writes every post and the next ID to hand out to w as JSON, in the format of
+// the snapshot file.
+func (r *MapRepository) Snapshot(w io.Writer) error {
+	r.mutex.RLock()
+	snapshot := r.snapshot()
+	r.mutex.RUnlock()
+
+	return json.NewEncoder(w).Encode(snapshot)
+}
+
+// Restore replaces the posts and the next ID with those in a snapshot read from rd. The
+// snapshot is read and checked in full before anything is replaced, and the replacement
+// happens under the write lock, so readers see either the old state or the restored one.
+// With an append log the restored state is written to the snapshot file and the log is
+// emptied, as by Compact; otherwise it is left for the next Flush.
+func (r *MapRepository) Restore(rd io.Reader) error {
+	data, err := io.ReadAll(rd)
+	if err != nil {
+		return err
+	}
+	posts, nextID, err := decodeMapSnapshot(data, r.now().UTC(), "backup")
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSnapshot, err)
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.posts = posts
+	r.nextID = nextID
+	r.fileIDs = nil
+	r.dirty = true
+	if r.log == nil {
+		return nil
+	}
+	if err := r.writeSnapshot(); err != nil {
+		return err
+	}
+	r.dirty = false
+	return r.log.Truncate()
+}
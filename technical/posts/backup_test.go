@@ -0,0 +1,160 @@
+package posts
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMapRepositorySnapshotRestore(t *testing.T) {
+	source := setupTestRepository()
+	if err := source.Delete(2); err != nil {
+		t.Fatalf("Failed to delete post: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := source.Snapshot(&buf); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	restored := &MapRepository{posts: map[int]PostRead{9: {ID: 9, Title: "Stale"}}, nextID: 10, now: time.Now}
+	if err := restored.Restore(&buf); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected, _, _ := source.List(context.Background(), ListParams{})
+	got, _, _ := restored.List(context.Background(), ListParams{})
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected posts %+v, got %+v", expected, got)
+	}
+
+	// The deleted post's ID stays taken after the restore.
+	created, err := restored.Create(PostCreateUpdate{Title: "New", Content: "Content", Author: "Jane Doe"})
+	if err != nil {
+		t.Fatalf("Failed to create post: %v", err)
+	}
+	if created.ID != 3 {
+		t.Errorf("Expected the restored next ID 3, got ID %d", created.ID)
+	}
+}
+
+func TestMapRepositoryRestoreInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{name: "Not JSON", data: "posts"},
+		{name: "Empty", data: ""},
+		{name: "Duplicate ID", data: `{"posts": [{"id": 1, "title": "A"}, {"id": 1, "title": "B"}]}`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := setupTestRepository()
+
+			err := repo.Restore(strings.NewReader(tc.data))
+			if !errors.Is(err, ErrInvalidSnapshot) {
+				t.Errorf("Expected ErrInvalidSnapshot, got %v", err)
+			}
+			if posts, _ := repo.GetAll(context.Background()); len(posts) != 2 {
+				t.Errorf("Expected the posts to be left alone, got %d posts", len(posts))
+			}
+		})
+	}
+}
+
+func TestMapRepositoryRestoreSurvivesReopen(t *testing.T) {
+	snapshotPath, logPath := setupWALFiles(t)
+	repo, err := OpenMapRepository(snapshotPath, logPath, 0)
+	if err != nil {
+		t.Fatalf("Failed to open repository: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := repo.Snapshot(&buf); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	mutateForRecovery(t, repo)
+	if err := repo.Restore(&buf); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	backedUp, _, _ := repo.List(context.Background(), ListParams{})
+	repo.Close()
+
+	// The log written before the restore must not be replayed over it.
+	reopened, err := OpenMapRepository(snapshotPath, logPath, 0)
+	if err != nil {
+		t.Fatalf("Failed to reopen repository: %v", err)
+	}
+	defer reopened.Close()
+
+	got, _, _ := reopened.List(context.Background(), ListParams{})
+	if !reflect.DeepEqual(got, backedUp) {
+		t.Errorf("Expected the restored posts %+v, got %+v", backedUp, got)
+	}
+}
+
+func TestAdminBackupRestore(t *testing.T) {
+	source := setupTestRepository()
+	target := &MapRepository{posts: make(map[int]PostRead), mutex: sync.RWMutex{}, nextID: 1, now: time.Now}
+	sourceMux := http.NewServeMux()
+	NewAdminHandler(nil, nil, source, "secret").RegisterRoutes(sourceMux)
+	targetMux := http.NewServeMux()
+	NewAdminHandler(nil, nil, target, "secret").RegisterRoutes(targetMux)
+
+	serve := func(mux *http.ServeMux, method, path, body, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, req)
+		return rr
+	}
+
+	backup := serve(sourceMux, http.MethodPost, "/admin/backup", "", "secret")
+	if backup.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, backup.Code)
+	}
+	if got := backup.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("Expected Content-Type application/json, got %q", got)
+	}
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		token          string
+		expectedStatus int
+	}{
+		{name: "Backup Without Token", method: http.MethodPost, path: "/admin/backup", expectedStatus: http.StatusUnauthorized},
+		{name: "Restore Without Token", method: http.MethodPost, path: "/admin/restore", body: backup.Body.String(), expectedStatus: http.StatusUnauthorized},
+		{name: "Backup Method Not Allowed", method: http.MethodGet, path: "/admin/backup", token: "secret", expectedStatus: http.StatusMethodNotAllowed},
+		{name: "Restore Method Not Allowed", method: http.MethodGet, path: "/admin/restore", token: "secret", expectedStatus: http.StatusMethodNotAllowed},
+		{name: "Restore Invalid Snapshot", method: http.MethodPost, path: "/admin/restore", body: "{", token: "secret", expectedStatus: http.StatusBadRequest},
+		{name: "Restore", method: http.MethodPost, path: "/admin/restore", body: backup.Body.String(), token: "secret", expectedStatus: http.StatusNoContent},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rr := serve(targetMux, tc.method, tc.path, tc.body, tc.token)
+			if rr.Code != tc.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tc.expectedStatus, rr.Code)
+			}
+		})
+	}
+
+	expected, _, _ := source.List(context.Background(), ListParams{})
+	got, _, _ := target.List(context.Background(), ListParams{})
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected the restored posts %+v, got %+v", expected, got)
+	}
+}
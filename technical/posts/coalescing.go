@@ -0,0 +1,192 @@
+package posts
+
+import (
+	"golang.org/x/sync/singleflight"
+	"io"
+	"slices"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// coalescingRepository shares one call to GetByID of repo among all concurrent calls for
+// the same ID, so that a burst of requests for a post makes a single fetch. Every other
+// method is passed through unchanged. Writes to a post, including the creation of a post
+// under its ID, forget the fetch in flight for it, so that a GetByID starting after a
+// write has returned never gets the post as it was before.
+type coalescingRepository struct {
+	Repository
+	group singleflight.Group
+	// generation is part of every key, so that raising it forgets all fetches in flight.
+	generation atomic.Uint64
+}
+
+// coalescingTransactor is the coalescingRepository of a repository that is a Transactor,
+// so that wrapping it keeps transactions available.
+type coalescingTransactor struct {
+	*coalescingRepository
+}
+
+// NewCoalescingRepository wraps repo so that concurrent GetByID calls for the same ID
+// share one underlying call, which helps most in front of slow backends. The result is
+// a Transactor if repo is one; calls made inside a transaction are not coalesced. Other
+// optional interfaces, such as Reloader and io.Closer, are not passed on and should be
+// used on repo itself.
+func NewCoalescingRepository(repo Repository) Repository {
+	coalescing := &coalescingRepository{Repository: repo}
+	if _, ok := repo.(Transactor); ok {
+		return coalescingTransactor{coalescing}
+	}
+	return coalescing
+}
+
+func (r *coalescingRepository) GetByID(id int) (PostRead, error) {
+	result, err, shared := r.group.Do(r.key(id), func() (interface{}, error) {
+		return r.Repository.GetByID(id)
+	})
+	post := result.(PostRead)
+	if shared {
+		// The callers share one PostRead; give each its own tags to modify.
+		post.Tags = slices.Clone(post.Tags)
+	}
+	return post, err
+}
+
+func (r *coalescingRepository) key(id int) string {
+	return strconv.FormatUint(r.generation.Load(), 10) + ":" + strconv.Itoa(id)
+}
+
+// forget makes the next GetByID for id start a new fetch rather than join one that may
+// have read the post before a write to it.
+func (r *coalescingRepository) forget(id int) {
+	r.group.Forget(r.key(id))
+}
+
+// forgetAll makes every GetByID start a new fetch.
+func (r *coalescingRepository) forgetAll() {
+	r.generation.Add(1)
+}
+
+func (r *coalescingRepository) Create(data PostCreateUpdate) (PostRead, error) {
+	post, err := r.Repository.Create(data)
+	if err == nil {
+		r.forget(post.ID)
+	}
+	return post, err
+}
+
+func (r *coalescingRepository) CreateMany(data []PostCreateUpdate) ([]PostRead, error) {
+	posts, err := r.Repository.CreateMany(data)
+	for _, post := range posts {
+		r.forget(post.ID)
+	}
+	return posts, err
+}
+
+func (r *coalescingRepository) Update(id int, data PostCreateUpdate) (PostRead, error) {
+	defer r.forget(id)
+	return r.Repository.Update(id, data)
+}
+
+func (r *coalescingRepository) Upsert(id int, data PostCreateUpdate) (PostRead, bool, error) {
+	defer r.forget(id)
+	return r.Repository.Upsert(id, data)
+}
+
+func (r *coalescingRepository) Delete(id int) error {
+	defer r.forget(id)
+	return r.Repository.Delete(id)
+}
+
+func (r *coalescingRepository) DeleteMany(ids []int) ([]int, error) {
+	defer func() {
+		for _, id := range ids {
+			r.forget(id)
+		}
+	}()
+	return r.Repository.DeleteMany(ids)
+}
+
+func (r *coalescingRepository) UpdateIfUnmodified(id int, data PostCreateUpdate, since time.Time) (PostRead, error) {
+	defer r.forget(id)
+	return r.Repository.UpdateIfUnmodified(id, data, since)
+}
+
+func (r *coalescingRepository) UpdateIfMatch(id int, data PostCreateUpdate, etag string) (PostRead, error) {
+	defer r.forget(id)
+	return r.Repository.UpdateIfMatch(id, data, etag)
+}
+
+func (r *coalescingRepository) DeleteIfUnmodified(id int, since time.Time) error {
+	defer r.forget(id)
+	return r.Repository.DeleteIfUnmodified(id, since)
+}
+
+func (r *coalescingRepository) IncrementViews(id int) (int, error) {
+	defer r.forget(id)
+	return r.Repository.IncrementViews(id)
+}
+
+// WithTx passes fn the underlying transaction, whose reads must see its own writes. The
+// posts it may have written are not known here, so every fetch in flight is forgotten
+// once it commits.
+func (r coalescingTransactor) WithTx(fn func(tx Repository) error) error {
+	defer r.forgetAll()
+	return r.Repository.(Transactor).WithTx(fn)
+}
+
+// coalescedReloader forgets every fetch in flight of a coalescing repository once the
+// repository behind it has been reloaded.
+type coalescedReloader struct {
+	Reloader
+	coalescing *coalescingRepository
+}
+
+func (r coalescedReloader) Reload() error {
+	defer r.coalescing.forgetAll()
+	return r.Reloader.Reload()
+}
+
+// coalescedSnapshotter forgets every fetch in flight of a coalescing repository once the
+// repository behind it has been restored.
+type coalescedSnapshotter struct {
+	Snapshotter
+	coalescing *coalescingRepository
+}
+
+func (s coalescedSnapshotter) Restore(rd io.Reader) error {
+	defer s.coalescing.forgetAll()
+	return s.Snapshotter.Restore(rd)
+}
+
+// asCoalescing returns the coalescingRepository of repo, or nil if repo was not returned
+// by NewCoalescingRepository.
+func asCoalescing(repo Repository) *coalescingRepository {
+	switch repo := repo.(type) {
+	case *coalescingRepository:
+		return repo
+	case coalescingTransactor:
+		return repo.coalescingRepository
+	}
+	return nil
+}
+
+// NewCoalescedReloader returns reloader, which reloads the repository wrapped by
+// coalescing, made to forget the fetches in flight of coalescing after every reload.
+// Coalescing must have been returned by NewCoalescingRepository; a nil reloader stays nil.
+func NewCoalescedReloader(reloader Reloader, coalescing Repository) Reloader {
+	c := asCoalescing(coalescing)
+	if reloader == nil || c == nil {
+		return reloader
+	}
+	return coalescedReloader{Reloader: reloader, coalescing: c}
+}
+
+// NewCoalescedSnapshotter is NewCoalescedReloader for the restores of a Snapshotter.
+func NewCoalescedSnapshotter(snapshotter Snapshotter, coalescing Repository) Snapshotter {
+	c := asCoalescing(coalescing)
+	if snapshotter == nil || c == nil {
+		return snapshotter
+	}
+	return coalescedSnapshotter{Snapshotter: snapshotter, coalescing: c}
+}
@@ -0,0 +1,192 @@
+package posts
+
+import (
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCoalescingRepositoryGetByID(t *testing.T) {
+	tests := []struct {
+		name          string
+		ids           []int
+		expectedCalls int32
+	}{
+		{name: "Same ID", ids: []int{7}, expectedCalls: 1},
+		{name: "Different IDs", ids: []int{7, 8, 9}, expectedCalls: 3},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var calls atomic.Int32
+			repo := NewCoalescingRepository(&MockRepository{
+				GetByIDFn: func(id int) (PostRead, error) {
+					calls.Add(1)
+					time.Sleep(50 * time.Millisecond)
+					return PostRead{ID: id, Title: "Title", Tags: []string{"go"}}, nil
+				},
+			})
+
+			const callers = 50
+			var wg sync.WaitGroup
+			start := make(chan struct{})
+			errs := make(chan error, callers)
+			for i := 0; i < callers; i++ {
+				id := tc.ids[i%len(tc.ids)]
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					<-start
+					post, err := repo.GetByID(id)
+					if err != nil {
+						errs <- err
+						return
+					}
+					if post.ID != id || post.Title != "Title" {
+						t.Errorf("Expected post %d, got %+v", id, post)
+					}
+					// Each caller may change its own copy of the tags.
+					post.Tags[0] = "mine"
+				}()
+			}
+			close(start)
+			wg.Wait()
+			close(errs)
+
+			for err := range errs {
+				t.Errorf("Expected no error, got %v", err)
+			}
+			if got := calls.Load(); got != tc.expectedCalls {
+				t.Errorf("Expected %d underlying calls, got %d", tc.expectedCalls, got)
+			}
+		})
+	}
+}
+
+// replacingRepository stands for a repository whose posts are replaced by Reload and
+// Restore without going through a wrapping coalescing repository.
+type replacingRepository struct{}
+
+func (replacingRepository) Reload() error              { return nil }
+func (replacingRepository) Snapshot(w io.Writer) error { return nil }
+func (replacingRepository) Restore(rd io.Reader) error { return nil }
+
+func TestCoalescingRepositoryForgetsOnWrite(t *testing.T) {
+	tests := []struct {
+		name  string
+		write func(repo Repository) error
+	}{
+		{
+			name: "Update",
+			write: func(repo Repository) error {
+				_, err := repo.Update(1, PostCreateUpdate{Title: "After"})
+				return err
+			},
+		},
+		{
+			name: "Create",
+			write: func(repo Repository) error {
+				_, err := repo.Create(PostCreateUpdate{Title: "After"})
+				return err
+			},
+		},
+		{
+			name: "CreateMany",
+			write: func(repo Repository) error {
+				_, err := repo.CreateMany([]PostCreateUpdate{{Title: "After"}})
+				return err
+			},
+		},
+		{
+			name: "Reload",
+			write: func(repo Repository) error {
+				return NewCoalescedReloader(replacingRepository{}, repo).Reload()
+			},
+		},
+		{
+			name: "Restore",
+			write: func(repo Repository) error {
+				return NewCoalescedSnapshotter(replacingRepository{}, repo).Restore(strings.NewReader("{}"))
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var calls atomic.Int32
+			release := make(chan struct{})
+			repo := NewCoalescingRepository(&MockRepository{
+				GetByIDFn: func(id int) (PostRead, error) {
+					if calls.Add(1) == 1 {
+						<-release
+						return PostRead{ID: id, Title: "Before"}, nil
+					}
+					return PostRead{ID: id, Title: "After"}, nil
+				},
+				CreateFn: func(data PostCreateUpdate) (PostRead, error) {
+					return PostRead{ID: 1, Title: data.Title}, nil
+				},
+				CreateManyFn: func(data []PostCreateUpdate) ([]PostRead, error) {
+					return []PostRead{{ID: 1, Title: data[0].Title}}, nil
+				},
+				UpdateFn: func(id int, data PostCreateUpdate) (PostRead, error) {
+					return PostRead{ID: id, Title: data.Title}, nil
+				},
+			})
+
+			before := make(chan PostRead)
+			go func() {
+				post, _ := repo.GetByID(1)
+				before <- post
+			}()
+			for calls.Load() == 0 {
+				time.Sleep(time.Millisecond)
+			}
+
+			if err := tc.write(repo); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			post, err := repo.GetByID(1)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if post.Title != "After" {
+				t.Errorf("Expected a read after the write to see it, got %+v", post)
+			}
+
+			close(release)
+			if post := <-before; post.Title != "Before" {
+				t.Errorf("Expected the earlier read to finish with its own result, got %+v", post)
+			}
+			if got := calls.Load(); got != 2 {
+				t.Errorf("Expected 2 underlying calls, got %d", got)
+			}
+		})
+	}
+}
+
+func TestCoalescingRepositoryKeepsTransactions(t *testing.T) {
+	if _, ok := NewCoalescingRepository(&MockRepository{}).(Transactor); ok {
+		t.Error("Expected a repository without transactions not to become a Transactor")
+	}
+
+	repo := NewCoalescingRepository(setupTestRepository())
+	transactor, ok := repo.(Transactor)
+	if !ok {
+		t.Fatal("Expected the wrapped MapRepository to remain a Transactor")
+	}
+
+	err := transactor.WithTx(func(tx Repository) error {
+		_, err := tx.Update(1, PostCreateUpdate{Title: "Updated", Content: "Updated Content", Author: "Jane Doe"})
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if post, _ := repo.GetByID(1); post.Title != "Updated" {
+		t.Errorf("Expected the transaction to be committed, got %+v", post)
+	}
+}
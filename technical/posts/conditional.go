@@ -0,0 +1,126 @@
+package posts
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var ErrPreconditionFailed = errors.New("post has been modified since the version the request was based on")
+
+// latestUpdate returns the most recent UpdatedAt among posts.
+func latestUpdate(posts []PostRead) time.Time {
+	var latest time.Time
+	for _, post := range posts {
+		if post.UpdatedAt.After(latest) {
+			latest = post.UpdatedAt
+		}
+	}
+	return latest
+}
+
+// checkNotModified sets Last-Modified and, when If-None-Match or If-Modified-Since
+// shows the client already has this version, writes a 304 and reports true.
+// If-None-Match is compared with etag using the weak comparison and, when both are
+// given, takes precedence; it is ignored when etag is empty. HTTP dates only carry
+// whole seconds, so If-Modified-Since is compared at second granularity. A missing or
+// malformed If-Modified-Since header is ignored.
+func checkNotModified(w http.ResponseWriter, r *http.Request, etag string, lastModified time.Time) bool {
+	if !lastModified.IsZero() {
+		w.Header().Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" && etag != "" {
+		if !etagMatchesWeak(ifNoneMatch, etag) {
+			return false
+		}
+		w.WriteHeader(http.StatusNotModified)
+		return true
+	}
+
+	if lastModified.IsZero() {
+		return false
+	}
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+	if lastModified.Truncate(time.Second).After(since) {
+		return false
+	}
+
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// ifUnmodifiedSince returns the time in the If-Unmodified-Since header. A missing or
+// malformed header reports false and is ignored, as for If-Modified-Since.
+func ifUnmodifiedSince(r *http.Request) (time.Time, bool) {
+	since, err := http.ParseTime(r.Header.Get("If-Unmodified-Since"))
+	if err != nil {
+		return time.Time{}, false
+	}
+	return since, true
+}
+
+// modifiedSince reports whether post was updated after since, at second granularity.
+func modifiedSince(post PostRead, since time.Time) bool {
+	return post.UpdatedAt.Truncate(time.Second).After(since)
+}
+
+// postETag returns a strong entity tag for the stored version of post. Views are left
+// out, so that reading a post does not invalidate the tag an editor holds.
+func postETag(post PostRead) string {
+	data, _ := json.Marshal(struct {
+		ID        int      `json:"id"`
+		Title     string   `json:"title"`
+		Content   string   `json:"content"`
+		Author    string   `json:"author"`
+		Status    string   `json:"status"`
+		Tags      []string `json:"tags"`
+		ImageURL  string   `json:"image_url,omitempty"`
+		UpdatedAt string   `json:"updated_at"`
+	}{post.ID, post.Title, post.Content, post.Author, post.Status, post.Tags, post.ImageURL, post.UpdatedAt.UTC().Format(time.RFC3339Nano)})
+	sum := sha256.Sum256(data)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// weakPostETag returns a weak entity tag for post derived from its ID, update time and
+// content length, which unlike postETag does not hash the content. Every update sets
+// UpdatedAt, so the tag still changes with each stored version, but as a weak tag it
+// only promises an equivalent representation and never satisfies If-Match.
+func weakPostETag(post PostRead) string {
+	return fmt.Sprintf(`W/"%d-%x-%d"`, post.ID, post.UpdatedAt.UnixNano(), len(post.Content))
+}
+
+// etagMatchesWeak reports whether an If-None-Match header value matches etag: it is
+// "*" or a comma-separated list containing etag, with any W/ prefix ignored on either
+// side as the weak comparison requires.
+func etagMatchesWeak(ifNoneMatch, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// etagMatches reports whether an If-Match header value matches etag: it is "*" or a
+// comma-separated list containing etag. If-Match uses the strong comparison, so weak
+// tags never match.
+func etagMatches(ifMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,414 @@
+package posts
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConditionalGet(t *testing.T) {
+	updatedAt := time.Date(2024, 1, 15, 10, 30, 45, 500, time.UTC)
+	post := PostRead{ID: 1, Title: "Test Post 1", Content: "Content 1", Author: "Author 1", UpdatedAt: updatedAt}
+	olderPost := PostRead{ID: 2, Title: "Test Post 2", Content: "Content 2", Author: "Author 2", UpdatedAt: updatedAt.Add(-time.Hour)}
+
+	tests := []struct {
+		name            string
+		ifModifiedSince string
+		expectedStatus  int
+	}{
+		{
+			name:            "Not Modified At Same Second",
+			ifModifiedSince: updatedAt.Format(http.TimeFormat),
+			expectedStatus:  http.StatusNotModified,
+		},
+		{
+			name:            "Not Modified Since Later Time",
+			ifModifiedSince: updatedAt.Add(time.Hour).Format(http.TimeFormat),
+			expectedStatus:  http.StatusNotModified,
+		},
+		{
+			name:            "Modified Since Earlier Time",
+			ifModifiedSince: updatedAt.Add(-time.Second).Format(http.TimeFormat),
+			expectedStatus:  http.StatusOK,
+		},
+		{
+			name:            "Malformed Header Ignored",
+			ifModifiedSince: "yesterday",
+			expectedStatus:  http.StatusOK,
+		},
+		{
+			name:            "No Header",
+			ifModifiedSince: "",
+			expectedStatus:  http.StatusOK,
+		},
+	}
+
+	mockService := &MockService{
+		GetAllPostsFn: func() ([]PostRead, error) {
+			return []PostRead{olderPost, post}, nil
+		},
+		GetPostByIDFn: func(id int) (PostRead, error) {
+			return post, nil
+		},
+	}
+	mux := http.NewServeMux()
+	NewHandler(mockService).RegisterRoutes(mux)
+
+	for _, path := range []string{"/posts", "/posts/1"} {
+		for _, tc := range tests {
+			t.Run(path+" "+tc.name, func(t *testing.T) {
+				req := httptest.NewRequest(http.MethodGet, path, nil)
+				if tc.ifModifiedSince != "" {
+					req.Header.Set("If-Modified-Since", tc.ifModifiedSince)
+				}
+				rr := httptest.NewRecorder()
+
+				mux.ServeHTTP(rr, req)
+
+				if rr.Code != tc.expectedStatus {
+					t.Errorf("Expected status %d, got %d", tc.expectedStatus, rr.Code)
+				}
+				if lastModified := rr.Header().Get("Last-Modified"); lastModified != updatedAt.Format(http.TimeFormat) {
+					t.Errorf("Expected Last-Modified %s, got %s", updatedAt.Format(http.TimeFormat), lastModified)
+				}
+				if tc.expectedStatus == http.StatusNotModified && rr.Body.Len() != 0 {
+					t.Errorf("Expected an empty body, got %q", rr.Body.String())
+				}
+			})
+		}
+	}
+}
+
+func TestIfUnmodifiedSince(t *testing.T) {
+	// setupTestRepository updates post 1 at 00:00 and post 2 at 01:00 on 2024-01-01.
+	before := time.Date(2024, 1, 1, 0, 30, 0, 0, time.UTC).Format(http.TimeFormat)
+	after := time.Date(2024, 1, 1, 2, 0, 0, 0, time.UTC).Format(http.TimeFormat)
+
+	tests := []struct {
+		name               string
+		method             string
+		path               string
+		ifUnmodifiedSince  string
+		expectedStatus     int
+		expectedTitle      string
+		expectedPostExists bool
+	}{
+		{
+			name:               "Update Unmodified",
+			method:             http.MethodPut,
+			path:               "/posts/2",
+			ifUnmodifiedSince:  after,
+			expectedStatus:     http.StatusOK,
+			expectedTitle:      "Updated Title",
+			expectedPostExists: true,
+		},
+		{
+			name:               "Update Modified",
+			method:             http.MethodPut,
+			path:               "/posts/2",
+			ifUnmodifiedSince:  before,
+			expectedStatus:     http.StatusPreconditionFailed,
+			expectedTitle:      "Test Post 2",
+			expectedPostExists: true,
+		},
+		{
+			name:               "Update Malformed Header Ignored",
+			method:             http.MethodPut,
+			path:               "/posts/2",
+			ifUnmodifiedSince:  "yesterday",
+			expectedStatus:     http.StatusOK,
+			expectedTitle:      "Updated Title",
+			expectedPostExists: true,
+		},
+		{
+			name:               "Delete Unmodified",
+			method:             http.MethodDelete,
+			path:               "/posts/2",
+			ifUnmodifiedSince:  after,
+			expectedStatus:     http.StatusNoContent,
+			expectedPostExists: false,
+		},
+		{
+			name:               "Delete Modified",
+			method:             http.MethodDelete,
+			path:               "/posts/2",
+			ifUnmodifiedSince:  before,
+			expectedStatus:     http.StatusPreconditionFailed,
+			expectedTitle:      "Test Post 2",
+			expectedPostExists: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := setupTestRepository()
+			mux := http.NewServeMux()
+			NewHandler(NewPostService(repo)).RegisterRoutes(mux)
+
+			var body io.Reader
+			if tc.method == http.MethodPut {
+				body = strings.NewReader(`{"title": "Updated Title", "content": "Updated Content", "author": "Jane Doe"}`)
+			}
+			req := httptest.NewRequest(tc.method, tc.path, body)
+			req.Header.Set("If-Unmodified-Since", tc.ifUnmodifiedSince)
+			rr := httptest.NewRecorder()
+
+			mux.ServeHTTP(rr, req)
+
+			if rr.Code != tc.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tc.expectedStatus, rr.Code)
+			}
+			post, ok := repo.posts[2]
+			if ok != tc.expectedPostExists {
+				t.Fatalf("Expected post to exist=%v, got %v", tc.expectedPostExists, ok)
+			}
+			if ok && post.Title != tc.expectedTitle {
+				t.Errorf("Expected title %s, got %s", tc.expectedTitle, post.Title)
+			}
+		})
+	}
+}
+
+func TestPatchIfMatch(t *testing.T) {
+	// The test authors contain digits, which a patched post would fail validation on.
+	setup := func() *MapRepository {
+		repo := setupTestRepository()
+		post := repo.posts[2]
+		post.Author = "Jane Doe"
+		repo.posts[2] = post
+		return repo
+	}
+	current := postETag(setup().posts[2])
+	stalePost := setup().posts[2]
+	stalePost.Title = "Earlier Title"
+	stale := postETag(stalePost)
+
+	tests := []struct {
+		name           string
+		ifMatch        string
+		expectedStatus int
+		expectedTitle  string
+	}{
+		{name: "Matching", ifMatch: current, expectedStatus: http.StatusOK, expectedTitle: "Patched Title"},
+		{name: "Matching In List", ifMatch: stale + ", " + current, expectedStatus: http.StatusOK, expectedTitle: "Patched Title"},
+		{name: "Any", ifMatch: "*", expectedStatus: http.StatusOK, expectedTitle: "Patched Title"},
+		{name: "Stale", ifMatch: stale, expectedStatus: http.StatusPreconditionFailed, expectedTitle: "Test Post 2"},
+		{name: "Weak Never Matches", ifMatch: "W/" + current, expectedStatus: http.StatusPreconditionFailed, expectedTitle: "Test Post 2"},
+		{name: "Missing Header Proceeds", ifMatch: "", expectedStatus: http.StatusOK, expectedTitle: "Patched Title"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := setup()
+			mux := http.NewServeMux()
+			NewHandler(NewPostService(repo)).RegisterRoutes(mux)
+
+			req := httptest.NewRequest(http.MethodPatch, "/posts/2", strings.NewReader(`{"title": "Patched Title"}`))
+			if tc.ifMatch != "" {
+				req.Header.Set("If-Match", tc.ifMatch)
+			}
+			rr := httptest.NewRecorder()
+			mux.ServeHTTP(rr, req)
+
+			if rr.Code != tc.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tc.expectedStatus, rr.Code, rr.Body.String())
+			}
+			post := repo.posts[2]
+			if post.Title != tc.expectedTitle {
+				t.Errorf("Expected title %s, got %s", tc.expectedTitle, post.Title)
+			}
+			if post.Content != "Test Content 2" || post.Author != "Jane Doe" {
+				t.Errorf("Expected the other fields to be kept, got %+v", post)
+			}
+			if tc.expectedStatus != http.StatusOK {
+				return
+			}
+
+			etag := rr.Header().Get("ETag")
+			if etag == current {
+				t.Error("Expected the ETag to change with the patch")
+			}
+			if etag != postETag(post) {
+				t.Errorf("Expected the ETag of the stored post %s, got %s", postETag(post), etag)
+			}
+
+			get := httptest.NewRecorder()
+			mux.ServeHTTP(get, httptest.NewRequest(http.MethodGet, "/posts/2", nil))
+			if got := get.Header().Get("ETag"); got != etag {
+				t.Errorf("Expected GET to return ETag %s, got %s", etag, got)
+			}
+		})
+	}
+}
+
+func TestPatchIfMatchNotFound(t *testing.T) {
+	mux := http.NewServeMux()
+	NewHandler(NewPostService(setupTestRepository())).RegisterRoutes(mux)
+
+	req := httptest.NewRequest(http.MethodPatch, "/posts/99", strings.NewReader(`{"title": "Patched Title"}`))
+	req.Header.Set("If-Match", "*")
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d, got %d", http.StatusNotFound, rr.Code)
+	}
+}
+
+func TestETagIgnoresViews(t *testing.T) {
+	post := setupTestRepository().posts[1]
+	viewed := post
+	viewed.Views++
+
+	if postETag(post) != postETag(viewed) {
+		t.Error("Expected views not to change the ETag")
+	}
+	viewed.Content = "Changed"
+	if postETag(post) == postETag(viewed) {
+		t.Error("Expected content to change the ETag")
+	}
+}
+
+func TestWeakPostETag(t *testing.T) {
+	updatedAt := time.Date(2024, 1, 15, 10, 30, 45, 500, time.UTC)
+	post := PostRead{ID: 7, Title: "Title", Content: "Content", Author: "Jane Doe", UpdatedAt: updatedAt}
+
+	expected := fmt.Sprintf(`W/"7-%x-7"`, updatedAt.UnixNano())
+	if etag := weakPostETag(post); etag != expected {
+		t.Errorf("Expected weak ETag %s, got %s", expected, etag)
+	}
+
+	edited := post
+	edited.Content = "Changed"
+	if weakPostETag(post) != weakPostETag(edited) {
+		t.Error("Expected the weak ETag to ignore content of the same length")
+	}
+	edited.UpdatedAt = updatedAt.Add(time.Millisecond)
+	if weakPostETag(post) == weakPostETag(edited) {
+		t.Error("Expected the update time to change the weak ETag")
+	}
+}
+
+func TestIfNoneMatch(t *testing.T) {
+	updatedAt := time.Date(2024, 1, 15, 10, 30, 45, 500, time.UTC)
+	post := PostRead{ID: 1, Title: "Test Post 1", Content: "Content 1", Author: "Author 1", UpdatedAt: updatedAt}
+	weak := weakPostETag(post)
+	strong := postETag(post)
+
+	tests := []struct {
+		name            string
+		weakETags       bool
+		ifNoneMatch     string
+		ifModifiedSince string
+		expectedETag    string
+		expectedStatus  int
+	}{
+		{name: "Weak Match", weakETags: true, ifNoneMatch: weak, expectedETag: weak, expectedStatus: http.StatusNotModified},
+		{name: "Weak Match Without Prefix", weakETags: true, ifNoneMatch: strings.TrimPrefix(weak, "W/"), expectedETag: weak, expectedStatus: http.StatusNotModified},
+		{name: "Weak Match In List", weakETags: true, ifNoneMatch: `"other", ` + weak, expectedETag: weak, expectedStatus: http.StatusNotModified},
+		{name: "Weak Mismatch", weakETags: true, ifNoneMatch: `W/"1-0-9"`, expectedETag: weak, expectedStatus: http.StatusOK},
+		{name: "Wildcard", weakETags: true, ifNoneMatch: "*", expectedETag: weak, expectedStatus: http.StatusNotModified},
+		{name: "Strong Match", ifNoneMatch: strong, expectedETag: strong, expectedStatus: http.StatusNotModified},
+		{name: "Strong Matches Weakly", ifNoneMatch: "W/" + strong, expectedETag: strong, expectedStatus: http.StatusNotModified},
+		{
+			name:            "Mismatch Overrides If-Modified-Since",
+			weakETags:       true,
+			ifNoneMatch:     `W/"1-0-9"`,
+			ifModifiedSince: updatedAt.Add(time.Hour).Format(http.TimeFormat),
+			expectedETag:    weak,
+			expectedStatus:  http.StatusOK,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			NewHandler(&MockService{
+				GetPostByIDFn: func(id int) (PostRead, error) {
+					return post, nil
+				},
+			}, WithWeakETags(tc.weakETags)).RegisterRoutes(mux)
+
+			req := httptest.NewRequest(http.MethodGet, "/posts/1", nil)
+			req.Header.Set("If-None-Match", tc.ifNoneMatch)
+			if tc.ifModifiedSince != "" {
+				req.Header.Set("If-Modified-Since", tc.ifModifiedSince)
+			}
+			rr := httptest.NewRecorder()
+			mux.ServeHTTP(rr, req)
+
+			if rr.Code != tc.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tc.expectedStatus, rr.Code)
+			}
+			if etag := rr.Header().Get("ETag"); etag != tc.expectedETag {
+				t.Errorf("Expected ETag %s, got %s", tc.expectedETag, etag)
+			}
+			if tc.expectedStatus == http.StatusNotModified && rr.Body.Len() != 0 {
+				t.Errorf("Expected an empty body, got %q", rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestWeakETagFailsIfMatch(t *testing.T) {
+	repo := setupTestRepository()
+	mux := http.NewServeMux()
+	NewHandler(NewPostService(repo), WithWeakETags(true)).RegisterRoutes(mux)
+
+	get := httptest.NewRecorder()
+	mux.ServeHTTP(get, httptest.NewRequest(http.MethodGet, "/posts/2", nil))
+	etag := get.Header().Get("ETag")
+	if !strings.HasPrefix(etag, `W/"`) {
+		t.Fatalf("Expected a weak ETag, got %q", etag)
+	}
+
+	req := httptest.NewRequest(http.MethodPatch, "/posts/2", strings.NewReader(`{"title": "Patched Title"}`))
+	req.Header.Set("If-Match", etag)
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusPreconditionFailed {
+		t.Errorf("Expected status %d, got %d", http.StatusPreconditionFailed, rr.Code)
+	}
+	if repo.posts[2].Title == "Patched Title" {
+		t.Error("Expected the post not to be patched")
+	}
+}
+
+func TestWeakETagsKeepIfMatchOnWrites(t *testing.T) {
+	repo := setupTestRepository()
+	mux := http.NewServeMux()
+	NewHandler(NewPostService(repo), WithWeakETags(true)).RegisterRoutes(mux)
+
+	put := httptest.NewRecorder()
+	mux.ServeHTTP(put, httptest.NewRequest(http.MethodPut, "/posts/2", strings.NewReader(`{"title": "Replaced", "content": "Content", "author": "Jane Doe"}`)))
+	if put.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, put.Code)
+	}
+	etag := put.Header().Get("ETag")
+	if etag != postETag(repo.posts[2]) {
+		t.Fatalf("Expected the strong ETag %s, got %q", postETag(repo.posts[2]), etag)
+	}
+
+	for _, title := range []string{"Patched Once", "Patched Twice"} {
+		req := httptest.NewRequest(http.MethodPatch, "/posts/2", strings.NewReader(`{"title": "`+title+`"}`))
+		req.Header.Set("If-Match", etag)
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d", http.StatusOK, rr.Code)
+		}
+		if repo.posts[2].Title != title {
+			t.Errorf("Expected title %s, got %s", title, repo.posts[2].Title)
+		}
+		etag = rr.Header().Get("ETag")
+		if strings.HasPrefix(etag, "W/") {
+			t.Fatalf("Expected a strong ETag, got %q", etag)
+		}
+	}
+}
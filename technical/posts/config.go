@@ -0,0 +1,271 @@
+package posts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	RepositoryKindMap   = "map"
+	RepositoryKindRedis = "redis"
+
+	defaultDataFile       = "blog_data.json"
+	defaultRedisAddr      = "localhost:6379"
+	defaultGRPCAddr       = ":9000"
+	defaultRequestTimeout = 10 * time.Second
+	defaultCacheMaxAge    = time.Minute
+	defaultCORSMaxAge     = 10 * time.Minute
+	defaultSlowQuery      = 100 * time.Millisecond
+	defaultShutdownDrain  = 15 * time.Second
+)
+
+var (
+	ErrUnknownRepositoryKind = errors.New("unknown repository kind")
+	ErrUnknownIDStrategy     = errors.New("unknown ID strategy")
+)
+
+type Config struct {
+	// RepositoryKind selects the storage backend, "map" or "redis".
+	RepositoryKind string
+	// DataFile is the JSON file the map repository is loaded from.
+	DataFile string
+	// WALFile, when set, makes the map repository append every mutation to this file
+	// and replay it on startup.
+	WALFile string
+	// RedisAddr is the host:port of the Redis server used by the redis repository.
+	RedisAddr string
+	// RequestTimeout bounds how long a single request may take before it is answered with 503.
+	RequestTimeout time.Duration
+	// ShutdownTimeout bounds how long shutdown waits for in-flight requests before
+	// closing their connections.
+	ShutdownTimeout time.Duration
+	// MaxPosts caps the number of posts the map repository holds; 0 means unlimited.
+	MaxPosts int
+	// AdminToken is the bearer token the /admin/ endpoints require; they are not
+	// served when it is empty.
+	AdminToken string
+	// GRPCAddr is the address the gRPC server listens on alongside the HTTP server.
+	GRPCAddr string
+	// IDStrategy selects how the map repository picks the IDs of new posts,
+	// "sequential", "random" or "uuid". The redis repository always counts up.
+	IDStrategy string
+	// AutoSaveInterval, when positive, makes the map repository write its state back to
+	// DataFile at most this often; Close writes any remaining changes.
+	AutoSaveInterval time.Duration
+	// CORSAllowedOrigins lists the origins allowed to call the API from a browser;
+	// CORS headers are not sent when it is empty.
+	CORSAllowedOrigins []string
+	// CORSMaxAge is how long browsers may cache a preflight response.
+	CORSMaxAge time.Duration
+	// CORSAllowCredentials lets browsers send cookies and Authorization headers with
+	// cross-origin requests; it requires CORSAllowedOrigins to list origins explicitly.
+	CORSAllowCredentials bool
+	// WeakETags makes GET /posts/{id} send weak ETags derived from a post's ID, update time
+	// and content length instead of strong ETags hashing its content; see WithWeakETags.
+	WeakETags bool
+	// CacheMaxAge is how long clients may cache successful GET responses for posts.
+	CacheMaxAge time.Duration
+	// LogLevel is the minimum level logged: debug, info, warn or error.
+	LogLevel string
+	// LogFormat is the log output format, text or json.
+	LogFormat string
+	// ReadOnly serves reads only: writes over HTTP are answered with 405 and writes over
+	// GraphQL and gRPC fail with ErrReadOnly.
+	ReadOnly bool
+	// StrictQuery answers unknown query parameters on GET /posts with 400 instead of ignoring them.
+	StrictQuery bool
+	// StringIDs encodes post IDs in JSON responses as strings instead of numbers.
+	StringIDs bool
+	// DefaultSort is the order of GET /posts without a sort parameter, in the same form,
+	// e.g. "-createdAt" for newest first.
+	DefaultSort string
+	// FeedTitle is the title of the RSS feed at GET /posts/feed.xml.
+	FeedTitle string
+	// FeedLink is the site URL the feed's post links are built on; the host the feed
+	// is requested from is used when it is empty.
+	FeedLink string
+	// FeedItems is how many of the most recent posts the feed lists.
+	FeedItems int
+	// PageDefaultLimit is the page size of GET /posts when offset is given without limit.
+	PageDefaultLimit int
+	// PageMaxLimit is the largest page size GET /posts serves.
+	PageMaxLimit int
+	// PageLimitReject answers a limit above PageMaxLimit with 400 instead of clamping it.
+	PageLimitReject bool
+	// SlowQueryThreshold is how long a repository call may take before it is logged as
+	// slow; 0 turns the logging off.
+	SlowQueryThreshold time.Duration
+	// RetryAttempts is how many times a repository call failing with a transient error
+	// is made in all; 1 or less turns retrying off.
+	RetryAttempts int
+	// RetryBackoff is the wait before the first retry, doubled for each further one.
+	RetryBackoff time.Duration
+	// CoalesceReads makes concurrent reads of the same post share one repository call.
+	CoalesceReads bool
+	// RejectTitleAsContent fails validation of posts whose content only repeats the title.
+	RejectTitleAsContent bool
+	// KnownAuthors, when set, are the only authors posts may have.
+	KnownAuthors []string
+}
+
+// LoadConfig reads the configuration from the environment, falling back to defaults
+// for unset variables.
+func LoadConfig() Config {
+	return Config{
+		RepositoryKind:       getEnv("REPO_KIND", RepositoryKindMap),
+		DataFile:             getEnv("DATA_FILE", defaultDataFile),
+		WALFile:              getEnv("WAL_FILE", ""),
+		RedisAddr:            getEnv("REDIS_ADDR", defaultRedisAddr),
+		RequestTimeout:       getEnvDuration("REQUEST_TIMEOUT", defaultRequestTimeout),
+		ShutdownTimeout:      getEnvDuration("SHUTDOWN_TIMEOUT", defaultShutdownDrain),
+		MaxPosts:             getEnvInt("MAX_POSTS", 0),
+		AdminToken:           getEnv("ADMIN_TOKEN", ""),
+		GRPCAddr:             getEnv("GRPC_ADDR", defaultGRPCAddr),
+		IDStrategy:           getEnv("ID_STRATEGY", IDStrategySequential),
+		WeakETags:            getEnvBool("WEAK_ETAGS", false),
+		CacheMaxAge:          getEnvDuration("CACHE_MAX_AGE", defaultCacheMaxAge),
+		AutoSaveInterval:     getEnvDuration("AUTOSAVE_INTERVAL", 0),
+		CORSAllowedOrigins:   getEnvList("CORS_ALLOWED_ORIGINS"),
+		CORSMaxAge:           getEnvDuration("CORS_MAX_AGE", defaultCORSMaxAge),
+		CORSAllowCredentials: getEnvBool("CORS_ALLOW_CREDENTIALS", false),
+		LogLevel:             getEnv("LOG_LEVEL", "info"),
+		LogFormat:            getEnv("LOG_FORMAT", LogFormatText),
+		ReadOnly:             getEnvBool("READ_ONLY", false),
+		StrictQuery:          getEnvBool("STRICT_QUERY", false),
+		StringIDs:            getEnvBool("STRING_IDS", false),
+		DefaultSort:          getEnv("DEFAULT_SORT", string(SortByID)),
+		FeedTitle:            getEnv("FEED_TITLE", defaultFeedTitle),
+		FeedLink:             getEnv("FEED_LINK", ""),
+		FeedItems:            getEnvInt("FEED_ITEMS", DefaultFeedItems),
+		PageDefaultLimit:     getEnvInt("PAGE_DEFAULT_LIMIT", DefaultPageLimit),
+		PageMaxLimit:         getEnvInt("PAGE_MAX_LIMIT", MaxPageLimit),
+		PageLimitReject:      getEnvBool("PAGE_LIMIT_REJECT", false),
+		RejectTitleAsContent: getEnvBool("REJECT_TITLE_AS_CONTENT", false),
+		SlowQueryThreshold:   getEnvNonNegativeDuration("SLOW_QUERY_THRESHOLD", defaultSlowQuery),
+		RetryAttempts:        getEnvInt("REPO_RETRY_ATTEMPTS", defaultRetryAttempts),
+		RetryBackoff:         getEnvDuration("REPO_RETRY_BACKOFF", defaultRetryBackoff),
+		CoalesceReads:        getEnvBool("COALESCE_READS", false),
+		KnownAuthors:         getEnvList("KNOWN_AUTHORS"),
+	}
+}
+
+// Pagination returns the page sizes configured for GET /posts.
+func (c Config) Pagination() PaginationConfig {
+	return PaginationConfig{
+		DefaultLimit:  c.PageDefaultLimit,
+		MaxLimit:      c.PageMaxLimit,
+		RejectOverMax: c.PageLimitReject,
+	}
+}
+
+// Feed returns the RSS feed configured for GET /posts/feed.xml.
+func (c Config) Feed() FeedConfig {
+	return FeedConfig{
+		Title: c.FeedTitle,
+		Link:  c.FeedLink,
+		Items: c.FeedItems,
+	}
+}
+
+// RetryPolicy returns the retries configured for repository calls.
+func (c Config) RetryPolicy() RetryPolicy {
+	policy := DefaultRetryPolicy()
+	policy.MaxAttempts = c.RetryAttempts
+	policy.InitialBackoff = c.RetryBackoff
+	return policy
+}
+
+// NewRepository constructs the repository selected by cfg.RepositoryKind.
+func NewRepository(cfg Config) (Repository, error) {
+	switch cfg.RepositoryKind {
+	case RepositoryKindMap:
+		ids, err := NewIDGenerator(cfg.IDStrategy)
+		if err != nil {
+			return nil, err
+		}
+		var repo *MapRepository
+		if cfg.WALFile != "" {
+			repo, err = OpenMapRepository(cfg.DataFile, cfg.WALFile, cfg.MaxPosts)
+		} else {
+			repo, err = LoadMapRepository(cfg.DataFile, cfg.MaxPosts)
+		}
+		if err != nil {
+			return nil, err
+		}
+		repo.SetIDGenerator(ids)
+		if cfg.AutoSaveInterval > 0 {
+			repo.StartAutoSave(cfg.AutoSaveInterval)
+		}
+		return repo, nil
+	case RepositoryKindRedis:
+		client := redis.NewClient(&redis.Options{Addr: cfg.RedisAddr})
+		if err := client.Ping(context.Background()).Err(); err != nil {
+			client.Close()
+			return nil, fmt.Errorf("connecting to redis at %s: %w", cfg.RedisAddr, err)
+		}
+		return NewRedisRepository(client), nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownRepositoryKind, cfg.RepositoryKind)
+	}
+}
+
+func getEnv(key, fallback string) string {
+	if value, ok := os.LookupEnv(key); ok && value != "" {
+		return value
+	}
+	return fallback
+}
+
+// getEnvDuration parses key as a time.Duration such as "5s", falling back on unset or invalid values.
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	value, err := time.ParseDuration(getEnv(key, ""))
+	if err != nil || value <= 0 {
+		return fallback
+	}
+	return value
+}
+
+// getEnvNonNegativeDuration is getEnvDuration for settings that an explicit "0" turns
+// off: it accepts zero and only falls back on unset, invalid or negative values.
+func getEnvNonNegativeDuration(key string, fallback time.Duration) time.Duration {
+	value, err := time.ParseDuration(getEnv(key, ""))
+	if err != nil || value < 0 {
+		return fallback
+	}
+	return value
+}
+
+// getEnvInt parses key as a non-negative integer, falling back on unset or invalid values.
+func getEnvInt(key string, fallback int) int {
+	value, err := strconv.Atoi(getEnv(key, ""))
+	if err != nil || value < 0 {
+		return fallback
+	}
+	return value
+}
+
+// getEnvBool parses key as a boolean such as "true" or "1", falling back on unset or invalid values.
+func getEnvBool(key string, fallback bool) bool {
+	value, err := strconv.ParseBool(getEnv(key, ""))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+// getEnvList splits key on commas, dropping blank entries; it returns nil when unset.
+func getEnvList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(getEnv(key, ""), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
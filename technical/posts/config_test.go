@@ -0,0 +1,288 @@
+package posts
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestLoadConfig(t *testing.T) {
+	t.Setenv("REPO_KIND", "")
+	t.Setenv("DATA_FILE", "")
+	t.Setenv("REQUEST_TIMEOUT", "")
+	t.Setenv("SHUTDOWN_TIMEOUT", "")
+	t.Setenv("GRPC_ADDR", "")
+	t.Setenv("MAX_POSTS", "")
+	t.Setenv("ID_STRATEGY", "")
+	t.Setenv("CACHE_MAX_AGE", "")
+	t.Setenv("WEAK_ETAGS", "")
+	t.Setenv("CORS_ALLOWED_ORIGINS", "")
+	t.Setenv("CORS_MAX_AGE", "")
+	t.Setenv("CORS_ALLOW_CREDENTIALS", "")
+	t.Setenv("LOG_LEVEL", "")
+	t.Setenv("LOG_FORMAT", "")
+	t.Setenv("PAGE_DEFAULT_LIMIT", "")
+	t.Setenv("PAGE_MAX_LIMIT", "")
+	t.Setenv("PAGE_LIMIT_REJECT", "")
+	t.Setenv("READ_ONLY", "")
+	t.Setenv("STRICT_QUERY", "")
+	t.Setenv("STRING_IDS", "")
+	t.Setenv("REJECT_TITLE_AS_CONTENT", "")
+	t.Setenv("KNOWN_AUTHORS", "")
+	t.Setenv("SLOW_QUERY_THRESHOLD", "")
+	t.Setenv("REPO_RETRY_ATTEMPTS", "")
+	t.Setenv("REPO_RETRY_BACKOFF", "")
+	t.Setenv("COALESCE_READS", "")
+	t.Setenv("DEFAULT_SORT", "")
+	t.Setenv("FEED_TITLE", "")
+	t.Setenv("FEED_LINK", "")
+	t.Setenv("FEED_ITEMS", "")
+
+	cfg := LoadConfig()
+	if cfg.RepositoryKind != RepositoryKindMap {
+		t.Errorf("Expected default repository kind %q, got %q", RepositoryKindMap, cfg.RepositoryKind)
+	}
+	if cfg.DataFile != "blog_data.json" {
+		t.Errorf("Expected default data file blog_data.json, got %q", cfg.DataFile)
+	}
+	if cfg.RequestTimeout != 10*time.Second {
+		t.Errorf("Expected default request timeout 10s, got %v", cfg.RequestTimeout)
+	}
+	if cfg.ShutdownTimeout != 15*time.Second {
+		t.Errorf("Expected default shutdown timeout 15s, got %v", cfg.ShutdownTimeout)
+	}
+	if cfg.MaxPosts != 0 {
+		t.Errorf("Expected unlimited posts by default, got %d", cfg.MaxPosts)
+	}
+	if cfg.GRPCAddr != ":9000" {
+		t.Errorf("Expected default gRPC address :9000, got %q", cfg.GRPCAddr)
+	}
+	if cfg.IDStrategy != IDStrategySequential {
+		t.Errorf("Expected default ID strategy %q, got %q", IDStrategySequential, cfg.IDStrategy)
+	}
+	if cfg.CacheMaxAge != time.Minute {
+		t.Errorf("Expected default cache max-age 1m, got %v", cfg.CacheMaxAge)
+	}
+	if cfg.WeakETags {
+		t.Error("Expected strong ETags by default")
+	}
+	if cfg.CORSAllowedOrigins != nil {
+		t.Errorf("Expected no CORS origins by default, got %v", cfg.CORSAllowedOrigins)
+	}
+	if cfg.CORSMaxAge != 10*time.Minute {
+		t.Errorf("Expected default CORS max-age 10m, got %v", cfg.CORSMaxAge)
+	}
+	if cfg.CORSAllowCredentials {
+		t.Error("Expected CORS credentials to be disallowed by default")
+	}
+	if cfg.LogLevel != "info" || cfg.LogFormat != LogFormatText {
+		t.Errorf("Expected info level text logs by default, got %s level %s logs", cfg.LogLevel, cfg.LogFormat)
+	}
+
+	if cfg.ReadOnly || cfg.StrictQuery {
+		t.Error("Expected writes and unknown query parameters to be allowed by default")
+	}
+	if cfg.StringIDs {
+		t.Error("Expected numeric IDs by default")
+	}
+	if cfg.SlowQueryThreshold != 100*time.Millisecond {
+		t.Errorf("Expected default slow query threshold 100ms, got %v", cfg.SlowQueryThreshold)
+	}
+	if cfg.RetryAttempts != 3 || cfg.RetryBackoff != 50*time.Millisecond {
+		t.Errorf("Expected 3 retry attempts 50ms apart by default, got %d and %v", cfg.RetryAttempts, cfg.RetryBackoff)
+	}
+	if cfg.CoalesceReads {
+		t.Error("Expected reads not to be coalesced by default")
+	}
+	if cfg.DefaultSort != "id" {
+		t.Errorf("Expected default sort id, got %q", cfg.DefaultSort)
+	}
+	if cfg.Feed() != DefaultFeedConfig() {
+		t.Errorf("Expected default feed %+v, got %+v", DefaultFeedConfig(), cfg.Feed())
+	}
+	if cfg.KnownAuthors != nil {
+		t.Errorf("Expected any author to be allowed by default, got %v", cfg.KnownAuthors)
+	}
+	if cfg.RejectTitleAsContent {
+		t.Error("Expected content repeating the title to be allowed by default")
+	}
+	if cfg.Pagination() != DefaultPaginationConfig() {
+		t.Errorf("Expected default pagination %+v, got %+v", DefaultPaginationConfig(), cfg.Pagination())
+	}
+
+	t.Setenv("REPO_KIND", "postgres")
+	t.Setenv("DATA_FILE", "/data/posts.json")
+	t.Setenv("REQUEST_TIMEOUT", "250ms")
+	t.Setenv("SHUTDOWN_TIMEOUT", "1m")
+	t.Setenv("MAX_POSTS", "500")
+	t.Setenv("CACHE_MAX_AGE", "5m")
+	t.Setenv("WEAK_ETAGS", "true")
+	t.Setenv("CORS_ALLOWED_ORIGINS", "https://a.example.com, ,https://b.example.com")
+	t.Setenv("CORS_ALLOW_CREDENTIALS", "true")
+	t.Setenv("PAGE_DEFAULT_LIMIT", "10")
+	t.Setenv("PAGE_MAX_LIMIT", "50")
+	t.Setenv("PAGE_LIMIT_REJECT", "true")
+	t.Setenv("READ_ONLY", "1")
+	t.Setenv("STRING_IDS", "true")
+	t.Setenv("REJECT_TITLE_AS_CONTENT", "true")
+	t.Setenv("KNOWN_AUTHORS", "Jane Doe, John Smith")
+	t.Setenv("SLOW_QUERY_THRESHOLD", "1s")
+	t.Setenv("REPO_RETRY_ATTEMPTS", "5")
+	t.Setenv("REPO_RETRY_BACKOFF", "10ms")
+	t.Setenv("COALESCE_READS", "true")
+	t.Setenv("DEFAULT_SORT", "-createdAt")
+	t.Setenv("FEED_TITLE", "Rakia")
+	t.Setenv("FEED_LINK", "https://blog.example.com")
+	t.Setenv("FEED_ITEMS", "5")
+
+	cfg = LoadConfig()
+	if cfg.RepositoryKind != "postgres" {
+		t.Errorf("Expected repository kind postgres, got %q", cfg.RepositoryKind)
+	}
+	if cfg.DataFile != "/data/posts.json" {
+		t.Errorf("Expected data file /data/posts.json, got %q", cfg.DataFile)
+	}
+	if cfg.RequestTimeout != 250*time.Millisecond {
+		t.Errorf("Expected request timeout 250ms, got %v", cfg.RequestTimeout)
+	}
+	if cfg.ShutdownTimeout != time.Minute {
+		t.Errorf("Expected shutdown timeout 1m, got %v", cfg.ShutdownTimeout)
+	}
+	if cfg.MaxPosts != 500 {
+		t.Errorf("Expected max posts 500, got %d", cfg.MaxPosts)
+	}
+	if cfg.CacheMaxAge != 5*time.Minute {
+		t.Errorf("Expected cache max-age 5m, got %v", cfg.CacheMaxAge)
+	}
+	if !cfg.WeakETags {
+		t.Error("Expected WEAK_ETAGS=true to send weak ETags")
+	}
+	if !slices.Equal(cfg.CORSAllowedOrigins, []string{"https://a.example.com", "https://b.example.com"}) {
+		t.Errorf("Expected two CORS origins, got %v", cfg.CORSAllowedOrigins)
+	}
+	if !cfg.CORSAllowCredentials {
+		t.Error("Expected CORS_ALLOW_CREDENTIALS=true to allow CORS credentials")
+	}
+	if !cfg.ReadOnly {
+		t.Error("Expected READ_ONLY=1 to enable read-only mode")
+	}
+	if !cfg.StringIDs {
+		t.Error("Expected STRING_IDS=true to encode IDs as strings")
+	}
+	if cfg.SlowQueryThreshold != time.Second {
+		t.Errorf("Expected slow query threshold 1s, got %v", cfg.SlowQueryThreshold)
+	}
+	if policy := cfg.RetryPolicy(); policy.MaxAttempts != 5 || policy.InitialBackoff != 10*time.Millisecond {
+		t.Errorf("Expected 5 retry attempts starting 10ms apart, got %+v", policy)
+	}
+	if cfg.DefaultSort != "-createdAt" {
+		t.Errorf("Expected default sort -createdAt, got %q", cfg.DefaultSort)
+	}
+	if !cfg.CoalesceReads {
+		t.Error("Expected COALESCE_READS=true to coalesce reads")
+	}
+	if expected := (FeedConfig{Title: "Rakia", Link: "https://blog.example.com", Items: 5}); cfg.Feed() != expected {
+		t.Errorf("Expected feed %+v, got %+v", expected, cfg.Feed())
+	}
+	if !cfg.RejectTitleAsContent {
+		t.Error("Expected REJECT_TITLE_AS_CONTENT=true to reject content repeating the title")
+	}
+	if !slices.Equal(cfg.KnownAuthors, []string{"Jane Doe", "John Smith"}) {
+		t.Errorf("Expected two known authors, got %v", cfg.KnownAuthors)
+	}
+	if expected := (PaginationConfig{DefaultLimit: 10, MaxLimit: 50, RejectOverMax: true}); cfg.Pagination() != expected {
+		t.Errorf("Expected pagination %+v, got %+v", expected, cfg.Pagination())
+	}
+
+	t.Setenv("REQUEST_TIMEOUT", "soon")
+
+	cfg = LoadConfig()
+	if cfg.RequestTimeout != 10*time.Second {
+		t.Errorf("Expected invalid request timeout to fall back to 10s, got %v", cfg.RequestTimeout)
+	}
+
+	t.Setenv("SLOW_QUERY_THRESHOLD", "0")
+
+	cfg = LoadConfig()
+	if cfg.SlowQueryThreshold != 0 {
+		t.Errorf("Expected SLOW_QUERY_THRESHOLD=0 to turn slow query logging off, got %v", cfg.SlowQueryThreshold)
+	}
+
+	t.Setenv("SLOW_QUERY_THRESHOLD", "-1s")
+
+	cfg = LoadConfig()
+	if cfg.SlowQueryThreshold != 100*time.Millisecond {
+		t.Errorf("Expected negative slow query threshold to fall back to 100ms, got %v", cfg.SlowQueryThreshold)
+	}
+}
+
+func TestNewRepository(t *testing.T) {
+	dataFile := filepath.Join(t.TempDir(), "blog_data.json")
+	content := `{"posts": [{"id": 4, "title": "Title", "content": "Content", "author": "Author"}]}`
+	if err := os.WriteFile(dataFile, []byte(content), 0o644); err != nil {
+		t.Fatalf("Failed to write data file: %v", err)
+	}
+
+	t.Run("Map Kind", func(t *testing.T) {
+		repo, err := NewRepository(Config{RepositoryKind: RepositoryKindMap, DataFile: dataFile})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		post, err := repo.GetByID(4)
+		if err != nil {
+			t.Fatalf("Expected post 4 to be loaded, got %v", err)
+		}
+		if post.Title != "Title" {
+			t.Errorf("Expected title Title, got %s", post.Title)
+		}
+
+		created, _ := repo.Create(PostCreateUpdate{Title: "New", Content: "New", Author: "New"})
+		if created.ID != 5 {
+			t.Errorf("Expected next ID 5, got %d", created.ID)
+		}
+	})
+
+	t.Run("Random IDs", func(t *testing.T) {
+		repo, err := NewRepository(Config{RepositoryKind: RepositoryKindMap, DataFile: dataFile, IDStrategy: IDStrategyRandom})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		created, _ := repo.Create(PostCreateUpdate{Title: "New", Content: "New", Author: "New"})
+		if _, err := repo.GetByID(created.ID); err != nil {
+			t.Errorf("Expected post %d to be retrievable, got %v", created.ID, err)
+		}
+	})
+
+	t.Run("Unknown ID Strategy", func(t *testing.T) {
+		_, err := NewRepository(Config{RepositoryKind: RepositoryKindMap, DataFile: dataFile, IDStrategy: "snowflake"})
+		if !errors.Is(err, ErrUnknownIDStrategy) {
+			t.Errorf("Expected ErrUnknownIDStrategy, got %v", err)
+		}
+	})
+
+	t.Run("Missing Data File", func(t *testing.T) {
+		_, err := NewRepository(Config{RepositoryKind: RepositoryKindMap, DataFile: filepath.Join(t.TempDir(), "missing.json")})
+		if err == nil {
+			t.Error("Expected an error for a missing data file")
+		}
+	})
+
+	t.Run("Unreachable Redis", func(t *testing.T) {
+		_, err := NewRepository(Config{RepositoryKind: RepositoryKindRedis, RedisAddr: "127.0.0.1:1"})
+		if err == nil {
+			t.Error("Expected an error for an unreachable Redis server")
+		}
+	})
+
+	t.Run("Unknown Kind", func(t *testing.T) {
+		_, err := NewRepository(Config{RepositoryKind: "cassandra"})
+		if !errors.Is(err, ErrUnknownRepositoryKind) {
+			t.Errorf("Expected ErrUnknownRepositoryKind, got %v", err)
+		}
+	})
+}
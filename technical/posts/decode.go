@@ -0,0 +1,70 @@
+package posts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+var (
+	// ErrInvalidBody is matched by every error decodeJSON returns for a body that is
+	// present but cannot be decoded.
+	ErrInvalidBody = errors.New("Invalid request body")
+	// ErrEmptyBody is returned by decodeJSON for a request without a body.
+	ErrEmptyBody = errors.New("Request body is required")
+
+	errMalformedBody = invalidBody("Request body is malformed or truncated JSON")
+)
+
+// invalidBodyError describes what is wrong with a request body for the client while
+// still matching ErrInvalidBody.
+type invalidBodyError struct {
+	message string
+}
+
+func invalidBody(format string, args ...interface{}) error {
+	return &invalidBodyError{message: fmt.Sprintf(format, args...)}
+}
+
+func (e *invalidBodyError) Error() string {
+	return e.message
+}
+
+func (e *invalidBodyError) Unwrap() error {
+	return ErrInvalidBody
+}
+
+// decodeJSON decodes the request body into v. Its errors are meant for the client:
+// they tell an empty body, truncated JSON, a syntax error and a field of the wrong
+// type apart, naming the offset or field at fault. All but ErrEmptyBody match ErrInvalidBody.
+func decodeJSON(r *http.Request, v interface{}) error {
+	err := json.NewDecoder(r.Body).Decode(v)
+	if err == nil {
+		return nil
+	}
+
+	var syntaxError *json.SyntaxError
+	var typeError *json.UnmarshalTypeError
+	switch {
+	case errors.Is(err, io.EOF):
+		return ErrEmptyBody
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return errMalformedBody
+	case errors.As(err, &syntaxError):
+		return invalidBody("Request body contains invalid JSON at offset %d", syntaxError.Offset)
+	case errors.As(err, &typeError) && typeError.Field != "":
+		return invalidBody("Field %q must be of type %s, got %s", typeError.Field, typeError.Type, typeError.Value)
+	case errors.As(err, &typeError):
+		return invalidBody("Request body must be a JSON object, got %s", typeError.Value)
+	default:
+		return ErrInvalidBody
+	}
+}
+
+// respondWithBodyError answers a request whose body decodeJSON rejected with 400 and
+// the reason as a JSON error.
+func respondWithBodyError(w http.ResponseWriter, r *http.Request, err error) {
+	respondWithJSON(w, r, http.StatusBadRequest, errorResponse{Error: err.Error()})
+}
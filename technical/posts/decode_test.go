@@ -0,0 +1,95 @@
+package posts
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDecodeJSON(t *testing.T) {
+	tests := []struct {
+		name             string
+		body             string
+		expectedError    string
+		expectedSentinel error
+	}{
+		{
+			name: "Valid Body",
+			body: `{"title": "Title", "content": "Content", "author": "Author"}`,
+		},
+		{
+			name:             "Empty Body",
+			body:             "",
+			expectedError:    "Request body is required",
+			expectedSentinel: ErrEmptyBody,
+		},
+		{
+			name:             "Truncated Body",
+			body:             `{"title": "Title", "content": `,
+			expectedError:    "Request body is malformed or truncated JSON",
+			expectedSentinel: ErrInvalidBody,
+		},
+		{
+			name:             "Syntax Error",
+			body:             `{"title": "Title",, "content": "Content"}`,
+			expectedError:    "Request body contains invalid JSON at offset 19",
+			expectedSentinel: ErrInvalidBody,
+		},
+		{
+			name:             "Field Type Mismatch",
+			body:             `{"title": 42, "content": "Content", "author": "Author"}`,
+			expectedError:    `Field "title" must be of type string, got number`,
+			expectedSentinel: ErrInvalidBody,
+		},
+		{
+			name:             "Not An Object",
+			body:             `"invalid json"`,
+			expectedError:    "Request body must be a JSON object, got string",
+			expectedSentinel: ErrInvalidBody,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/posts", strings.NewReader(tc.body))
+
+			var data PostCreateUpdate
+			err := decodeJSON(req, &data)
+
+			if tc.expectedError == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tc.expectedError {
+				t.Errorf("Expected error %q, got %v", tc.expectedError, err)
+			}
+			if !errors.Is(err, tc.expectedSentinel) {
+				t.Errorf("Expected error to match %v, got %v", tc.expectedSentinel, err)
+			}
+		})
+	}
+}
+
+func TestCreatePostReportsDecodeError(t *testing.T) {
+	handler := NewHandler(&MockService{})
+
+	req := httptest.NewRequest(http.MethodPost, "/posts", strings.NewReader(""))
+	rr := httptest.NewRecorder()
+	handler.CreatePost(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, rr.Code)
+	}
+	var body errorResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Expected a JSON error, got %q", rr.Body.String())
+	}
+	if body.Error != ErrEmptyBody.Error() {
+		t.Errorf("Expected error %q, got %q", ErrEmptyBody.Error(), body.Error)
+	}
+}
@@ -1,28 +1,176 @@
 package posts
 
 import (
-	"github.com/go-playground/validator/v10"
+	"time"
 )
 
 type PostRead struct {
-	ID      int    `json:"id"`
-	Title   string `json:"title"`
-	Content string `json:"content"`
-	Author  string `json:"author"`
+	ID          int       `json:"id"`
+	UID         string    `json:"uid,omitempty"`
+	Title       string    `json:"title"`
+	Content     string    `json:"content"`
+	Author      string    `json:"author"`
+	Status      string    `json:"status"`
+	Views       int       `json:"views"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	Tags        []string  `json:"tags,omitempty"`
+	ImageURL    string    `json:"image_url,omitempty"`
+	ContentHTML string    `json:"content_html,omitempty"`
+	Truncated   bool      `json:"truncated,omitempty"`
+	WordCount   *int      `json:"word_count,omitempty"`
+	CharCount   *int      `json:"char_count,omitempty"`
+}
+
+type PostViews struct {
+	ID    int `json:"id"`
+	Views int `json:"views"`
+}
+
+type AuthorStats struct {
+	Author string `json:"author"`
+	Count  int    `json:"count"`
+}
+
+// PostStats counts the posts of the authors it lists. With ?prefix= only matching
+// authors and their posts are counted; with limit or offset Authors holds one page of
+// them, AuthorCount says how many there are in all and Links navigates the pages.
+type PostStats struct {
+	Total       int           `json:"total"`
+	Authors     []AuthorStats `json:"authors"`
+	AuthorCount int           `json:"author_count,omitempty"`
+	Links       *PageLinks    `json:"links,omitempty"`
+}
+
+// AuthorPage is the /authors response when limit or offset is given.
+type AuthorPage struct {
+	Data  []string  `json:"data"`
+	Total int       `json:"total"`
+	Links PageLinks `json:"links"`
 }
 
+// PostNeighbors holds the posts before and after a post in ID order; either is null
+// at the ends.
+type PostNeighbors struct {
+	Prev *PostRead `json:"prev"`
+	Next *PostRead `json:"next"`
+}
+
+type BulkDeleteRequest struct {
+	IDs []int `json:"ids"`
+}
+
+// ValidationResult is the response of a dry-run validation that passed.
+type ValidationResult struct {
+	Valid bool `json:"valid"`
+}
+
+// MaintenanceStatus reports or sets the maintenance level: off, read-only or closed.
+type MaintenanceStatus struct {
+	Level string `json:"level" enums:"off,read-only,closed"`
+}
+
+// PostList wraps a collection as {"posts": [...]}, the shape of the storage file, for
+// clients that ask for an envelope instead of a bare array.
+type PostList struct {
+	Posts interface{} `json:"posts" swaggertype:"array,object"`
+}
+
+// BatchGetRequest is the body of POST /posts/batch-get.
+type BatchGetRequest struct {
+	IDs []int `json:"ids"`
+}
+
+type BulkDeleteResult struct {
+	Deleted  []int `json:"deleted"`
+	NotFound []int `json:"not_found"`
+}
+
+// BulkPatchRequest is the body of PATCH /posts: the same patch is applied to every listed post.
+type BulkPatchRequest struct {
+	IDs   []int     `json:"ids"`
+	Patch PostPatch `json:"patch"`
+}
+
+type BulkPatchResult struct {
+	Updated  []PostRead `json:"updated"`
+	NotFound []int      `json:"not_found"`
+}
+
+type ImportRowError struct {
+	Line  int    `json:"line"`
+	Error string `json:"error"`
+}
+
+type ImportResult struct {
+	CreatedIDs []int            `json:"created_ids"`
+	Errors     []ImportRowError `json:"errors"`
+}
+
+const (
+	StatusDraft     = "draft"
+	StatusPublished = "published"
+)
+
+// PostCreateUpdate carries no rule for Content: whether it may be empty depends on
+// the status, which validatePostContent checks.
 type PostCreateUpdate struct {
 	Title   string `json:"title" validate:"required"`
-	Content string `json:"content" validate:"required"`
-	Author  string `json:"author" validate:"required"`
+	Content string `json:"content"`
+	Author  string `json:"author" validate:"required,author,known_author"`
+	Status  string `json:"status,omitempty" validate:"omitempty,oneof=draft published" enums:"draft,published"`
+	// Tags are lowercased and de-duplicated before they are validated.
+	Tags []string `json:"tags,omitempty" validate:"tag_count,dive,tag"`
+	// ImageURL is the address of the post's cover image, if it has one.
+	ImageURL string `json:"image_url,omitempty" validate:"omitempty,http_url"`
 }
 
-var validate *validator.Validate
+// PostPatch is the body of PATCH /posts/{id}: fields that are absent or null keep
+// their current values.
+type PostPatch struct {
+	Title    *string   `json:"title,omitempty"`
+	Content  *string   `json:"content,omitempty"`
+	Author   *string   `json:"author,omitempty"`
+	Status   *string   `json:"status,omitempty" enums:"draft,published"`
+	Tags     *[]string `json:"tags,omitempty"`
+	ImageURL *string   `json:"image_url,omitempty"`
+}
 
-func init() {
-	validate = validator.New()
+// apply returns the full update that sets the patched fields of post.
+func (p PostPatch) apply(post PostRead) PostCreateUpdate {
+	data := PostCreateUpdate{
+		Title:    post.Title,
+		Content:  post.Content,
+		Author:   post.Author,
+		Status:   post.Status,
+		Tags:     post.Tags,
+		ImageURL: post.ImageURL,
+	}
+	if p.Title != nil {
+		data.Title = *p.Title
+	}
+	if p.Content != nil {
+		data.Content = *p.Content
+	}
+	if p.Author != nil {
+		data.Author = *p.Author
+	}
+	if p.Status != nil {
+		data.Status = *p.Status
+	}
+	if p.Tags != nil {
+		data.Tags = *p.Tags
+	}
+	if p.ImageURL != nil {
+		data.ImageURL = *p.ImageURL
+	}
+	return data
 }
 
-func (d *PostCreateUpdate) Validate() error {
-	return validate.Struct(d)
+// PostStatus returns the requested status, defaulting to published.
+func (d *PostCreateUpdate) PostStatus() string {
+	if d.Status == "" {
+		return StatusPublished
+	}
+	return d.Status
 }
@@ -0,0 +1,29 @@
+package posts
+
+const (
+	EventPostCreated = "post.created"
+	EventPostUpdated = "post.updated"
+	EventPostDeleted = "post.deleted"
+)
+
+// PostEvent describes a stored change to a post. Post is the post as stored after the
+// change and is omitted for deletions.
+type PostEvent struct {
+	Type string    `json:"type"`
+	ID   int       `json:"id"`
+	Post *PostRead `json:"post,omitempty"`
+}
+
+// EventPublisher is notified by the service after each post is created, updated or
+// deleted. Publish is called on the request path and must not block.
+type EventPublisher interface {
+	Publish(event PostEvent)
+}
+
+func postChanged(eventType string, post PostRead) PostEvent {
+	return PostEvent{Type: eventType, ID: post.ID, Post: &post}
+}
+
+func postDeleted(id int) PostEvent {
+	return PostEvent{Type: EventPostDeleted, ID: id}
+}
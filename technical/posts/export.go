@@ -0,0 +1,137 @@
+package posts
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+)
+
+var csvHeader = []string{"id", "title", "content", "author"}
+
+// ndjsonFlushInterval is how many posts an NDJSON export writes between flushes.
+const ndjsonFlushInterval = 500
+
+// ExportPosts handles GET /posts/export
+// @Summary Export all posts
+// @Description Download all blog posts as a CSV attachment, a JSON array or NDJSON streamed one post per line
+// @Tags posts
+// @Produce text/csv
+// @Produce json
+// @Produce application/x-ndjson
+// @Param format query string false "Export format" Enums(csv, json, ndjson) default(csv)
+// @Success 200 {array} PostRead
+// @Failure 400 {object} string "Unsupported export format"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /posts/export [get]
+func (h *Handler) ExportPosts(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "csv"
+	}
+	switch format {
+	case "csv", "json":
+	case "ndjson":
+		h.exportNDJSON(w, r)
+		return
+	default:
+		http.Error(w, "Unsupported export format", http.StatusBadRequest)
+		return
+	}
+
+	posts, err := h.service.GetAllPosts(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	slices.SortFunc(posts, func(a, b PostRead) int {
+		return a.ID - b.ID
+	})
+
+	if format == "json" {
+		respondWithJSON(w, r, http.StatusOK, emptyIfNil(posts))
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", `attachment; filename="posts.csv"`)
+	w.WriteHeader(http.StatusOK)
+
+	// Rows go straight to the ResponseWriter; csv.Writer only buffers up to its flush.
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return
+	}
+	for _, post := range posts {
+		record := []string{strconv.Itoa(post.ID), post.Title, post.Content, post.Author}
+		if err := writer.Write(record); err != nil {
+			return
+		}
+	}
+	writer.Flush()
+}
+
+// exportNDJSON writes one JSON object per line as the posts are iterated in ID order,
+// flushing every ndjsonFlushInterval posts, so the whole set is never held in memory.
+func (h *Handler) exportNDJSON(w http.ResponseWriter, r *http.Request) {
+	controller := http.NewResponseController(w)
+	// flush pushes out what has been written. A writer that cannot flush, such as one
+	// buffering the response, makes the export be held in memory; that is logged once.
+	canFlush := true
+	flush := func() error {
+		if !canFlush {
+			return nil
+		}
+		err := controller.Flush()
+		if errors.Is(err, http.ErrNotSupported) {
+			canFlush = false
+			h.logger.Warn("export cannot be flushed and is buffered", "request_id", RequestIDFromContext(r.Context()))
+			return nil
+		}
+		return err
+	}
+	encoder := json.NewEncoder(w)
+	started := false
+	start := func() {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.Header().Set("Content-Disposition", `attachment; filename="posts.ndjson"`)
+		w.WriteHeader(http.StatusOK)
+		started = true
+	}
+
+	written := 0
+	err := h.service.IteratePosts(r.Context(), func(post PostRead) error {
+		if !started {
+			start()
+		}
+		var line interface{} = post
+		if wantsStringIDs(r) {
+			line = newStringIDPost(post)
+		}
+		if err := encoder.Encode(line); err != nil {
+			return err
+		}
+		written++
+		if written%ndjsonFlushInterval == 0 {
+			return flush()
+		}
+		return nil
+	})
+	if err != nil {
+		// Once the first post is out the status is sent, and the client sees a short body.
+		if !started {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		h.logger.Error("export interrupted", "request_id", RequestIDFromContext(r.Context()), "written", written, "error", err)
+		return
+	}
+	if !started {
+		start()
+	}
+	if err := flush(); err != nil {
+		h.logger.Error("export interrupted", "request_id", RequestIDFromContext(r.Context()), "written", written, "error", err)
+	}
+}
@@ -0,0 +1,222 @@
+package posts
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestExportPostsCSV(t *testing.T) {
+	mux := http.NewServeMux()
+	NewHandler(NewPostService(setupTestRepository())).RegisterRoutes(mux)
+
+	req := httptest.NewRequest(http.MethodGet, "/posts/export?format=csv", nil)
+	rr := httptest.NewRecorder()
+
+	mux.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+	if contentType := rr.Header().Get("Content-Type"); contentType != "text/csv" {
+		t.Errorf("Expected Content-Type text/csv, got %s", contentType)
+	}
+	if disposition := rr.Header().Get("Content-Disposition"); !strings.HasPrefix(disposition, "attachment;") {
+		t.Errorf("Expected an attachment Content-Disposition, got %s", disposition)
+	}
+
+	records, err := csv.NewReader(rr.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to parse CSV: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("Expected 3 rows, got %d", len(records))
+	}
+	if !slices.Equal(records[0], []string{"id", "title", "content", "author"}) {
+		t.Errorf("Unexpected header row: %v", records[0])
+	}
+	if !slices.Equal(records[1], []string{"1", "Test Post 1", "Test Content 1", "Test Author 1"}) {
+		t.Errorf("Unexpected data row: %v", records[1])
+	}
+}
+
+func TestExportPostsJSON(t *testing.T) {
+	mux := http.NewServeMux()
+	NewHandler(NewPostService(setupTestRepository())).RegisterRoutes(mux)
+
+	req := httptest.NewRequest(http.MethodGet, "/posts/export?format=json", nil)
+	rr := httptest.NewRecorder()
+
+	mux.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+
+	var response []PostRead
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if len(response) != 2 || response[0].ID != 1 || response[1].ID != 2 {
+		t.Errorf("Expected posts 1 and 2 in order, got %v", response)
+	}
+}
+
+func TestExportPostsUnsupportedFormat(t *testing.T) {
+	mux := http.NewServeMux()
+	NewHandler(NewPostService(setupTestRepository())).RegisterRoutes(mux)
+
+	req := httptest.NewRequest(http.MethodGet, "/posts/export?format=xml", nil)
+	rr := httptest.NewRecorder()
+
+	mux.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, rr.Code)
+	}
+}
+
+func TestExportPostsNDJSON(t *testing.T) {
+	repo := setupTestRepository()
+	// Enough posts to be flushed several times.
+	data := make([]PostCreateUpdate, 2*ndjsonFlushInterval+10)
+	for i := range data {
+		data[i] = PostCreateUpdate{Title: fmt.Sprintf("Post %d", i), Content: "Content", Author: "Author"}
+	}
+	if _, err := repo.CreateMany(data); err != nil {
+		t.Fatalf("Failed to fill repository: %v", err)
+	}
+	mux := http.NewServeMux()
+	NewHandler(NewPostService(repo)).RegisterRoutes(mux)
+
+	req := httptest.NewRequest(http.MethodGet, "/posts/export?format=ndjson", nil)
+	rr := httptest.NewRecorder()
+
+	mux.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+	if contentType := rr.Header().Get("Content-Type"); contentType != "application/x-ndjson" {
+		t.Errorf("Expected Content-Type application/x-ndjson, got %s", contentType)
+	}
+	if !rr.Flushed {
+		t.Error("Expected the export to be flushed as it was written")
+	}
+
+	lines := strings.Split(strings.TrimSuffix(rr.Body.String(), "\n"), "\n")
+	if len(lines) != len(data)+2 {
+		t.Fatalf("Expected %d lines, got %d", len(data)+2, len(lines))
+	}
+	for i, line := range lines {
+		var post PostRead
+		if err := json.Unmarshal([]byte(line), &post); err != nil {
+			t.Fatalf("Failed to parse line %d: %v", i+1, err)
+		}
+		if post.ID != i+1 {
+			t.Errorf("Expected post %d on line %d, got %d", i+1, i+1, post.ID)
+		}
+	}
+}
+
+func TestExportPostsNDJSONError(t *testing.T) {
+	mux := http.NewServeMux()
+	NewHandler(&MockService{
+		IteratePostsFn: func(fn func(post PostRead) error) error {
+			return errors.New("database error")
+		},
+	}).RegisterRoutes(mux)
+
+	req := httptest.NewRequest(http.MethodGet, "/posts/export?format=ndjson", nil)
+	rr := httptest.NewRecorder()
+
+	mux.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status %d, got %d", http.StatusInternalServerError, rr.Code)
+	}
+}
+
+func TestExportPostsNDJSONLogsInterruption(t *testing.T) {
+	var logs bytes.Buffer
+	mux := http.NewServeMux()
+	NewHandler(&MockService{
+		IteratePostsFn: func(fn func(post PostRead) error) error {
+			if err := fn(PostRead{ID: 1, Title: "Title"}); err != nil {
+				return err
+			}
+			return errors.New("database error")
+		},
+	}, WithLogger(slog.New(slog.NewTextHandler(&logs, nil)))).RegisterRoutes(mux)
+
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/posts/export?format=ndjson", nil))
+
+	if rr.Code != http.StatusOK {
+		t.Errorf("Expected status %d once streaming started, got %d", http.StatusOK, rr.Code)
+	}
+	if !strings.Contains(logs.String(), "export interrupted") || !strings.Contains(logs.String(), "database error") {
+		t.Errorf("Expected the interruption to be logged, got %q", logs.String())
+	}
+}
+
+// failingFlushWriter fails every flush with err, as a connection that has gone away does.
+type failingFlushWriter struct {
+	*httptest.ResponseRecorder
+	err error
+}
+
+func (w failingFlushWriter) FlushError() error {
+	return w.err
+}
+
+func TestExportPostsNDJSONFlushErrors(t *testing.T) {
+	tests := []struct {
+		name            string
+		flushErr        error
+		expectedVisited int
+		expectedLog     string
+	}{
+		{name: "Failed Flush Stops Export", flushErr: errors.New("connection reset"), expectedVisited: ndjsonFlushInterval, expectedLog: "connection reset"},
+		{name: "Unsupported Flush Buffers Export", flushErr: http.ErrNotSupported, expectedVisited: ndjsonFlushInterval + 10, expectedLog: "export cannot be flushed"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var logs bytes.Buffer
+			visited := 0
+			handler := NewHandler(&MockService{
+				IteratePostsFn: func(fn func(post PostRead) error) error {
+					for id := 1; id <= ndjsonFlushInterval+10; id++ {
+						visited++
+						if err := fn(PostRead{ID: id, Title: "Title"}); err != nil {
+							return err
+						}
+					}
+					return nil
+				},
+			}, WithLogger(slog.New(slog.NewTextHandler(&logs, nil))))
+
+			w := failingFlushWriter{ResponseRecorder: httptest.NewRecorder(), err: tc.flushErr}
+			handler.ExportPosts(w, httptest.NewRequest(http.MethodGet, "/posts/export?format=ndjson", nil))
+
+			if visited != tc.expectedVisited {
+				t.Errorf("Expected %d posts to be visited, got %d", tc.expectedVisited, visited)
+			}
+			if !strings.Contains(logs.String(), tc.expectedLog) {
+				t.Errorf("Expected the log to contain %q, got %q", tc.expectedLog, logs.String())
+			}
+			if count := strings.Count(logs.String(), "level="); count != 1 {
+				t.Errorf("Expected a single log entry, got %q", logs.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package posts
+
+import (
+	"encoding/xml"
+	"net/http"
+	"time"
+)
+
+const (
+	// DefaultFeedItems is how many posts the feed lists unless configured otherwise.
+	DefaultFeedItems = 20
+	defaultFeedTitle = "Blog"
+
+	rssMediaType = "application/rss+xml"
+	dcNamespace  = "http://purl.org/dc/elements/1.1/"
+)
+
+// FeedConfig describes the RSS feed served at GET /posts/feed.xml.
+type FeedConfig struct {
+	// Title is the title of the feed.
+	Title string
+	// Link is the absolute URL of the site, such as https://blog.example.com, that post
+	// links are built on. When empty the scheme and host of the request are used.
+	Link string
+	// Items is how many of the most recent posts the feed lists, at most 50.
+	Items int
+}
+
+// DefaultFeedConfig returns a feed titled "Blog" of the 20 most recent posts, linked to
+// the host it is requested from.
+func DefaultFeedConfig() FeedConfig {
+	return FeedConfig{
+		Title: defaultFeedTitle,
+		Items: DefaultFeedItems,
+	}
+}
+
+// withDefaults fills in an empty title and a non-positive number of items.
+func (c FeedConfig) withDefaults() FeedConfig {
+	if c.Title == "" {
+		c.Title = defaultFeedTitle
+	}
+	if c.Items <= 0 {
+		c.Items = DefaultFeedItems
+	}
+	return c
+}
+
+// rssFeed is an RSS 2.0 document. Authors are given as dc:creator, since the RSS author
+// element must hold an email address.
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	DC      string     `xml:"xmlns:dc,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title       string    `xml:"title"`
+	Link        string    `xml:"link"`
+	Description string    `xml:"description"`
+	Items       []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string `xml:"title"`
+	Link        string `xml:"link"`
+	GUID        string `xml:"guid"`
+	Creator     string `xml:"dc:creator"`
+	Description string `xml:"description"`
+	PubDate     string `xml:"pubDate"`
+}
+
+// newRSSFeed builds the feed of posts, whose links start with site, for a request to
+// the routes serving r.
+func newRSSFeed(r *http.Request, cfg FeedConfig, site string, posts []PostRead) rssFeed {
+	items := make([]rssItem, 0, len(posts))
+	for _, post := range posts {
+		link := site + postLocation(r, post.ID)
+		items = append(items, rssItem{
+			Title:       post.Title,
+			Link:        link,
+			GUID:        link,
+			Creator:     post.Author,
+			Description: post.Content,
+			PubDate:     post.CreatedAt.UTC().Format(time.RFC1123Z),
+		})
+	}
+	return rssFeed{
+		Version: "2.0",
+		DC:      dcNamespace,
+		Channel: rssChannel{
+			Title:       cfg.Title,
+			Link:        site,
+			Description: "The most recent posts of " + cfg.Title,
+			Items:       items,
+		},
+	}
+}
+
+// feedSite returns the configured site URL, or the scheme and host r was sent to.
+func feedSite(r *http.Request, cfg FeedConfig) string {
+	if cfg.Link != "" {
+		return cfg.Link
+	}
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
+
+// GetFeed handles GET /posts/feed.xml
+// @Summary Get an RSS feed of recent posts
+// @Description Get the most recently created published posts, newest first, as an RSS 2.0 feed. The number of items and the feed's title and link are configured on the server.
+// @Tags posts
+// @Produce application/rss+xml
+// @Success 200 {string} string "RSS 2.0 document"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /posts/feed.xml [get]
+func (h *Handler) GetFeed(w http.ResponseWriter, r *http.Request) {
+	posts, err := h.service.GetRecentPosts(r.Context(), h.feed.Items)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	published := posts[:0:0]
+	for _, post := range posts {
+		if post.Status != StatusDraft {
+			published = append(published, post)
+		}
+	}
+
+	data, err := xml.MarshalIndent(newRSSFeed(r, h.feed, feedSite(r, h.feed), published), "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", rssMediaType+"; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(xml.Header))
+	w.Write(data)
+}
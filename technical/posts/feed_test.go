@@ -0,0 +1,143 @@
+package posts
+
+import (
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGetFeed(t *testing.T) {
+	created := time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)
+	recent := []PostRead{
+		{ID: 2, Title: "Fish & Chips", Content: "<p>Crispy</p> & \"hot\"", Author: "Jane Doe", Status: StatusPublished, CreatedAt: created},
+		{ID: 3, Title: "Unfinished", Content: "", Author: "Jane Doe", Status: StatusDraft, CreatedAt: created},
+		{ID: 1, Title: "First Post", Content: "Hello", Author: "John Smith", Status: StatusPublished, CreatedAt: created.Add(-time.Hour)},
+	}
+
+	tests := []struct {
+		name          string
+		feed          *FeedConfig
+		expectedN     int
+		expectedTitle string
+		expectedSite  string
+	}{
+		{
+			name:          "Defaults",
+			expectedN:     DefaultFeedItems,
+			expectedTitle: "Blog",
+			expectedSite:  "http://example.com",
+		},
+		{
+			name:          "Configured",
+			feed:          &FeedConfig{Title: "Rakia", Link: "https://blog.example.org", Items: 5},
+			expectedN:     5,
+			expectedTitle: "Rakia",
+			expectedSite:  "https://blog.example.org",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var requested int
+			var opts []HandlerOption
+			if tc.feed != nil {
+				opts = append(opts, WithFeed(*tc.feed))
+			}
+			mux := http.NewServeMux()
+			NewHandler(&MockService{
+				GetRecentPostsFn: func(n int) ([]PostRead, error) {
+					requested = n
+					return recent, nil
+				},
+			}, opts...).RegisterRoutes(mux)
+
+			req := httptest.NewRequest(http.MethodGet, "/posts/feed.xml", nil)
+			rr := httptest.NewRecorder()
+			mux.ServeHTTP(rr, req)
+
+			if rr.Code != http.StatusOK {
+				t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+			}
+			if contentType := rr.Header().Get("Content-Type"); contentType != "application/rss+xml; charset=utf-8" {
+				t.Errorf("Expected an RSS content type, got %q", contentType)
+			}
+			if requested != tc.expectedN {
+				t.Errorf("Expected %d recent posts to be requested, got %d", tc.expectedN, requested)
+			}
+			if !strings.HasPrefix(rr.Body.String(), xml.Header) {
+				t.Errorf("Expected the document to start with an XML declaration, got %q", rr.Body.String())
+			}
+			if !strings.Contains(rr.Body.String(), "&lt;p&gt;Crispy&lt;/p&gt; &amp; &#34;hot&#34;") {
+				t.Errorf("Expected the content to be escaped, got %s", rr.Body.String())
+			}
+
+			var feed struct {
+				Version string `xml:"version,attr"`
+				Channel struct {
+					Title string `xml:"title"`
+					Link  string `xml:"link"`
+					Items []struct {
+						Title       string `xml:"title"`
+						Link        string `xml:"link"`
+						GUID        string `xml:"guid"`
+						Creator     string `xml:"http://purl.org/dc/elements/1.1/ creator"`
+						Description string `xml:"description"`
+						PubDate     string `xml:"pubDate"`
+					} `xml:"item"`
+				} `xml:"channel"`
+			}
+			if err := xml.Unmarshal(rr.Body.Bytes(), &feed); err != nil {
+				t.Fatalf("Expected a valid XML document, got %v", err)
+			}
+			if feed.Version != "2.0" {
+				t.Errorf("Expected RSS version 2.0, got %q", feed.Version)
+			}
+			if feed.Channel.Title != tc.expectedTitle || feed.Channel.Link != tc.expectedSite {
+				t.Errorf("Expected channel %q at %q, got %q at %q", tc.expectedTitle, tc.expectedSite, feed.Channel.Title, feed.Channel.Link)
+			}
+			if len(feed.Channel.Items) != 2 {
+				t.Fatalf("Expected 2 items without the draft, got %d", len(feed.Channel.Items))
+			}
+
+			item := feed.Channel.Items[0]
+			if item.Title != "Fish & Chips" {
+				t.Errorf("Expected title %q, got %q", "Fish & Chips", item.Title)
+			}
+			if item.Creator != "Jane Doe" {
+				t.Errorf("Expected creator %q, got %q", "Jane Doe", item.Creator)
+			}
+			if item.Description != recent[0].Content {
+				t.Errorf("Expected description %q, got %q", recent[0].Content, item.Description)
+			}
+			if expected := created.Format(time.RFC1123Z); item.PubDate != expected {
+				t.Errorf("Expected pubDate %q, got %q", expected, item.PubDate)
+			}
+			if expected := tc.expectedSite + "/posts/2"; item.Link != expected || item.GUID != expected {
+				t.Errorf("Expected link and guid %q, got %q and %q", expected, item.Link, item.GUID)
+			}
+			if feed.Channel.Items[1].Title != "First Post" {
+				t.Errorf("Expected the posts to keep their order, got %q second", feed.Channel.Items[1].Title)
+			}
+		})
+	}
+}
+
+func TestGetFeedServiceError(t *testing.T) {
+	handler := NewHandler(&MockService{
+		GetRecentPostsFn: func(n int) ([]PostRead, error) {
+			return nil, errors.New("storage failure")
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/posts/feed.xml", nil)
+	rr := httptest.NewRecorder()
+	handler.GetFeed(rr, req)
+
+	if rr.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status %d, got %d", http.StatusInternalServerError, rr.Code)
+	}
+}
@@ -0,0 +1,55 @@
+package posts
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// postFields are the JSON names of PostRead that ?fields= may select.
+var postFields = []string{"id", "uid", "title", "content", "author", "status", "views", "created_at", "updated_at", "tags", "image_url", "content_html", "truncated", "word_count", "char_count"}
+
+// parseFields returns the fields selected by the fields query parameter, or nil when
+// it is absent, rejecting names that PostRead does not have.
+func parseFields(r *http.Request) ([]string, error) {
+	raw := r.URL.Query().Get("fields")
+	if raw == "" {
+		return nil, nil
+	}
+
+	var fields []string
+	for _, field := range strings.Split(raw, ",") {
+		field = strings.TrimSpace(field)
+		if !slices.Contains(postFields, field) {
+			return nil, fmt.Errorf("unknown field %q; allowed fields are %s", field, strings.Join(postFields, ", "))
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// projectPost returns the JSON representation of post restricted to fields. Numbers
+// are kept as json.Number so that IDs beyond 2^53 survive the round trip.
+func projectPost(post PostRead, fields []string) (map[string]interface{}, error) {
+	data, err := json.Marshal(post)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var full map[string]interface{}
+	if err := decoder.Decode(&full); err != nil {
+		return nil, err
+	}
+
+	projected := make(map[string]interface{}, len(fields))
+	for _, field := range fields {
+		if value, ok := full[field]; ok {
+			projected[field] = value
+		}
+	}
+	return projected, nil
+}
@@ -0,0 +1,87 @@
+package posts
+
+import (
+	"encoding/json"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestFieldsProjection(t *testing.T) {
+	repo := setupTestRepository()
+	repo.Update(1, PostCreateUpdate{Title: "Test Post 1", Content: "Test Content 1", Author: "Test Author 1", ImageURL: "https://example.com/cover.png"})
+	mux := http.NewServeMux()
+	NewHandler(NewPostService(repo)).RegisterRoutes(mux)
+
+	tests := []struct {
+		name           string
+		url            string
+		expectedStatus int
+		expectedKeys   []string
+	}{
+		{
+			name:           "Collection Subset",
+			url:            "/posts?fields=id,title",
+			expectedStatus: http.StatusOK,
+			expectedKeys:   []string{"id", "title"},
+		},
+		{
+			name:           "Item Subset",
+			url:            "/posts/1?fields=author",
+			expectedStatus: http.StatusOK,
+			expectedKeys:   []string{"author"},
+		},
+		{
+			name:           "Item Image URL",
+			url:            "/posts/1?fields=id,image_url",
+			expectedStatus: http.StatusOK,
+			expectedKeys:   []string{"id", "image_url"},
+		},
+		{
+			name:           "Collection Unknown Field",
+			url:            "/posts?fields=id,password",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Item Unknown Field",
+			url:            "/posts/1?fields=password",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tc.url, nil))
+
+			if rr.Code != tc.expectedStatus {
+				t.Fatalf("Expected status %d, got %d", tc.expectedStatus, rr.Code)
+			}
+			if tc.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var objects []map[string]any
+			if rr.Body.Bytes()[0] == '[' {
+				if err := json.Unmarshal(rr.Body.Bytes(), &objects); err != nil {
+					t.Fatalf("Failed to unmarshal response: %v", err)
+				}
+			} else {
+				var object map[string]any
+				if err := json.Unmarshal(rr.Body.Bytes(), &object); err != nil {
+					t.Fatalf("Failed to unmarshal response: %v", err)
+				}
+				objects = append(objects, object)
+			}
+
+			for _, object := range objects {
+				keys := slices.Sorted(maps.Keys(object))
+				if !slices.Equal(keys, tc.expectedKeys) {
+					t.Errorf("Expected keys %v, got %v", tc.expectedKeys, keys)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package posts
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+var errInvertedRange = errors.New("createdAfter must not be later than createdBefore")
+
+// TimeRange is the half-open interval [After, Before); a zero bound leaves that side open.
+type TimeRange struct {
+	After  time.Time
+	Before time.Time
+}
+
+func (tr TimeRange) IsZero() bool {
+	return tr.After.IsZero() && tr.Before.IsZero()
+}
+
+func (tr TimeRange) Contains(t time.Time) bool {
+	return (tr.After.IsZero() || !t.Before(tr.After)) && (tr.Before.IsZero() || t.Before(tr.Before))
+}
+
+// parseCreatedRange reads the RFC 3339 createdAfter and createdBefore query parameters,
+// either of which may be omitted.
+func parseCreatedRange(r *http.Request) (TimeRange, error) {
+	var tr TimeRange
+	for _, bound := range []struct {
+		name  string
+		value *time.Time
+	}{
+		{"createdAfter", &tr.After},
+		{"createdBefore", &tr.Before},
+	} {
+		raw := r.URL.Query().Get(bound.name)
+		if raw == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return TimeRange{}, fmt.Errorf("%s must be an RFC 3339 time such as 2024-01-01T00:00:00Z", bound.name)
+		}
+		*bound.value = t
+	}
+
+	if !tr.After.IsZero() && !tr.Before.IsZero() && tr.After.After(tr.Before) {
+		return TimeRange{}, errInvertedRange
+	}
+	return tr, nil
+}
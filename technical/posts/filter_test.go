@@ -0,0 +1,75 @@
+package posts
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParseCreatedRange(t *testing.T) {
+	after := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	before := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		query         string
+		expected      TimeRange
+		expectedError bool
+	}{
+		{name: "Absent", query: "", expected: TimeRange{}},
+		{name: "Open Before", query: "createdAfter=2024-01-01T00:00:00Z", expected: TimeRange{After: after}},
+		{name: "Open After", query: "createdBefore=2024-02-01T00:00:00Z", expected: TimeRange{Before: before}},
+		{name: "Bounded", query: "createdAfter=2024-01-01T00:00:00Z&createdBefore=2024-02-01T00:00:00Z", expected: TimeRange{After: after, Before: before}},
+		{name: "Equal Bounds", query: "createdAfter=2024-01-01T00:00:00Z&createdBefore=2024-01-01T00:00:00Z", expected: TimeRange{After: after, Before: after}},
+		{name: "Inverted", query: "createdAfter=2024-02-01T00:00:00Z&createdBefore=2024-01-01T00:00:00Z", expectedError: true},
+		{name: "Date Only", query: "createdAfter=2024-01-01", expectedError: true},
+		{name: "Garbage", query: "createdBefore=yesterday", expectedError: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/posts?"+tc.query, nil)
+
+			tr, err := parseCreatedRange(r)
+			if tc.expectedError {
+				if err == nil {
+					t.Errorf("Expected an error, got %+v", tr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if !tr.After.Equal(tc.expected.After) || !tr.Before.Equal(tc.expected.Before) {
+				t.Errorf("Expected %+v, got %+v", tc.expected, tr)
+			}
+		})
+	}
+}
+
+func TestTimeRangeContains(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+
+	tests := []struct {
+		name     string
+		tr       TimeRange
+		t        time.Time
+		expected bool
+	}{
+		{name: "Open", tr: TimeRange{}, t: start, expected: true},
+		{name: "At After", tr: TimeRange{After: start, Before: end}, t: start, expected: true},
+		{name: "At Before", tr: TimeRange{After: start, Before: end}, t: end, expected: false},
+		{name: "Inside", tr: TimeRange{After: start, Before: end}, t: start.Add(time.Minute), expected: true},
+		{name: "Earlier Than After", tr: TimeRange{After: start}, t: start.Add(-time.Second), expected: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.tr.Contains(tc.t); got != tc.expected {
+				t.Errorf("Expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
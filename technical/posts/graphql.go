@@ -0,0 +1,312 @@
+package posts
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/graphql-go/graphql"
+	"net/http"
+	"slices"
+)
+
+const graphQLAllow = "GET, POST"
+
+// Error codes reported in the extensions of GraphQL errors, so that clients can tell
+// a missing post apart from invalid input without parsing messages.
+const (
+	graphQLCodeNotFound = "NOT_FOUND"
+	graphQLCodeBadInput = "BAD_USER_INPUT"
+	graphQLCodeConflict = "CONFLICT"
+	graphQLCodeReadOnly = "FORBIDDEN"
+	graphQLCodeInternal = "INTERNAL_SERVER_ERROR"
+)
+
+// graphQLError carries a service error into the GraphQL response along with its code.
+type graphQLError struct {
+	err  error
+	code string
+}
+
+func (e graphQLError) Error() string {
+	return e.err.Error()
+}
+
+func (e graphQLError) Unwrap() error {
+	return e.err
+}
+
+func (e graphQLError) Extensions() map[string]interface{} {
+	extensions := map[string]interface{}{"code": e.code}
+	var validationError ValidationError
+	if errors.As(e.err, &validationError) {
+		extensions["errors"] = validationError
+	}
+	return extensions
+}
+
+// newGraphQLError classifies err the way the HTTP handlers choose a status code.
+func newGraphQLError(err error) error {
+	var validationError ValidationError
+	switch {
+	case errors.Is(err, ErrPostNotFound):
+		return graphQLError{err: err, code: graphQLCodeNotFound}
+	case errors.As(err, &validationError), errors.Is(err, InvalidPostIDError):
+		return graphQLError{err: err, code: graphQLCodeBadInput}
+	case errors.Is(err, ErrDuplicatePost):
+		return graphQLError{err: err, code: graphQLCodeConflict}
+	case errors.Is(err, ErrReadOnly):
+		return graphQLError{err: err, code: graphQLCodeReadOnly}
+	default:
+		return graphQLError{err: err, code: graphQLCodeInternal}
+	}
+}
+
+var graphQLPostType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Post",
+	Fields: graphql.Fields{
+		"id":        &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		"uid":       &graphql.Field{Type: graphql.String},
+		"title":     &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"content":   &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"author":    &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"status":    &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"views":     &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		"createdAt": &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+		"updatedAt": &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+		"tags":      &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String)))},
+		"imageUrl":  &graphql.Field{Type: graphql.String},
+	},
+})
+
+var graphQLPostInputType = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "PostInput",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"title":   &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+		"content": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+		"author":  &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+		"status":  &graphql.InputObjectFieldConfig{Type: graphql.String},
+		// Tags and imageUrl that are left out of updatePost keep their current values.
+		"tags":     &graphql.InputObjectFieldConfig{Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+		"imageUrl": &graphql.InputObjectFieldConfig{Type: graphql.String},
+	},
+})
+
+// GraphQLHandler serves a GraphQL schema over the post service at /graphql.
+type GraphQLHandler struct {
+	service Service
+	schema  graphql.Schema
+}
+
+func NewGraphQLHandler(service Service) *GraphQLHandler {
+	h := &GraphQLHandler{service: service}
+
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"posts": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphQLPostType))),
+					Args: graphql.FieldConfigArgument{
+						"id":     &graphql.ArgumentConfig{Type: graphql.Int},
+						"author": &graphql.ArgumentConfig{Type: graphql.String},
+						"limit":  &graphql.ArgumentConfig{Type: graphql.Int},
+					},
+					Resolve: h.resolvePosts,
+				},
+			},
+		}),
+		Mutation: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Mutation",
+			Fields: graphql.Fields{
+				"createPost": &graphql.Field{
+					Type: graphql.NewNonNull(graphQLPostType),
+					Args: graphql.FieldConfigArgument{
+						"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphQLPostInputType)},
+					},
+					Resolve: h.resolveCreatePost,
+				},
+				"updatePost": &graphql.Field{
+					Type: graphql.NewNonNull(graphQLPostType),
+					Args: graphql.FieldConfigArgument{
+						"id":    &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+						"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphQLPostInputType)},
+					},
+					Resolve: h.resolveUpdatePost,
+				},
+				"deletePost": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Boolean),
+					Args: graphql.FieldConfigArgument{
+						"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+					},
+					Resolve: h.resolveDeletePost,
+				},
+			},
+		}),
+	})
+	if err != nil {
+		panic(err)
+	}
+	h.schema = schema
+
+	return h
+}
+
+type graphQLRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// ServeHTTP accepts a query as a JSON body on POST or as the query parameter on GET.
+// Execution errors are reported in the errors member of a 200 response, as GraphQL clients expect.
+func (h *GraphQLHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req graphQLRequest
+	switch r.Method {
+	case http.MethodGet:
+		req.Query = r.URL.Query().Get("query")
+		req.OperationName = r.URL.Query().Get("operationName")
+		if raw := r.URL.Query().Get("variables"); raw != "" {
+			if err := json.Unmarshal([]byte(raw), &req.Variables); err != nil {
+				http.Error(w, "Invalid variables", http.StatusBadRequest)
+				return
+			}
+		}
+	case http.MethodPost:
+		if err := decodeJSON(r, &req); err != nil {
+			respondWithBodyError(w, r, err)
+			return
+		}
+	default:
+		methodNotAllowed(w, graphQLAllow)
+		return
+	}
+	if req.Query == "" {
+		http.Error(w, "Query is required", http.StatusBadRequest)
+		return
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema:         h.schema,
+		RequestString:  req.Query,
+		OperationName:  req.OperationName,
+		VariableValues: req.Variables,
+		Context:        r.Context(),
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+func (h *GraphQLHandler) resolvePosts(p graphql.ResolveParams) (interface{}, error) {
+	if id, ok := p.Args["id"].(int); ok {
+		post, err := h.service.GetPostByID(p.Context, id)
+		if err != nil {
+			return nil, newGraphQLError(err)
+		}
+		return []interface{}{graphQLPost(post)}, nil
+	}
+
+	limit, hasLimit := p.Args["limit"].(int)
+	if hasLimit && limit < 0 {
+		return nil, graphQLError{err: errors.New("limit must not be negative"), code: graphQLCodeBadInput}
+	}
+
+	posts, err := h.service.GetAllPosts(p.Context)
+	if err != nil {
+		return nil, newGraphQLError(err)
+	}
+	slices.SortFunc(posts, func(a, b PostRead) int { return a.ID - b.ID })
+
+	author, hasAuthor := p.Args["author"].(string)
+	result := make([]interface{}, 0, len(posts))
+	for _, post := range posts {
+		if hasLimit && len(result) == limit {
+			break
+		}
+		if hasAuthor && post.Author != author {
+			continue
+		}
+		result = append(result, graphQLPost(post))
+	}
+	return result, nil
+}
+
+func (h *GraphQLHandler) resolveCreatePost(p graphql.ResolveParams) (interface{}, error) {
+	post, err := h.service.CreatePost(p.Context, graphQLPostPatch(p.Args["input"]).apply(PostRead{}))
+	if err != nil {
+		return nil, newGraphQLError(err)
+	}
+	return graphQLPost(post), nil
+}
+
+func (h *GraphQLHandler) resolveUpdatePost(p graphql.ResolveParams) (interface{}, error) {
+	id, _ := p.Args["id"].(int)
+	post, err := h.service.PatchPost(p.Context, id, graphQLPostPatch(p.Args["input"]), "")
+	if err != nil {
+		return nil, newGraphQLError(err)
+	}
+	return graphQLPost(post), nil
+}
+
+func (h *GraphQLHandler) resolveDeletePost(p graphql.ResolveParams) (interface{}, error) {
+	id, _ := p.Args["id"].(int)
+	if err := h.service.DeletePost(p.Context, id); err != nil {
+		return nil, newGraphQLError(err)
+	}
+	return true, nil
+}
+
+func graphQLPost(post PostRead) map[string]interface{} {
+	return map[string]interface{}{
+		"id":        post.ID,
+		"uid":       graphQLOptionalString(post.UID),
+		"title":     post.Title,
+		"content":   post.Content,
+		"author":    post.Author,
+		"status":    post.Status,
+		"views":     post.Views,
+		"createdAt": post.CreatedAt,
+		"updatedAt": post.UpdatedAt,
+		"tags":      graphQLTags(post.Tags),
+		"imageUrl":  graphQLOptionalString(post.ImageURL),
+	}
+}
+
+// graphQLOptionalString returns null for an empty value, such as the image URL of a
+// post without an image.
+func graphQLOptionalString(value string) interface{} {
+	if value == "" {
+		return nil
+	}
+	return value
+}
+
+// graphQLTags returns tags as a non-null list.
+func graphQLTags(tags []string) []string {
+	if tags == nil {
+		return []string{}
+	}
+	return tags
+}
+
+// graphQLPostPatch converts a PostInput into a patch that replaces the title, content,
+// author and status and sets the tags and image URL only when the input has them.
+func graphQLPostPatch(arg interface{}) PostPatch {
+	input, _ := arg.(map[string]interface{})
+	title, _ := input["title"].(string)
+	content, _ := input["content"].(string)
+	author, _ := input["author"].(string)
+	status, _ := input["status"].(string)
+	patch := PostPatch{Title: &title, Content: &content, Author: &author, Status: &status}
+	if values, ok := input["tags"].([]interface{}); ok {
+		tags := make([]string, 0, len(values))
+		for _, value := range values {
+			tag, _ := value.(string)
+			tags = append(tags, tag)
+		}
+		patch.Tags = &tags
+	}
+	if imageURL, ok := input["imageUrl"].(string); ok {
+		patch.ImageURL = &imageURL
+	}
+	return patch
+}
@@ -0,0 +1,331 @@
+package posts
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type graphQLTestResponse struct {
+	Data   map[string]json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string                 `json:"message"`
+		Extensions map[string]interface{} `json:"extensions"`
+	} `json:"errors"`
+}
+
+func postGraphQL(t *testing.T, handler http.Handler, query string, variables map[string]interface{}) graphQLTestResponse {
+	t.Helper()
+
+	body, _ := json.Marshal(graphQLRequest{Query: query, Variables: variables})
+	req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, rr.Code)
+	}
+	var resp graphQLTestResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	return resp
+}
+
+func TestGraphQLPostsQuery(t *testing.T) {
+	handler := NewGraphQLHandler(NewPostService(setupTestRepository()))
+
+	testCases := []struct {
+		name          string
+		query         string
+		expectedIDs   []int
+		expectedError string
+	}{
+		{
+			name:        "All posts",
+			query:       `{ posts { id title } }`,
+			expectedIDs: []int{1, 2},
+		},
+		{
+			name:        "By ID",
+			query:       `{ posts(id: 2) { id title } }`,
+			expectedIDs: []int{2},
+		},
+		{
+			name:        "By author",
+			query:       `{ posts(author: "Test Author 1") { id title } }`,
+			expectedIDs: []int{1},
+		},
+		{
+			name:        "With limit",
+			query:       `{ posts(limit: 1) { id title } }`,
+			expectedIDs: []int{1},
+		},
+		{
+			name:          "Not found",
+			query:         `{ posts(id: 99) { id title } }`,
+			expectedError: graphQLCodeNotFound,
+		},
+		{
+			name:          "Negative limit",
+			query:         `{ posts(limit: -1) { id title } }`,
+			expectedError: graphQLCodeBadInput,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := postGraphQL(t, handler, tc.query, nil)
+
+			if tc.expectedError != "" {
+				if len(resp.Errors) != 1 {
+					t.Fatalf("Expected 1 error, got %+v", resp.Errors)
+				}
+				if code := resp.Errors[0].Extensions["code"]; code != tc.expectedError {
+					t.Errorf("Expected error code %s, got %v", tc.expectedError, code)
+				}
+				return
+			}
+
+			if len(resp.Errors) != 0 {
+				t.Fatalf("Expected no errors, got %+v", resp.Errors)
+			}
+			var posts []struct {
+				ID int `json:"id"`
+			}
+			if err := json.Unmarshal(resp.Data["posts"], &posts); err != nil {
+				t.Fatalf("Failed to decode posts: %v", err)
+			}
+			if len(posts) != len(tc.expectedIDs) {
+				t.Fatalf("Expected %d posts, got %d", len(tc.expectedIDs), len(posts))
+			}
+			for i, id := range tc.expectedIDs {
+				if posts[i].ID != id {
+					t.Errorf("Expected post %d at index %d, got %d", id, i, posts[i].ID)
+				}
+			}
+		})
+	}
+}
+
+func TestGraphQLMutations(t *testing.T) {
+	handler := NewGraphQLHandler(NewPostService(setupTestRepository()))
+
+	resp := postGraphQL(t, handler,
+		`mutation($input: PostInput!) { createPost(input: $input) { id title author status } }`,
+		map[string]interface{}{
+			"input": map[string]interface{}{"title": "New Post", "content": "New Content", "author": "Jane Doe"},
+		})
+	if len(resp.Errors) != 0 {
+		t.Fatalf("Expected no errors, got %+v", resp.Errors)
+	}
+	var created struct {
+		ID     int    `json:"id"`
+		Title  string `json:"title"`
+		Status string `json:"status"`
+	}
+	json.Unmarshal(resp.Data["createPost"], &created)
+	if created.ID != 3 || created.Title != "New Post" || created.Status != StatusPublished {
+		t.Errorf("Expected created post 3, got %+v", created)
+	}
+
+	resp = postGraphQL(t, handler,
+		`mutation { updatePost(id: 3, input: {title: "Updated", content: "Updated Content", author: "Jane Doe"}) { title } }`, nil)
+	if len(resp.Errors) != 0 {
+		t.Fatalf("Expected no errors, got %+v", resp.Errors)
+	}
+	if string(resp.Data["updatePost"]) != `{"title":"Updated"}` {
+		t.Errorf("Expected updated title, got %s", resp.Data["updatePost"])
+	}
+
+	resp = postGraphQL(t, handler, `mutation { deletePost(id: 3) }`, nil)
+	if len(resp.Errors) != 0 || string(resp.Data["deletePost"]) != "true" {
+		t.Errorf("Expected deletePost to return true, got %+v", resp)
+	}
+}
+
+func TestGraphQLUpdateKeepsTags(t *testing.T) {
+	handler := NewGraphQLHandler(NewPostService(setupTestRepository()))
+
+	resp := postGraphQL(t, handler,
+		`mutation { createPost(input: {title: "Tagged", content: "Content", author: "Jane Doe", tags: ["go", "news"]}) { tags } }`, nil)
+	if len(resp.Errors) != 0 {
+		t.Fatalf("Expected no errors, got %+v", resp.Errors)
+	}
+	if string(resp.Data["createPost"]) != `{"tags":["go","news"]}` {
+		t.Errorf("Expected created tags, got %s", resp.Data["createPost"])
+	}
+
+	testCases := []struct {
+		name         string
+		query        string
+		expectedPost string
+	}{
+		{
+			name:         "Tags left out",
+			query:        `mutation { updatePost(id: 3, input: {title: "Updated", content: "Content", author: "Jane Doe"}) { tags } }`,
+			expectedPost: `{"tags":["go","news"]}`,
+		},
+		{
+			name:         "Tags replaced",
+			query:        `mutation { updatePost(id: 3, input: {title: "Updated", content: "Content", author: "Jane Doe", tags: ["go"]}) { tags } }`,
+			expectedPost: `{"tags":["go"]}`,
+		},
+		{
+			name:         "Tags cleared",
+			query:        `mutation { updatePost(id: 3, input: {title: "Updated", content: "Content", author: "Jane Doe", tags: []}) { tags } }`,
+			expectedPost: `{"tags":[]}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := postGraphQL(t, handler, tc.query, nil)
+
+			if len(resp.Errors) != 0 {
+				t.Fatalf("Expected no errors, got %+v", resp.Errors)
+			}
+			if string(resp.Data["updatePost"]) != tc.expectedPost {
+				t.Errorf("Expected %s, got %s", tc.expectedPost, resp.Data["updatePost"])
+			}
+		})
+	}
+}
+
+func TestGraphQLUpdateKeepsImageURL(t *testing.T) {
+	handler := NewGraphQLHandler(NewPostService(setupTestRepository()))
+
+	resp := postGraphQL(t, handler,
+		`mutation { createPost(input: {title: "Cover", content: "Content", author: "Jane Doe", imageUrl: "https://example.com/cover.png"}) { imageUrl } }`, nil)
+	if len(resp.Errors) != 0 {
+		t.Fatalf("Expected no errors, got %+v", resp.Errors)
+	}
+	if string(resp.Data["createPost"]) != `{"imageUrl":"https://example.com/cover.png"}` {
+		t.Errorf("Expected created image URL, got %s", resp.Data["createPost"])
+	}
+
+	testCases := []struct {
+		name         string
+		query        string
+		expectedPost string
+	}{
+		{
+			name:         "Image URL left out",
+			query:        `mutation { updatePost(id: 3, input: {title: "Updated", content: "Content", author: "Jane Doe"}) { imageUrl } }`,
+			expectedPost: `{"imageUrl":"https://example.com/cover.png"}`,
+		},
+		{
+			name:         "Image URL replaced",
+			query:        `mutation { updatePost(id: 3, input: {title: "Updated", content: "Content", author: "Jane Doe", imageUrl: "https://example.com/new.png"}) { imageUrl } }`,
+			expectedPost: `{"imageUrl":"https://example.com/new.png"}`,
+		},
+		{
+			name:         "Image URL cleared",
+			query:        `mutation { updatePost(id: 3, input: {title: "Updated", content: "Content", author: "Jane Doe", imageUrl: ""}) { imageUrl } }`,
+			expectedPost: `{"imageUrl":null}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := postGraphQL(t, handler, tc.query, nil)
+
+			if len(resp.Errors) != 0 {
+				t.Fatalf("Expected no errors, got %+v", resp.Errors)
+			}
+			if string(resp.Data["updatePost"]) != tc.expectedPost {
+				t.Errorf("Expected %s, got %s", tc.expectedPost, resp.Data["updatePost"])
+			}
+		})
+	}
+}
+
+func TestGraphQLMutationErrors(t *testing.T) {
+	handler := NewGraphQLHandler(NewPostService(setupTestRepository()))
+
+	testCases := []struct {
+		name         string
+		query        string
+		expectedCode string
+	}{
+		{
+			name:         "Update not found",
+			query:        `mutation { updatePost(id: 99, input: {title: "Title", content: "Content", author: "Jane Doe"}) { id } }`,
+			expectedCode: graphQLCodeNotFound,
+		},
+		{
+			name:         "Create invalid",
+			query:        `mutation { createPost(input: {title: "", content: "Content", author: "Jane Doe"}) { id } }`,
+			expectedCode: graphQLCodeBadInput,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := postGraphQL(t, handler, tc.query, nil)
+
+			if len(resp.Errors) != 1 {
+				t.Fatalf("Expected 1 error, got %+v", resp.Errors)
+			}
+			if resp.Errors[0].Message == "" {
+				t.Error("Expected an error message")
+			}
+			if code := resp.Errors[0].Extensions["code"]; code != tc.expectedCode {
+				t.Errorf("Expected error code %s, got %v", tc.expectedCode, code)
+			}
+		})
+	}
+}
+
+func TestGraphQLHandlerRequests(t *testing.T) {
+	handler := NewGraphQLHandler(NewPostService(setupTestRepository()))
+
+	testCases := []struct {
+		name           string
+		method         string
+		target         string
+		body           string
+		expectedStatus int
+	}{
+		{
+			name:           "GET query",
+			method:         http.MethodGet,
+			target:         "/graphql?query=%7Bposts%7Bid%7D%7D",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Invalid body",
+			method:         http.MethodPost,
+			target:         "/graphql",
+			body:           "{",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Missing query",
+			method:         http.MethodPost,
+			target:         "/graphql",
+			body:           "{}",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Method not allowed",
+			method:         http.MethodPut,
+			target:         "/graphql",
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.target, bytes.NewBufferString(tc.body))
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			if rr.Code != tc.expectedStatus {
+				t.Errorf("Expected status code %d, got %d", tc.expectedStatus, rr.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package posts
+
+import (
+	"context"
+	"errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"technical/postspb"
+)
+
+// GRPCServer exposes Service over gRPC as defined in proto/posts.proto.
+type GRPCServer struct {
+	postspb.UnimplementedPostServiceServer
+	service Service
+}
+
+func NewGRPCServer(service Service) *GRPCServer {
+	return &GRPCServer{service: service}
+}
+
+func (s *GRPCServer) GetAll(ctx context.Context, _ *emptypb.Empty) (*postspb.PostList, error) {
+	posts, err := s.service.GetAllPosts(ctx)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	list := &postspb.PostList{Posts: make([]*postspb.Post, len(posts))}
+	for i, post := range posts {
+		list.Posts[i] = postToProto(post)
+	}
+	return list, nil
+}
+
+func (s *GRPCServer) GetById(ctx context.Context, req *postspb.GetByIdRequest) (*postspb.Post, error) {
+	post, err := s.service.GetPostByID(ctx, int(req.GetId()))
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return postToProto(post), nil
+}
+
+func (s *GRPCServer) Create(ctx context.Context, req *postspb.PostInput) (*postspb.Post, error) {
+	post, err := s.service.CreatePost(ctx, postPatchFromProto(req).apply(PostRead{}))
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return postToProto(post), nil
+}
+
+func (s *GRPCServer) Update(ctx context.Context, req *postspb.UpdateRequest) (*postspb.Post, error) {
+	post, err := s.service.PatchPost(ctx, int(req.GetId()), postPatchFromProto(req.GetPost()), "")
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return postToProto(post), nil
+}
+
+func (s *GRPCServer) Delete(ctx context.Context, req *postspb.DeleteRequest) (*emptypb.Empty, error) {
+	if err := s.service.DeletePost(ctx, int(req.GetId())); err != nil {
+		return nil, grpcError(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// grpcError maps service errors onto gRPC status codes the way the HTTP handlers map them onto status codes.
+func grpcError(err error) error {
+	var validationError ValidationError
+	switch {
+	case errors.Is(err, ErrPostNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.As(err, &validationError), errors.Is(err, InvalidPostIDError):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, ErrDuplicatePost):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, ErrCapacityExceeded):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, ErrReadOnly):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+func postToProto(post PostRead) *postspb.Post {
+	return &postspb.Post{
+		Id:        int64(post.ID),
+		Uid:       post.UID,
+		Title:     post.Title,
+		Content:   post.Content,
+		Author:    post.Author,
+		Status:    post.Status,
+		Views:     int64(post.Views),
+		CreatedAt: timestamppb.New(post.CreatedAt),
+		UpdatedAt: timestamppb.New(post.UpdatedAt),
+		Tags:      post.Tags,
+		ImageUrl:  post.ImageURL,
+	}
+}
+
+// postPatchFromProto converts a PostInput into a patch that replaces the title, content,
+// author and status and sets the tags and image URL only when the input has them.
+func postPatchFromProto(input *postspb.PostInput) PostPatch {
+	title, content, author, status := input.GetTitle(), input.GetContent(), input.GetAuthor(), input.GetStatus()
+	patch := PostPatch{Title: &title, Content: &content, Author: &author, Status: &status}
+	if input.GetTags() != nil {
+		tags := input.GetTags().GetValues()
+		patch.Tags = &tags
+	}
+	if input.ImageUrl != nil {
+		imageURL := input.GetImageUrl()
+		patch.ImageURL = &imageURL
+	}
+	return patch
+}
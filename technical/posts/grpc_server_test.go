@@ -0,0 +1,265 @@
+package posts
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"net"
+	"slices"
+	"technical/postspb"
+	"testing"
+)
+
+// setupGRPCClient serves service over an in-memory listener and returns a client connected to it.
+func setupGRPCClient(t *testing.T, service Service) postspb.PostServiceClient {
+	t.Helper()
+
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	postspb.RegisterPostServiceServer(server, NewGRPCServer(service))
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("Failed to dial bufconn: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return postspb.NewPostServiceClient(conn)
+}
+
+func TestGRPCServer(t *testing.T) {
+	client := setupGRPCClient(t, NewPostService(setupTestRepository()))
+	ctx := context.Background()
+
+	created, err := client.Create(ctx, &postspb.PostInput{Title: "New Post", Content: "New Content", Author: "Jane Doe"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if created.GetTitle() != "New Post" {
+		t.Errorf("Expected title New Post, got %s", created.GetTitle())
+	}
+
+	post, err := client.GetById(ctx, &postspb.GetByIdRequest{Id: created.GetId()})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if post.GetAuthor() != "Jane Doe" {
+		t.Errorf("Expected author Jane Doe, got %s", post.GetAuthor())
+	}
+
+	updated, err := client.Update(ctx, &postspb.UpdateRequest{
+		Id:   created.GetId(),
+		Post: &postspb.PostInput{Title: "Updated Post", Content: "Updated Content", Author: "Jane Doe"},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if updated.GetTitle() != "Updated Post" {
+		t.Errorf("Expected title Updated Post, got %s", updated.GetTitle())
+	}
+
+	list, err := client.GetAll(ctx, &emptypb.Empty{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(list.GetPosts()) != 3 {
+		t.Errorf("Expected 3 posts, got %d", len(list.GetPosts()))
+	}
+
+	if _, err := client.Delete(ctx, &postspb.DeleteRequest{Id: created.GetId()}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+}
+
+func TestGRPCServerUpdateKeepsTags(t *testing.T) {
+	client := setupGRPCClient(t, NewPostService(setupTestRepository()))
+	ctx := context.Background()
+
+	created, err := client.Create(ctx, &postspb.PostInput{
+		Title: "Tagged", Content: "Content", Author: "Jane Doe",
+		Tags: &postspb.Tags{Values: []string{"go", "news"}},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !slices.Equal(created.GetTags(), []string{"go", "news"}) {
+		t.Errorf("Expected tags [go news], got %v", created.GetTags())
+	}
+
+	testCases := []struct {
+		name         string
+		tags         *postspb.Tags
+		expectedTags []string
+	}{
+		{
+			name:         "Tags left out",
+			tags:         nil,
+			expectedTags: []string{"go", "news"},
+		},
+		{
+			name:         "Tags replaced",
+			tags:         &postspb.Tags{Values: []string{"go"}},
+			expectedTags: []string{"go"},
+		},
+		{
+			name:         "Tags cleared",
+			tags:         &postspb.Tags{},
+			expectedTags: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			updated, err := client.Update(ctx, &postspb.UpdateRequest{
+				Id:   created.GetId(),
+				Post: &postspb.PostInput{Title: "Updated", Content: "Content", Author: "Jane Doe", Tags: tc.tags},
+			})
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if !slices.Equal(updated.GetTags(), tc.expectedTags) {
+				t.Errorf("Expected tags %v, got %v", tc.expectedTags, updated.GetTags())
+			}
+		})
+	}
+}
+
+func TestGRPCServerUpdateKeepsImageURL(t *testing.T) {
+	client := setupGRPCClient(t, NewPostService(setupTestRepository()))
+	ctx := context.Background()
+
+	created, err := client.Create(ctx, &postspb.PostInput{
+		Title: "Cover", Content: "Content", Author: "Jane Doe",
+		ImageUrl: proto.String("https://example.com/cover.png"),
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if created.GetImageUrl() != "https://example.com/cover.png" {
+		t.Errorf("Expected image URL https://example.com/cover.png, got %q", created.GetImageUrl())
+	}
+
+	testCases := []struct {
+		name             string
+		imageURL         *string
+		expectedImageURL string
+	}{
+		{
+			name:             "Image URL left out",
+			imageURL:         nil,
+			expectedImageURL: "https://example.com/cover.png",
+		},
+		{
+			name:             "Image URL replaced",
+			imageURL:         proto.String("https://example.com/new.png"),
+			expectedImageURL: "https://example.com/new.png",
+		},
+		{
+			name:             "Image URL cleared",
+			imageURL:         proto.String(""),
+			expectedImageURL: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			updated, err := client.Update(ctx, &postspb.UpdateRequest{
+				Id:   created.GetId(),
+				Post: &postspb.PostInput{Title: "Updated", Content: "Content", Author: "Jane Doe", ImageUrl: tc.imageURL},
+			})
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if updated.GetImageUrl() != tc.expectedImageURL {
+				t.Errorf("Expected image URL %q, got %q", tc.expectedImageURL, updated.GetImageUrl())
+			}
+		})
+	}
+}
+
+func TestGRPCServerErrors(t *testing.T) {
+	client := setupGRPCClient(t, NewPostService(setupTestRepository()))
+	ctx := context.Background()
+
+	testCases := []struct {
+		name         string
+		call         func() error
+		expectedCode codes.Code
+	}{
+		{
+			name: "GetById not found",
+			call: func() error {
+				_, err := client.GetById(ctx, &postspb.GetByIdRequest{Id: 99})
+				return err
+			},
+			expectedCode: codes.NotFound,
+		},
+		{
+			name: "Update not found",
+			call: func() error {
+				_, err := client.Update(ctx, &postspb.UpdateRequest{
+					Id:   99,
+					Post: &postspb.PostInput{Title: "Title", Content: "Content", Author: "Jane Doe"},
+				})
+				return err
+			},
+			expectedCode: codes.NotFound,
+		},
+		{
+			name: "Create invalid",
+			call: func() error {
+				_, err := client.Create(ctx, &postspb.PostInput{Title: "", Content: "Content", Author: "Jane Doe"})
+				return err
+			},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name: "Update invalid ID",
+			call: func() error {
+				_, err := client.Update(ctx, &postspb.UpdateRequest{
+					Id:   -1,
+					Post: &postspb.PostInput{Title: "Title", Content: "Content", Author: "Jane Doe"},
+				})
+				return err
+			},
+			expectedCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if code := status.Code(tc.call()); code != tc.expectedCode {
+				t.Errorf("Expected code %v, got %v", tc.expectedCode, code)
+			}
+		})
+	}
+}
+
+func TestGRPCServerReadOnly(t *testing.T) {
+	client := setupGRPCClient(t, NewPostService(setupTestRepository(), WithReadOnly(true)))
+	ctx := context.Background()
+
+	if _, err := client.GetById(ctx, &postspb.GetByIdRequest{Id: 1}); err != nil {
+		t.Fatalf("Expected reads to succeed, got %v", err)
+	}
+	_, err := client.Create(ctx, &postspb.PostInput{Title: "Title", Content: "Content", Author: "Jane Doe"})
+	if code := status.Code(err); code != codes.PermissionDenied {
+		t.Errorf("Expected code %v, got %v", codes.PermissionDenied, code)
+	}
+	_, err = client.Delete(ctx, &postspb.DeleteRequest{Id: 1})
+	if code := status.Code(err); code != codes.PermissionDenied {
+		t.Errorf("Expected code %v, got %v", codes.PermissionDenied, code)
+	}
+}
@@ -1,74 +1,421 @@
 package posts
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/go-playground/validator/v10"
+	"log/slog"
+	"mime"
 	"net/http"
+	"slices"
 	"strconv"
 	"strings"
 )
 
 type Handler struct {
-	service Service
+	service     Service
+	logger      *slog.Logger
+	pagination  PaginationConfig
+	strictQuery bool
+	basePath    string
+	defaultSort PostSort
+	feed        FeedConfig
+	stringIDs   bool
+	weakETags   bool
 }
 
-func NewHandler(service Service) *Handler {
-	return &Handler{
-		service: service,
+type HandlerOption func(*Handler)
+
+// WithLogger makes the handler log failures it cannot report to the client, such as
+// an export that breaks off mid-stream, to logger instead of the default logger.
+func WithLogger(logger *slog.Logger) HandlerOption {
+	return func(h *Handler) {
+		h.logger = logger
+	}
+}
+
+// WithPagination sets the default and maximum page sizes of GET /posts; sizes that are
+// not positive keep their defaults.
+func WithPagination(cfg PaginationConfig) HandlerOption {
+	return func(h *Handler) {
+		h.pagination = cfg.withDefaults()
+	}
+}
+
+// WithStrictQuery makes GET /posts answer query parameters it does not understand, such
+// as a misspelled ?limmit=10, with 400 instead of ignoring them.
+func WithStrictQuery(enabled bool) HandlerOption {
+	return func(h *Handler) {
+		h.strictQuery = enabled
+	}
+}
+
+// WithBasePath mounts the routes under prefix, e.g. "/api/v1", so that they become
+// /api/v1/posts and so on; links and Location headers in responses include it.
+func WithBasePath(prefix string) HandlerOption {
+	return func(h *Handler) {
+		h.basePath = strings.TrimSuffix(prefix, "/")
+	}
+}
+
+// WithDefaultSort sets the order of GET /posts when the request has no sort parameter,
+// e.g. PostSort{Field: SortByCreatedAt, Desc: true} for newest first. The default is
+// ID order.
+func WithDefaultSort(order PostSort) HandlerOption {
+	return func(h *Handler) {
+		h.defaultSort = order
+	}
+}
+
+// WithStringIDs encodes the IDs of posts in JSON responses as strings, e.g. "id": "42",
+// for clients that cannot represent every integer exactly. Posts are still stored and
+// addressed by integer IDs, and lists of bare IDs stay numeric.
+func WithStringIDs(enabled bool) HandlerOption {
+	return func(h *Handler) {
+		h.stringIDs = enabled
+	}
+}
+
+// WithWeakETags makes GET /posts/{id} carry weak ETags derived from a post's ID, update
+// time and content length instead of strong ETags hashing its content, which is cheaper
+// for large posts. Weak ETags still answer If-None-Match with 304. If-Match requires the
+// strong comparison, so PUT and PATCH keep sending the strong ETag of the post they wrote
+// for clients to pass back in If-Match.
+func WithWeakETags(enabled bool) HandlerOption {
+	return func(h *Handler) {
+		h.weakETags = enabled
+	}
+}
+
+// WithFeed sets the title, link and length of the RSS feed; an empty title and a
+// non-positive length keep their defaults.
+func WithFeed(cfg FeedConfig) HandlerOption {
+	return func(h *Handler) {
+		h.feed = cfg.withDefaults()
 	}
 }
 
+func NewHandler(service Service, opts ...HandlerOption) *Handler {
+	h := &Handler{
+		service:    service,
+		logger:     slog.Default(),
+		pagination: DefaultPaginationConfig(),
+		feed:       DefaultFeedConfig(),
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
 func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
-	mux.HandleFunc("/posts", func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case http.MethodGet:
-			h.GetAllPosts(w, r)
-		case http.MethodPost:
-			h.CreatePost(w, r)
-		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		}
+	handle := func(pattern string, handler http.HandlerFunc) {
+		mux.Handle(h.basePath+pattern, h.withRouteContext(handler))
+	}
+
+	handle("/posts", h.serveCollection)
+	handle("/authors", func(w http.ResponseWriter, r *http.Request) {
+		serveRead(w, r, h.GetAuthors)
 	})
 
-	mux.HandleFunc("/posts/", func(w http.ResponseWriter, r *http.Request) {
-		if r.URL.Path == "/posts/" || r.URL.Path == "/posts" {
+	handle("/posts/", func(w http.ResponseWriter, r *http.Request) {
+		segments, ok := postPathSegments(strings.TrimPrefix(r.URL.Path, h.basePath))
+		if !ok {
+			http.NotFound(w, r)
 			return
 		}
 
-		idStr := strings.TrimPrefix(r.URL.Path, "/posts/")
-
-		switch r.Method {
-		case http.MethodGet:
-			h.GetPostByID(w, r, idStr)
-		case http.MethodPut:
-			h.UpdatePost(w, r, idStr)
-		case http.MethodDelete:
-			h.DeletePost(w, r, idStr)
+		switch {
+		case len(segments) == 0:
+			h.serveCollection(w, r)
+		case len(segments) == 1 && segments[0] == "export":
+			serveRead(w, r, h.ExportPosts)
+		case len(segments) == 1 && segments[0] == "stats":
+			serveRead(w, r, h.GetStats)
+		case len(segments) == 1 && segments[0] == "recent":
+			serveRead(w, r, h.GetRecentPosts)
+		case len(segments) == 1 && segments[0] == "batch-get":
+			serveAction(w, r, h.BatchGetPosts)
+		case len(segments) == 1 && segments[0] == "feed.xml":
+			serveRead(w, r, h.GetFeed)
+		case len(segments) == 1 && segments[0] == "search":
+			serveRead(w, r, h.SearchPosts)
+		case len(segments) == 1 && segments[0] == "random":
+			serveRead(w, r, h.GetRandomPost)
+		case len(segments) == 1 && segments[0] == "validate":
+			serveAction(w, r, h.ValidatePost)
+		case len(segments) == 1 && segments[0] == "import":
+			serveAction(w, r, h.ImportPosts)
+		case len(segments) == 1:
+			h.serveItem(w, r, segments[0])
+		case len(segments) == 2 && segments[1] == "view":
+			serveAction(w, r, func(w http.ResponseWriter, r *http.Request) {
+				h.IncrementViews(w, r, segments[0])
+			})
+		case len(segments) == 2 && segments[1] == "neighbors":
+			serveRead(w, r, func(w http.ResponseWriter, r *http.Request) {
+				h.GetPostNeighbors(w, r, segments[0])
+			})
 		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			http.NotFound(w, r)
 		}
 	})
 }
 
+const (
+	collectionAllow = "GET, HEAD, POST, PATCH, DELETE, OPTIONS"
+	itemAllow       = "GET, HEAD, PUT, PATCH, DELETE, OPTIONS"
+	// readAllow is used by the read-only sub-routes such as /posts/stats and /authors.
+	readAllow = "GET, HEAD, OPTIONS"
+	// actionAllow is used by the POST-only sub-routes such as /posts/import and /posts/{id}/view.
+	actionAllow = "POST, OPTIONS"
+)
+
+func (h *Handler) serveCollection(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetAllPosts(w, r)
+	case http.MethodHead:
+		h.GetAllPosts(headResponseWriter{w}, r)
+	case http.MethodPost:
+		h.CreatePost(w, r)
+	case http.MethodPatch:
+		h.PatchPosts(w, r)
+	case http.MethodDelete:
+		h.DeletePosts(w, r)
+	case http.MethodOptions:
+		respondWithOptions(w, collectionAllow)
+	default:
+		methodNotAllowed(w, collectionAllow)
+	}
+}
+
+func (h *Handler) serveItem(w http.ResponseWriter, r *http.Request, idStr string) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetPostByID(w, r, idStr)
+	case http.MethodHead:
+		h.GetPostByID(headResponseWriter{w}, r, idStr)
+	case http.MethodPut:
+		h.UpdatePost(w, r, idStr)
+	case http.MethodPatch:
+		h.PatchPost(w, r, idStr)
+	case http.MethodDelete:
+		h.DeletePost(w, r, idStr)
+	case http.MethodOptions:
+		respondWithOptions(w, itemAllow)
+	default:
+		methodNotAllowed(w, itemAllow)
+	}
+}
+
+// serveRead serves a read-only route: GET through get, HEAD through get with the
+// body discarded, and OPTIONS.
+func serveRead(w http.ResponseWriter, r *http.Request, get http.HandlerFunc) {
+	switch r.Method {
+	case http.MethodGet:
+		get(w, r)
+	case http.MethodHead:
+		get(headResponseWriter{w}, r)
+	case http.MethodOptions:
+		respondWithOptions(w, readAllow)
+	default:
+		methodNotAllowed(w, readAllow)
+	}
+}
+
+// serveAction serves a route that only accepts POST, plus OPTIONS.
+func serveAction(w http.ResponseWriter, r *http.Request, post http.HandlerFunc) {
+	switch r.Method {
+	case http.MethodPost:
+		post(w, r)
+	case http.MethodOptions:
+		respondWithOptions(w, actionAllow)
+	default:
+		methodNotAllowed(w, actionAllow)
+	}
+}
+
+// headResponseWriter serves HEAD requests through the GET handlers: headers,
+// including Content-Length, are kept while the body is discarded.
+type headResponseWriter struct {
+	http.ResponseWriter
+}
+
+func (w headResponseWriter) Write(b []byte) (int, error) {
+	return len(b), nil
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer, so that
+// streaming handlers such as the NDJSON export can still flush.
+func (w headResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func respondWithOptions(w http.ResponseWriter, allow string) {
+	w.Header().Set("Allow", allow)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func methodNotAllowed(w http.ResponseWriter, allow string) {
+	w.Header().Set("Allow", allow)
+	http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+}
+
+const basePathKey contextKey = "basePath"
+
+// postETag returns the weak or strong ETag that GET /posts/{id} sends for post, as
+// configured with WithWeakETags.
+func (h *Handler) postETag(post PostRead) string {
+	if h.weakETags {
+		return weakPostETag(post)
+	}
+	return postETag(post)
+}
+
+// withRouteContext records the handler's base path and ID encoding in the request
+// context, where the functions writing responses find them through basePath and
+// wantsStringIDs.
+func (h *Handler) withRouteContext(next http.Handler) http.Handler {
+	if h.basePath == "" && !h.stringIDs {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if h.basePath != "" {
+			ctx = context.WithValue(ctx, basePathKey, h.basePath)
+		}
+		if h.stringIDs {
+			ctx = context.WithValue(ctx, stringIDsKey, true)
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// basePath returns the prefix the routes serving r are mounted under.
+func basePath(r *http.Request) string {
+	prefix, _ := r.Context().Value(basePathKey).(string)
+	return prefix
+}
+
+// postLocation returns the URL path of post id for a request to the routes serving r.
+func postLocation(r *http.Request, id int) string {
+	return basePath(r) + "/posts/" + strconv.Itoa(id)
+}
+
+// postPathSegments splits the path below /posts/ into segments, ignoring a single
+// trailing slash. It reports false for malformed paths containing empty segments.
+func postPathSegments(path string) ([]string, bool) {
+	rest := strings.TrimPrefix(path, "/posts/")
+	if rest == "" {
+		return nil, true
+	}
+
+	segments := strings.Split(strings.TrimSuffix(rest, "/"), "/")
+	for _, segment := range segments {
+		if segment == "" {
+			return nil, false
+		}
+	}
+	return segments, true
+}
+
 // GetAllPosts handles GET /posts
+//
+// Without limit or offset every post is returned as a plain array. With only offset,
+// pages hold the configured default number of posts. A limit above the configured
+// maximum is clamped to it, or answered with 400 if the handler was built with
+// PaginationConfig.RejectOverMax.
 // @Summary Get all posts
 // @Description Get a list of all blog posts
 // @Tags posts
 // @Accept json
 // @Produce json
+// @Produce application/vnd.api+json
+// @Param render query string false "Set to html to include content rendered from Markdown" Enums(html)
+// @Param fields query string false "Comma-separated fields to include, e.g. id,title"
+// @Param view query string false "Set to summary to return PostSummary items with a content excerpt" Enums(summary, full)
+// @Param stats query bool false "Set to true to include word_count and char_count computed from the content"
+// @Param limit query int false "Page size (default 20, max 100 unless configured otherwise); with limit or offset the response is a PostPage"
+// @Param offset query int false "Number of posts to skip, in ID order"
+// @Param envelope query bool false "Wrap the unpaginated list as {\"posts\": [...]}"
+// @Param createdAfter query string false "Only posts created at or after this RFC 3339 time"
+// @Param createdBefore query string false "Only posts created before this RFC 3339 time"
+// @Param sort query string false "Order by id, title, author, createdAt or updatedAt, descending with a leading -, e.g. -createdAt; ties are ordered by ID. Defaults to the configured sort, id unless set otherwise"
+// @Param If-Modified-Since header string false "Return 304 if the collection has not changed since this time"
 // @Success 200 {array} PostRead
+// @Success 200 {array} PostSummary
+// @Success 200 {object} PostList
+// @Success 200 {object} PostPage
+// @Success 304 "Not Modified"
+// @Failure 400 {object} string "Unknown field or view, invalid pagination, a limit above the maximum when configured to reject it, invalid date range, unknown sort, or an unknown query parameter in strict mode"
 // @Failure 500 {object} string "Internal Server Error"
 // @Router /posts [get]
 func (h *Handler) GetAllPosts(w http.ResponseWriter, r *http.Request) {
-	posts, err := h.service.GetAllPosts()
+	if err := checkQueryParams(r, h.strictQuery, listQueryParams); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if _, err := parseFields(r); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if _, err := wantsSummary(r); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	page, paginated, err := parsePagination(r, h.pagination)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	created, err := parseCreatedRange(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	order, err := parseSort(r, h.defaultSort)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if paginated {
+		posts, total, err := h.service.ListPosts(r.Context(), ListParams{Limit: page.Limit, Offset: page.Offset, Created: created, Sort: order})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		page.Total = total
+
+		if checkNotModified(w, r, "", latestUpdate(posts)) {
+			return
+		}
+
+		respondWithPostPage(w, r, http.StatusOK, posts, page)
+		return
+	}
+
+	var posts []PostRead
+	if created.IsZero() {
+		posts, err = h.service.GetAllPosts(r.Context())
+		sortPosts(posts, order)
+	} else {
+		posts, _, err = h.service.ListPosts(r.Context(), ListParams{Created: created, Sort: order})
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	respondWithJSON(w, http.StatusOK, posts)
+	if checkNotModified(w, r, "", latestUpdate(posts)) {
+		return
+	}
+
+	respondWithPosts(w, r, http.StatusOK, posts)
 }
 
 // GetPostByID handles GET /posts/{id}
@@ -77,20 +424,45 @@ func (h *Handler) GetAllPosts(w http.ResponseWriter, r *http.Request) {
 // @Tags posts
 // @Accept json
 // @Produce json
+// @Produce application/vnd.api+json
 // @Param id path int true "Post ID"
+// @Param render query string false "Set to html to include content rendered from Markdown" Enums(html)
+// @Param fields query string false "Comma-separated fields to include, e.g. id,title"
+// @Param excerpt query int false "Cut content to this many characters and set truncated when it was longer"
+// @Param excerptMode query string false "Set to sentence to end an excerpt at the last sentence end, or else the last whole word, within the limit" Enums(chars, sentence)
+// @Param stats query bool false "Set to true to include word_count and char_count computed from the content"
+// @Param If-None-Match header string false "Return 304 if the post's ETag is one of these; takes precedence over If-Modified-Since"
+// @Param If-Modified-Since header string false "Return 304 if the post has not changed since this time"
 // @Success 200 {object} PostRead
-// @Failure 400 {object} string "Invalid post ID"
+// @Header 200 {string} ETag "The post's ETag, for If-None-Match and, unless weak, for If-Match on PATCH"
+// @Success 304 "Not Modified"
+// @Failure 400 {object} string "Invalid post ID, unknown field, invalid excerpt length or unknown excerpt mode"
 // @Failure 404 {object} string "Post not found"
 // @Failure 500 {object} string "Internal Server Error"
 // @Router /posts/{id} [get]
 func (h *Handler) GetPostByID(w http.ResponseWriter, r *http.Request, idStr string) {
+	if _, err := parseFields(r); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	excerptLength, err := parseExcerpt(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	mode, err := parseExcerptMode(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
 		http.Error(w, "Invalid post ID", http.StatusBadRequest)
 		return
 	}
 
-	post, err := h.service.GetPostByID(id)
+	post, err := h.service.GetPostByID(r.Context(), id)
 	if err != nil {
 		if errors.Is(err, ErrPostNotFound) {
 			http.Error(w, err.Error(), http.StatusNotFound)
@@ -102,7 +474,20 @@ func (h *Handler) GetPostByID(w http.ResponseWriter, r *http.Request, idStr stri
 		return
 	}
 
-	respondWithJSON(w, http.StatusOK, post)
+	etag := h.postETag(post)
+	w.Header().Set("ETag", etag)
+	if checkNotModified(w, r, etag, post.UpdatedAt) {
+		return
+	}
+
+	// The stats describe the whole post, not the excerpt.
+	if wantsContentStats(r) {
+		post = withContentStats(post)
+	}
+	if excerptLength > 0 {
+		post.Content, post.Truncated = mode.truncate(post.Content, excerptLength)
+	}
+	respondWithPost(w, r, http.StatusOK, post)
 }
 
 // CreatePost handles POST /posts
@@ -111,26 +496,34 @@ func (h *Handler) GetPostByID(w http.ResponseWriter, r *http.Request, idStr stri
 // @Tags posts
 // @Accept json
 // @Produce json
+// @Produce application/vnd.api+json
 // @Param post body PostCreateUpdate true "Post data"
+// @Param Idempotency-Key header string false "Key making retried creates return the original post"
 // @Success 201 {object} PostRead
-// @Failure 400 {object} string "Invalid request body or validation error"
+// @Header 201 {string} Location "URL of the new post"
+// @Failure 400 {object} validationErrorResponse "Invalid request body or validation error"
+// @Failure 409 {object} string "A post with the same title and author exists"
+// @Failure 422 {object} string "The idempotency key was already used with a different body"
+// @Failure 507 {object} string "The repository is at capacity"
 // @Router /posts [post]
 func (h *Handler) CreatePost(w http.ResponseWriter, r *http.Request) {
 	var req PostCreateUpdate
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+	if err := decodeJSON(r, &req); err != nil {
+		respondWithBodyError(w, r, err)
 		return
 	}
 
-	post, err := h.service.CreatePost(req)
+	var post PostRead
+	var err error
+	if key := r.Header.Get(IdempotencyKeyHeader); key != "" {
+		post, err = h.service.CreatePostIdempotent(r.Context(), key, req)
+	} else {
+		post, err = h.service.CreatePost(r.Context(), req)
+	}
 	if err != nil {
-		var validationErrors validator.ValidationErrors
-		if errors.As(err, &validationErrors) {
-			errorMessages := make([]string, len(validationErrors))
-			for i, fieldError := range validationErrors {
-				errorMessages[i] = fmt.Sprintf("Field validation for '%s' failed on the '%s' tag", fieldError.Field(), fieldError.Tag())
-			}
-			http.Error(w, fmt.Sprintf("Validation failed: %s", strings.Join(errorMessages, "; ")), http.StatusBadRequest)
+		var validationError ValidationError
+		if errors.As(err, &validationError) {
+			respondWithJSON(w, r, http.StatusBadRequest, validationErrorResponse{Error: validationError.Error(), Errors: validationError})
 			return
 		}
 
@@ -140,24 +533,77 @@ func (h *Handler) CreatePost(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		if errors.Is(err, ErrDuplicatePost) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+
+		if errors.Is(err, ErrIdempotencyKeyReused) {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+
+		if errors.Is(err, ErrCapacityExceeded) {
+			http.Error(w, err.Error(), http.StatusInsufficientStorage)
+			return
+		}
+
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	respondWithJSON(w, http.StatusCreated, post)
+	w.Header().Set("Location", postLocation(r, post.ID))
+	respondWithPost(w, r, http.StatusCreated, post)
+}
+
+// ValidatePost handles POST /posts/validate
+// @Summary Validate a post without saving it
+// @Description Run the checks a create would run, after the same normalization, without storing anything
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Param post body PostCreateUpdate true "Post data"
+// @Success 200 {object} ValidationResult
+// @Failure 400 {object} validationErrorResponse "Invalid request body or validation error"
+// @Router /posts/validate [post]
+func (h *Handler) ValidatePost(w http.ResponseWriter, r *http.Request) {
+	var req PostCreateUpdate
+	if err := decodeJSON(r, &req); err != nil {
+		respondWithBodyError(w, r, err)
+		return
+	}
+
+	if err := h.service.ValidatePost(r.Context(), req); err != nil {
+		var validationError ValidationError
+		if errors.As(err, &validationError) {
+			respondWithJSON(w, r, http.StatusBadRequest, validationErrorResponse{Error: validationError.Error(), Errors: validationError})
+			return
+		}
+
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJSON(w, r, http.StatusOK, ValidationResult{Valid: true})
 }
 
 // UpdatePost handles PUT /posts/{id}
-// @Summary Update a post
-// @Description Update an existing blog post
+// @Summary Create or replace a post
+// @Description Replace the blog post with the given ID, creating it if it does not exist. With If-Unmodified-Since the post must already exist.
 // @Tags posts
 // @Accept json
 // @Produce json
+// @Produce application/vnd.api+json
 // @Param id path int true "Post ID"
 // @Param post body PostCreateUpdate true "Updated post data"
-// @Success 200 {object} PostRead
-// @Failure 400 {object} string "Invalid post ID or request body"
+// @Param If-Unmodified-Since header string false "Only update if the post has not changed since this time"
+// @Success 200 {object} PostRead "Post replaced"
+// @Success 201 {object} PostRead "Post created"
+// @Header 201 {string} Location "URL of the new post"
+// @Failure 400 {object} validationErrorResponse "Invalid post ID, request body or validation error"
 // @Failure 404 {object} string "Post not found"
+// @Failure 412 {object} string "Post modified since If-Unmodified-Since"
+// @Failure 507 {object} string "The repository is at capacity"
 // @Router /posts/{id} [put]
 func (h *Handler) UpdatePost(w http.ResponseWriter, r *http.Request, idStr string) {
 	id, err := strconv.Atoi(idStr)
@@ -167,25 +613,31 @@ func (h *Handler) UpdatePost(w http.ResponseWriter, r *http.Request, idStr strin
 	}
 
 	var req PostCreateUpdate
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+	if err := decodeJSON(r, &req); err != nil {
+		respondWithBodyError(w, r, err)
 		return
 	}
 
-	post, err := h.service.UpdatePost(id, req)
+	var post PostRead
+	var created bool
+	if since, ok := ifUnmodifiedSince(r); ok {
+		post, err = h.service.UpdatePostIfUnmodified(r.Context(), id, req, since)
+	} else {
+		post, created, err = h.service.UpsertPost(r.Context(), id, req)
+	}
 	if err != nil {
 		if errors.Is(err, ErrPostNotFound) {
 			http.Error(w, err.Error(), http.StatusNotFound)
 			return
 		}
+		if errors.Is(err, ErrPreconditionFailed) {
+			http.Error(w, err.Error(), http.StatusPreconditionFailed)
+			return
+		}
 
-		var validationErrors validator.ValidationErrors
-		if errors.As(err, &validationErrors) {
-			errorMessages := make([]string, len(validationErrors))
-			for i, fieldError := range validationErrors {
-				errorMessages[i] = fmt.Sprintf("Field validation for '%s' failed on the '%s' tag", fieldError.Field(), fieldError.Tag())
-			}
-			http.Error(w, fmt.Sprintf("Validation failed: %s", strings.Join(errorMessages, "; ")), http.StatusBadRequest)
+		var validationError ValidationError
+		if errors.As(err, &validationError) {
+			respondWithJSON(w, r, http.StatusBadRequest, validationErrorResponse{Error: validationError.Error(), Errors: validationError})
 			return
 		}
 
@@ -195,11 +647,113 @@ func (h *Handler) UpdatePost(w http.ResponseWriter, r *http.Request, idStr strin
 			return
 		}
 
+		if errors.Is(err, ErrCapacityExceeded) {
+			http.Error(w, err.Error(), http.StatusInsufficientStorage)
+			return
+		}
+
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("ETag", postETag(post))
+	if created {
+		w.Header().Set("Location", postLocation(r, post.ID))
+		respondWithPost(w, r, http.StatusCreated, post)
+		return
+	}
+	respondWithPost(w, r, http.StatusOK, post)
+}
+
+// PatchPost handles PATCH /posts/{id}
+// @Summary Partially update a post
+// @Description Set the fields present in the body on the post with the given ID. Without If-Match the patch is applied to the current version.
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Produce application/vnd.api+json
+// @Param id path int true "Post ID"
+// @Param post body PostPatch true "Fields to change"
+// @Param If-Match header string false "Only update if the post's ETag is one of these, or * for any"
+// @Success 200 {object} PostRead
+// @Header 200 {string} ETag "The strong ETag of the updated post, for If-Match"
+// @Failure 400 {object} validationErrorResponse "Invalid post ID, request body or validation error"
+// @Failure 404 {object} string "Post not found"
+// @Failure 412 {object} string "The post's ETag does not match If-Match"
+// @Router /posts/{id} [patch]
+func (h *Handler) PatchPost(w http.ResponseWriter, r *http.Request, idStr string) {
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		http.Error(w, "Invalid post ID", http.StatusBadRequest)
+		return
+	}
+
+	var req PostPatch
+	if err := decodeJSON(r, &req); err != nil {
+		respondWithBodyError(w, r, err)
+		return
+	}
+
+	post, err := h.service.PatchPost(r.Context(), id, req, r.Header.Get("If-Match"))
+	if err != nil {
+		if errors.Is(err, ErrPostNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, ErrPreconditionFailed) {
+			http.Error(w, err.Error(), http.StatusPreconditionFailed)
+			return
+		}
+
+		var validationError ValidationError
+		if errors.As(err, &validationError) {
+			respondWithJSON(w, r, http.StatusBadRequest, validationErrorResponse{Error: validationError.Error(), Errors: validationError})
+			return
+		}
+
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	respondWithJSON(w, http.StatusOK, post)
+	w.Header().Set("ETag", postETag(post))
+	respondWithPost(w, r, http.StatusOK, post)
+}
+
+// PatchPosts handles PATCH /posts
+// @Summary Partially update several posts
+// @Description Apply the same partial update to every listed post and report which were not found. If any patched post is invalid, none is changed.
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Param request body BulkPatchRequest true "Post IDs and the fields to change"
+// @Success 200 {object} BulkPatchResult
+// @Failure 400 {object} string "Invalid or missing post IDs, or invalid input"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /posts [patch]
+func (h *Handler) PatchPosts(w http.ResponseWriter, r *http.Request) {
+	var req BulkPatchRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondWithBodyError(w, r, err)
+		return
+	}
+
+	result, err := h.service.PatchPosts(r.Context(), req.IDs, req.Patch)
+	if err != nil {
+		var validationError ValidationError
+		if errors.As(err, &validationError) {
+			respondWithJSON(w, r, http.StatusBadRequest, validationErrorResponse{Error: err.Error(), Errors: validationError})
+			return
+		}
+
+		if errors.Is(err, ErrNoPostIDs) || errors.Is(err, InvalidPostIDError) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		} else {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	respondWithJSON(w, r, http.StatusOK, result)
 }
 
 // DeletePost handles DELETE /posts/{id}
@@ -209,9 +763,11 @@ func (h *Handler) UpdatePost(w http.ResponseWriter, r *http.Request, idStr strin
 // @Accept json
 // @Produce json
 // @Param id path int true "Post ID"
+// @Param If-Unmodified-Since header string false "Only delete if the post has not changed since this time"
 // @Success 204 "No Content"
 // @Failure 400 {object} string "Invalid post ID"
 // @Failure 404 {object} string "Post not found"
+// @Failure 412 {object} string "Post modified since If-Unmodified-Since"
 // @Failure 500 {object} string "Internal Server Error"
 // @Router /posts/{id} [delete]
 func (h *Handler) DeletePost(w http.ResponseWriter, r *http.Request, idStr string) {
@@ -221,20 +777,427 @@ func (h *Handler) DeletePost(w http.ResponseWriter, r *http.Request, idStr strin
 		return
 	}
 
-	err = h.service.DeletePost(id)
+	if since, ok := ifUnmodifiedSince(r); ok {
+		err = h.service.DeletePostIfUnmodified(r.Context(), id, since)
+	} else {
+		err = h.service.DeletePost(r.Context(), id)
+	}
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		if errors.Is(err, ErrPreconditionFailed) {
+			http.Error(w, err.Error(), http.StatusPreconditionFailed)
+		} else if errors.Is(err, ErrPostNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+		} else if errors.Is(err, InvalidPostIDError) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		} else {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
 
-func respondWithJSON(w http.ResponseWriter, status int, data interface{}) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	err := json.NewEncoder(w).Encode(data)
+// DeletePosts handles DELETE /posts
+// @Summary Delete several posts
+// @Description Delete the posts listed in the body or in the ids query parameter and report which were not found
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Param ids query string false "Comma-separated post IDs, used when there is no body"
+// @Param request body BulkDeleteRequest false "Post IDs"
+// @Success 200 {object} BulkDeleteResult
+// @Failure 400 {object} string "Invalid or missing post IDs"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /posts [delete]
+func (h *Handler) DeletePosts(w http.ResponseWriter, r *http.Request) {
+	ids, err := bulkDeleteIDs(r)
+	if errors.Is(err, ErrInvalidBody) || errors.Is(err, ErrEmptyBody) {
+		respondWithBodyError(w, r, err)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.service.DeletePosts(r.Context(), ids)
+	if err != nil {
+		if errors.Is(err, ErrNoPostIDs) || errors.Is(err, InvalidPostIDError) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		} else {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	respondWithJSON(w, r, http.StatusOK, result)
+}
+
+// BatchGetPosts handles POST /posts/batch-get
+// @Summary Get several posts by ID
+// @Description Get the posts whose IDs are listed in the body, for lists too long for a query string. Posts are returned in the order their IDs are first listed; repeated IDs are returned once and missing ones are left out. At most 1000 distinct IDs may be given.
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Param request body BatchGetRequest true "Post IDs"
+// @Success 200 {array} PostRead
+// @Failure 400 {object} string "Invalid, missing or too many post IDs"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /posts/batch-get [post]
+func (h *Handler) BatchGetPosts(w http.ResponseWriter, r *http.Request) {
+	var req BatchGetRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondWithBodyError(w, r, err)
+		return
+	}
+
+	posts, err := h.service.GetPostsByIDs(r.Context(), req.IDs)
+	if err != nil {
+		if errors.Is(err, ErrNoPostIDs) || errors.Is(err, InvalidPostIDError) || errors.Is(err, ErrTooManyPostIDs) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		} else {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	respondWithPosts(w, r, http.StatusOK, posts)
+}
+
+// bulkDeleteIDs reads the IDs from the ids query parameter or, if it is absent, from the JSON body.
+func bulkDeleteIDs(r *http.Request) ([]int, error) {
+	if raw := r.URL.Query().Get("ids"); raw != "" {
+		parts := strings.Split(raw, ",")
+		ids := make([]int, len(parts))
+		for i, part := range parts {
+			id, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil {
+				return nil, fmt.Errorf("invalid post ID %q", part)
+			}
+			ids[i] = id
+		}
+		return ids, nil
+	}
+
+	var req BulkDeleteRequest
+	if err := decodeJSON(r, &req); err != nil {
+		return nil, err
+	}
+	return req.IDs, nil
+}
+
+// IncrementViews handles POST /posts/{id}/view
+// @Summary Register a post view
+// @Description Increment the view counter of a blog post and return the new count
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Param id path int true "Post ID"
+// @Success 200 {object} PostViews
+// @Failure 400 {object} string "Invalid post ID"
+// @Failure 404 {object} string "Post not found"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /posts/{id}/view [post]
+func (h *Handler) IncrementViews(w http.ResponseWriter, r *http.Request, idStr string) {
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		http.Error(w, "Invalid post ID", http.StatusBadRequest)
+		return
+	}
+
+	views, err := h.service.IncrementViews(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, ErrPostNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+		} else if errors.Is(err, InvalidPostIDError) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		} else {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	respondWithJSON(w, r, http.StatusOK, PostViews{ID: id, Views: views})
+}
+
+// GetStats handles GET /posts/stats
+// @Summary Get post statistics
+// @Description Get the total number of posts and the number of posts per author, sorted by count descending
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Param prefix query string false "Only count authors whose name starts with this, ignoring case"
+// @Param limit query int false "Number of authors per page; with limit or offset the response includes author_count and links"
+// @Param offset query int false "Number of authors to skip"
+// @Success 200 {object} PostStats
+// @Failure 400 {object} string "Invalid pagination"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /posts/stats [get]
+func (h *Handler) GetStats(w http.ResponseWriter, r *http.Request) {
+	page, paginated, err := parsePagination(r, h.pagination)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	stats, err := h.service.Stats(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if prefix := r.URL.Query().Get("prefix"); prefix != "" {
+		stats.Authors = slices.DeleteFunc(stats.Authors, func(a AuthorStats) bool { return !hasPrefixFold(a.Author, prefix) })
+		stats.Total = 0
+		for _, a := range stats.Authors {
+			stats.Total += a.Count
+		}
+	}
+	if paginated {
+		page.Total = len(stats.Authors)
+		links := paginationLinks(r.URL, page)
+		stats.Authors = pageOf(stats.Authors, page)
+		stats.AuthorCount = page.Total
+		stats.Links = &links
+	}
+
+	respondWithJSON(w, r, http.StatusOK, stats)
+}
+
+// GetAuthors handles GET /authors
+// @Summary List authors
+// @Description Get the distinct authors of all posts in alphabetical order
+// @Tags authors
+// @Produce json
+// @Param prefix query string false "Only authors whose name starts with this, ignoring case"
+// @Param limit query int false "Page size; with limit or offset the response is an AuthorPage"
+// @Param offset query int false "Number of authors to skip"
+// @Success 200 {array} string
+// @Success 200 {object} AuthorPage
+// @Failure 400 {object} string "Invalid pagination"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /authors [get]
+func (h *Handler) GetAuthors(w http.ResponseWriter, r *http.Request) {
+	page, paginated, err := parsePagination(r, h.pagination)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	authors, err := h.service.Authors(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if prefix := r.URL.Query().Get("prefix"); prefix != "" {
+		authors = slices.DeleteFunc(authors, func(author string) bool { return !hasPrefixFold(author, prefix) })
+	}
+	if paginated {
+		page.Total = len(authors)
+		respondWithJSON(w, r, http.StatusOK, AuthorPage{
+			Data:  emptyIfNil(pageOf(authors, page)),
+			Total: page.Total,
+			Links: paginationLinks(r.URL, page),
+		})
+		return
+	}
+
+	respondWithJSON(w, r, http.StatusOK, emptyIfNil(authors))
+}
+
+const defaultRecentPosts = 10
+
+// GetRecentPosts handles GET /posts/recent
+// @Summary Get the most recent posts
+// @Description Get the n most recently created posts, newest first. n is clamped to at most 50.
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Produce application/vnd.api+json
+// @Param n query int false "Number of posts to return (default 10, max 50)"
+// @Param envelope query bool false "Wrap the list as {\"posts\": [...]}"
+// @Success 200 {array} PostRead
+// @Success 200 {object} PostList
+// @Failure 400 {object} string "Invalid n"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /posts/recent [get]
+func (h *Handler) GetRecentPosts(w http.ResponseWriter, r *http.Request) {
+	n := defaultRecentPosts
+	if raw := r.URL.Query().Get("n"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 {
+			http.Error(w, "n must be a positive integer", http.StatusBadRequest)
+			return
+		}
+		n = parsed
+	}
+
+	posts, err := h.service.GetRecentPosts(r.Context(), n)
 	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+
+	respondWithPosts(w, r, http.StatusOK, posts)
+}
+
+// GetRandomPost handles GET /posts/random
+// @Summary Get a random post
+// @Description Get one randomly selected blog post
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Produce application/vnd.api+json
+// @Success 200 {object} PostRead
+// @Failure 404 {object} string "No posts"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /posts/random [get]
+func (h *Handler) GetRandomPost(w http.ResponseWriter, r *http.Request) {
+	post, err := h.service.GetRandomPost(r.Context())
+	if err != nil {
+		if errors.Is(err, ErrPostNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+		} else {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	respondWithPost(w, r, http.StatusOK, post)
+}
+
+// SearchPosts handles GET /posts/search
+// @Summary Search posts
+// @Description Get the posts whose title or content contains q, in ID order. Case is ignored, and so are accents unless strictAccents is set, so that "cafe" matches "café".
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Produce application/vnd.api+json
+// @Param q query string true "Text to search for"
+// @Param strictAccents query bool false "Set to true to match accented letters only with themselves"
+// @Param envelope query bool false "Wrap the list as {\"posts\": [...]}"
+// @Success 200 {array} PostRead
+// @Success 200 {object} PostList
+// @Failure 400 {object} string "Missing q or invalid strictAccents"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /posts/search [get]
+func (h *Handler) SearchPosts(w http.ResponseWriter, r *http.Request) {
+	query, err := parseSearchQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	posts, err := h.service.SearchPosts(r.Context(), query)
+	if err != nil {
+		if errors.Is(err, ErrEmptySearch) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		} else {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	respondWithPosts(w, r, http.StatusOK, posts)
+}
+
+// GetPostNeighbors handles GET /posts/{id}/neighbors
+// @Summary Get the previous and next posts
+// @Description Get the posts before and after a post in ID order, which is also creation order; prev or next is null at either end
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Param id path int true "Post ID"
+// @Success 200 {object} PostNeighbors
+// @Failure 400 {object} string "Invalid post ID"
+// @Failure 404 {object} string "Post not found"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /posts/{id}/neighbors [get]
+func (h *Handler) GetPostNeighbors(w http.ResponseWriter, r *http.Request, idStr string) {
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		http.Error(w, "Invalid post ID", http.StatusBadRequest)
+		return
+	}
+
+	neighbors, err := h.service.GetPostNeighbors(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, ErrPostNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+		} else if errors.Is(err, InvalidPostIDError) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		} else {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	respondWithJSON(w, r, http.StatusOK, neighbors)
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+type validationErrorResponse struct {
+	Error  string          `json:"error"`
+	Errors ValidationError `json:"errors"`
+}
+
+// emptyIfNil returns s, or an empty slice when s is nil, so that an empty list is
+// encoded as [] rather than null.
+func emptyIfNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
+
+// respondWithJSON encodes data before committing the status so that an encoding
+// failure results in a 500 instead of a truncated body with a success status.
+func respondWithJSON(w http.ResponseWriter, r *http.Request, status int, data interface{}) {
+	if wantsStringIDs(r) {
+		data = withStringIDs(data)
+	}
+	writeJSON(w, r, status, "application/json", data)
+}
+
+// writeJSON is respondWithJSON with a given content type. The body is indented when
+// the request asks for it with wantsPrettyJSON.
+func writeJSON(w http.ResponseWriter, r *http.Request, status int, contentType string, data interface{}) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	if wantsPrettyJSON(r) {
+		encoder.SetIndent("", "  ")
+	}
+	if err := encoder.Encode(data); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(errorResponse{Error: "failed to encode response"})
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+	w.WriteHeader(status)
+	buf.WriteTo(w)
+}
+
+// wantsPrettyJSON reports whether the client asked for indented JSON with ?pretty=true
+// or a pretty=true parameter on an Accept media type, e.g. "application/json; pretty=true".
+func wantsPrettyJSON(r *http.Request) bool {
+	if r == nil {
+		return false
+	}
+	if pretty, err := strconv.ParseBool(r.URL.Query().Get("pretty")); err == nil {
+		return pretty
+	}
+	for _, accepted := range strings.Split(r.Header.Get("Accept"), ",") {
+		if _, params, err := mime.ParseMediaType(accepted); err == nil && params["pretty"] == "true" {
+			return true
+		}
+	}
+	return false
 }
@@ -2,41 +2,143 @@ package posts
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 type MockService struct {
-	GetAllPostsFn func() ([]PostRead, error)
-	GetPostByIDFn func(id int) (PostRead, error)
-	CreatePostFn  func(req PostCreateUpdate) (PostRead, error)
-	UpdatePostFn  func(id int, req PostCreateUpdate) (PostRead, error)
-	DeletePostFn  func(id int) error
+	GetAllPostsFn            func() ([]PostRead, error)
+	GetPostByIDFn            func(id int) (PostRead, error)
+	GetPostsByIDsFn          func(ids []int) ([]PostRead, error)
+	CreatePostFn             func(req PostCreateUpdate) (PostRead, error)
+	UpdatePostFn             func(id int, req PostCreateUpdate) (PostRead, error)
+	DeletePostFn             func(id int) error
+	IncrementViewsFn         func(id int) (int, error)
+	CreatePostIdempotentFn   func(key string, req PostCreateUpdate) (PostRead, error)
+	ImportPostsFn            func(r io.Reader, atomic bool) (ImportResult, error)
+	StatsFn                  func() (PostStats, error)
+	AuthorsFn                func() ([]string, error)
+	GetRecentPostsFn         func(n int) ([]PostRead, error)
+	GetRandomPostFn          func() (PostRead, error)
+	GetPostNeighborsFn       func(id int) (PostNeighbors, error)
+	SearchPostsFn            func(query SearchQuery) ([]PostRead, error)
+	DeletePostsFn            func(ids []int) (BulkDeleteResult, error)
+	ListPostsFn              func(params ListParams) ([]PostRead, int, error)
+	IteratePostsFn           func(fn func(post PostRead) error) error
+	UpdatePostIfUnmodifiedFn func(id int, req PostCreateUpdate, since time.Time) (PostRead, error)
+	DeletePostIfUnmodifiedFn func(id int, since time.Time) error
+	UpsertPostFn             func(id int, req PostCreateUpdate) (PostRead, bool, error)
+	PatchPostFn              func(id int, patch PostPatch, ifMatch string) (PostRead, error)
+	PatchPostsFn             func(ids []int, patch PostPatch) (BulkPatchResult, error)
+	ValidatePostFn           func(req PostCreateUpdate) error
 }
 
-func (m *MockService) GetAllPosts() ([]PostRead, error) {
+func (m *MockService) GetAllPosts(ctx context.Context) ([]PostRead, error) {
 	return m.GetAllPostsFn()
 }
 
-func (m *MockService) GetPostByID(id int) (PostRead, error) {
+func (m *MockService) GetPostByID(ctx context.Context, id int) (PostRead, error) {
 	return m.GetPostByIDFn(id)
 }
 
-func (m *MockService) CreatePost(req PostCreateUpdate) (PostRead, error) {
+func (m *MockService) GetPostsByIDs(ctx context.Context, ids []int) ([]PostRead, error) {
+	return m.GetPostsByIDsFn(ids)
+}
+
+func (m *MockService) CreatePost(ctx context.Context, req PostCreateUpdate) (PostRead, error) {
 	return m.CreatePostFn(req)
 }
 
-func (m *MockService) UpdatePost(id int, req PostCreateUpdate) (PostRead, error) {
+func (m *MockService) CreatePostIdempotent(ctx context.Context, key string, req PostCreateUpdate) (PostRead, error) {
+	return m.CreatePostIdempotentFn(key, req)
+}
+
+func (m *MockService) ImportPosts(ctx context.Context, r io.Reader, atomic bool) (ImportResult, error) {
+	return m.ImportPostsFn(r, atomic)
+}
+
+func (m *MockService) Stats(ctx context.Context) (PostStats, error) {
+	return m.StatsFn()
+}
+
+func (m *MockService) Authors(ctx context.Context) ([]string, error) {
+	return m.AuthorsFn()
+}
+
+func (m *MockService) DeletePosts(ctx context.Context, ids []int) (BulkDeleteResult, error) {
+	return m.DeletePostsFn(ids)
+}
+
+func (m *MockService) ListPosts(ctx context.Context, params ListParams) ([]PostRead, int, error) {
+	return m.ListPostsFn(params)
+}
+
+func (m *MockService) IteratePosts(ctx context.Context, fn func(post PostRead) error) error {
+	return m.IteratePostsFn(fn)
+}
+
+func (m *MockService) UpdatePostIfUnmodified(ctx context.Context, id int, req PostCreateUpdate, since time.Time) (PostRead, error) {
+	return m.UpdatePostIfUnmodifiedFn(id, req, since)
+}
+
+func (m *MockService) PatchPost(ctx context.Context, id int, patch PostPatch, ifMatch string) (PostRead, error) {
+	return m.PatchPostFn(id, patch, ifMatch)
+}
+
+func (m *MockService) PatchPosts(ctx context.Context, ids []int, patch PostPatch) (BulkPatchResult, error) {
+	return m.PatchPostsFn(ids, patch)
+}
+
+func (m *MockService) DeletePostIfUnmodified(ctx context.Context, id int, since time.Time) error {
+	return m.DeletePostIfUnmodifiedFn(id, since)
+}
+
+func (m *MockService) GetRandomPost(ctx context.Context) (PostRead, error) {
+	return m.GetRandomPostFn()
+}
+
+func (m *MockService) GetPostNeighbors(ctx context.Context, id int) (PostNeighbors, error) {
+	return m.GetPostNeighborsFn(id)
+}
+
+func (m *MockService) SearchPosts(ctx context.Context, query SearchQuery) ([]PostRead, error) {
+	return m.SearchPostsFn(query)
+}
+
+func (m *MockService) GetRecentPosts(ctx context.Context, n int) ([]PostRead, error) {
+	return m.GetRecentPostsFn(n)
+}
+
+func (m *MockService) UpdatePost(ctx context.Context, id int, req PostCreateUpdate) (PostRead, error) {
 	return m.UpdatePostFn(id, req)
 }
 
-func (m *MockService) DeletePost(id int) error {
+func (m *MockService) UpsertPost(ctx context.Context, id int, req PostCreateUpdate) (PostRead, bool, error) {
+	return m.UpsertPostFn(id, req)
+}
+
+func (m *MockService) ValidatePost(ctx context.Context, req PostCreateUpdate) error {
+	return m.ValidatePostFn(req)
+}
+
+func (m *MockService) DeletePost(ctx context.Context, id int) error {
 	return m.DeletePostFn(id)
 }
 
+func (m *MockService) IncrementViews(ctx context.Context, id int) (int, error) {
+	return m.IncrementViewsFn(id)
+}
+
 var testPosts = []PostRead{
 	{ID: 1, Title: "Test Post 1", Content: "Content 1", Author: "Author 1"},
 	{ID: 2, Title: "Test Post 2", Content: "Content 2", Author: "Author 2"},
@@ -281,6 +383,19 @@ func TestCreatePost(t *testing.T) {
 			expectedStatus: http.StatusBadRequest,
 			expectedBody:   nil,
 		},
+		{
+			name: "Capacity Exceeded",
+			requestBody: PostCreateUpdate{
+				Title:   "New Post",
+				Content: "New Content",
+				Author:  "New Author",
+			},
+			mockCreateFn: func(req PostCreateUpdate) (PostRead, error) {
+				return PostRead{}, ErrCapacityExceeded
+			},
+			expectedStatus: http.StatusInsufficientStorage,
+			expectedBody:   nil,
+		},
 	}
 
 	for _, tc := range tests {
@@ -332,7 +447,7 @@ func TestUpdatePost(t *testing.T) {
 		name           string
 		postID         string
 		requestBody    interface{}
-		mockUpdateFn   func(id int, req PostCreateUpdate) (PostRead, error)
+		mockUpsertFn   func(id int, req PostCreateUpdate) (PostRead, bool, error)
 		expectedStatus int
 		expectedBody   *PostRead
 	}{
@@ -344,13 +459,13 @@ func TestUpdatePost(t *testing.T) {
 				Content: "Updated Content",
 				Author:  "Updated Author",
 			},
-			mockUpdateFn: func(id int, req PostCreateUpdate) (PostRead, error) {
+			mockUpsertFn: func(id int, req PostCreateUpdate) (PostRead, bool, error) {
 				return PostRead{
 					ID:      id,
 					Title:   req.Title,
 					Content: req.Content,
 					Author:  req.Author,
-				}, nil
+				}, false, nil
 			},
 			expectedStatus: http.StatusOK,
 			expectedBody: &PostRead{
@@ -368,8 +483,8 @@ func TestUpdatePost(t *testing.T) {
 				Content: "Updated Content",
 				Author:  "Updated Author",
 			},
-			mockUpdateFn: func(id int, req PostCreateUpdate) (PostRead, error) {
-				return PostRead{}, nil
+			mockUpsertFn: func(id int, req PostCreateUpdate) (PostRead, bool, error) {
+				return PostRead{}, false, nil
 			},
 			expectedStatus: http.StatusBadRequest,
 			expectedBody:   nil,
@@ -378,24 +493,48 @@ func TestUpdatePost(t *testing.T) {
 			name:        "Invalid Request Body",
 			postID:      "1",
 			requestBody: "invalid json",
-			mockUpdateFn: func(id int, req PostCreateUpdate) (PostRead, error) {
-				return PostRead{}, nil
+			mockUpsertFn: func(id int, req PostCreateUpdate) (PostRead, bool, error) {
+				return PostRead{}, false, nil
 			},
 			expectedStatus: http.StatusBadRequest,
 			expectedBody:   nil,
 		},
 		{
-			name:   "Post Not Found",
+			name:   "Created",
 			postID: "999",
 			requestBody: PostCreateUpdate{
 				Title:   "Updated Post",
 				Content: "Updated Content",
 				Author:  "Updated Author",
 			},
-			mockUpdateFn: func(id int, req PostCreateUpdate) (PostRead, error) {
-				return PostRead{}, ErrPostNotFound
+			mockUpsertFn: func(id int, req PostCreateUpdate) (PostRead, bool, error) {
+				return PostRead{
+					ID:      id,
+					Title:   req.Title,
+					Content: req.Content,
+					Author:  req.Author,
+				}, true, nil
 			},
-			expectedStatus: http.StatusNotFound,
+			expectedStatus: http.StatusCreated,
+			expectedBody: &PostRead{
+				ID:      999,
+				Title:   "Updated Post",
+				Content: "Updated Content",
+				Author:  "Updated Author",
+			},
+		},
+		{
+			name:   "Capacity Exceeded",
+			postID: "999",
+			requestBody: PostCreateUpdate{
+				Title:   "Updated Post",
+				Content: "Updated Content",
+				Author:  "Updated Author",
+			},
+			mockUpsertFn: func(id int, req PostCreateUpdate) (PostRead, bool, error) {
+				return PostRead{}, false, ErrCapacityExceeded
+			},
+			expectedStatus: http.StatusInsufficientStorage,
 			expectedBody:   nil,
 		},
 		{
@@ -405,8 +544,8 @@ func TestUpdatePost(t *testing.T) {
 				Title:  "Updated Post",
 				Author: "Updated Author",
 			},
-			mockUpdateFn: func(id int, req PostCreateUpdate) (PostRead, error) {
-				return PostRead{}, errors.New("validation error")
+			mockUpsertFn: func(id int, req PostCreateUpdate) (PostRead, bool, error) {
+				return PostRead{}, false, errors.New("validation error")
 			},
 			expectedStatus: http.StatusBadRequest,
 			expectedBody:   nil,
@@ -416,7 +555,7 @@ func TestUpdatePost(t *testing.T) {
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
 			mockService := &MockService{
-				UpdatePostFn: tc.mockUpdateFn,
+				UpsertPostFn: tc.mockUpsertFn,
 			}
 
 			handler := NewHandler(mockService)
@@ -433,7 +572,7 @@ func TestUpdatePost(t *testing.T) {
 			if rr.Code != tc.expectedStatus {
 				t.Errorf("Expected status %d, got %d", tc.expectedStatus, rr.Code)
 			}
-			if tc.expectedStatus == http.StatusOK && tc.expectedBody != nil {
+			if tc.expectedBody != nil {
 				var response PostRead
 				err = json.Unmarshal(rr.Body.Bytes(), &response)
 				if err != nil {
@@ -513,3 +652,1607 @@ func TestDeletePost(t *testing.T) {
 		})
 	}
 }
+
+func TestNonPositivePostID(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		expectedStatus int
+	}{
+		{name: "Get Zero", method: http.MethodGet, path: "/posts/0", expectedStatus: http.StatusBadRequest},
+		{name: "Get Negative", method: http.MethodGet, path: "/posts/-5", expectedStatus: http.StatusBadRequest},
+		{name: "Delete Zero", method: http.MethodDelete, path: "/posts/0", expectedStatus: http.StatusBadRequest},
+		{name: "Delete Negative", method: http.MethodDelete, path: "/posts/-5", expectedStatus: http.StatusBadRequest},
+		{name: "Get Missing", method: http.MethodGet, path: "/posts/99", expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			NewHandler(NewPostService(setupTestRepository())).RegisterRoutes(mux)
+
+			req := httptest.NewRequest(tc.method, tc.path, nil)
+			rr := httptest.NewRecorder()
+			mux.ServeHTTP(rr, req)
+
+			if rr.Code != tc.expectedStatus {
+				t.Errorf("Expected status %d, got %d: %s", tc.expectedStatus, rr.Code, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestIncrementViews(t *testing.T) {
+	tests := []struct {
+		name            string
+		postID          string
+		mockIncrementFn func(id int) (int, error)
+		expectedStatus  int
+		expectedViews   int
+	}{
+		{
+			name:   "Success",
+			postID: "1",
+			mockIncrementFn: func(id int) (int, error) {
+				return 5, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedViews:  5,
+		},
+		{
+			name:   "Invalid ID",
+			postID: "invalid",
+			mockIncrementFn: func(id int) (int, error) {
+				return 0, nil
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "Negative ID",
+			postID: "-1",
+			mockIncrementFn: func(id int) (int, error) {
+				return 0, InvalidPostIDError
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "Post Not Found",
+			postID: "999",
+			mockIncrementFn: func(id int) (int, error) {
+				return 0, ErrPostNotFound
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockService := &MockService{
+				IncrementViewsFn: tc.mockIncrementFn,
+			}
+
+			handler := NewHandler(mockService)
+
+			req, err := setupTestRequest(http.MethodPost, "/posts/"+tc.postID+"/view", nil)
+			if err != nil {
+				t.Fatalf("Failed to create request: %v", err)
+			}
+
+			rr := httptest.NewRecorder()
+
+			handler.IncrementViews(rr, req, tc.postID)
+
+			if rr.Code != tc.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tc.expectedStatus, rr.Code)
+			}
+			if tc.expectedStatus == http.StatusOK {
+				var response PostViews
+				err = json.Unmarshal(rr.Body.Bytes(), &response)
+				if err != nil {
+					t.Fatalf("Failed to unmarshal response: %v", err)
+				}
+
+				if response.Views != tc.expectedViews {
+					t.Errorf("Expected %d views, got %d", tc.expectedViews, response.Views)
+				}
+			}
+		})
+	}
+}
+
+func TestIncrementViewsConcurrent(t *testing.T) {
+	repo := setupTestRepository()
+	mux := http.NewServeMux()
+	NewHandler(NewPostService(repo)).RegisterRoutes(mux)
+
+	const workers = 20
+	const requestsPerWorker = 50
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < requestsPerWorker; j++ {
+				req := httptest.NewRequest(http.MethodPost, "/posts/1/view", nil)
+				rr := httptest.NewRecorder()
+				mux.ServeHTTP(rr, req)
+				if rr.Code != http.StatusOK {
+					t.Errorf("Expected status %d, got %d", http.StatusOK, rr.Code)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	post, err := repo.GetByID(1)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if post.Views != workers*requestsPerWorker {
+		t.Errorf("Expected %d views, got %d", workers*requestsPerWorker, post.Views)
+	}
+}
+
+func TestRoutePathNormalization(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		expectedStatus int
+		expectedRoute  string
+	}{
+		{name: "Collection", method: http.MethodGet, path: "/posts", expectedStatus: http.StatusOK, expectedRoute: "collection"},
+		{name: "Collection Trailing Slash", method: http.MethodGet, path: "/posts/", expectedStatus: http.StatusOK, expectedRoute: "collection"},
+		{name: "Create Trailing Slash", method: http.MethodPost, path: "/posts/", expectedStatus: http.StatusCreated, expectedRoute: "create"},
+		{name: "Item", method: http.MethodGet, path: "/posts/1", expectedStatus: http.StatusOK, expectedRoute: "item"},
+		{name: "Item Trailing Slash", method: http.MethodGet, path: "/posts/1/", expectedStatus: http.StatusOK, expectedRoute: "item"},
+		{name: "View Trailing Slash", method: http.MethodPost, path: "/posts/1/view/", expectedStatus: http.StatusOK, expectedRoute: "view"},
+		{name: "Nested Segments", method: http.MethodGet, path: "/posts/1/2", expectedStatus: http.StatusNotFound},
+		{name: "Too Many Segments", method: http.MethodPost, path: "/posts/1/view/2", expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var route string
+			mockService := &MockService{
+				GetAllPostsFn: func() ([]PostRead, error) {
+					route = "collection"
+					return testPosts, nil
+				},
+				CreatePostFn: func(req PostCreateUpdate) (PostRead, error) {
+					route = "create"
+					return testPosts[0], nil
+				},
+				GetPostByIDFn: func(id int) (PostRead, error) {
+					route = "item"
+					return testPosts[0], nil
+				},
+				IncrementViewsFn: func(id int) (int, error) {
+					route = "view"
+					return 1, nil
+				},
+			}
+
+			mux := http.NewServeMux()
+			NewHandler(mockService).RegisterRoutes(mux)
+
+			req, err := setupTestRequest(tc.method, tc.path, testPosts[0])
+			if err != nil {
+				t.Fatalf("Failed to create request: %v", err)
+			}
+
+			rr := httptest.NewRecorder()
+
+			mux.ServeHTTP(rr, req)
+
+			if rr.Code != tc.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tc.expectedStatus, rr.Code)
+			}
+			if route != tc.expectedRoute {
+				t.Errorf("Expected route %q, got %q", tc.expectedRoute, route)
+			}
+		})
+	}
+}
+
+func TestPostPathSegments(t *testing.T) {
+	tests := []struct {
+		path             string
+		expectedSegments []string
+		expectedOK       bool
+	}{
+		{path: "/posts/", expectedSegments: nil, expectedOK: true},
+		{path: "/posts/1", expectedSegments: []string{"1"}, expectedOK: true},
+		{path: "/posts/1/", expectedSegments: []string{"1"}, expectedOK: true},
+		{path: "/posts/1/view", expectedSegments: []string{"1", "view"}, expectedOK: true},
+		{path: "/posts//1", expectedSegments: nil, expectedOK: false},
+		{path: "/posts//", expectedSegments: nil, expectedOK: false},
+		{path: "/posts/1//", expectedSegments: nil, expectedOK: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.path, func(t *testing.T) {
+			segments, ok := postPathSegments(tc.path)
+			if ok != tc.expectedOK {
+				t.Errorf("Expected ok %v, got %v", tc.expectedOK, ok)
+			}
+			if !slices.Equal(segments, tc.expectedSegments) {
+				t.Errorf("Expected segments %v, got %v", tc.expectedSegments, segments)
+			}
+		})
+	}
+}
+
+func TestCreatePostIdempotencyKey(t *testing.T) {
+	tests := []struct {
+		name            string
+		keys            []string
+		expectedCreated int
+	}{
+		{
+			name:            "Same Key Twice",
+			keys:            []string{"key-1", "key-1"},
+			expectedCreated: 1,
+		},
+		{
+			name:            "Different Keys",
+			keys:            []string{"key-1", "key-2"},
+			expectedCreated: 2,
+		},
+		{
+			name:            "No Key",
+			keys:            []string{"", ""},
+			expectedCreated: 2,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := setupTestRepository()
+			mux := http.NewServeMux()
+			// Identical bodies without a shared key would otherwise be rejected as duplicates.
+			NewHandler(NewPostService(repo, WithDuplicateCheck(false))).RegisterRoutes(mux)
+
+			ids := make(map[int]bool)
+			for _, key := range tc.keys {
+				req, err := setupTestRequest(http.MethodPost, "/posts", PostCreateUpdate{
+					Title:   "New Post",
+					Content: "New Content",
+					Author:  "New Author",
+				})
+				if err != nil {
+					t.Fatalf("Failed to create request: %v", err)
+				}
+				if key != "" {
+					req.Header.Set(IdempotencyKeyHeader, key)
+				}
+
+				rr := httptest.NewRecorder()
+
+				mux.ServeHTTP(rr, req)
+
+				if rr.Code != http.StatusCreated {
+					t.Errorf("Expected status %d, got %d", http.StatusCreated, rr.Code)
+				}
+
+				var response PostRead
+				if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+					t.Fatalf("Failed to unmarshal response: %v", err)
+				}
+				ids[response.ID] = true
+			}
+
+			if len(ids) != tc.expectedCreated {
+				t.Errorf("Expected %d distinct posts in responses, got %d", tc.expectedCreated, len(ids))
+			}
+
+			posts, _ := repo.GetAll(context.Background())
+			if len(posts) != 2+tc.expectedCreated {
+				t.Errorf("Expected %d posts in repository, got %d", 2+tc.expectedCreated, len(posts))
+			}
+		})
+	}
+}
+
+func TestCreatePostIdempotencyKeyReused(t *testing.T) {
+	mux := http.NewServeMux()
+	NewHandler(NewPostService(setupTestRepository())).RegisterRoutes(mux)
+
+	tests := []struct {
+		name           string
+		title          string
+		expectedStatus int
+	}{
+		{
+			name:           "First Request",
+			title:          "New Post",
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "Different Body",
+			title:          "Other Post",
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := setupTestRequest(http.MethodPost, "/posts", PostCreateUpdate{
+				Title:   tc.title,
+				Content: "New Content",
+				Author:  "New Author",
+			})
+			if err != nil {
+				t.Fatalf("Failed to create request: %v", err)
+			}
+			req.Header.Set(IdempotencyKeyHeader, "key-1")
+
+			rr := httptest.NewRecorder()
+
+			mux.ServeHTTP(rr, req)
+
+			if rr.Code != tc.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tc.expectedStatus, rr.Code)
+			}
+		})
+	}
+}
+
+func TestEmptyListsEncodeAsArrays(t *testing.T) {
+	mux := http.NewServeMux()
+	NewHandler(&MockService{
+		GetAllPostsFn: func() ([]PostRead, error) {
+			return nil, nil
+		},
+		ListPostsFn: func(params ListParams) ([]PostRead, int, error) {
+			return nil, 0, nil
+		},
+		GetRecentPostsFn: func(n int) ([]PostRead, error) {
+			return nil, nil
+		},
+		AuthorsFn: func() ([]string, error) {
+			return nil, nil
+		},
+	}).RegisterRoutes(mux)
+
+	tests := []struct {
+		name         string
+		url          string
+		expectedBody string
+	}{
+		{name: "All Posts", url: "/posts", expectedBody: `[]`},
+		{name: "Created Range", url: "/posts?createdAfter=2024-01-01T00:00:00Z", expectedBody: `[]`},
+		{name: "Fields", url: "/posts?fields=id", expectedBody: `[]`},
+		{name: "Rendered", url: "/posts?render=html", expectedBody: `[]`},
+		{name: "Envelope", url: "/posts?envelope=true", expectedBody: `{"posts":[]}`},
+		{name: "Recent", url: "/posts/recent", expectedBody: `[]`},
+		{name: "JSON Export", url: "/posts/export?format=json", expectedBody: `[]`},
+		{name: "Authors", url: "/authors", expectedBody: `[]`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tc.url, nil))
+
+			if rr.Code != http.StatusOK {
+				t.Fatalf("Expected status %d, got %d", http.StatusOK, rr.Code)
+			}
+			if body := strings.TrimSpace(rr.Body.String()); body != tc.expectedBody {
+				t.Errorf("Expected body %s, got %s", tc.expectedBody, body)
+			}
+		})
+	}
+}
+
+func TestGetAllPostsFromEmptyRepository(t *testing.T) {
+	repo := setupTestRepository()
+	repo.posts = make(map[int]PostRead)
+	mux := http.NewServeMux()
+	NewHandler(NewPostService(repo)).RegisterRoutes(mux)
+
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/posts", nil))
+
+	if body := strings.TrimSpace(rr.Body.String()); body != "[]" {
+		t.Errorf("Expected an empty JSON array, got %s", body)
+	}
+}
+
+func TestGetAuthors(t *testing.T) {
+	tests := []struct {
+		name           string
+		authors        []string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{name: "Authors", authors: []string{"Adam", "Zoe"}, expectedStatus: http.StatusOK, expectedBody: `["Adam","Zoe"]`},
+		{name: "No Authors", authors: []string{}, expectedStatus: http.StatusOK, expectedBody: `[]`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			NewHandler(&MockService{
+				AuthorsFn: func() ([]string, error) {
+					return tc.authors, nil
+				},
+			}).RegisterRoutes(mux)
+
+			rr := httptest.NewRecorder()
+			mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/authors", nil))
+
+			if rr.Code != tc.expectedStatus {
+				t.Fatalf("Expected status %d, got %d", tc.expectedStatus, rr.Code)
+			}
+			if body := strings.TrimSpace(rr.Body.String()); body != tc.expectedBody {
+				t.Errorf("Expected body %s, got %s", tc.expectedBody, body)
+			}
+		})
+	}
+}
+
+func TestGetAuthorsPrefixAndPaging(t *testing.T) {
+	authors := []string{"Adam", "Alice", "alfred", "Ålesund", "Bob", "Zoe"}
+	mux := http.NewServeMux()
+	NewHandler(&MockService{
+		AuthorsFn: func() ([]string, error) {
+			return slices.Clone(authors), nil
+		},
+	}).RegisterRoutes(mux)
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectedBody   string
+		expectedTotal  int
+		expectedNext   bool
+	}{
+		{name: "Prefix", query: "?prefix=al", expectedStatus: http.StatusOK, expectedBody: `["Alice","alfred"]`},
+		{name: "Prefix Ignores Case", query: "?prefix=AL", expectedStatus: http.StatusOK, expectedBody: `["Alice","alfred"]`},
+		{name: "Multibyte Prefix", query: "?prefix=%C3%A5", expectedStatus: http.StatusOK, expectedBody: `["Ålesund"]`},
+		{name: "No Match", query: "?prefix=x", expectedStatus: http.StatusOK, expectedBody: `[]`},
+		{name: "First Page", query: "?limit=2", expectedStatus: http.StatusOK, expectedBody: `["Adam","Alice"]`, expectedTotal: 6, expectedNext: true},
+		{name: "Last Page", query: "?limit=2&offset=4", expectedStatus: http.StatusOK, expectedBody: `["Bob","Zoe"]`, expectedTotal: 6},
+		{name: "Paged Prefix", query: "?prefix=a&limit=2&offset=2", expectedStatus: http.StatusOK, expectedBody: `["alfred"]`, expectedTotal: 3},
+		{name: "Invalid Limit", query: "?limit=-1", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/authors"+tc.query, nil))
+
+			if rr.Code != tc.expectedStatus {
+				t.Fatalf("Expected status %d, got %d", tc.expectedStatus, rr.Code)
+			}
+			if tc.expectedStatus != http.StatusOK {
+				return
+			}
+			if tc.expectedTotal == 0 {
+				if body := strings.TrimSpace(rr.Body.String()); body != tc.expectedBody {
+					t.Errorf("Expected body %s, got %s", tc.expectedBody, body)
+				}
+				return
+			}
+
+			var page struct {
+				Data  json.RawMessage `json:"data"`
+				Total int             `json:"total"`
+				Links PageLinks       `json:"links"`
+			}
+			if err := json.Unmarshal(rr.Body.Bytes(), &page); err != nil {
+				t.Fatalf("Failed to unmarshal response: %v", err)
+			}
+			if string(page.Data) != tc.expectedBody {
+				t.Errorf("Expected data %s, got %s", tc.expectedBody, page.Data)
+			}
+			if page.Total != tc.expectedTotal {
+				t.Errorf("Expected total %d, got %d", tc.expectedTotal, page.Total)
+			}
+			if (page.Links.Next != nil) != tc.expectedNext {
+				t.Errorf("Expected next link present=%v, got %v", tc.expectedNext, page.Links.Next)
+			}
+		})
+	}
+}
+
+func TestGetAuthorsFromEmptyRepository(t *testing.T) {
+	repo := setupTestRepository()
+	repo.posts = make(map[int]PostRead)
+	mux := http.NewServeMux()
+	NewHandler(NewPostService(repo)).RegisterRoutes(mux)
+
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/authors", nil))
+
+	if body := strings.TrimSpace(rr.Body.String()); body != "[]" {
+		t.Errorf("Expected an empty JSON array, got %s", body)
+	}
+}
+
+func TestGetStats(t *testing.T) {
+	repo := setupTestRepository()
+	repo.posts[3] = PostRead{ID: 3, Title: "Test Post 3", Content: "Test Content 3", Author: "Test Author 2"}
+	repo.posts[4] = PostRead{ID: 4, Title: "Test Post 4", Content: "Test Content 4", Author: "Test Author 3"}
+
+	mux := http.NewServeMux()
+	NewHandler(NewPostService(repo)).RegisterRoutes(mux)
+
+	req := httptest.NewRequest(http.MethodGet, "/posts/stats", nil)
+	rr := httptest.NewRecorder()
+
+	mux.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+
+	var response PostStats
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+
+	if response.Total != 4 {
+		t.Errorf("Expected total 4, got %d", response.Total)
+	}
+	expected := []AuthorStats{
+		{Author: "Test Author 2", Count: 2},
+		{Author: "Test Author 1", Count: 1},
+		{Author: "Test Author 3", Count: 1},
+	}
+	if !slices.Equal(response.Authors, expected) {
+		t.Errorf("Expected authors %v, got %v", expected, response.Authors)
+	}
+}
+
+func TestGetStatsPrefixAndPaging(t *testing.T) {
+	repo := setupTestRepository()
+	repo.posts[3] = PostRead{ID: 3, Title: "Test Post 3", Content: "Test Content 3", Author: "Test Author 2"}
+	repo.posts[4] = PostRead{ID: 4, Title: "Test Post 4", Content: "Test Content 4", Author: "Other Author"}
+
+	mux := http.NewServeMux()
+	NewHandler(NewPostService(repo)).RegisterRoutes(mux)
+
+	tests := []struct {
+		name                string
+		query               string
+		expectedTotal       int
+		expectedAuthors     []AuthorStats
+		expectedAuthorCount int
+		expectedLinks       bool
+	}{
+		{
+			name:            "Prefix",
+			query:           "?prefix=test",
+			expectedTotal:   3,
+			expectedAuthors: []AuthorStats{{Author: "Test Author 2", Count: 2}, {Author: "Test Author 1", Count: 1}},
+		},
+		{
+			name:                "Page",
+			query:               "?limit=1&offset=1",
+			expectedTotal:       4,
+			expectedAuthors:     []AuthorStats{{Author: "Other Author", Count: 1}},
+			expectedAuthorCount: 3,
+			expectedLinks:       true,
+		},
+		{
+			name:                "Paged Prefix",
+			query:               "?prefix=TEST&limit=1",
+			expectedTotal:       3,
+			expectedAuthors:     []AuthorStats{{Author: "Test Author 2", Count: 2}},
+			expectedAuthorCount: 2,
+			expectedLinks:       true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/posts/stats"+tc.query, nil))
+
+			if rr.Code != http.StatusOK {
+				t.Fatalf("Expected status %d, got %d", http.StatusOK, rr.Code)
+			}
+			var response PostStats
+			if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to unmarshal response: %v", err)
+			}
+			if response.Total != tc.expectedTotal {
+				t.Errorf("Expected total %d, got %d", tc.expectedTotal, response.Total)
+			}
+			if !slices.Equal(response.Authors, tc.expectedAuthors) {
+				t.Errorf("Expected authors %v, got %v", tc.expectedAuthors, response.Authors)
+			}
+			if response.AuthorCount != tc.expectedAuthorCount {
+				t.Errorf("Expected author count %d, got %d", tc.expectedAuthorCount, response.AuthorCount)
+			}
+			if (response.Links != nil) != tc.expectedLinks {
+				t.Errorf("Expected links present=%v, got %+v", tc.expectedLinks, response.Links)
+			}
+		})
+	}
+}
+
+func TestGetRecentPosts(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectedN      int
+	}{
+		{name: "Default", query: "", expectedStatus: http.StatusOK, expectedN: defaultRecentPosts},
+		{name: "Explicit N", query: "?n=5", expectedStatus: http.StatusOK, expectedN: 5},
+		{name: "Non Numeric N", query: "?n=abc", expectedStatus: http.StatusBadRequest},
+		{name: "Negative N", query: "?n=-1", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var requested int
+			mockService := &MockService{
+				GetRecentPostsFn: func(n int) ([]PostRead, error) {
+					requested = n
+					return testPostsData, nil
+				},
+			}
+			mux := http.NewServeMux()
+			NewHandler(mockService).RegisterRoutes(mux)
+
+			rr := httptest.NewRecorder()
+			mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/posts/recent"+tc.query, nil))
+
+			if rr.Code != tc.expectedStatus {
+				t.Fatalf("Expected status %d, got %d", tc.expectedStatus, rr.Code)
+			}
+			if tc.expectedStatus == http.StatusOK && requested != tc.expectedN {
+				t.Errorf("Expected service to be asked for %d posts, got %d", tc.expectedN, requested)
+			}
+		})
+	}
+}
+
+func TestGetRecentPostsClampsExcessiveN(t *testing.T) {
+	repo := setupTestRepository()
+	mux := http.NewServeMux()
+	NewHandler(NewPostService(repo)).RegisterRoutes(mux)
+
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/posts/recent?n=100000", nil))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+
+	var response []PostRead
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if len(response) != 2 || response[0].ID != 2 || response[1].ID != 1 {
+		t.Errorf("Expected posts 2 then 1, got %v", response)
+	}
+}
+
+func TestGetRandomPost(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockFn         func() (PostRead, error)
+		expectedStatus int
+	}{
+		{
+			name: "Success",
+			mockFn: func() (PostRead, error) {
+				return testPostsData[1], nil
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "No Posts",
+			mockFn: func() (PostRead, error) {
+				return PostRead{}, ErrPostNotFound
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "Service Error",
+			mockFn: func() (PostRead, error) {
+				return PostRead{}, errors.New("service error")
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			NewHandler(&MockService{GetRandomPostFn: tc.mockFn}).RegisterRoutes(mux)
+
+			rr := httptest.NewRecorder()
+			mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/posts/random", nil))
+
+			if rr.Code != tc.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tc.expectedStatus, rr.Code)
+			}
+		})
+	}
+}
+
+func TestCreatePostDuplicate(t *testing.T) {
+	tests := []struct {
+		name           string
+		post           PostCreateUpdate
+		expectedStatus int
+	}{
+		{
+			name:           "Exact Duplicate",
+			post:           PostCreateUpdate{Title: "Hello World", Content: "Some content", Author: "Jane Doe"},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "Duplicate Differing In Case And Whitespace",
+			post:           PostCreateUpdate{Title: "  hello   WORLD ", Content: "Some content", Author: "jane doe"},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "Same Title Different Author",
+			post:           PostCreateUpdate{Title: "Hello World", Content: "Some content", Author: "John Doe"},
+			expectedStatus: http.StatusCreated,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := setupTestRepository()
+			repo.posts[3] = PostRead{ID: 3, Title: "Hello World", Content: "Original content", Author: "Jane Doe"}
+			mux := http.NewServeMux()
+			NewHandler(NewPostService(repo)).RegisterRoutes(mux)
+
+			req, err := setupTestRequest(http.MethodPost, "/posts", tc.post)
+			if err != nil {
+				t.Fatalf("Failed to create request: %v", err)
+			}
+			rr := httptest.NewRecorder()
+
+			mux.ServeHTTP(rr, req)
+
+			if rr.Code != tc.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tc.expectedStatus, rr.Code)
+			}
+		})
+	}
+}
+
+func TestBatchGetPosts(t *testing.T) {
+	stored := make([]PostRead, 500)
+	storedIDs := make([]int, len(stored))
+	for i := range stored {
+		storedIDs[i] = i + 1
+		stored[i] = PostRead{ID: i + 1, Title: "Post " + strconv.Itoa(i+1), Content: "Content", Author: "Jane Doe"}
+	}
+	idList := func(from, to int) string {
+		ids := make([]string, 0, to-from+1)
+		for id := from; id <= to; id++ {
+			ids = append(ids, strconv.Itoa(id))
+		}
+		return `{"ids": [` + strings.Join(ids, ",") + `]}`
+	}
+
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		expectedIDs    []int
+	}{
+		{
+			name:           "Duplicates And Missing",
+			body:           `{"ids": [3, 9999, 1, 3, 1]}`,
+			expectedStatus: http.StatusOK,
+			expectedIDs:    []int{3, 1},
+		},
+		{
+			name:           "Only Missing",
+			body:           `{"ids": [9999]}`,
+			expectedStatus: http.StatusOK,
+			expectedIDs:    []int{},
+		},
+		{
+			name:           "Large List",
+			body:           idList(1, 1000),
+			expectedStatus: http.StatusOK,
+			expectedIDs:    storedIDs,
+		},
+		{
+			name:           "Too Many",
+			body:           idList(1, 1001),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Empty ID List",
+			body:           `{"ids": []}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid ID",
+			body:           `{"ids": [1, 0]}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid Body",
+			body:           `not json`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, err := NewMapRepositoryWithPosts(stored)
+			if err != nil {
+				t.Fatalf("Failed to set up repository: %v", err)
+			}
+			mux := http.NewServeMux()
+			NewHandler(NewPostService(repo)).RegisterRoutes(mux)
+
+			req := httptest.NewRequest(http.MethodPost, "/posts/batch-get", strings.NewReader(tc.body))
+			rr := httptest.NewRecorder()
+
+			mux.ServeHTTP(rr, req)
+
+			if rr.Code != tc.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tc.expectedStatus, rr.Code, rr.Body.String())
+			}
+			if tc.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var response []PostRead
+			if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to unmarshal response: %v", err)
+			}
+			ids := make([]int, len(response))
+			for i, post := range response {
+				ids[i] = post.ID
+			}
+			if !slices.Equal(ids, tc.expectedIDs) {
+				t.Errorf("Expected posts %v, got %v", tc.expectedIDs, ids)
+			}
+		})
+	}
+}
+
+func TestDeletePosts(t *testing.T) {
+	tests := []struct {
+		name             string
+		path             string
+		body             string
+		expectedStatus   int
+		expectedDeleted  []int
+		expectedNotFound []int
+	}{
+		{
+			name:             "IDs In Body",
+			path:             "/posts",
+			body:             `{"ids": [1, 99, 2]}`,
+			expectedStatus:   http.StatusOK,
+			expectedDeleted:  []int{1, 2},
+			expectedNotFound: []int{99},
+		},
+		{
+			name:             "IDs In Query",
+			path:             "/posts?ids=2,42",
+			expectedStatus:   http.StatusOK,
+			expectedDeleted:  []int{2},
+			expectedNotFound: []int{42},
+		},
+		{
+			name:             "Repeated ID",
+			path:             "/posts?ids=1,1",
+			expectedStatus:   http.StatusOK,
+			expectedDeleted:  []int{1},
+			expectedNotFound: []int{},
+		},
+		{
+			name:           "Invalid Query ID",
+			path:           "/posts?ids=1,abc",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Empty ID List",
+			path:           "/posts",
+			body:           `{"ids": []}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid Body",
+			path:           "/posts",
+			body:           `not json`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := setupTestRepository()
+			mux := http.NewServeMux()
+			NewHandler(NewPostService(repo)).RegisterRoutes(mux)
+
+			req := httptest.NewRequest(http.MethodDelete, tc.path, strings.NewReader(tc.body))
+			rr := httptest.NewRecorder()
+
+			mux.ServeHTTP(rr, req)
+
+			if rr.Code != tc.expectedStatus {
+				t.Fatalf("Expected status %d, got %d", tc.expectedStatus, rr.Code)
+			}
+			if tc.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var response BulkDeleteResult
+			if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to unmarshal response: %v", err)
+			}
+			if !slices.Equal(response.Deleted, tc.expectedDeleted) {
+				t.Errorf("Expected deleted %v, got %v", tc.expectedDeleted, response.Deleted)
+			}
+			if !slices.Equal(response.NotFound, tc.expectedNotFound) {
+				t.Errorf("Expected not found %v, got %v", tc.expectedNotFound, response.NotFound)
+			}
+			for _, id := range tc.expectedDeleted {
+				if _, ok := repo.posts[id]; ok {
+					t.Errorf("Expected post %d to be deleted", id)
+				}
+			}
+		})
+	}
+}
+
+func TestCreatePostValidationErrorBody(t *testing.T) {
+	mux := http.NewServeMux()
+	NewHandler(NewPostService(setupTestRepository())).RegisterRoutes(mux)
+
+	req, err := setupTestRequest(http.MethodPost, "/posts", PostCreateUpdate{Content: "Content", Author: "Jane Doe"})
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+	rr := httptest.NewRecorder()
+
+	mux.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("Expected status %d, got %d", http.StatusBadRequest, rr.Code)
+	}
+	var response validationErrorResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if len(response.Errors) != 1 || response.Errors[0].Field != "Title" || response.Errors[0].Rule != "required" {
+		t.Errorf("Expected a single required error on Title, got %v", response.Errors)
+	}
+	if !strings.HasPrefix(response.Error, "Validation failed: ") {
+		t.Errorf("Expected a summary message, got %q", response.Error)
+	}
+}
+
+func TestPatchPosts(t *testing.T) {
+	tests := []struct {
+		name             string
+		body             string
+		expectedStatus   int
+		expectedUpdated  []int
+		expectedNotFound []int
+		expectedAuthors  map[int]string
+	}{
+		{
+			name:             "All Found",
+			body:             `{"ids": [1, 2], "patch": {"author": "Jane Doe"}}`,
+			expectedStatus:   http.StatusOK,
+			expectedUpdated:  []int{1, 2},
+			expectedNotFound: []int{},
+			expectedAuthors:  map[int]string{1: "Jane Doe", 2: "Jane Doe"},
+		},
+		{
+			name:             "Missing ID",
+			body:             `{"ids": [2, 99, 2], "patch": {"author": "Jane Doe", "tags": ["go"]}}`,
+			expectedStatus:   http.StatusOK,
+			expectedUpdated:  []int{2},
+			expectedNotFound: []int{99},
+			expectedAuthors:  map[int]string{1: "Test Author 1", 2: "Jane Doe"},
+		},
+		{
+			name:            "Invalid Patch Changes Nothing",
+			body:            `{"ids": [1, 2], "patch": {"title": ""}}`,
+			expectedStatus:  http.StatusBadRequest,
+			expectedAuthors: map[int]string{1: "Test Author 1", 2: "Test Author 2"},
+		},
+		{
+			name:           "Empty ID List",
+			body:           `{"ids": [], "patch": {"author": "Jane Doe"}}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid Body",
+			body:           `not json`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := setupTestRepository()
+			mux := http.NewServeMux()
+			NewHandler(NewPostService(repo)).RegisterRoutes(mux)
+
+			req := httptest.NewRequest(http.MethodPatch, "/posts", strings.NewReader(tc.body))
+			rr := httptest.NewRecorder()
+
+			mux.ServeHTTP(rr, req)
+
+			if rr.Code != tc.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tc.expectedStatus, rr.Code, rr.Body.String())
+			}
+			for id, author := range tc.expectedAuthors {
+				if got := repo.posts[id].Author; got != author {
+					t.Errorf("Expected post %d to have author %q, got %q", id, author, got)
+				}
+			}
+			if tc.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var response BulkPatchResult
+			if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to unmarshal response: %v", err)
+			}
+			updated := make([]int, len(response.Updated))
+			for i, post := range response.Updated {
+				updated[i] = post.ID
+				if post.Author != "Jane Doe" {
+					t.Errorf("Expected post %d to be returned with its new author, got %q", post.ID, post.Author)
+				}
+			}
+			if !slices.Equal(updated, tc.expectedUpdated) {
+				t.Errorf("Expected updated %v, got %v", tc.expectedUpdated, updated)
+			}
+			if !slices.Equal(response.NotFound, tc.expectedNotFound) {
+				t.Errorf("Expected not found %v, got %v", tc.expectedNotFound, response.NotFound)
+			}
+		})
+	}
+}
+
+func TestBasePath(t *testing.T) {
+	tests := []struct {
+		name             string
+		method           string
+		path             string
+		accept           string
+		body             string
+		expectedStatus   int
+		expectedLocation string
+		expectedLink     string
+	}{
+		{name: "Collection", method: http.MethodGet, path: "/api/v1/posts", expectedStatus: http.StatusOK},
+		{name: "Item", method: http.MethodGet, path: "/api/v1/posts/1", expectedStatus: http.StatusOK},
+		{name: "Authors", method: http.MethodGet, path: "/api/v1/authors", expectedStatus: http.StatusOK},
+		{name: "Stats", method: http.MethodGet, path: "/api/v1/posts/stats", expectedStatus: http.StatusOK},
+		{name: "Unprefixed Path", method: http.MethodGet, path: "/posts", expectedStatus: http.StatusNotFound},
+		{
+			name:             "Create Location",
+			method:           http.MethodPost,
+			path:             "/api/v1/posts",
+			body:             `{"title": "New Post", "content": "New Content", "author": "Jane Doe"}`,
+			expectedStatus:   http.StatusCreated,
+			expectedLocation: "/api/v1/posts/3",
+		},
+		{name: "Page Links", method: http.MethodGet, path: "/api/v1/posts?limit=1", expectedStatus: http.StatusOK, expectedLink: `"next":"/api/v1/posts?limit=1\u0026offset=1"`},
+		{name: "JSON:API Links", method: http.MethodGet, path: "/api/v1/posts/1", accept: jsonAPIMediaType, expectedStatus: http.StatusOK, expectedLink: `"self":"/api/v1/posts/1"`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			NewHandler(NewPostService(setupTestRepository()), WithBasePath("/api/v1/")).RegisterRoutes(mux)
+
+			req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+			if tc.accept != "" {
+				req.Header.Set("Accept", tc.accept)
+			}
+			rr := httptest.NewRecorder()
+
+			mux.ServeHTTP(rr, req)
+
+			if rr.Code != tc.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tc.expectedStatus, rr.Code, rr.Body.String())
+			}
+			if got := rr.Header().Get("Location"); got != tc.expectedLocation {
+				t.Errorf("Expected Location %q, got %q", tc.expectedLocation, got)
+			}
+			if tc.expectedLink != "" && !strings.Contains(rr.Body.String(), tc.expectedLink) {
+				t.Errorf("Expected the body to contain %s, got %s", tc.expectedLink, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestGetPostNeighbors(t *testing.T) {
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{name: "First Post", path: "/posts/1/neighbors", expectedStatus: http.StatusOK, expectedBody: `"prev":null`},
+		{name: "Last Post", path: "/posts/2/neighbors", expectedStatus: http.StatusOK, expectedBody: `"next":null`},
+		{name: "Missing Post", path: "/posts/99/neighbors", expectedStatus: http.StatusNotFound},
+		{name: "Invalid ID", path: "/posts/abc/neighbors", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			NewHandler(NewPostService(setupTestRepository())).RegisterRoutes(mux)
+
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			rr := httptest.NewRecorder()
+
+			mux.ServeHTTP(rr, req)
+
+			if rr.Code != tc.expectedStatus {
+				t.Fatalf("Expected status %d, got %d", tc.expectedStatus, rr.Code)
+			}
+			if tc.expectedStatus != http.StatusOK {
+				return
+			}
+			if !strings.Contains(rr.Body.String(), tc.expectedBody) {
+				t.Errorf("Expected the body to contain %s, got %s", tc.expectedBody, rr.Body.String())
+			}
+
+			var response PostNeighbors
+			if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to unmarshal response: %v", err)
+			}
+			if (response.Prev == nil) == (response.Next == nil) {
+				t.Errorf("Expected exactly one neighbor of a post among two, got %+v", response)
+			}
+		})
+	}
+}
+
+func TestAllowHeader(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		expectedStatus int
+		expectedAllow  string
+	}{
+		{name: "Collection Options", method: http.MethodOptions, path: "/posts", expectedStatus: http.StatusNoContent, expectedAllow: "GET, HEAD, POST, PATCH, DELETE, OPTIONS"},
+		{name: "Collection Options Trailing Slash", method: http.MethodOptions, path: "/posts/", expectedStatus: http.StatusNoContent, expectedAllow: "GET, HEAD, POST, PATCH, DELETE, OPTIONS"},
+		{name: "Item Options", method: http.MethodOptions, path: "/posts/1", expectedStatus: http.StatusNoContent, expectedAllow: "GET, HEAD, PUT, PATCH, DELETE, OPTIONS"},
+		{name: "Collection Method Not Allowed", method: http.MethodPut, path: "/posts", expectedStatus: http.StatusMethodNotAllowed, expectedAllow: "GET, HEAD, POST, PATCH, DELETE, OPTIONS"},
+		{name: "Item Method Not Allowed", method: http.MethodPost, path: "/posts/1", expectedStatus: http.StatusMethodNotAllowed, expectedAllow: "GET, HEAD, PUT, PATCH, DELETE, OPTIONS"},
+		{name: "Export Method Not Allowed", method: http.MethodPost, path: "/posts/export", expectedStatus: http.StatusMethodNotAllowed, expectedAllow: "GET, HEAD, OPTIONS"},
+		{name: "Stats Method Not Allowed", method: http.MethodDelete, path: "/posts/stats", expectedStatus: http.StatusMethodNotAllowed, expectedAllow: "GET, HEAD, OPTIONS"},
+		{name: "Recent Method Not Allowed", method: http.MethodPost, path: "/posts/recent", expectedStatus: http.StatusMethodNotAllowed, expectedAllow: "GET, HEAD, OPTIONS"},
+		{name: "Random Method Not Allowed", method: http.MethodPut, path: "/posts/random", expectedStatus: http.StatusMethodNotAllowed, expectedAllow: "GET, HEAD, OPTIONS"},
+		{name: "Batch Get Method Not Allowed", method: http.MethodGet, path: "/posts/batch-get", expectedStatus: http.StatusMethodNotAllowed, expectedAllow: "POST, OPTIONS"},
+		{name: "Feed Method Not Allowed", method: http.MethodPost, path: "/posts/feed.xml", expectedStatus: http.StatusMethodNotAllowed, expectedAllow: "GET, HEAD, OPTIONS"},
+		{name: "Search Method Not Allowed", method: http.MethodPost, path: "/posts/search", expectedStatus: http.StatusMethodNotAllowed, expectedAllow: "GET, HEAD, OPTIONS"},
+		{name: "Validate Method Not Allowed", method: http.MethodGet, path: "/posts/validate", expectedStatus: http.StatusMethodNotAllowed, expectedAllow: "POST, OPTIONS"},
+		{name: "Import Method Not Allowed", method: http.MethodGet, path: "/posts/import", expectedStatus: http.StatusMethodNotAllowed, expectedAllow: "POST, OPTIONS"},
+		{name: "View Method Not Allowed", method: http.MethodGet, path: "/posts/1/view", expectedStatus: http.StatusMethodNotAllowed, expectedAllow: "POST, OPTIONS"},
+		{name: "Neighbors Method Not Allowed", method: http.MethodPost, path: "/posts/1/neighbors", expectedStatus: http.StatusMethodNotAllowed, expectedAllow: "GET, HEAD, OPTIONS"},
+		{name: "Authors Method Not Allowed", method: http.MethodPost, path: "/authors", expectedStatus: http.StatusMethodNotAllowed, expectedAllow: "GET, HEAD, OPTIONS"},
+		{name: "Export Options", method: http.MethodOptions, path: "/posts/export", expectedStatus: http.StatusNoContent, expectedAllow: "GET, HEAD, OPTIONS"},
+		{name: "Stats Options", method: http.MethodOptions, path: "/posts/stats", expectedStatus: http.StatusNoContent, expectedAllow: "GET, HEAD, OPTIONS"},
+		{name: "Recent Options", method: http.MethodOptions, path: "/posts/recent", expectedStatus: http.StatusNoContent, expectedAllow: "GET, HEAD, OPTIONS"},
+		{name: "Random Options", method: http.MethodOptions, path: "/posts/random", expectedStatus: http.StatusNoContent, expectedAllow: "GET, HEAD, OPTIONS"},
+		{name: "Batch Get Options", method: http.MethodOptions, path: "/posts/batch-get", expectedStatus: http.StatusNoContent, expectedAllow: "POST, OPTIONS"},
+		{name: "Feed Options", method: http.MethodOptions, path: "/posts/feed.xml", expectedStatus: http.StatusNoContent, expectedAllow: "GET, HEAD, OPTIONS"},
+		{name: "Search Options", method: http.MethodOptions, path: "/posts/search", expectedStatus: http.StatusNoContent, expectedAllow: "GET, HEAD, OPTIONS"},
+		{name: "Validate Options", method: http.MethodOptions, path: "/posts/validate", expectedStatus: http.StatusNoContent, expectedAllow: "POST, OPTIONS"},
+		{name: "Import Options", method: http.MethodOptions, path: "/posts/import", expectedStatus: http.StatusNoContent, expectedAllow: "POST, OPTIONS"},
+		{name: "View Options", method: http.MethodOptions, path: "/posts/1/view", expectedStatus: http.StatusNoContent, expectedAllow: "POST, OPTIONS"},
+		{name: "Neighbors Options", method: http.MethodOptions, path: "/posts/1/neighbors", expectedStatus: http.StatusNoContent, expectedAllow: "GET, HEAD, OPTIONS"},
+		{name: "Authors Options", method: http.MethodOptions, path: "/authors", expectedStatus: http.StatusNoContent, expectedAllow: "GET, HEAD, OPTIONS"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			NewHandler(&MockService{}).RegisterRoutes(mux)
+
+			req := httptest.NewRequest(tc.method, tc.path, nil)
+			rr := httptest.NewRecorder()
+
+			mux.ServeHTTP(rr, req)
+
+			if rr.Code != tc.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tc.expectedStatus, rr.Code)
+			}
+			if allow := rr.Header().Get("Allow"); allow != tc.expectedAllow {
+				t.Errorf("Expected Allow %q, got %q", tc.expectedAllow, allow)
+			}
+		})
+	}
+}
+
+type unmarshalablePayload struct {
+	Updates chan int `json:"updates"`
+}
+
+func TestRespondWithJSON(t *testing.T) {
+	tests := []struct {
+		name           string
+		url            string
+		accept         string
+		data           interface{}
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "Success",
+			data:           PostViews{ID: 1, Views: 2},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"id":1,"views":2}` + "\n",
+		},
+		{
+			name:           "Pretty Query",
+			url:            "/?pretty=true",
+			data:           PostViews{ID: 1, Views: 2},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   "{\n  \"id\": 1,\n  \"views\": 2\n}\n",
+		},
+		{
+			name:           "Pretty Accept Parameter",
+			accept:         "application/json; pretty=true",
+			data:           PostViews{ID: 1, Views: 2},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   "{\n  \"id\": 1,\n  \"views\": 2\n}\n",
+		},
+		{
+			name:           "Pretty Query Off",
+			url:            "/?pretty=false",
+			accept:         "application/json; pretty=true",
+			data:           PostViews{ID: 1, Views: 2},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"id":1,"views":2}` + "\n",
+		},
+		{
+			name:           "Marshal Failure",
+			data:           unmarshalablePayload{Updates: make(chan int)},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"error":"failed to encode response"}` + "\n",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			url := tc.url
+			if url == "" {
+				url = "/"
+			}
+			req := httptest.NewRequest(http.MethodGet, url, nil)
+			if tc.accept != "" {
+				req.Header.Set("Accept", tc.accept)
+			}
+			rr := httptest.NewRecorder()
+
+			respondWithJSON(rr, req, http.StatusCreated, tc.data)
+
+			if rr.Code != tc.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tc.expectedStatus, rr.Code)
+			}
+			if contentType := rr.Header().Get("Content-Type"); contentType != "application/json" {
+				t.Errorf("Expected Content-Type application/json, got %s", contentType)
+			}
+			if rr.Body.String() != tc.expectedBody {
+				t.Errorf("Expected body %q, got %q", tc.expectedBody, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestHeadRequests(t *testing.T) {
+	tests := []struct {
+		name            string
+		path            string
+		expectedStatus  int
+		expectedHeaders []string
+	}{
+		{name: "Existing Post", path: "/posts/1", expectedStatus: http.StatusOK, expectedHeaders: []string{"Content-Type", "Content-Length", "Last-Modified"}},
+		{name: "Missing Post", path: "/posts/999", expectedStatus: http.StatusNotFound},
+		{name: "Collection", path: "/posts", expectedStatus: http.StatusOK, expectedHeaders: []string{"Content-Type", "Content-Length", "Last-Modified"}},
+		{name: "Stats", path: "/posts/stats", expectedStatus: http.StatusOK, expectedHeaders: []string{"Content-Type", "Content-Length"}},
+		{name: "Search", path: "/posts/search?q=Post", expectedStatus: http.StatusOK, expectedHeaders: []string{"Content-Type", "Content-Length"}},
+		{name: "Neighbors", path: "/posts/1/neighbors", expectedStatus: http.StatusOK, expectedHeaders: []string{"Content-Type", "Content-Length"}},
+		{name: "Authors", path: "/authors", expectedStatus: http.StatusOK, expectedHeaders: []string{"Content-Type", "Content-Length"}},
+		{name: "Feed", path: "/posts/feed.xml", expectedStatus: http.StatusOK, expectedHeaders: []string{"Content-Type"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			NewHandler(NewPostService(setupTestRepository())).RegisterRoutes(mux)
+
+			getRecorder := httptest.NewRecorder()
+			mux.ServeHTTP(getRecorder, httptest.NewRequest(http.MethodGet, tc.path, nil))
+
+			rr := httptest.NewRecorder()
+			mux.ServeHTTP(rr, httptest.NewRequest(http.MethodHead, tc.path, nil))
+
+			if rr.Code != tc.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tc.expectedStatus, rr.Code)
+			}
+			if rr.Body.Len() != 0 {
+				t.Errorf("Expected an empty body, got %q", rr.Body.String())
+			}
+			if tc.expectedStatus != http.StatusOK {
+				return
+			}
+
+			for _, header := range tc.expectedHeaders {
+				if rr.Header().Get(header) == "" {
+					t.Errorf("Expected %s header to be set", header)
+				}
+				if rr.Header().Get(header) != getRecorder.Header().Get(header) {
+					t.Errorf("Expected %s %q to match GET, got %q", header, getRecorder.Header().Get(header), rr.Header().Get(header))
+				}
+			}
+			if length := rr.Header().Get("Content-Length"); length != "" && length != strconv.Itoa(getRecorder.Body.Len()) {
+				t.Errorf("Expected Content-Length %d, got %s", getRecorder.Body.Len(), rr.Header().Get("Content-Length"))
+			}
+		})
+	}
+}
+
+func TestGetAllPostsEnvelope(t *testing.T) {
+	mux := http.NewServeMux()
+	NewHandler(NewPostService(setupTestRepository())).RegisterRoutes(mux)
+
+	tests := []struct {
+		name            string
+		url             string
+		expectedWrapped bool
+	}{
+		{name: "Bare Array By Default", url: "/posts", expectedWrapped: false},
+		{name: "Envelope False", url: "/posts?envelope=false", expectedWrapped: false},
+		{name: "Wrapped", url: "/posts?envelope=true", expectedWrapped: true},
+		{name: "Wrapped With Fields", url: "/posts?envelope=true&fields=id", expectedWrapped: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tc.url, nil))
+
+			if rr.Code != http.StatusOK {
+				t.Fatalf("Expected status %d, got %d", http.StatusOK, rr.Code)
+			}
+
+			var posts []map[string]any
+			if tc.expectedWrapped {
+				var wrapped struct {
+					Posts []map[string]any `json:"posts"`
+				}
+				if err := json.Unmarshal(rr.Body.Bytes(), &wrapped); err != nil {
+					t.Fatalf("Expected a wrapped object, got %s", rr.Body.String())
+				}
+				posts = wrapped.Posts
+			} else if err := json.Unmarshal(rr.Body.Bytes(), &posts); err != nil {
+				t.Fatalf("Expected a bare array, got %s", rr.Body.String())
+			}
+
+			if len(posts) != 2 {
+				t.Errorf("Expected 2 posts, got %d", len(posts))
+			}
+		})
+	}
+}
+
+func TestGetAllPostsCreatedRange(t *testing.T) {
+	mux := http.NewServeMux()
+	NewHandler(NewPostService(setupTestRepository())).RegisterRoutes(mux)
+
+	tests := []struct {
+		name           string
+		url            string
+		paginated      bool
+		expectedStatus int
+		expectedIDs    []int
+		expectedTotal  int
+	}{
+		{name: "Open After", url: "/posts?createdAfter=2024-01-01T00:30:00Z", expectedStatus: http.StatusOK, expectedIDs: []int{2}},
+		{name: "Open Before", url: "/posts?createdBefore=2024-01-01T00:30:00Z", expectedStatus: http.StatusOK, expectedIDs: []int{1}},
+		{name: "Bounded", url: "/posts?createdAfter=2024-01-01T00:00:00Z&createdBefore=2024-01-01T02:00:00Z", expectedStatus: http.StatusOK, expectedIDs: []int{1, 2}},
+		{name: "Bounded And Paginated", url: "/posts?createdAfter=2024-01-01T00:00:00Z&limit=1&offset=1", paginated: true, expectedStatus: http.StatusOK, expectedIDs: []int{2}, expectedTotal: 2},
+		{name: "Inverted", url: "/posts?createdAfter=2024-01-02T00:00:00Z&createdBefore=2024-01-01T00:00:00Z", expectedStatus: http.StatusBadRequest},
+		{name: "Invalid Format", url: "/posts?createdAfter=2024-01-01", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tc.url, nil))
+
+			if rr.Code != tc.expectedStatus {
+				t.Fatalf("Expected status %d, got %d", tc.expectedStatus, rr.Code)
+			}
+			if tc.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var posts []PostRead
+			if tc.paginated {
+				var page struct {
+					Data  []PostRead `json:"data"`
+					Total int        `json:"total"`
+				}
+				if err := json.Unmarshal(rr.Body.Bytes(), &page); err != nil {
+					t.Fatalf("Failed to decode response: %v", err)
+				}
+				if page.Total != tc.expectedTotal {
+					t.Errorf("Expected total %d, got %d", tc.expectedTotal, page.Total)
+				}
+				posts = page.Data
+			} else if err := json.Unmarshal(rr.Body.Bytes(), &posts); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			ids := make([]int, len(posts))
+			for i, post := range posts {
+				ids[i] = post.ID
+			}
+			if !slices.Equal(ids, tc.expectedIDs) {
+				t.Errorf("Expected IDs %v, got %v", tc.expectedIDs, ids)
+			}
+		})
+	}
+}
+
+func TestValidatePost(t *testing.T) {
+	tests := []struct {
+		name           string
+		requestBody    interface{}
+		expectedStatus int
+		expectedFields []string
+	}{
+		{
+			name:           "Valid",
+			requestBody:    PostCreateUpdate{Title: "  New   Post ", Content: "New Content", Author: "Jane Doe"},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Missing Required Fields",
+			requestBody:    PostCreateUpdate{Content: "New Content"},
+			expectedStatus: http.StatusBadRequest,
+			expectedFields: []string{"Author", "Title"},
+		},
+		{
+			name:           "Invalid Request Body",
+			requestBody:    "invalid json",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := setupTestRepository()
+			mux := http.NewServeMux()
+			NewHandler(NewPostService(repo)).RegisterRoutes(mux)
+
+			req, err := setupTestRequest(http.MethodPost, "/posts/validate", tc.requestBody)
+			if err != nil {
+				t.Fatalf("Failed to create request: %v", err)
+			}
+			rr := httptest.NewRecorder()
+			mux.ServeHTTP(rr, req)
+
+			if rr.Code != tc.expectedStatus {
+				t.Fatalf("Expected status %d, got %d", tc.expectedStatus, rr.Code)
+			}
+			if len(repo.posts) != 2 {
+				t.Errorf("Expected validation not to store anything, got %d posts", len(repo.posts))
+			}
+
+			if tc.expectedStatus == http.StatusOK {
+				var result ValidationResult
+				if err := json.Unmarshal(rr.Body.Bytes(), &result); err != nil || !result.Valid {
+					t.Errorf("Expected {\"valid\":true}, got %s", rr.Body.String())
+				}
+			}
+			if tc.expectedFields != nil {
+				var response validationErrorResponse
+				if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+					t.Fatalf("Failed to unmarshal response: %v", err)
+				}
+				fields := make([]string, len(response.Errors))
+				for i, fieldError := range response.Errors {
+					fields[i] = fieldError.Field
+				}
+				slices.Sort(fields)
+				if !slices.Equal(fields, tc.expectedFields) {
+					t.Errorf("Expected errors for %v, got %v", tc.expectedFields, fields)
+				}
+			}
+		})
+	}
+}
+
+func TestPostImageURL(t *testing.T) {
+	tests := []struct {
+		name             string
+		body             string
+		expectedStatus   int
+		expectedImageURL string
+	}{
+		{name: "Valid URL", body: `{"title": "Title", "content": "Content", "author": "Jane Doe", "image_url": " https://example.com/cover.png "}`, expectedStatus: http.StatusCreated, expectedImageURL: "https://example.com/cover.png"},
+		{name: "No URL", body: `{"title": "Title", "content": "Content", "author": "Jane Doe"}`, expectedStatus: http.StatusCreated},
+		{name: "Invalid URL", body: `{"title": "Title", "content": "Content", "author": "Jane Doe", "image_url": "not a url"}`, expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := setupTestRepository()
+			mux := http.NewServeMux()
+			NewHandler(NewPostService(repo)).RegisterRoutes(mux)
+
+			rr := httptest.NewRecorder()
+			mux.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/posts", strings.NewReader(tc.body)))
+
+			if rr.Code != tc.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tc.expectedStatus, rr.Code, rr.Body.String())
+			}
+			if tc.expectedStatus != http.StatusCreated {
+				return
+			}
+
+			var post PostRead
+			if err := json.Unmarshal(rr.Body.Bytes(), &post); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if post.ImageURL != tc.expectedImageURL {
+				t.Errorf("Expected image URL %q, got %q", tc.expectedImageURL, post.ImageURL)
+			}
+			if stored, _ := repo.GetByID(post.ID); stored.ImageURL != tc.expectedImageURL {
+				t.Errorf("Expected stored image URL %q, got %q", tc.expectedImageURL, stored.ImageURL)
+			}
+		})
+	}
+}
+
+func TestPatchSeededPosts(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		url            string
+		body           string
+		expectedStatus int
+	}{
+		{name: "Title Only", method: http.MethodPatch, url: "/posts/1", body: `{"title": "Patched"}`, expectedStatus: http.StatusOK},
+		{name: "Bulk Title Only", method: http.MethodPatch, url: "/posts", body: `{"ids": [1, 2], "patch": {"title": "Patched"}}`, expectedStatus: http.StatusOK},
+		{name: "Same Author", method: http.MethodPatch, url: "/posts/1", body: `{"title": "Patched", "author": "Author 1"}`, expectedStatus: http.StatusOK},
+		{name: "New Invalid Author", method: http.MethodPatch, url: "/posts/1", body: `{"author": "Author 9"}`, expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// The seed data predates the author rules: every author contains a digit.
+			repo, err := LoadMapRepository("../blog_data.json", 0)
+			if err != nil {
+				t.Fatalf("Failed to load the seed data: %v", err)
+			}
+			mux := http.NewServeMux()
+			NewHandler(NewPostService(repo)).RegisterRoutes(mux)
+
+			rr := httptest.NewRecorder()
+			mux.ServeHTTP(rr, httptest.NewRequest(tc.method, tc.url, strings.NewReader(tc.body)))
+
+			if rr.Code != tc.expectedStatus {
+				t.Errorf("Expected status %d, got %d: %s", tc.expectedStatus, rr.Code, rr.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package posts
+
+import (
+	"net/http"
+)
+
+// HealthStatus is the response of the health endpoint.
+type HealthStatus struct {
+	// Status is "ok" once the repository has loaded and "starting" before.
+	Status string `json:"status"`
+	// InFlight is the number of API requests being served.
+	InFlight int `json:"in_flight"`
+}
+
+// HealthHandler reports whether gate is ready, answering 503 until it is, along with
+// the number of requests inFlight is tracking.
+func HealthHandler(gate *ReadinessGate, inFlight *InFlightTracker) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			methodNotAllowed(w, "GET, HEAD")
+			return
+		}
+		status := HealthStatus{Status: "ok", InFlight: inFlight.Count()}
+		code := http.StatusOK
+		if !gate.Ready() {
+			status.Status = "starting"
+			code = http.StatusServiceUnavailable
+		}
+		respondWithJSON(w, r, code, status)
+	})
+}
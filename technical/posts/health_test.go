@@ -0,0 +1,47 @@
+package posts
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHealthHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		ready          bool
+		expectedStatus int
+		expectedBody   HealthStatus
+	}{
+		{name: "Starting", ready: false, expectedStatus: http.StatusServiceUnavailable, expectedBody: HealthStatus{Status: "starting", InFlight: 1}},
+		{name: "Ready", ready: true, expectedStatus: http.StatusOK, expectedBody: HealthStatus{Status: "ok", InFlight: 1}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var gate ReadinessGate
+			if tc.ready {
+				gate.MarkReady()
+			}
+			var inFlight InFlightTracker
+			health := HealthHandler(&gate, &inFlight)
+			// A request held open by the tracked API shows up in the count.
+			inFlight.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				rr := httptest.NewRecorder()
+				health.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+				if rr.Code != tc.expectedStatus {
+					t.Errorf("Expected status %d, got %d", tc.expectedStatus, rr.Code)
+				}
+				var body HealthStatus
+				if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+					t.Fatalf("Failed to unmarshal response: %v", err)
+				}
+				if body != tc.expectedBody {
+					t.Errorf("Expected %+v, got %+v", tc.expectedBody, body)
+				}
+			})).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/posts", nil))
+		})
+	}
+}
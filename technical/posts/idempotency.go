@@ -0,0 +1,121 @@
+package posts
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	IdempotencyKeyHeader         = "Idempotency-Key"
+	defaultIdempotencyTTL        = 24 * time.Hour
+	defaultIdempotencyMaxEntries = 10000
+)
+
+// ErrIdempotencyKeyReused is returned when an idempotency key is sent again with a
+// different request body.
+var ErrIdempotencyKeyReused = errors.New("idempotency key was already used with a different request body")
+
+type idempotencyEntry struct {
+	key         string
+	fingerprint [sha256.Size]byte
+	done        chan struct{}
+	post        PostRead
+	err         error
+	expiresAt   time.Time
+}
+
+// idempotencyCache remembers the outcome of creates by key. Concurrent calls with
+// the same key wait for the first one instead of creating a second post.
+//
+// Finished entries are queued in the order they expire, which is the order they
+// finished in since the TTL is fixed, so expired entries are evicted from the front
+// of the queue. Once maxEntries is reached the entry closest to expiry is dropped.
+type idempotencyCache struct {
+	entries    map[string]*idempotencyEntry
+	queue      []*idempotencyEntry
+	ttl        time.Duration
+	maxEntries int
+	now        func() time.Time
+	mutex      sync.Mutex
+}
+
+func newIdempotencyCache(ttl time.Duration, maxEntries int) *idempotencyCache {
+	return &idempotencyCache{
+		entries:    make(map[string]*idempotencyEntry),
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		now:        time.Now,
+	}
+}
+
+// idempotencyFingerprint identifies a request body so that a reused key can be told
+// apart from a retry.
+func idempotencyFingerprint(data PostCreateUpdate) [sha256.Size]byte {
+	body, _ := json.Marshal(data)
+	return sha256.Sum256(body)
+}
+
+func (c *idempotencyCache) do(ctx context.Context, key string, fingerprint [sha256.Size]byte, create func() (PostRead, error)) (PostRead, error) {
+	c.mutex.Lock()
+	c.evictExpired()
+	if entry, ok := c.entries[key]; ok {
+		c.mutex.Unlock()
+		if entry.fingerprint != fingerprint {
+			return PostRead{}, ErrIdempotencyKeyReused
+		}
+		select {
+		case <-entry.done:
+			return entry.post, entry.err
+		case <-ctx.Done():
+			return PostRead{}, ctx.Err()
+		}
+	}
+	c.evictOverflow()
+	entry := &idempotencyEntry{key: key, fingerprint: fingerprint, done: make(chan struct{})}
+	c.entries[key] = entry
+	c.mutex.Unlock()
+
+	entry.post, entry.err = create()
+
+	c.mutex.Lock()
+	if entry.err != nil {
+		// Failed creates are not remembered so that the client can retry them.
+		delete(c.entries, key)
+	} else {
+		entry.expiresAt = c.now().Add(c.ttl)
+		c.queue = append(c.queue, entry)
+	}
+	c.mutex.Unlock()
+	close(entry.done)
+
+	return entry.post, entry.err
+}
+
+// evictExpired must be called with the mutex held. It stops at the first entry that
+// has not expired. In-flight entries are not queued yet and are never evicted.
+func (c *idempotencyCache) evictExpired() {
+	now := c.now()
+	for len(c.queue) > 0 && now.After(c.queue[0].expiresAt) {
+		c.dropOldest()
+	}
+}
+
+// evictOverflow must be called with the mutex held. It makes room for one more entry.
+func (c *idempotencyCache) evictOverflow() {
+	for len(c.entries) >= c.maxEntries && len(c.queue) > 0 {
+		c.dropOldest()
+	}
+}
+
+func (c *idempotencyCache) dropOldest() {
+	entry := c.queue[0]
+	c.queue[0] = nil
+	c.queue = c.queue[1:]
+	if c.entries[entry.key] == entry {
+		delete(c.entries, entry.key)
+	}
+}
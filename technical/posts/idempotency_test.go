@@ -0,0 +1,147 @@
+package posts
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+var testFingerprint = sha256.Sum256([]byte("body"))
+
+func TestIdempotencyCacheExpiry(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache := newIdempotencyCache(time.Minute, defaultIdempotencyMaxEntries)
+	cache.now = func() time.Time { return now }
+
+	calls := 0
+	create := func() (PostRead, error) {
+		calls++
+		return PostRead{ID: calls}, nil
+	}
+
+	first, _ := cache.do(context.Background(), "key", testFingerprint, create)
+	second, _ := cache.do(context.Background(), "key", testFingerprint, create)
+	if first.ID != second.ID || calls != 1 {
+		t.Errorf("Expected the cached post to be returned, got IDs %d and %d after %d calls", first.ID, second.ID, calls)
+	}
+
+	now = now.Add(2 * time.Minute)
+	third, _ := cache.do(context.Background(), "key", testFingerprint, create)
+	if third.ID == first.ID || calls != 2 {
+		t.Errorf("Expected a new create after expiry, got ID %d after %d calls", third.ID, calls)
+	}
+}
+
+func TestIdempotencyCacheDoesNotRememberErrors(t *testing.T) {
+	cache := newIdempotencyCache(time.Minute, defaultIdempotencyMaxEntries)
+
+	_, err := cache.do(context.Background(), "key", testFingerprint, func() (PostRead, error) {
+		return PostRead{}, errors.New("repository error")
+	})
+	if err == nil {
+		t.Fatal("Expected the create error to be returned")
+	}
+
+	post, err := cache.do(context.Background(), "key", testFingerprint, func() (PostRead, error) {
+		return PostRead{ID: 1}, nil
+	})
+	if err != nil || post.ID != 1 {
+		t.Errorf("Expected the retry to create a post, got %v, %v", post, err)
+	}
+}
+
+func TestIdempotencyCacheConcurrentSameKey(t *testing.T) {
+	cache := newIdempotencyCache(time.Minute, defaultIdempotencyMaxEntries)
+
+	var mutex sync.Mutex
+	calls := 0
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cache.do(context.Background(), "key", testFingerprint, func() (PostRead, error) {
+				mutex.Lock()
+				defer mutex.Unlock()
+				calls++
+				return PostRead{ID: calls}, nil
+			})
+		}()
+	}
+	wg.Wait()
+
+	if calls != 1 {
+		t.Errorf("Expected exactly one create, got %d", calls)
+	}
+}
+
+func TestIdempotencyCacheRejectsDifferentBody(t *testing.T) {
+	cache := newIdempotencyCache(time.Minute, defaultIdempotencyMaxEntries)
+	create := func() (PostRead, error) { return PostRead{ID: 1}, nil }
+
+	if _, err := cache.do(context.Background(), "key", testFingerprint, create); err != nil {
+		t.Fatalf("Expected the first create to succeed, got %v", err)
+	}
+
+	_, err := cache.do(context.Background(), "key", sha256.Sum256([]byte("other body")), create)
+	if !errors.Is(err, ErrIdempotencyKeyReused) {
+		t.Errorf("Expected ErrIdempotencyKeyReused, got %v", err)
+	}
+}
+
+func TestIdempotencyCacheMaxEntries(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache := newIdempotencyCache(time.Minute, 2)
+	cache.now = func() time.Time { return now }
+
+	calls := 0
+	create := func() (PostRead, error) {
+		calls++
+		return PostRead{ID: calls}, nil
+	}
+
+	for _, key := range []string{"first", "second", "third"} {
+		cache.do(context.Background(), key, testFingerprint, create)
+		now = now.Add(time.Second)
+	}
+
+	if len(cache.entries) != 2 {
+		t.Errorf("Expected 2 remembered keys, got %d", len(cache.entries))
+	}
+	if _, ok := cache.entries["first"]; ok {
+		t.Error("Expected the oldest key to be evicted")
+	}
+
+	post, _ := cache.do(context.Background(), "third", testFingerprint, create)
+	if post.ID != 3 || calls != 3 {
+		t.Errorf("Expected the newest key to still be remembered, got ID %d after %d calls", post.ID, calls)
+	}
+}
+
+func TestIdempotencyCacheWaiterHonoursContext(t *testing.T) {
+	cache := newIdempotencyCache(time.Minute, defaultIdempotencyMaxEntries)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	go cache.do(context.Background(), "key", testFingerprint, func() (PostRead, error) {
+		close(started)
+		<-release
+		return PostRead{ID: 1}, nil
+	})
+	<-started
+	defer close(release)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := cache.do(ctx, "key", testFingerprint, func() (PostRead, error) {
+		t.Error("Expected the waiter not to create a post")
+		return PostRead{}, nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
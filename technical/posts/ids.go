@@ -0,0 +1,89 @@
+package posts
+
+import (
+	"crypto/rand"
+	"fmt"
+	"github.com/google/uuid"
+	"math/big"
+)
+
+const (
+	IDStrategySequential = "sequential"
+	IDStrategyRandom     = "random"
+	IDStrategyUUID       = "uuid"
+)
+
+// IDGenerator chooses the ID of each new post. next is one above the highest ID the
+// repository has ever used and taken reports whether an ID is in use. MapRepository
+// calls it with its write lock held.
+type IDGenerator interface {
+	NextID(next int, taken func(id int) bool) int
+}
+
+// SequentialIDGenerator hands out next, so IDs count up from 1 and are never reused.
+// Should next already be taken, it moves on to the first free ID after it. It is the default.
+type SequentialIDGenerator struct{}
+
+func (SequentialIDGenerator) NextID(next int, taken func(id int) bool) int {
+	for taken(next) {
+		next++
+	}
+	return next
+}
+
+// maxRandomID keeps random IDs exactly representable as JavaScript numbers.
+const maxRandomID = 1<<53 - 1
+
+// RandomIDGenerator hands out unpredictable IDs between 1 and 2^53-1, so that they do
+// not reveal how many posts exist and are unlikely to collide between instances.
+type RandomIDGenerator struct{}
+
+func (RandomIDGenerator) NextID(next int, taken func(id int) bool) int {
+	for {
+		n, err := rand.Int(rand.Reader, big.NewInt(maxRandomID))
+		if err != nil {
+			panic(err)
+		}
+		if id := int(n.Int64()) + 1; !taken(id) {
+			return id
+		}
+	}
+}
+
+// UIDGenerator is implemented by IDGenerators that also give each new post a string
+// identifier, its UID, which unlike its ID is unique across instances.
+type UIDGenerator interface {
+	NewUID() string
+}
+
+// UUIDGenerator numbers posts like SequentialIDGenerator and gives each a random UUID
+// as its UID, so that posts created by different instances can be told apart.
+type UUIDGenerator struct {
+	SequentialIDGenerator
+}
+
+func (UUIDGenerator) NewUID() string {
+	return uuid.NewString()
+}
+
+// newUID returns a UID from uids, or none if uids is nil.
+func newUID(uids UIDGenerator) string {
+	if uids == nil {
+		return ""
+	}
+	return uids.NewUID()
+}
+
+// NewIDGenerator returns the generator for an ID_STRATEGY value; empty means sequential.
+func NewIDGenerator(strategy string) (IDGenerator, error) {
+	switch strategy {
+	case "", IDStrategySequential:
+		return SequentialIDGenerator{}, nil
+	case IDStrategyRandom:
+		return RandomIDGenerator{}, nil
+	case IDStrategyUUID:
+		return UUIDGenerator{}, nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownIDStrategy, strategy)
+	}
+}
@@ -0,0 +1,175 @@
+package posts
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"testing"
+)
+
+func TestMapRepositoryIDGenerators(t *testing.T) {
+	tests := []struct {
+		name string
+		ids  IDGenerator
+	}{
+		{name: "Sequential", ids: SequentialIDGenerator{}},
+		{name: "Random", ids: RandomIDGenerator{}},
+		{name: "UUID", ids: UUIDGenerator{}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := setupTestRepository()
+			repo.SetIDGenerator(tc.ids)
+
+			single, err := repo.Create(PostCreateUpdate{Title: "Single", Content: "Content", Author: "Author"})
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			batch, err := repo.CreateMany([]PostCreateUpdate{
+				{Title: "First", Content: "Content", Author: "Author"},
+				{Title: "Second", Content: "Content", Author: "Author"},
+			})
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			seen := map[int]bool{1: true, 2: true}
+			for _, created := range append(batch, single) {
+				if created.ID < 1 || created.ID > maxRandomID {
+					t.Errorf("Expected an ID between 1 and %d, got %d", maxRandomID, created.ID)
+				}
+				if seen[created.ID] {
+					t.Errorf("Expected ID %d to be unique", created.ID)
+				}
+				seen[created.ID] = true
+
+				post, err := repo.GetByID(created.ID)
+				if err != nil {
+					t.Fatalf("Expected post %d to be retrievable, got %v", created.ID, err)
+				}
+				if post.Title != created.Title {
+					t.Errorf("Expected title %q, got %q", created.Title, post.Title)
+				}
+			}
+		})
+	}
+}
+
+func TestUUIDGeneratorUIDs(t *testing.T) {
+	repo := setupTestRepository()
+	repo.SetIDGenerator(UUIDGenerator{})
+
+	single, err := repo.Create(PostCreateUpdate{Title: "Single", Content: "Content", Author: "Author"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	batch, err := repo.CreateMany([]PostCreateUpdate{
+		{Title: "First", Content: "Content", Author: "Author"},
+		{Title: "Second", Content: "Content", Author: "Author"},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	upserted, _, err := repo.Upsert(10, PostCreateUpdate{Title: "Upserted", Content: "Content", Author: "Author"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	seen := map[string]bool{}
+	for _, created := range append(batch, single, upserted) {
+		if _, err := uuid.Parse(created.UID); err != nil {
+			t.Errorf("Expected post %d to have a UUID, got %q", created.ID, created.UID)
+		}
+		if seen[created.UID] {
+			t.Errorf("Expected UID %s to be unique", created.UID)
+		}
+		seen[created.UID] = true
+
+		post, err := repo.GetByUID(created.UID)
+		if err != nil {
+			t.Fatalf("Expected post %s to be retrievable, got %v", created.UID, err)
+		}
+		if post.ID != created.ID || post.Title != created.Title {
+			t.Errorf("Expected post %d %q, got %d %q", created.ID, created.Title, post.ID, post.Title)
+		}
+	}
+
+	updated, err := repo.Update(single.ID, PostCreateUpdate{Title: "Updated", Content: "Content", Author: "Author"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if updated.UID != single.UID {
+		t.Errorf("Expected the update to keep UID %s, got %q", single.UID, updated.UID)
+	}
+	if _, err := repo.GetByUID("00000000-0000-0000-0000-000000000000"); !errors.Is(err, ErrPostNotFound) {
+		t.Errorf("Expected ErrPostNotFound for an unknown UID, got %v", err)
+	}
+}
+
+func TestSequentialIDGeneratorIsDefault(t *testing.T) {
+	repo := setupTestRepository()
+
+	posts, _ := repo.CreateMany([]PostCreateUpdate{
+		{Title: "First", Content: "Content", Author: "Author"},
+		{Title: "Second", Content: "Content", Author: "Author"},
+	})
+	if posts[0].ID != 3 || posts[1].ID != 4 {
+		t.Errorf("Expected IDs 3 and 4, got %d and %d", posts[0].ID, posts[1].ID)
+	}
+}
+
+func TestSequentialIDsContinueAfterRandomOnes(t *testing.T) {
+	repo := setupTestRepository()
+	repo.SetIDGenerator(RandomIDGenerator{})
+	random, _ := repo.Create(PostCreateUpdate{Title: "Random", Content: "Content", Author: "Author"})
+
+	repo.SetIDGenerator(SequentialIDGenerator{})
+	sequential, _ := repo.Create(PostCreateUpdate{Title: "Sequential", Content: "Content", Author: "Author"})
+
+	if expected := max(random.ID, 2) + 1; sequential.ID != expected {
+		t.Errorf("Expected sequential ID %d, got %d", expected, sequential.ID)
+	}
+}
+
+func TestRandomIDGeneratorSkipsTakenIDs(t *testing.T) {
+	calls := 0
+	id := RandomIDGenerator{}.NextID(1, func(id int) bool {
+		calls++
+		return calls < 3
+	})
+	if calls != 3 {
+		t.Errorf("Expected 3 draws before a free ID, got %d", calls)
+	}
+	if id < 1 || id > maxRandomID {
+		t.Errorf("Expected an ID between 1 and %d, got %d", maxRandomID, id)
+	}
+}
+
+// idGeneratorFunc adapts a function to IDGenerator.
+type idGeneratorFunc func(next int, taken func(id int) bool) int
+
+func (f idGeneratorFunc) NextID(next int, taken func(id int) bool) int {
+	return f(next, taken)
+}
+
+func TestSequentialIDGeneratorSkipsTakenIDs(t *testing.T) {
+	repo := setupTestRepository()
+	// A next ID that lags behind the stored posts must not overwrite them.
+	repo.nextID = 1
+
+	created, err := repo.Create(PostCreateUpdate{Title: "Created", Content: "Content", Author: "Author"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if created.ID != 3 {
+		t.Errorf("Expected ID 3, got %d", created.ID)
+	}
+	if post, _ := repo.GetByID(1); post.Title != "Test Post 1" {
+		t.Errorf("Expected post 1 to be kept, got %+v", post)
+	}
+
+	next, _ := repo.Create(PostCreateUpdate{Title: "Next", Content: "Content", Author: "Author"})
+	if next.ID != 4 {
+		t.Errorf("Expected ID 4, got %d", next.ID)
+	}
+}
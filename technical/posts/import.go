@@ -0,0 +1,134 @@
+package posts
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const maxImportSize = 10 << 20
+
+var ErrInvalidImport = errors.New("invalid import")
+
+type importRow struct {
+	line int
+	data PostCreateUpdate
+}
+
+// parseImportCSV reads a CSV whose header names the title, content and author
+// columns (in any order; other columns such as id are ignored). Rows that cannot
+// be parsed are reported as row errors rather than aborting the whole import.
+func parseImportCSV(r io.Reader) ([]importRow, []ImportRowError, error) {
+	reader := csv.NewReader(r)
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: cannot read CSV header: %w", ErrInvalidImport, err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range []string{"title", "content", "author"} {
+		if _, ok := columns[name]; !ok {
+			return nil, nil, fmt.Errorf("%w: missing %q column", ErrInvalidImport, name)
+		}
+	}
+
+	var rows []importRow
+	var rowErrors []ImportRowError
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		var parseError *csv.ParseError
+		if errors.As(err, &parseError) {
+			rowErrors = append(rowErrors, ImportRowError{Line: parseError.StartLine, Error: parseError.Err.Error()})
+			continue
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %w", ErrInvalidImport, err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		rows = append(rows, importRow{
+			line: line,
+			data: PostCreateUpdate{
+				Title:   record[columns["title"]],
+				Content: record[columns["content"]],
+				Author:  record[columns["author"]],
+			},
+		})
+	}
+	return rows, rowErrors, nil
+}
+
+// isBodyTooLarge reports whether err comes from reading past the http.MaxBytesReader limit.
+func isBodyTooLarge(err error) bool {
+	var maxBytesError *http.MaxBytesError
+	return errors.As(err, &maxBytesError)
+}
+
+func importErrorMessage(err error) string {
+	return newValidationError(err).Error()
+}
+
+// ImportPosts handles POST /posts/import
+// @Summary Import posts from CSV
+// @Description Create posts from a CSV upload (multipart field "file" or a raw text/csv body) with title, content and author columns
+// @Tags posts
+// @Accept text/csv
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file false "CSV file"
+// @Param atomic query bool false "Create nothing if any row is invalid"
+// @Success 200 {object} ImportResult
+// @Failure 400 {object} string "Invalid CSV upload"
+// @Failure 413 {object} string "The upload is larger than 10 MiB"
+// @Failure 422 {object} ImportResult "Atomic import rejected because of invalid rows"
+// @Failure 507 {object} string "The import would exceed the repository's capacity"
+// @Failure 500 {object} string "Internal Server Error"
+// @Router /posts/import [post]
+func (h *Handler) ImportPosts(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportSize)
+
+	var body io.Reader = r.Body
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		file, _, err := r.FormFile("file")
+		if isBodyTooLarge(err) {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		if err != nil {
+			http.Error(w, "Missing CSV file", http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+		body = file
+	}
+	atomic := r.URL.Query().Get("atomic") == "true"
+
+	result, err := h.service.ImportPosts(r.Context(), body, atomic)
+	if err != nil {
+		if isBodyTooLarge(err) {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+		} else if errors.Is(err, ErrInvalidImport) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		} else if errors.Is(err, ErrCapacityExceeded) {
+			http.Error(w, err.Error(), http.StatusInsufficientStorage)
+		} else {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	if atomic && len(result.Errors) > 0 {
+		respondWithJSON(w, r, http.StatusUnprocessableEntity, result)
+		return
+	}
+	respondWithJSON(w, r, http.StatusOK, result)
+}
@@ -0,0 +1,200 @@
+package posts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestImportPosts(t *testing.T) {
+	tests := []struct {
+		name               string
+		query              string
+		csv                string
+		expectedStatus     int
+		expectedCreatedIDs []int
+		expectedErrorLines []int
+	}{
+		{
+			name: "Clean Import",
+			csv: "title,content,author\n" +
+				"First Post,First Content,Jane Doe\n" +
+				"Second Post,\"Multi\nline content\",John Smith\n",
+			expectedStatus:     http.StatusOK,
+			expectedCreatedIDs: []int{3, 4},
+			expectedErrorLines: []int{},
+		},
+		{
+			name: "Columns In Any Order With ID",
+			csv: "id,author,title,content\n" +
+				"7,Jane Doe,First Post,First Content\n",
+			expectedStatus:     http.StatusOK,
+			expectedCreatedIDs: []int{3},
+			expectedErrorLines: []int{},
+		},
+		{
+			name: "One Invalid Row",
+			csv: "title,content,author\n" +
+				"First Post,First Content,Jane Doe\n" +
+				"Second Post,,John Smith\n" +
+				"Third Post,Third Content\n" +
+				"Fourth Post,Fourth Content,John Smith\n",
+			expectedStatus:     http.StatusOK,
+			expectedCreatedIDs: []int{3, 4},
+			expectedErrorLines: []int{3, 4},
+		},
+		{
+			name:  "Atomic Import With Invalid Row",
+			query: "?atomic=true",
+			csv: "title,content,author\n" +
+				"First Post,First Content,Jane Doe\n" +
+				"Second Post,,John Smith\n",
+			expectedStatus:     http.StatusUnprocessableEntity,
+			expectedCreatedIDs: []int{},
+			expectedErrorLines: []int{3},
+		},
+		{
+			name: "Duplicate Of Existing Post",
+			csv: "title,content,author\n" +
+				"Test Post 1,Other Content,Test Author 1\n" +
+				"First Post,First Content,Jane Doe\n",
+			expectedStatus:     http.StatusOK,
+			expectedCreatedIDs: []int{3},
+			expectedErrorLines: []int{2},
+		},
+		{
+			name: "Duplicate Rows",
+			csv: "title,content,author\n" +
+				"First Post,First Content,Jane Doe\n" +
+				"First Post,Other Content,Jane Doe\n",
+			expectedStatus:     http.StatusOK,
+			expectedCreatedIDs: []int{3},
+			expectedErrorLines: []int{3},
+		},
+		{
+			name:           "Missing Column",
+			csv:            "title,content\nFirst Post,First Content\n",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := setupTestRepository()
+			mux := http.NewServeMux()
+			NewHandler(NewPostService(repo)).RegisterRoutes(mux)
+
+			req := httptest.NewRequest(http.MethodPost, "/posts/import"+tc.query, strings.NewReader(tc.csv))
+			req.Header.Set("Content-Type", "text/csv")
+			rr := httptest.NewRecorder()
+
+			mux.ServeHTTP(rr, req)
+
+			if rr.Code != tc.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tc.expectedStatus, rr.Code, rr.Body.String())
+			}
+			if tc.expectedStatus == http.StatusBadRequest {
+				return
+			}
+
+			var result ImportResult
+			if err := json.Unmarshal(rr.Body.Bytes(), &result); err != nil {
+				t.Fatalf("Failed to unmarshal response: %v", err)
+			}
+			if !slices.Equal(result.CreatedIDs, tc.expectedCreatedIDs) {
+				t.Errorf("Expected created IDs %v, got %v", tc.expectedCreatedIDs, result.CreatedIDs)
+			}
+
+			errorLines := []int{}
+			for _, rowError := range result.Errors {
+				errorLines = append(errorLines, rowError.Line)
+			}
+			if !slices.Equal(errorLines, tc.expectedErrorLines) {
+				t.Errorf("Expected error lines %v, got %v", tc.expectedErrorLines, errorLines)
+			}
+
+			posts, _ := repo.GetAll(context.Background())
+			if len(posts) != 2+len(tc.expectedCreatedIDs) {
+				t.Errorf("Expected %d posts in repository, got %d", 2+len(tc.expectedCreatedIDs), len(posts))
+			}
+		})
+	}
+}
+
+func TestImportPostsMultipart(t *testing.T) {
+	repo := setupTestRepository()
+	mux := http.NewServeMux()
+	NewHandler(NewPostService(repo)).RegisterRoutes(mux)
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", "posts.csv")
+	if err != nil {
+		t.Fatalf("Failed to create form file: %v", err)
+	}
+	part.Write([]byte("title,content,author\nFirst Post,First Content,Jane Doe\n"))
+	writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/posts/import", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	rr := httptest.NewRecorder()
+
+	mux.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+
+	var result ImportResult
+	if err := json.Unmarshal(rr.Body.Bytes(), &result); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if !slices.Equal(result.CreatedIDs, []int{3}) {
+		t.Errorf("Expected created IDs [3], got %v", result.CreatedIDs)
+	}
+}
+
+func TestImportPostsTooLarge(t *testing.T) {
+	oversized := "title,content,author\n" + strings.Repeat("x", maxImportSize)
+
+	var multipartBody bytes.Buffer
+	writer := multipart.NewWriter(&multipartBody)
+	part, err := writer.CreateFormFile("file", "posts.csv")
+	if err != nil {
+		t.Fatalf("Failed to create form file: %v", err)
+	}
+	part.Write([]byte(oversized))
+	writer.Close()
+
+	tests := []struct {
+		name        string
+		body        []byte
+		contentType string
+	}{
+		{name: "Raw CSV", body: []byte(oversized), contentType: "text/csv"},
+		{name: "Multipart", body: multipartBody.Bytes(), contentType: writer.FormDataContentType()},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			NewHandler(NewPostService(setupTestRepository())).RegisterRoutes(mux)
+
+			req := httptest.NewRequest(http.MethodPost, "/posts/import", bytes.NewReader(tc.body))
+			req.Header.Set("Content-Type", tc.contentType)
+			rr := httptest.NewRecorder()
+
+			mux.ServeHTTP(rr, req)
+
+			if rr.Code != http.StatusRequestEntityTooLarge {
+				t.Errorf("Expected status %d, got %d: %s", http.StatusRequestEntityTooLarge, rr.Code, rr.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package posts
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	OutcomeSuccess = "success"
+	OutcomeError   = "error"
+)
+
+// RepositoryRecorder receives one observation per call made through an
+// InstrumentedRepository: the method name, its outcome (OutcomeSuccess or
+// OutcomeError) and how long it took. PrometheusRepositoryRecorder is one.
+type RepositoryRecorder interface {
+	ObserveRepositoryCall(method, outcome string, duration time.Duration)
+}
+
+// InstrumentedRepository passes every call through to repo and reports it to a
+// RepositoryRecorder. It can wrap, and be wrapped by, the other Repository decorators.
+type InstrumentedRepository struct {
+	repo     Repository
+	recorder RepositoryRecorder
+}
+
+// instrumentedTransactor is the InstrumentedRepository of a repository that is a
+// Transactor, so that wrapping it keeps transactions available.
+type instrumentedTransactor struct {
+	*InstrumentedRepository
+}
+
+// NewInstrumentedRepository wraps repo so that its calls are reported to recorder. The
+// result is a Transactor if repo is one. Other optional interfaces, such as Reloader
+// and io.Closer, are not passed on and should be used on repo itself.
+func NewInstrumentedRepository(repo Repository, recorder RepositoryRecorder) Repository {
+	instrumented := &InstrumentedRepository{repo: repo, recorder: recorder}
+	if _, ok := repo.(Transactor); ok {
+		return instrumentedTransactor{instrumented}
+	}
+	return instrumented
+}
+
+// observe reports the call to method that started at start and ended with *err.
+func (r *InstrumentedRepository) observe(method string, start time.Time, err *error) {
+	outcome := OutcomeSuccess
+	if *err != nil {
+		outcome = OutcomeError
+	}
+	r.recorder.ObserveRepositoryCall(method, outcome, time.Since(start))
+}
+
+func (r *InstrumentedRepository) GetAll(ctx context.Context) (posts []PostRead, err error) {
+	defer r.observe("GetAll", time.Now(), &err)
+	return r.repo.GetAll(ctx)
+}
+
+func (r *InstrumentedRepository) List(ctx context.Context, params ListParams) (posts []PostRead, total int, err error) {
+	defer r.observe("List", time.Now(), &err)
+	return r.repo.List(ctx, params)
+}
+
+// Iterate is timed as a whole, including the time spent in fn.
+func (r *InstrumentedRepository) Iterate(ctx context.Context, fn func(post PostRead) error) (err error) {
+	defer r.observe("Iterate", time.Now(), &err)
+	return r.repo.Iterate(ctx, fn)
+}
+
+func (r *InstrumentedRepository) GetByID(id int) (post PostRead, err error) {
+	defer r.observe("GetByID", time.Now(), &err)
+	return r.repo.GetByID(id)
+}
+
+func (r *InstrumentedRepository) GetByIDs(ids []int) (posts []PostRead, err error) {
+	defer r.observe("GetByIDs", time.Now(), &err)
+	return r.repo.GetByIDs(ids)
+}
+
+func (r *InstrumentedRepository) Exists(id int) (exists bool, err error) {
+	defer r.observe("Exists", time.Now(), &err)
+	return r.repo.Exists(id)
+}
+
+func (r *InstrumentedRepository) Create(data PostCreateUpdate) (post PostRead, err error) {
+	defer r.observe("Create", time.Now(), &err)
+	return r.repo.Create(data)
+}
+
+func (r *InstrumentedRepository) CreateMany(data []PostCreateUpdate) (posts []PostRead, err error) {
+	defer r.observe("CreateMany", time.Now(), &err)
+	return r.repo.CreateMany(data)
+}
+
+func (r *InstrumentedRepository) Update(id int, data PostCreateUpdate) (post PostRead, err error) {
+	defer r.observe("Update", time.Now(), &err)
+	return r.repo.Update(id, data)
+}
+
+func (r *InstrumentedRepository) Upsert(id int, data PostCreateUpdate) (post PostRead, created bool, err error) {
+	defer r.observe("Upsert", time.Now(), &err)
+	return r.repo.Upsert(id, data)
+}
+
+func (r *InstrumentedRepository) Delete(id int) (err error) {
+	defer r.observe("Delete", time.Now(), &err)
+	return r.repo.Delete(id)
+}
+
+func (r *InstrumentedRepository) DeleteMany(ids []int) (deleted []int, err error) {
+	defer r.observe("DeleteMany", time.Now(), &err)
+	return r.repo.DeleteMany(ids)
+}
+
+func (r *InstrumentedRepository) UpdateIfUnmodified(id int, data PostCreateUpdate, since time.Time) (post PostRead, err error) {
+	defer r.observe("UpdateIfUnmodified", time.Now(), &err)
+	return r.repo.UpdateIfUnmodified(id, data, since)
+}
+
+func (r *InstrumentedRepository) UpdateIfMatch(id int, data PostCreateUpdate, etag string) (post PostRead, err error) {
+	defer r.observe("UpdateIfMatch", time.Now(), &err)
+	return r.repo.UpdateIfMatch(id, data, etag)
+}
+
+func (r *InstrumentedRepository) DeleteIfUnmodified(id int, since time.Time) (err error) {
+	defer r.observe("DeleteIfUnmodified", time.Now(), &err)
+	return r.repo.DeleteIfUnmodified(id, since)
+}
+
+func (r *InstrumentedRepository) IncrementViews(id int) (views int, err error) {
+	defer r.observe("IncrementViews", time.Now(), &err)
+	return r.repo.IncrementViews(id)
+}
+
+func (r *InstrumentedRepository) CountByAuthor(ctx context.Context) (counts map[string]int, err error) {
+	defer r.observe("CountByAuthor", time.Now(), &err)
+	return r.repo.CountByAuthor(ctx)
+}
+
+func (r *InstrumentedRepository) Authors(ctx context.Context) (authors []string, err error) {
+	defer r.observe("Authors", time.Now(), &err)
+	return r.repo.Authors(ctx)
+}
+
+func (r *InstrumentedRepository) GetRecent(ctx context.Context, n int) (posts []PostRead, err error) {
+	defer r.observe("GetRecent", time.Now(), &err)
+	return r.repo.GetRecent(ctx, n)
+}
+
+func (r *InstrumentedRepository) GetRandom() (post PostRead, err error) {
+	defer r.observe("GetRandom", time.Now(), &err)
+	return r.repo.GetRandom()
+}
+
+func (r *InstrumentedRepository) Neighbors(ctx context.Context, id int) (prev, next *PostRead, err error) {
+	defer r.observe("Neighbors", time.Now(), &err)
+	return r.repo.Neighbors(ctx, id)
+}
+
+func (r *InstrumentedRepository) ExistsByTitleAndAuthor(title, author string) (exists bool, err error) {
+	defer r.observe("ExistsByTitleAndAuthor", time.Now(), &err)
+	return r.repo.ExistsByTitleAndAuthor(title, author)
+}
+
+// WithTx is timed as a whole, including the time spent in fn; the calls fn makes on
+// the transaction are recorded as well.
+func (r instrumentedTransactor) WithTx(fn func(tx Repository) error) (err error) {
+	defer r.observe("WithTx", time.Now(), &err)
+	return r.repo.(Transactor).WithTx(func(tx Repository) error {
+		return fn(&InstrumentedRepository{repo: tx, recorder: r.recorder})
+	})
+}
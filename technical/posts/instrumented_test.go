@@ -0,0 +1,100 @@
+package posts
+
+import (
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+)
+
+type repositoryCall struct {
+	method  string
+	outcome string
+}
+
+// fakeRecorder is a RepositoryRecorder that keeps the calls it observes.
+type fakeRecorder struct {
+	mutex sync.Mutex
+	calls []repositoryCall
+}
+
+func (f *fakeRecorder) ObserveRepositoryCall(method, outcome string, duration time.Duration) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.calls = append(f.calls, repositoryCall{method: method, outcome: outcome})
+}
+
+func (f *fakeRecorder) count(call repositoryCall) int {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	n := 0
+	for _, c := range f.calls {
+		if c == call {
+			n++
+		}
+	}
+	return n
+}
+
+func TestInstrumentedRepository(t *testing.T) {
+	recorder := &fakeRecorder{}
+	repo := NewInstrumentedRepository(setupTestRepository(), recorder)
+
+	if _, err := repo.GetByID(1); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := repo.GetByID(1); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := repo.GetByID(99); err == nil {
+		t.Fatal("Expected an error for a missing post")
+	}
+	if err := repo.Delete(99); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		call          repositoryCall
+		expectedCount int
+	}{
+		{name: "Success", call: repositoryCall{method: "GetByID", outcome: OutcomeSuccess}, expectedCount: 2},
+		{name: "Error", call: repositoryCall{method: "GetByID", outcome: OutcomeError}, expectedCount: 1},
+		{name: "Other Method", call: repositoryCall{method: "Delete", outcome: OutcomeSuccess}, expectedCount: 1},
+		{name: "Not Called", call: repositoryCall{method: "Create", outcome: OutcomeSuccess}, expectedCount: 0},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := recorder.count(tc.call); got != tc.expectedCount {
+				t.Errorf("Expected %d %s calls with outcome %s, got %d", tc.expectedCount, tc.call.method, tc.call.outcome, got)
+			}
+		})
+	}
+}
+
+func TestInstrumentedRepositoryComposes(t *testing.T) {
+	recorder := &fakeRecorder{}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	repo := NewInstrumentedRepository(NewSlowQueryRepository(setupTestRepository(), time.Second, logger), recorder)
+
+	transactor, ok := repo.(Transactor)
+	if !ok {
+		t.Fatal("Expected the decorated MapRepository to remain a Transactor")
+	}
+	err := transactor.WithTx(func(tx Repository) error {
+		_, err := tx.Update(99, PostCreateUpdate{Title: "Title", Content: "Content", Author: "Jane Doe"})
+		return err
+	})
+	if err == nil {
+		t.Fatal("Expected updating a missing post to fail")
+	}
+
+	if got := recorder.count(repositoryCall{method: "Update", outcome: OutcomeError}); got != 1 {
+		t.Errorf("Expected 1 failed Update inside the transaction, got %d", got)
+	}
+	if got := recorder.count(repositoryCall{method: "WithTx", outcome: OutcomeError}); got != 1 {
+		t.Errorf("Expected 1 failed WithTx, got %d", got)
+	}
+}
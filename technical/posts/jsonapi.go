@@ -0,0 +1,187 @@
+package posts
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const jsonAPIMediaType = "application/vnd.api+json"
+
+type jsonAPIPostAttributes struct {
+	UID         string    `json:"uid,omitempty"`
+	Title       string    `json:"title"`
+	Content     string    `json:"content"`
+	Author      string    `json:"author"`
+	Status      string    `json:"status"`
+	Views       int       `json:"views"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	Tags        []string  `json:"tags,omitempty"`
+	ImageURL    string    `json:"image_url,omitempty"`
+	ContentHTML string    `json:"content_html,omitempty"`
+	Truncated   bool      `json:"truncated,omitempty"`
+	WordCount   *int      `json:"word_count,omitempty"`
+	CharCount   *int      `json:"char_count,omitempty"`
+}
+
+type jsonAPILinks struct {
+	Self string `json:"self"`
+}
+
+type jsonAPIResource struct {
+	Type       string                `json:"type"`
+	ID         string                `json:"id"`
+	Attributes jsonAPIPostAttributes `json:"attributes"`
+	Links      jsonAPILinks          `json:"links"`
+}
+
+type jsonAPIPageLinks struct {
+	Self string `json:"self"`
+	PageLinks
+}
+
+type jsonAPIMeta struct {
+	Total int `json:"total"`
+}
+
+type jsonAPIDocument struct {
+	Data  interface{}  `json:"data"`
+	Links interface{}  `json:"links,omitempty"`
+	Meta  *jsonAPIMeta `json:"meta,omitempty"`
+}
+
+// wantsJSONAPI reports whether the client asked for the JSON:API media type.
+func wantsJSONAPI(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), jsonAPIMediaType)
+}
+
+// newJSONAPIResource links the resource to its URL below base, the path the routes are mounted under.
+func newJSONAPIResource(base string, post PostRead) jsonAPIResource {
+	id := strconv.Itoa(post.ID)
+	return jsonAPIResource{
+		Type: "posts",
+		ID:   id,
+		Attributes: jsonAPIPostAttributes{
+			UID:         post.UID,
+			Title:       post.Title,
+			Content:     post.Content,
+			Author:      post.Author,
+			Status:      post.Status,
+			Views:       post.Views,
+			CreatedAt:   post.CreatedAt,
+			UpdatedAt:   post.UpdatedAt,
+			Tags:        post.Tags,
+			ImageURL:    post.ImageURL,
+			ContentHTML: post.ContentHTML,
+			Truncated:   post.Truncated,
+			WordCount:   post.WordCount,
+			CharCount:   post.CharCount,
+		},
+		Links: jsonAPILinks{Self: base + "/posts/" + id},
+	}
+}
+
+// respondWithPost writes a single post as plain JSON or, when negotiated, as a JSON:API document.
+// Plain JSON is restricted to the fields selected by ?fields=; unknown names are
+// rejected earlier by the GET handlers and ignored here.
+func respondWithPost(w http.ResponseWriter, r *http.Request, status int, post PostRead) {
+	if wantsRenderedHTML(r) {
+		post.ContentHTML = renderMarkdown(post.Content)
+	}
+	if wantsContentStats(r) && post.WordCount == nil {
+		post = withContentStats(post)
+	}
+	if !wantsJSONAPI(r) {
+		if fields, err := parseFields(r); err == nil && fields != nil {
+			projected, err := projectPost(post, fields)
+			if err != nil {
+				respondWithJSON(w, r, http.StatusInternalServerError, errorResponse{Error: "failed to encode response"})
+				return
+			}
+			respondWithJSON(w, r, status, projected)
+			return
+		}
+		respondWithJSON(w, r, status, post)
+		return
+	}
+	writeJSON(w, r, status, jsonAPIMediaType, jsonAPIDocument{Data: newJSONAPIResource(basePath(r), post)})
+}
+
+// respondWithPosts writes a list of posts as a plain JSON array or, when negotiated, as a JSON:API document.
+func respondWithPosts(w http.ResponseWriter, r *http.Request, status int, posts []PostRead) {
+	posts = withContentStatsAll(r, withRenderedHTML(r, emptyIfNil(posts)))
+	if !wantsJSONAPI(r) {
+		data, err := plainPosts(r, posts)
+		if err != nil {
+			respondWithJSON(w, r, http.StatusInternalServerError, errorResponse{Error: "failed to encode response"})
+			return
+		}
+		if wantsEnvelope(r) {
+			respondWithJSON(w, r, status, PostList{Posts: data})
+			return
+		}
+		respondWithJSON(w, r, status, data)
+		return
+	}
+	writeJSON(w, r, status, jsonAPIMediaType, jsonAPIDocument{Data: newJSONAPIResources(basePath(r), posts), Links: &jsonAPILinks{Self: basePath(r) + "/posts"}})
+}
+
+// respondWithPostPage writes one page of posts together with the total count and navigation links.
+func respondWithPostPage(w http.ResponseWriter, r *http.Request, status int, posts []PostRead, page pagination) {
+	posts = withContentStatsAll(r, withRenderedHTML(r, emptyIfNil(posts)))
+	links := paginationLinks(r.URL, page)
+	if !wantsJSONAPI(r) {
+		data, err := plainPosts(r, posts)
+		if err != nil {
+			respondWithJSON(w, r, http.StatusInternalServerError, errorResponse{Error: "failed to encode response"})
+			return
+		}
+		respondWithJSON(w, r, status, PostPage{Data: data, Total: page.Total, Links: links})
+		return
+	}
+	writeJSON(w, r, status, jsonAPIMediaType, jsonAPIDocument{
+		Data:  newJSONAPIResources(basePath(r), posts),
+		Links: jsonAPIPageLinks{Self: r.URL.RequestURI(), PageLinks: links},
+		Meta:  &jsonAPIMeta{Total: page.Total},
+	})
+}
+
+// wantsEnvelope reports whether the client asked, with ?envelope=true, for a plain
+// collection wrapped in a PostList rather than a bare array.
+func wantsEnvelope(r *http.Request) bool {
+	return r.URL.Query().Get("envelope") == "true"
+}
+
+// plainPosts returns posts, their summaries when ?view=summary is given, or their
+// projections when ?fields= selects a subset.
+func plainPosts(r *http.Request, posts []PostRead) (interface{}, error) {
+	if summary, _ := wantsSummary(r); summary {
+		summaries := make([]PostSummary, len(posts))
+		for i, post := range posts {
+			summaries[i] = NewPostSummary(post)
+		}
+		return summaries, nil
+	}
+
+	fields, err := parseFields(r)
+	if err != nil || fields == nil {
+		return posts, nil
+	}
+	projected := make([]map[string]interface{}, len(posts))
+	for i, post := range posts {
+		if projected[i], err = projectPost(post, fields); err != nil {
+			return nil, err
+		}
+	}
+	return projected, nil
+}
+
+func newJSONAPIResources(base string, posts []PostRead) []jsonAPIResource {
+	resources := make([]jsonAPIResource, len(posts))
+	for i, post := range posts {
+		resources[i] = newJSONAPIResource(base, post)
+	}
+	return resources
+}
@@ -0,0 +1,143 @@
+package posts
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestJSONAPINegotiation(t *testing.T) {
+	mux := http.NewServeMux()
+	NewHandler(NewPostService(setupTestRepository())).RegisterRoutes(mux)
+
+	t.Run("Single Post Envelope", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/posts/1", nil)
+		req.Header.Set("Accept", "application/vnd.api+json")
+		rr := httptest.NewRecorder()
+
+		mux.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d", http.StatusOK, rr.Code)
+		}
+		if contentType := rr.Header().Get("Content-Type"); contentType != "application/vnd.api+json" {
+			t.Errorf("Expected Content-Type application/vnd.api+json, got %s", contentType)
+		}
+
+		var response struct {
+			Data jsonAPIResource `json:"data"`
+		}
+		if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Failed to unmarshal response: %v", err)
+		}
+		if response.Data.Type != "posts" || response.Data.ID != "1" {
+			t.Errorf("Expected type posts and id \"1\", got %s and %q", response.Data.Type, response.Data.ID)
+		}
+		if response.Data.Attributes.Title != "Test Post 1" {
+			t.Errorf("Expected title Test Post 1, got %s", response.Data.Attributes.Title)
+		}
+		if response.Data.Links.Self != "/posts/1" {
+			t.Errorf("Expected self link /posts/1, got %s", response.Data.Links.Self)
+		}
+	})
+
+	t.Run("Collection Envelope", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/posts", nil)
+		req.Header.Set("Accept", "application/vnd.api+json")
+		rr := httptest.NewRecorder()
+
+		mux.ServeHTTP(rr, req)
+
+		var response struct {
+			Data  []jsonAPIResource `json:"data"`
+			Links jsonAPILinks      `json:"links"`
+		}
+		if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Failed to unmarshal response: %v", err)
+		}
+		if len(response.Data) != 2 {
+			t.Errorf("Expected 2 resources, got %d", len(response.Data))
+		}
+		if response.Links.Self != "/posts" {
+			t.Errorf("Expected self link /posts, got %s", response.Links.Self)
+		}
+	})
+
+	t.Run("Plain JSON By Default", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/posts/1", nil)
+		req.Header.Set("Accept", "application/json")
+		rr := httptest.NewRecorder()
+
+		mux.ServeHTTP(rr, req)
+
+		if contentType := rr.Header().Get("Content-Type"); contentType != "application/json" {
+			t.Errorf("Expected Content-Type application/json, got %s", contentType)
+		}
+
+		var response map[string]interface{}
+		if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Failed to unmarshal response: %v", err)
+		}
+		if _, ok := response["data"]; ok {
+			t.Error("Expected a plain post without a data envelope")
+		}
+		if response["title"] != "Test Post 1" {
+			t.Errorf("Expected title Test Post 1, got %v", response["title"])
+		}
+	})
+}
+
+func TestPrettyJSONKeepsContentType(t *testing.T) {
+	mux := http.NewServeMux()
+	NewHandler(NewPostService(setupTestRepository())).RegisterRoutes(mux)
+
+	tests := []struct {
+		name                string
+		accept              string
+		expectedContentType string
+	}{
+		{name: "Plain JSON", accept: "application/json", expectedContentType: "application/json"},
+		{name: "JSON:API", accept: jsonAPIMediaType, expectedContentType: jsonAPIMediaType},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/posts/1?pretty=true", nil)
+			req.Header.Set("Accept", tc.accept)
+			rr := httptest.NewRecorder()
+			mux.ServeHTTP(rr, req)
+
+			if got := rr.Header().Get("Content-Type"); got != tc.expectedContentType {
+				t.Errorf("Expected Content-Type %s, got %s", tc.expectedContentType, got)
+			}
+			if !strings.Contains(rr.Body.String(), "\n  ") {
+				t.Errorf("Expected an indented body, got %s", rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestJSONAPIImageURL(t *testing.T) {
+	repo := setupTestRepository()
+	repo.Update(1, PostCreateUpdate{Title: "Test Post 1", Content: "Test Content 1", Author: "Test Author 1", ImageURL: "https://example.com/cover.png"})
+	mux := http.NewServeMux()
+	NewHandler(NewPostService(repo)).RegisterRoutes(mux)
+
+	req := httptest.NewRequest(http.MethodGet, "/posts/1", nil)
+	req.Header.Set("Accept", "application/vnd.api+json")
+	rr := httptest.NewRecorder()
+
+	mux.ServeHTTP(rr, req)
+
+	var response struct {
+		Data jsonAPIResource `json:"data"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if response.Data.Attributes.ImageURL != "https://example.com/cover.png" {
+		t.Errorf("Expected image URL https://example.com/cover.png, got %q", response.Data.Attributes.ImageURL)
+	}
+}
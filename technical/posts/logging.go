@@ -0,0 +1,39 @@
+package posts
+
+import (
+	"io"
+	"log/slog"
+	"strings"
+)
+
+const (
+	LogFormatText = "text"
+	LogFormatJSON = "json"
+)
+
+// NewLogger builds the logger described by level (debug, info, warn or error) and
+// format (text or json) that writes to w. Unknown values fall back to info and text,
+// and the logger warns about them rather than failing startup.
+func NewLogger(level, format string, w io.Writer) *slog.Logger {
+	var slogLevel slog.Level
+	levelErr := slogLevel.UnmarshalText([]byte(level))
+	if levelErr != nil {
+		slogLevel = slog.LevelInfo
+	}
+	options := &slog.HandlerOptions{Level: slogLevel}
+
+	var logger *slog.Logger
+	switch strings.ToLower(format) {
+	case LogFormatJSON:
+		logger = slog.New(slog.NewJSONHandler(w, options))
+	default:
+		logger = slog.New(slog.NewTextHandler(w, options))
+		if !strings.EqualFold(format, LogFormatText) {
+			logger.Warn("unknown log format, using text", "format", format)
+		}
+	}
+	if levelErr != nil {
+		logger.Warn("unknown log level, using info", "level", level)
+	}
+	return logger
+}
@@ -0,0 +1,64 @@
+package posts
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestNewLogger(t *testing.T) {
+	tests := []struct {
+		name            string
+		level           string
+		format          string
+		expectedLevel   slog.Level
+		expectedJSON    bool
+		expectedWarning string
+	}{
+		{name: "Debug Text", level: "debug", format: "text", expectedLevel: slog.LevelDebug},
+		{name: "Info Text", level: "info", format: "text", expectedLevel: slog.LevelInfo},
+		{name: "Warn JSON", level: "warn", format: "json", expectedLevel: slog.LevelWarn, expectedJSON: true},
+		{name: "Error JSON", level: "error", format: "json", expectedLevel: slog.LevelError, expectedJSON: true},
+		{name: "Upper Case", level: "DEBUG", format: "JSON", expectedLevel: slog.LevelDebug, expectedJSON: true},
+		{name: "Unknown Level", level: "verbose", format: "json", expectedLevel: slog.LevelInfo, expectedJSON: true, expectedWarning: "unknown log level"},
+		{name: "Unknown Format", level: "info", format: "xml", expectedLevel: slog.LevelInfo, expectedWarning: "unknown log format"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := NewLogger(tc.level, tc.format, &buf)
+
+			switch logger.Handler().(type) {
+			case *slog.JSONHandler:
+				if !tc.expectedJSON {
+					t.Error("Expected a text handler, got a JSON handler")
+				}
+			case *slog.TextHandler:
+				if tc.expectedJSON {
+					t.Error("Expected a JSON handler, got a text handler")
+				}
+			default:
+				t.Errorf("Expected a text or JSON handler, got %T", logger.Handler())
+			}
+
+			ctx := context.Background()
+			if !logger.Enabled(ctx, tc.expectedLevel) {
+				t.Errorf("Expected level %v to be enabled", tc.expectedLevel)
+			}
+			if tc.expectedLevel > slog.LevelDebug && logger.Enabled(ctx, tc.expectedLevel-4) {
+				t.Errorf("Expected levels below %v to be disabled", tc.expectedLevel)
+			}
+
+			if tc.expectedWarning == "" {
+				if buf.Len() != 0 {
+					t.Errorf("Expected no warnings, got %q", buf.String())
+				}
+			} else if !strings.Contains(buf.String(), tc.expectedWarning) {
+				t.Errorf("Expected a warning containing %q, got %q", tc.expectedWarning, buf.String())
+			}
+		})
+	}
+}
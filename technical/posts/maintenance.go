@@ -0,0 +1,97 @@
+package posts
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// MaintenanceLevel says how much of the API is available during maintenance.
+type MaintenanceLevel int32
+
+const (
+	// MaintenanceOff serves every request.
+	MaintenanceOff MaintenanceLevel = iota
+	// MaintenanceReadOnly serves reads and refuses writes with 503.
+	MaintenanceReadOnly
+	// MaintenanceClosed refuses every request with 503.
+	MaintenanceClosed
+)
+
+var maintenanceLevelNames = map[MaintenanceLevel]string{
+	MaintenanceOff:      "off",
+	MaintenanceReadOnly: "read-only",
+	MaintenanceClosed:   "closed",
+}
+
+func (l MaintenanceLevel) String() string {
+	if name, ok := maintenanceLevelNames[l]; ok {
+		return name
+	}
+	return fmt.Sprintf("MaintenanceLevel(%d)", int32(l))
+}
+
+// ParseMaintenanceLevel parses "off", "read-only" or "closed".
+func ParseMaintenanceLevel(s string) (MaintenanceLevel, error) {
+	for level, name := range maintenanceLevelNames {
+		if name == s {
+			return level, nil
+		}
+	}
+	return MaintenanceOff, fmt.Errorf("unknown maintenance level %q; expected off, read-only or closed", s)
+}
+
+// maintenanceRetryAfter is how long clients are told to wait while the API is under maintenance.
+const maintenanceRetryAfter = 30 * time.Second
+
+// MaintenanceMode holds the current maintenance level, which can be changed at any
+// time with Set. The zero value is off.
+type MaintenanceMode struct {
+	level atomic.Int32
+}
+
+func (m *MaintenanceMode) Set(level MaintenanceLevel) {
+	m.level.Store(int32(level))
+}
+
+func (m *MaintenanceMode) Level() MaintenanceLevel {
+	return MaintenanceLevel(m.level.Load())
+}
+
+// Middleware responds 503 with Retry-After to the requests the current level refuses.
+// GET, HEAD and OPTIONS count as reads.
+func (m *MaintenanceMode) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch level := m.Level(); {
+		case level == MaintenanceClosed,
+			level == MaintenanceReadOnly && !isReadMethod(r.Method):
+			w.Header().Set("Retry-After", strconv.Itoa(int(maintenanceRetryAfter.Seconds())))
+			respondWithJSON(w, r, http.StatusServiceUnavailable, errorResponse{Error: "the API is " + level.String() + " for maintenance"})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// readOnlyAllow lists the methods ReadOnlyMiddleware lets through.
+const readOnlyAllow = "GET, HEAD, OPTIONS"
+
+// ReadOnlyMiddleware permanently refuses every request other than a read with 405,
+// unlike the read-only maintenance level, which is temporary and answers with 503.
+// GraphQL mutations sent with GET are refused by the service's WithReadOnly option.
+func ReadOnlyMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !isReadMethod(r.Method) {
+			w.Header().Set("Allow", readOnlyAllow)
+			respondWithJSON(w, r, http.StatusMethodNotAllowed, errorResponse{Error: ErrReadOnly.Error()})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func isReadMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
@@ -0,0 +1,154 @@
+package posts
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestMaintenanceMiddleware(t *testing.T) {
+	tests := []struct {
+		name           string
+		level          MaintenanceLevel
+		method         string
+		expectedStatus int
+	}{
+		{name: "Off Read", level: MaintenanceOff, method: http.MethodGet, expectedStatus: http.StatusOK},
+		{name: "Off Write", level: MaintenanceOff, method: http.MethodPost, expectedStatus: http.StatusOK},
+		{name: "Read-Only Read", level: MaintenanceReadOnly, method: http.MethodGet, expectedStatus: http.StatusOK},
+		{name: "Read-Only Head", level: MaintenanceReadOnly, method: http.MethodHead, expectedStatus: http.StatusOK},
+		{name: "Read-Only Write", level: MaintenanceReadOnly, method: http.MethodPost, expectedStatus: http.StatusServiceUnavailable},
+		{name: "Read-Only Delete", level: MaintenanceReadOnly, method: http.MethodDelete, expectedStatus: http.StatusServiceUnavailable},
+		{name: "Closed Read", level: MaintenanceClosed, method: http.MethodGet, expectedStatus: http.StatusServiceUnavailable},
+		{name: "Closed Write", level: MaintenanceClosed, method: http.MethodPut, expectedStatus: http.StatusServiceUnavailable},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var maintenance MaintenanceMode
+			maintenance.Set(tc.level)
+			handler := maintenance.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, httptest.NewRequest(tc.method, "/posts", nil))
+
+			if rr.Code != tc.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tc.expectedStatus, rr.Code)
+			}
+			retryAfter := rr.Header().Get("Retry-After")
+			if tc.expectedStatus == http.StatusServiceUnavailable && retryAfter != "30" {
+				t.Errorf("Expected Retry-After 30, got %q", retryAfter)
+			}
+			if tc.expectedStatus == http.StatusOK && retryAfter != "" {
+				t.Errorf("Expected no Retry-After, got %q", retryAfter)
+			}
+		})
+	}
+}
+
+func TestMaintenanceModeToggle(t *testing.T) {
+	var maintenance MaintenanceMode
+	handler := maintenance.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+	post := func() int {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/posts", nil))
+		return rr.Code
+	}
+
+	if code := post(); code != http.StatusCreated {
+		t.Errorf("Expected status %d by default, got %d", http.StatusCreated, code)
+	}
+	maintenance.Set(MaintenanceReadOnly)
+	if code := post(); code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status %d in read-only mode, got %d", http.StatusServiceUnavailable, code)
+	}
+	maintenance.Set(MaintenanceOff)
+	if code := post(); code != http.StatusCreated {
+		t.Errorf("Expected status %d after switching off, got %d", http.StatusCreated, code)
+	}
+}
+
+func TestParseMaintenanceLevel(t *testing.T) {
+	for _, level := range []MaintenanceLevel{MaintenanceOff, MaintenanceReadOnly, MaintenanceClosed} {
+		parsed, err := ParseMaintenanceLevel(level.String())
+		if err != nil || parsed != level {
+			t.Errorf("Expected %s to round-trip, got %v, %v", level, parsed, err)
+		}
+	}
+	if _, err := ParseMaintenanceLevel("readonly"); err == nil {
+		t.Error("Expected an error for an unknown level")
+	}
+}
+
+func TestReadOnlyMode(t *testing.T) {
+	repo := setupTestRepository()
+	service := NewPostService(repo, WithReadOnly(true))
+	mux := http.NewServeMux()
+	NewHandler(service).RegisterRoutes(mux)
+	mux.Handle("/graphql", NewGraphQLHandler(service))
+	handler := ReadOnlyMiddleware(mux)
+
+	body := `{"title": "Title", "content": "Content", "author": "Author"}`
+	tests := []struct {
+		name           string
+		method         string
+		target         string
+		body           string
+		expectedStatus int
+	}{
+		{name: "Get All", method: http.MethodGet, target: "/posts", expectedStatus: http.StatusOK},
+		{name: "Get By ID", method: http.MethodGet, target: "/posts/1", expectedStatus: http.StatusOK},
+		{name: "Head", method: http.MethodHead, target: "/posts/1", expectedStatus: http.StatusOK},
+		{name: "Authors", method: http.MethodGet, target: "/authors", expectedStatus: http.StatusOK},
+		{name: "Create", method: http.MethodPost, target: "/posts", body: body, expectedStatus: http.StatusMethodNotAllowed},
+		{name: "Update", method: http.MethodPut, target: "/posts/1", body: body, expectedStatus: http.StatusMethodNotAllowed},
+		{name: "Patch", method: http.MethodPatch, target: "/posts/1", body: body, expectedStatus: http.StatusMethodNotAllowed},
+		{name: "Delete", method: http.MethodDelete, target: "/posts/1", expectedStatus: http.StatusMethodNotAllowed},
+		{name: "Bulk Delete", method: http.MethodDelete, target: "/posts?ids=1,2", expectedStatus: http.StatusMethodNotAllowed},
+		{name: "View", method: http.MethodPost, target: "/posts/1/view", expectedStatus: http.StatusMethodNotAllowed},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, httptest.NewRequest(tc.method, tc.target, strings.NewReader(tc.body)))
+
+			if rr.Code != tc.expectedStatus {
+				t.Fatalf("Expected status %d, got %d", tc.expectedStatus, rr.Code)
+			}
+			if tc.expectedStatus != http.StatusMethodNotAllowed {
+				return
+			}
+			if allow := rr.Header().Get("Allow"); allow != readOnlyAllow {
+				t.Errorf("Expected Allow %q, got %q", readOnlyAllow, allow)
+			}
+			if !strings.Contains(rr.Body.String(), ErrReadOnly.Error()) {
+				t.Errorf("Expected the body to explain the refusal, got %q", rr.Body.String())
+			}
+		})
+	}
+
+	t.Run("GraphQL Mutation Over GET", func(t *testing.T) {
+		query := url.QueryEscape(`mutation { deletePost(id: 1) }`)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/graphql?query="+query, nil))
+
+		if !strings.Contains(rr.Body.String(), graphQLCodeReadOnly) {
+			t.Errorf("Expected a %s error, got %s", graphQLCodeReadOnly, rr.Body.String())
+		}
+	})
+
+	if posts, _ := repo.GetAll(context.Background()); len(posts) != 2 {
+		t.Errorf("Expected the posts to be left unchanged, got %d posts", len(posts))
+	}
+	if post, _ := repo.GetByID(1); post.Views != 0 {
+		t.Errorf("Expected no views to be counted, got %d", post.Views)
+	}
+}
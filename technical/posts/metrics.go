@@ -0,0 +1,144 @@
+package posts
+
+import (
+	"context"
+	"github.com/prometheus/client_golang/prometheus"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MetricsMiddleware records http_requests_total and http_request_duration_seconds,
+// labeled by route, method and status, on reg. basePath is the prefix the post routes
+// are mounted under; it is left out of the route label.
+func MetricsMiddleware(reg prometheus.Registerer, basePath string) func(http.Handler) http.Handler {
+	requests := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "Total number of HTTP requests.",
+	}, []string{"route", "method", "status"})
+	duration := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "HTTP request latency in seconds.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"route", "method", "status"})
+	reg.MustRegister(requests, duration)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+
+			next.ServeHTTP(sw, r)
+
+			labels := prometheus.Labels{
+				"route":  requestRoute(r, basePath),
+				"method": r.Method,
+				"status": strconv.Itoa(sw.status),
+			}
+			requests.With(labels).Inc()
+			duration.With(labels).Observe(time.Since(start).Seconds())
+		})
+	}
+}
+
+// PrometheusRepositoryRecorder is a RepositoryRecorder that records calls as
+// repository_calls_total and repository_call_duration_seconds, labeled by method and outcome.
+type PrometheusRepositoryRecorder struct {
+	calls    *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+// NewPrometheusRepositoryRecorder creates the repository metrics on reg.
+func NewPrometheusRepositoryRecorder(reg prometheus.Registerer) *PrometheusRepositoryRecorder {
+	r := &PrometheusRepositoryRecorder{
+		calls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "repository_calls_total",
+			Help: "Total number of repository calls.",
+		}, []string{"method", "outcome"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "repository_call_duration_seconds",
+			Help:    "Repository call latency in seconds.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "outcome"}),
+	}
+	reg.MustRegister(r.calls, r.duration)
+	return r
+}
+
+func (r *PrometheusRepositoryRecorder) ObserveRepositoryCall(method, outcome string, duration time.Duration) {
+	labels := prometheus.Labels{"method": method, "outcome": outcome}
+	r.calls.With(labels).Inc()
+	r.duration.With(labels).Observe(duration.Seconds())
+}
+
+// Counter is implemented by repositories that can count their posts without reading them.
+type Counter interface {
+	Count(ctx context.Context) (int, error)
+}
+
+// RegisterPostsGauge exposes the number of posts in repo as the posts_total gauge. The
+// gauge is read on every scrape, so repo should be a Counter; other repositories are
+// counted by reading every post.
+func RegisterPostsGauge(reg prometheus.Registerer, repo Repository) error {
+	count := func(ctx context.Context) (int, error) {
+		posts, err := repo.GetAll(ctx)
+		return len(posts), err
+	}
+	if counter, ok := repo.(Counter); ok {
+		count = counter.Count
+	}
+	return reg.Register(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "posts_total",
+		Help: "Number of posts in the repository.",
+	}, func() float64 {
+		n, err := count(context.Background())
+		if err != nil {
+			return 0
+		}
+		return float64(n)
+	}))
+}
+
+// postSubRoutes and postItemRoutes are the fixed routes below /posts/ and /posts/{id}/.
+var (
+	postSubRoutes  = []string{"export", "stats", "recent", "batch-get", "feed.xml", "search", "random", "validate", "import"}
+	postItemRoutes = []string{"view", "neighbors"}
+)
+
+// requestRoute returns the route label of r for the routes mounted under basePath.
+func requestRoute(r *http.Request, basePath string) string {
+	if path, ok := strings.CutPrefix(r.URL.Path, basePath); ok {
+		return routeLabel(path)
+	}
+	return "other"
+}
+
+// routeLabel returns the route template serving path, with post IDs collapsed, so that
+// the route label keeps a bounded cardinality. Paths that match no route are "other".
+func routeLabel(path string) string {
+	switch path {
+	case "/posts", "/posts/":
+		return "/posts"
+	case "/authors":
+		return "/authors"
+	}
+	if !strings.HasPrefix(path, "/posts/") {
+		return "other"
+	}
+	segments, ok := postPathSegments(path)
+	if !ok {
+		return "other"
+	}
+	switch {
+	case len(segments) == 1 && slices.Contains(postSubRoutes, segments[0]):
+		return "/posts/" + segments[0]
+	case len(segments) == 1:
+		return "/posts/{id}"
+	case len(segments) == 2 && slices.Contains(postItemRoutes, segments[1]):
+		return "/posts/{id}/" + segments[1]
+	default:
+		return "other"
+	}
+}
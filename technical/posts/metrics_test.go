@@ -0,0 +1,141 @@
+package posts
+
+import (
+	"context"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMetricsEndpoint(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	repo := setupTestRepository()
+	if err := RegisterPostsGauge(reg, repo); err != nil {
+		t.Fatalf("Failed to register posts gauge: %v", err)
+	}
+
+	mux := http.NewServeMux()
+	NewHandler(NewPostService(repo), WithBasePath("/api/v1")).RegisterRoutes(mux)
+	mux.Handle("/metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+	server := httptest.NewServer(MetricsMiddleware(reg, "/api/v1")(mux))
+	defer server.Close()
+
+	for i := 0; i < 3; i++ {
+		resp, err := http.Get(server.URL + "/api/v1/posts/1")
+		if err != nil {
+			t.Fatalf("Failed to send request: %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	resp, err := http.Get(server.URL + "/metrics")
+	if err != nil {
+		t.Fatalf("Failed to scrape metrics: %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("Failed to read metrics: %v", err)
+	}
+
+	expected := []string{
+		`http_requests_total{method="GET",route="/posts/{id}",status="200"} 3`,
+		`http_request_duration_seconds_count{method="GET",route="/posts/{id}",status="200"} 3`,
+		`posts_total 2`,
+	}
+	for _, line := range expected {
+		if !strings.Contains(string(body), line) {
+			t.Errorf("Expected metrics to contain %q", line)
+		}
+	}
+}
+
+// countingRepository is a Counter whose GetAll must not be called.
+type countingRepository struct {
+	MockRepository
+}
+
+func (countingRepository) Count(ctx context.Context) (int, error) {
+	return 5, nil
+}
+
+func TestPostsGaugeCounts(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	repo := &countingRepository{MockRepository{
+		GetAllFn: func() ([]PostRead, error) {
+			t.Error("Expected the gauge to count posts without reading them")
+			return nil, nil
+		},
+	}}
+	if err := RegisterPostsGauge(reg, repo); err != nil {
+		t.Fatalf("Failed to register posts gauge: %v", err)
+	}
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("Failed to gather metrics: %v", err)
+	}
+	if len(families) != 1 || families[0].GetMetric()[0].GetGauge().GetValue() != 5 {
+		t.Errorf("Expected posts_total 5, got %v", families)
+	}
+}
+
+func TestRouteLabel(t *testing.T) {
+	tests := map[string]string{
+		"/posts":             "/posts",
+		"/posts/":            "/posts",
+		"/posts/42":          "/posts/{id}",
+		"/posts/42/":         "/posts/{id}",
+		"/posts/7/view":      "/posts/{id}/view",
+		"/posts/7/neighbors": "/posts/{id}/neighbors",
+		"/posts/7/anything":  "other",
+		"/posts/7/view/more": "other",
+		"/posts//view":       "other",
+		"/posts/stats":       "/posts/stats",
+		"/posts/export":      "/posts/export",
+		"/posts/recent":      "/posts/recent",
+		"/posts/feed.xml":    "/posts/feed.xml",
+		"/authors":           "/authors",
+		"/swagger/":          "other",
+	}
+
+	for path, expected := range tests {
+		if got := routeLabel(path); got != expected {
+			t.Errorf("routeLabel(%q) = %q, want %q", path, got, expected)
+		}
+	}
+}
+
+func TestPrometheusRepositoryRecorder(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	repo := NewInstrumentedRepository(setupTestRepository(), NewPrometheusRepositoryRecorder(reg))
+	repo.GetByID(1)
+	repo.GetByID(99)
+
+	server := httptest.NewServer(promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+	defer server.Close()
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to scrape metrics: %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("Failed to read metrics: %v", err)
+	}
+
+	expected := []string{
+		`repository_calls_total{method="GetByID",outcome="success"} 1`,
+		`repository_calls_total{method="GetByID",outcome="error"} 1`,
+		`repository_call_duration_seconds_count{method="GetByID",outcome="success"} 1`,
+	}
+	for _, line := range expected {
+		if !strings.Contains(string(body), line) {
+			t.Errorf("Expected metrics to contain %q", line)
+		}
+	}
+}
@@ -0,0 +1,510 @@
+package posts
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"log/slog"
+	"maps"
+	"mime"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+type contextKey string
+
+const requestIDKey contextKey = "requestID"
+
+// RequestIDMiddleware tags every request with an ID taken from the X-Request-ID
+// header or freshly generated, stores it in the request context and echoes it back.
+func RequestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(RequestIDHeader)
+		if requestID == "" {
+			requestID = uuid.NewString()
+		}
+
+		w.Header().Set(RequestIDHeader, requestID)
+		ctx := context.WithValue(r.Context(), requestIDKey, requestID)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// RequestIDFromContext returns the request ID stored by RequestIDMiddleware, or "" if there is none.
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey).(string)
+	return requestID
+}
+
+// LoggingMiddleware logs one line per request with its status and duration.
+func LoggingMiddleware(logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+
+			next.ServeHTTP(sw, r)
+
+			logger.InfoContext(r.Context(), "request completed",
+				slog.String("request_id", RequestIDFromContext(r.Context())),
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+				slog.Int("status", sw.status),
+				slog.Duration("duration", time.Since(start)),
+			)
+		})
+	}
+}
+
+type statusWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer, e.g. to flush it.
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Hijack lets WebSocket upgrades pass through the logging, metrics and tracing
+// middleware; a hijacked request is recorded with status 101.
+func (w *statusWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("response writer does not support hijacking")
+	}
+	w.status = http.StatusSwitchingProtocols
+	w.wroteHeader = true
+	return hijacker.Hijack()
+}
+
+// TimeoutMiddleware cancels the request context after d and, if the handler has not
+// finished by then, responds 503 with a JSON error. Output written by the handler is
+// buffered so that it is either sent whole or discarded in favour of the timeout error.
+func TimeoutMiddleware(d time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), d)
+			defer cancel()
+
+			tw := &timeoutWriter{header: make(http.Header), status: http.StatusOK}
+			done := make(chan struct{})
+			panicked := make(chan any, 1)
+			go func() {
+				defer func() {
+					if p := recover(); p != nil {
+						panicked <- p
+					}
+				}()
+				next.ServeHTTP(tw, r.WithContext(ctx))
+				close(done)
+			}()
+
+			select {
+			case p := <-panicked:
+				panic(p)
+			case <-done:
+				tw.mutex.Lock()
+				defer tw.mutex.Unlock()
+				maps.Copy(w.Header(), tw.header)
+				w.WriteHeader(tw.status)
+				w.Write(tw.body.Bytes())
+			case <-ctx.Done():
+				tw.mutex.Lock()
+				defer tw.mutex.Unlock()
+				tw.timedOut = true
+				respondWithJSON(w, r, http.StatusServiceUnavailable, errorResponse{Error: "request timed out"})
+			}
+		})
+	}
+}
+
+type timeoutWriter struct {
+	header      http.Header
+	body        bytes.Buffer
+	status      int
+	wroteHeader bool
+	timedOut    bool
+	mutex       sync.Mutex
+}
+
+func (w *timeoutWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *timeoutWriter) WriteHeader(status int) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.timedOut || w.wroteHeader {
+		return
+	}
+	w.status = status
+	w.wroteHeader = true
+}
+
+func (w *timeoutWriter) Write(b []byte) (int, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	w.wroteHeader = true
+	return w.body.Write(b)
+}
+
+// readinessRetryAfter is how long clients are told to wait while the server warms up.
+const readinessRetryAfter = 5 * time.Second
+
+// ReadinessGate holds requests off with 503 until MarkReady is called, e.g. once the
+// repository has finished loading. The zero value is not ready.
+type ReadinessGate struct {
+	ready atomic.Bool
+}
+
+func (g *ReadinessGate) MarkReady() {
+	g.ready.Store(true)
+}
+
+func (g *ReadinessGate) Ready() bool {
+	return g.ready.Load()
+}
+
+// Middleware responds 503 with Retry-After until the gate is marked ready.
+func (g *ReadinessGate) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !g.Ready() {
+			w.Header().Set("Retry-After", strconv.Itoa(int(readinessRetryAfter.Seconds())))
+			respondWithJSON(w, r, http.StatusServiceUnavailable, errorResponse{Error: "server is starting up"})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// GzipConfig decides which responses GzipMiddleware compresses.
+type GzipConfig struct {
+	// MinSize is the smallest body, in bytes, that is worth compressing.
+	MinSize int
+	// CompressibleTypes lists the media types to compress. An entry such as "text/*"
+	// matches every subtype.
+	CompressibleTypes []string
+}
+
+func DefaultGzipConfig() GzipConfig {
+	return GzipConfig{
+		MinSize:           1024,
+		CompressibleTypes: []string{"application/json", "text/*"},
+	}
+}
+
+// GzipMiddleware compresses responses for clients that accept gzip, provided the body
+// reaches cfg.MinSize and has a compressible content type. The start of the body is
+// buffered until that can be decided, so small responses pass through untouched.
+func GzipMiddleware(cfg GzipConfig) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept-Encoding")
+			if !acceptsGzip(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			gw := &gzipWriter{ResponseWriter: w, cfg: cfg, status: http.StatusOK}
+			defer gw.Close()
+			next.ServeHTTP(gw, r)
+		})
+	}
+}
+
+// acceptsGzip reports whether the Accept-Encoding header allows gzip.
+func acceptsGzip(r *http.Request) bool {
+	for _, coding := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		name, params, _ := strings.Cut(coding, ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name != "gzip" && name != "*" {
+			continue
+		}
+		if q, ok := strings.CutPrefix(strings.ReplaceAll(params, " ", ""), "q="); ok {
+			if weight, err := strconv.ParseFloat(q, 64); err == nil && weight == 0 {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+type gzipWriter struct {
+	http.ResponseWriter
+	cfg     GzipConfig
+	status  int
+	buf     bytes.Buffer
+	decided bool
+	gz      *gzip.Writer
+}
+
+func (w *gzipWriter) WriteHeader(status int) {
+	if !w.decided {
+		w.status = status
+	}
+}
+
+func (w *gzipWriter) Write(b []byte) (int, error) {
+	if w.decided {
+		if w.gz != nil {
+			return w.gz.Write(b)
+		}
+		return w.ResponseWriter.Write(b)
+	}
+
+	w.buf.Write(b)
+	if w.buf.Len() >= w.cfg.MinSize {
+		if err := w.decide(); err != nil {
+			return 0, err
+		}
+	}
+	return len(b), nil
+}
+
+// decide sends the header, compressed or not, followed by whatever has been buffered.
+func (w *gzipWriter) decide() error {
+	w.decided = true
+
+	header := w.Header()
+	if header.Get("Content-Type") == "" && w.buf.Len() > 0 {
+		header.Set("Content-Type", http.DetectContentType(w.buf.Bytes()))
+	}
+	if w.shouldCompress() {
+		header.Set("Content-Encoding", "gzip")
+		header.Del("Content-Length")
+		w.gz = gzip.NewWriter(w.ResponseWriter)
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+
+	if w.buf.Len() == 0 {
+		return nil
+	}
+	var err error
+	if w.gz != nil {
+		_, err = w.gz.Write(w.buf.Bytes())
+	} else {
+		_, err = w.ResponseWriter.Write(w.buf.Bytes())
+	}
+	w.buf.Reset()
+	return err
+}
+
+func (w *gzipWriter) shouldCompress() bool {
+	if w.buf.Len() < w.cfg.MinSize || w.buf.Len() == 0 {
+		return false
+	}
+	if w.Header().Get("Content-Encoding") != "" {
+		return false
+	}
+	mediaType, _, err := mime.ParseMediaType(w.Header().Get("Content-Type"))
+	if err != nil {
+		return false
+	}
+	for _, compressible := range w.cfg.CompressibleTypes {
+		if prefix, ok := strings.CutSuffix(compressible, "/*"); ok {
+			if strings.HasPrefix(mediaType, prefix+"/") {
+				return true
+			}
+		} else if mediaType == compressible {
+			return true
+		}
+	}
+	return false
+}
+
+// Flush commits to the compression decision so far and pushes out what has been written.
+func (w *gzipWriter) Flush() {
+	if !w.decided {
+		if err := w.decide(); err != nil {
+			return
+		}
+	}
+	if w.gz != nil {
+		w.gz.Flush()
+	}
+	http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Close sends a response that never reached MinSize and finishes the gzip stream.
+func (w *gzipWriter) Close() error {
+	if !w.decided {
+		if err := w.decide(); err != nil {
+			return err
+		}
+	}
+	if w.gz != nil {
+		return w.gz.Close()
+	}
+	return nil
+}
+
+// CacheControlMiddleware lets clients and CDNs cache successful GET and HEAD responses
+// for maxAge, announcing it through Cache-Control and Expires. Every other response,
+// including failed reads, is marked no-store. Handlers that set Cache-Control themselves
+// keep their value.
+func CacheControlMiddleware(maxAge time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			cw := &cacheControlWriter{ResponseWriter: w, maxAge: maxAge, read: isReadMethod(r.Method) && r.Method != http.MethodOptions}
+			next.ServeHTTP(cw, r)
+		})
+	}
+}
+
+type cacheControlWriter struct {
+	http.ResponseWriter
+	maxAge      time.Duration
+	read        bool
+	wroteHeader bool
+}
+
+func (w *cacheControlWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		w.setHeaders(status)
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *cacheControlWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer, e.g. to flush it.
+func (w *cacheControlWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *cacheControlWriter) setHeaders(status int) {
+	header := w.Header()
+	if header.Get("Cache-Control") != "" {
+		return
+	}
+	if !w.read || (status != http.StatusOK && status != http.StatusNotModified) {
+		header.Set("Cache-Control", "no-store")
+		return
+	}
+	header.Set("Cache-Control", "public, max-age="+strconv.Itoa(int(w.maxAge.Seconds())))
+	header.Set("Expires", time.Now().Add(w.maxAge).UTC().Format(http.TimeFormat))
+}
+
+// CORSConfig decides which cross-origin requests CORSMiddleware allows.
+type CORSConfig struct {
+	// AllowedOrigins lists the origins that may call the API; "*" allows any origin.
+	AllowedOrigins []string
+	AllowedMethods []string
+	AllowedHeaders []string
+	// ExposedHeaders lists the response headers that scripts on other origins may read.
+	ExposedHeaders []string
+	// MaxAge is how long browsers may cache the result of a preflight request.
+	MaxAge time.Duration
+	// AllowCredentials lets browsers send cookies and Authorization headers with
+	// cross-origin requests. Browsers ignore it when the allowed origin is "*", so it
+	// cannot be combined with a wildcard in AllowedOrigins.
+	AllowCredentials bool
+}
+
+// ErrCORSWildcardCredentials is returned by CORSConfig.Validate when credentials are
+// allowed for any origin.
+var ErrCORSWildcardCredentials = errors.New(`CORS credentials cannot be allowed when the allowed origins include "*"; list the origins explicitly`)
+
+// Validate reports whether cfg is a configuration that browsers will honour.
+func (cfg CORSConfig) Validate() error {
+	if cfg.AllowCredentials && slices.Contains(cfg.AllowedOrigins, "*") {
+		return ErrCORSWildcardCredentials
+	}
+	return nil
+}
+
+func DefaultCORSConfig() CORSConfig {
+	return CORSConfig{
+		AllowedMethods: []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete},
+		AllowedHeaders: []string{"Content-Type", "Authorization", "If-Match", "If-None-Match", "If-Modified-Since", "If-Unmodified-Since", "Idempotency-Key", RequestIDHeader},
+		ExposedHeaders: []string{RequestIDHeader, "ETag", "Last-Modified", "Retry-After"},
+		MaxAge:         10 * time.Minute,
+	}
+}
+
+// CORSMiddleware adds CORS headers to requests from allowed origins and answers their
+// preflight requests itself with 204. Requests from other origins pass through without
+// CORS headers, so browsers block them. It panics if cfg does not pass Validate.
+func CORSMiddleware(cfg CORSConfig) func(http.Handler) http.Handler {
+	if err := cfg.Validate(); err != nil {
+		panic(err)
+	}
+	allowAny := slices.Contains(cfg.AllowedOrigins, "*")
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			header := w.Header()
+			header.Add("Vary", "Origin")
+			if origin == "" || (!allowAny && !slices.Contains(cfg.AllowedOrigins, origin)) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if allowAny {
+				header.Set("Access-Control-Allow-Origin", "*")
+			} else {
+				header.Set("Access-Control-Allow-Origin", origin)
+			}
+			if cfg.AllowCredentials {
+				header.Set("Access-Control-Allow-Credentials", "true")
+			}
+
+			if r.Method != http.MethodOptions || r.Header.Get("Access-Control-Request-Method") == "" {
+				if len(cfg.ExposedHeaders) > 0 {
+					header.Set("Access-Control-Expose-Headers", strings.Join(cfg.ExposedHeaders, ", "))
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			header.Add("Vary", "Access-Control-Request-Method")
+			header.Add("Vary", "Access-Control-Request-Headers")
+			header.Set("Access-Control-Allow-Methods", strings.Join(cfg.AllowedMethods, ", "))
+			if len(cfg.AllowedHeaders) > 0 {
+				header.Set("Access-Control-Allow-Headers", strings.Join(cfg.AllowedHeaders, ", "))
+			}
+			if cfg.MaxAge > 0 {
+				header.Set("Access-Control-Max-Age", strconv.Itoa(int(cfg.MaxAge.Seconds())))
+			}
+			w.WriteHeader(http.StatusNoContent)
+		})
+	}
+}
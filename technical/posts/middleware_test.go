@@ -0,0 +1,557 @@
+package posts
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"nhooyr.io/websocket"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRequestIDMiddleware(t *testing.T) {
+	tests := []struct {
+		name       string
+		incomingID string
+	}{
+		{
+			name:       "Incoming ID Preserved",
+			incomingID: "incoming-request-id",
+		},
+		{
+			name:       "ID Generated When Absent",
+			incomingID: "",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var contextID string
+			handler := RequestIDMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				contextID = RequestIDFromContext(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/posts", nil)
+			if tc.incomingID != "" {
+				req.Header.Set(RequestIDHeader, tc.incomingID)
+			}
+			rr := httptest.NewRecorder()
+
+			handler.ServeHTTP(rr, req)
+
+			responseID := rr.Header().Get(RequestIDHeader)
+			if responseID == "" {
+				t.Fatal("Expected a request ID in the response header")
+			}
+			if responseID != contextID {
+				t.Errorf("Expected context ID %s to match response ID %s", contextID, responseID)
+			}
+			if tc.incomingID != "" && responseID != tc.incomingID {
+				t.Errorf("Expected request ID %s, got %s", tc.incomingID, responseID)
+			}
+		})
+	}
+}
+
+func TestLoggingMiddlewareIncludesRequestID(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+
+	handler := RequestIDMiddleware(LoggingMiddleware(logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})))
+
+	req := httptest.NewRequest(http.MethodGet, "/posts", nil)
+	req.Header.Set(RequestIDHeader, "logged-request-id")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if !strings.Contains(logs.String(), "request_id=logged-request-id") {
+		t.Errorf("Expected log to contain the request ID, got %q", logs.String())
+	}
+	if !strings.Contains(logs.String(), "status=418") {
+		t.Errorf("Expected log to contain the response status, got %q", logs.String())
+	}
+}
+
+func TestLoggingMiddlewareAllowsWebSocketUpgrade(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+
+	hub := NewHub()
+	logged := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer close(logged)
+		LoggingMiddleware(logger)(hub).ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	conn, _, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("Expected the upgrade to succeed, got %v", err)
+	}
+	conn.Close(websocket.StatusNormalClosure, "")
+	<-logged
+
+	if !strings.Contains(logs.String(), "status=101") {
+		t.Errorf("Expected log to contain status 101, got %q", logs.String())
+	}
+}
+
+func TestTimeoutMiddleware(t *testing.T) {
+	tests := []struct {
+		name           string
+		handlerDelay   time.Duration
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "Fast Handler",
+			handlerDelay:   0,
+			expectedStatus: http.StatusCreated,
+			expectedBody:   "created",
+		},
+		{
+			name:           "Slow Handler",
+			handlerDelay:   time.Second,
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   `{"error":"request timed out"}` + "\n",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := TimeoutMiddleware(50 * time.Millisecond)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				select {
+				case <-time.After(tc.handlerDelay):
+				case <-r.Context().Done():
+					return
+				}
+				w.Header().Set("X-Handler", "done")
+				w.WriteHeader(http.StatusCreated)
+				w.Write([]byte("created"))
+			}))
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/posts", nil))
+
+			if rr.Code != tc.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tc.expectedStatus, rr.Code)
+			}
+			if rr.Body.String() != tc.expectedBody {
+				t.Errorf("Expected body %q, got %q", tc.expectedBody, rr.Body.String())
+			}
+			if tc.expectedStatus == http.StatusCreated && rr.Header().Get("X-Handler") != "done" {
+				t.Error("Expected handler headers to be copied to the response")
+			}
+		})
+	}
+}
+
+func TestTimeoutMiddlewareCancelsService(t *testing.T) {
+	repoCalled := false
+	mockRepo := &MockRepository{
+		GetAllFn: func() ([]PostRead, error) {
+			repoCalled = true
+			return nil, nil
+		},
+	}
+	service := NewPostService(mockRepo)
+
+	finished := make(chan struct{})
+	handler := TimeoutMiddleware(10 * time.Millisecond)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer close(finished)
+		<-r.Context().Done()
+		if _, err := service.GetAllPosts(r.Context()); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+		}
+	}))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/posts", nil))
+
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status %d, got %d", http.StatusServiceUnavailable, rr.Code)
+	}
+	<-finished
+	if repoCalled {
+		t.Error("Expected the repository not to be called after the deadline")
+	}
+}
+
+func TestReadinessGate(t *testing.T) {
+	var gate ReadinessGate
+	mux := http.NewServeMux()
+	NewHandler(NewPostService(setupTestRepository())).RegisterRoutes(mux)
+	handler := gate.Middleware(mux)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/posts", nil))
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status %d before ready, got %d", http.StatusServiceUnavailable, rr.Code)
+	}
+	if rr.Header().Get("Retry-After") != "5" {
+		t.Errorf("Expected Retry-After 5, got %q", rr.Header().Get("Retry-After"))
+	}
+
+	gate.MarkReady()
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/posts", nil))
+	if rr.Code != http.StatusOK {
+		t.Errorf("Expected status %d after ready, got %d", http.StatusOK, rr.Code)
+	}
+	if rr.Header().Get("Retry-After") != "" {
+		t.Errorf("Expected no Retry-After after ready, got %q", rr.Header().Get("Retry-After"))
+	}
+}
+
+func TestCacheControlMiddleware(t *testing.T) {
+	mux := http.NewServeMux()
+	NewHandler(NewPostService(setupTestRepository())).RegisterRoutes(mux)
+	handler := CacheControlMiddleware(5 * time.Minute)(mux)
+
+	tests := []struct {
+		name                 string
+		method               string
+		url                  string
+		body                 string
+		expectedStatus       int
+		expectedCacheControl string
+		expectedExpires      bool
+	}{
+		{name: "Get All", method: http.MethodGet, url: "/posts", expectedStatus: http.StatusOK, expectedCacheControl: "public, max-age=300", expectedExpires: true},
+		{name: "Get By ID", method: http.MethodGet, url: "/posts/1", expectedStatus: http.StatusOK, expectedCacheControl: "public, max-age=300", expectedExpires: true},
+		{name: "Get Missing", method: http.MethodGet, url: "/posts/999", expectedStatus: http.StatusNotFound, expectedCacheControl: "no-store"},
+		{name: "Create", method: http.MethodPost, url: "/posts", body: `{"title": "New", "content": "Content", "author": "Author"}`, expectedStatus: http.StatusCreated, expectedCacheControl: "no-store"},
+		{name: "Delete", method: http.MethodDelete, url: "/posts/2", expectedStatus: http.StatusNoContent, expectedCacheControl: "no-store"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.url, strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			rr := httptest.NewRecorder()
+			before := time.Now()
+			handler.ServeHTTP(rr, req)
+
+			if rr.Code != tc.expectedStatus {
+				t.Fatalf("Expected status %d, got %d", tc.expectedStatus, rr.Code)
+			}
+			if got := rr.Header().Get("Cache-Control"); got != tc.expectedCacheControl {
+				t.Errorf("Expected Cache-Control %q, got %q", tc.expectedCacheControl, got)
+			}
+
+			expires := rr.Header().Get("Expires")
+			if !tc.expectedExpires {
+				if expires != "" {
+					t.Errorf("Expected no Expires header, got %q", expires)
+				}
+				return
+			}
+			at, err := http.ParseTime(expires)
+			if err != nil {
+				t.Fatalf("Expected a valid Expires header, got %q", expires)
+			}
+			if at.Before(before.Add(5*time.Minute).Truncate(time.Second)) || at.After(time.Now().Add(5*time.Minute)) {
+				t.Errorf("Expected Expires five minutes from now, got %v", at)
+			}
+		})
+	}
+}
+
+func TestCacheControlMiddlewareKeepsHandlerValue(t *testing.T) {
+	handler := CacheControlMiddleware(time.Minute)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "private")
+		w.Write([]byte("ok"))
+	}))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/posts", nil))
+
+	if got := rr.Header().Get("Cache-Control"); got != "private" {
+		t.Errorf("Expected Cache-Control private, got %q", got)
+	}
+}
+
+func TestCORSMiddleware(t *testing.T) {
+	cfg := DefaultCORSConfig()
+	cfg.AllowedOrigins = []string{"https://blog.example.com"}
+	cfg.MaxAge = 30 * time.Minute
+	mux := http.NewServeMux()
+	NewHandler(NewPostService(setupTestRepository())).RegisterRoutes(mux)
+	handler := CORSMiddleware(cfg)(mux)
+
+	tests := []struct {
+		name                 string
+		method               string
+		origin               string
+		requestMethod        string
+		expectedStatus       int
+		expectedAllowOrigin  string
+		expectedMaxAge       string
+		expectedExposeHeader bool
+	}{
+		{name: "Preflight", method: http.MethodOptions, origin: "https://blog.example.com", requestMethod: http.MethodPut, expectedStatus: http.StatusNoContent, expectedAllowOrigin: "https://blog.example.com", expectedMaxAge: "1800"},
+		{name: "Simple Request", method: http.MethodGet, origin: "https://blog.example.com", expectedStatus: http.StatusOK, expectedAllowOrigin: "https://blog.example.com", expectedExposeHeader: true},
+		{name: "Disallowed Origin", method: http.MethodOptions, origin: "https://evil.example.com", requestMethod: http.MethodPut, expectedStatus: http.StatusNoContent},
+		{name: "Same Origin", method: http.MethodGet, expectedStatus: http.StatusOK},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, "/posts", nil)
+			if tc.origin != "" {
+				req.Header.Set("Origin", tc.origin)
+			}
+			if tc.requestMethod != "" {
+				req.Header.Set("Access-Control-Request-Method", tc.requestMethod)
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			if rr.Code != tc.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tc.expectedStatus, rr.Code)
+			}
+			if got := rr.Header().Get("Access-Control-Allow-Origin"); got != tc.expectedAllowOrigin {
+				t.Errorf("Expected Access-Control-Allow-Origin %q, got %q", tc.expectedAllowOrigin, got)
+			}
+			if got := rr.Header().Get("Access-Control-Max-Age"); got != tc.expectedMaxAge {
+				t.Errorf("Expected Access-Control-Max-Age %q, got %q", tc.expectedMaxAge, got)
+			}
+			exposed := rr.Header().Get("Access-Control-Expose-Headers")
+			if tc.expectedExposeHeader != (strings.Contains(exposed, RequestIDHeader) && strings.Contains(exposed, "ETag")) {
+				t.Errorf("Expected exposed headers %v, got %q", tc.expectedExposeHeader, exposed)
+			}
+		})
+	}
+}
+
+func TestCORSMiddlewareAnyOrigin(t *testing.T) {
+	cfg := DefaultCORSConfig()
+	cfg.AllowedOrigins = []string{"*"}
+	handler := CORSMiddleware(cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected the preflight not to reach the handler")
+	}))
+
+	req := httptest.NewRequest(http.MethodOptions, "/posts/1", nil)
+	req.Header.Set("Origin", "https://anywhere.example.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodDelete)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if got := rr.Header().Get("Access-Control-Allow-Origin"); got != "*" {
+		t.Errorf("Expected Access-Control-Allow-Origin *, got %q", got)
+	}
+	if got := rr.Header().Get("Access-Control-Allow-Methods"); !strings.Contains(got, http.MethodDelete) {
+		t.Errorf("Expected DELETE to be allowed, got %q", got)
+	}
+	if got := rr.Header().Get("Access-Control-Max-Age"); got != "600" {
+		t.Errorf("Expected the default Access-Control-Max-Age 600, got %q", got)
+	}
+	allowedHeaders := strings.Split(rr.Header().Get("Access-Control-Allow-Headers"), ", ")
+	for _, header := range []string{"If-Match", "If-None-Match", "If-Modified-Since", "If-Unmodified-Since"} {
+		if !slices.Contains(allowedHeaders, header) {
+			t.Errorf("Expected %s to be an allowed header, got %v", header, allowedHeaders)
+		}
+	}
+}
+
+func TestCORSMiddlewareCredentials(t *testing.T) {
+	cfg := DefaultCORSConfig()
+	cfg.AllowedOrigins = []string{"https://blog.example.com"}
+	cfg.AllowCredentials = true
+	handler := CORSMiddleware(cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name                     string
+		origin                   string
+		requestMethod            string
+		expectedStatus           int
+		expectedAllowOrigin      string
+		expectedAllowCredentials string
+	}{
+		{name: "Preflight", origin: "https://blog.example.com", requestMethod: http.MethodPatch, expectedStatus: http.StatusNoContent, expectedAllowOrigin: "https://blog.example.com", expectedAllowCredentials: "true"},
+		{name: "Simple Request", origin: "https://blog.example.com", expectedStatus: http.StatusOK, expectedAllowOrigin: "https://blog.example.com", expectedAllowCredentials: "true"},
+		{name: "Disallowed Origin", origin: "https://evil.example.com", expectedStatus: http.StatusOK},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			method := http.MethodGet
+			if tc.requestMethod != "" {
+				method = http.MethodOptions
+			}
+			req := httptest.NewRequest(method, "/posts", nil)
+			req.Header.Set("Origin", tc.origin)
+			if tc.requestMethod != "" {
+				req.Header.Set("Access-Control-Request-Method", tc.requestMethod)
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			if rr.Code != tc.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tc.expectedStatus, rr.Code)
+			}
+			if got := rr.Header().Get("Access-Control-Allow-Origin"); got != tc.expectedAllowOrigin {
+				t.Errorf("Expected Access-Control-Allow-Origin %q, got %q", tc.expectedAllowOrigin, got)
+			}
+			if got := rr.Header().Get("Access-Control-Allow-Credentials"); got != tc.expectedAllowCredentials {
+				t.Errorf("Expected Access-Control-Allow-Credentials %q, got %q", tc.expectedAllowCredentials, got)
+			}
+		})
+	}
+}
+
+func TestCORSConfigValidate(t *testing.T) {
+	tests := []struct {
+		name             string
+		origins          []string
+		allowCredentials bool
+		expectedErr      error
+	}{
+		{name: "Wildcard Without Credentials", origins: []string{"*"}},
+		{name: "Listed Origins With Credentials", origins: []string{"https://blog.example.com"}, allowCredentials: true},
+		{name: "Wildcard With Credentials", origins: []string{"https://blog.example.com", "*"}, allowCredentials: true, expectedErr: ErrCORSWildcardCredentials},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := DefaultCORSConfig()
+			cfg.AllowedOrigins = tc.origins
+			cfg.AllowCredentials = tc.allowCredentials
+			if err := cfg.Validate(); !errors.Is(err, tc.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tc.expectedErr, err)
+			}
+		})
+	}
+
+	t.Run("Middleware Panics", func(t *testing.T) {
+		defer func() {
+			if p := recover(); p != ErrCORSWildcardCredentials {
+				t.Errorf("Expected a panic with %v, got %v", ErrCORSWildcardCredentials, p)
+			}
+		}()
+		cfg := DefaultCORSConfig()
+		cfg.AllowedOrigins = []string{"*"}
+		cfg.AllowCredentials = true
+		CORSMiddleware(cfg)
+	})
+}
+
+func TestGzipMiddleware(t *testing.T) {
+	largeJSON := `{"content":"` + strings.Repeat("a", 2048) + `"}`
+	cfg := GzipConfig{MinSize: 1024, CompressibleTypes: []string{"application/json", "text/*"}}
+
+	tests := []struct {
+		name               string
+		acceptEncoding     string
+		contentType        string
+		body               string
+		expectedCompressed bool
+	}{
+		{name: "Below Threshold", acceptEncoding: "gzip", contentType: "application/json", body: `{"id":1}`, expectedCompressed: false},
+		{name: "Above Threshold JSON", acceptEncoding: "gzip", contentType: "application/json", body: largeJSON, expectedCompressed: true},
+		{name: "Above Threshold Text Subtype", acceptEncoding: "gzip, deflate", contentType: "text/csv", body: largeJSON, expectedCompressed: true},
+		{name: "Incompressible Type", acceptEncoding: "gzip", contentType: "image/png", body: largeJSON, expectedCompressed: false},
+		{name: "Client Without Gzip", acceptEncoding: "", contentType: "application/json", body: largeJSON, expectedCompressed: false},
+		{name: "Client Refusing Gzip", acceptEncoding: "gzip;q=0", contentType: "application/json", body: largeJSON, expectedCompressed: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := GzipMiddleware(cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tc.contentType)
+				w.WriteHeader(http.StatusCreated)
+				// Written in small pieces so that the decision is made mid-body.
+				for _, chunk := range strings.SplitAfter(tc.body, "a") {
+					w.Write([]byte(chunk))
+				}
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/posts", nil)
+			if tc.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", tc.acceptEncoding)
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			if rr.Code != http.StatusCreated {
+				t.Errorf("Expected status %d, got %d", http.StatusCreated, rr.Code)
+			}
+			if vary := rr.Header().Get("Vary"); vary != "Accept-Encoding" {
+				t.Errorf("Expected Vary Accept-Encoding, got %q", vary)
+			}
+
+			body := rr.Body.String()
+			compressed := rr.Header().Get("Content-Encoding") == "gzip"
+			if compressed != tc.expectedCompressed {
+				t.Fatalf("Expected compressed %v, got Content-Encoding %q", tc.expectedCompressed, rr.Header().Get("Content-Encoding"))
+			}
+			if compressed {
+				reader, err := gzip.NewReader(rr.Body)
+				if err != nil {
+					t.Fatalf("Failed to open gzip body: %v", err)
+				}
+				decompressed, err := io.ReadAll(reader)
+				if err != nil {
+					t.Fatalf("Failed to read gzip body: %v", err)
+				}
+				body = string(decompressed)
+			}
+			if body != tc.body {
+				t.Errorf("Expected body of %d bytes, got %d bytes", len(tc.body), len(body))
+			}
+		})
+	}
+}
+
+func TestGzipMiddlewareEmptyResponse(t *testing.T) {
+	handler := GzipMiddleware(DefaultGzipConfig())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	req := httptest.NewRequest(http.MethodDelete, "/posts/1", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusNoContent {
+		t.Errorf("Expected status %d, got %d", http.StatusNoContent, rr.Code)
+	}
+	if rr.Header().Get("Content-Encoding") != "" || rr.Header().Get("Content-Type") != "" || rr.Body.Len() != 0 {
+		t.Errorf("Expected an empty, unencoded response, got headers %v and body %q", rr.Header(), rr.Body.String())
+	}
+}
+
+func TestMiddlewaresPassFlushesThrough(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	handler := LoggingMiddleware(logger)(GzipMiddleware(DefaultGzipConfig())(CacheControlMiddleware(time.Minute)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("partial"))
+		if err := http.NewResponseController(w).Flush(); err != nil {
+			t.Errorf("Expected flushing to be supported, got %v", err)
+		}
+	}))))
+
+	req := httptest.NewRequest(http.MethodGet, "/posts/export", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if !rr.Flushed {
+		t.Error("Expected the response to be flushed")
+	}
+	if rr.Body.String() != "partial" {
+		t.Errorf("Expected the small body uncompressed, got %q", rr.Body.String())
+	}
+}
@@ -0,0 +1,143 @@
+package posts
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+var (
+	errInvalidPagination = errors.New("limit must be a positive integer and offset a non-negative integer")
+	errLimitTooLarge     = errors.New("limit exceeds the maximum page size")
+)
+
+// PaginationConfig sets the page sizes of GET /posts.
+type PaginationConfig struct {
+	// DefaultLimit is the page size when offset is given without limit.
+	DefaultLimit int
+	// MaxLimit is the largest page size served.
+	MaxLimit int
+	// RejectOverMax answers a limit above MaxLimit with 400 instead of clamping it to MaxLimit.
+	RejectOverMax bool
+}
+
+// DefaultPaginationConfig returns pages of 20 posts, clamping requests for more than 100.
+func DefaultPaginationConfig() PaginationConfig {
+	return PaginationConfig{
+		DefaultLimit: DefaultPageLimit,
+		MaxLimit:     MaxPageLimit,
+	}
+}
+
+// withDefaults replaces non-positive sizes with the defaults and keeps DefaultLimit
+// within MaxLimit.
+func (c PaginationConfig) withDefaults() PaginationConfig {
+	if c.MaxLimit <= 0 {
+		c.MaxLimit = MaxPageLimit
+	}
+	if c.DefaultLimit <= 0 {
+		c.DefaultLimit = DefaultPageLimit
+	}
+	c.DefaultLimit = min(c.DefaultLimit, c.MaxLimit)
+	return c
+}
+
+type pagination struct {
+	Limit  int
+	Offset int
+	Total  int
+}
+
+// PageLinks navigates a paginated collection; a link is null when there is no such page.
+type PageLinks struct {
+	First *string `json:"first"`
+	Prev  *string `json:"prev"`
+	Next  *string `json:"next"`
+	Last  *string `json:"last"`
+}
+
+// PostPage is the collection response when limit or offset is given.
+type PostPage struct {
+	Data  interface{} `json:"data" swaggertype:"array,object"`
+	Total int         `json:"total"`
+	Links PageLinks   `json:"links"`
+}
+
+// parsePagination reads limit and offset, reporting false when neither is present
+// so that unpaginated requests keep receiving a plain array. An omitted limit is
+// cfg.DefaultLimit; one above cfg.MaxLimit is clamped to it, or rejected with
+// errLimitTooLarge when cfg.RejectOverMax is set.
+func parsePagination(r *http.Request, cfg PaginationConfig) (pagination, bool, error) {
+	query := r.URL.Query()
+	if !query.Has("limit") && !query.Has("offset") {
+		return pagination{}, false, nil
+	}
+
+	page := pagination{Limit: cfg.DefaultLimit}
+	if raw := query.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 {
+			return pagination{}, false, errInvalidPagination
+		}
+		if limit > cfg.MaxLimit && cfg.RejectOverMax {
+			return pagination{}, false, fmt.Errorf("%w of %d", errLimitTooLarge, cfg.MaxLimit)
+		}
+		page.Limit = min(limit, cfg.MaxLimit)
+	}
+	if raw := query.Get("offset"); raw != "" {
+		offset, err := strconv.Atoi(raw)
+		if err != nil || offset < 0 {
+			return pagination{}, false, errInvalidPagination
+		}
+		page.Offset = offset
+	}
+	return page, true, nil
+}
+
+// paginationLinks builds the first, prev, next and last links for page from the request
+// URL, keeping its other query parameters.
+func paginationLinks(u *url.URL, page pagination) PageLinks {
+	link := func(offset int) *string {
+		query := u.Query()
+		query.Set("limit", strconv.Itoa(page.Limit))
+		query.Set("offset", strconv.Itoa(offset))
+		s := u.Path + "?" + query.Encode()
+		return &s
+	}
+
+	lastOffset := 0
+	if page.Total > 0 {
+		lastOffset = (page.Total - 1) / page.Limit * page.Limit
+	}
+
+	links := PageLinks{
+		First: link(0),
+		Last:  link(lastOffset),
+	}
+	if page.Offset > 0 {
+		links.Prev = link(max(page.Offset-page.Limit, 0))
+	}
+	if page.Offset+page.Limit < page.Total {
+		links.Next = link(page.Offset + page.Limit)
+	}
+	return links
+}
+
+// pageOf returns the items of page, for collections that are paged in memory.
+func pageOf[T any](items []T, page pagination) []T {
+	start, end := pageBounds(ListParams{Limit: page.Limit, Offset: page.Offset}, len(items))
+	return items[start:end]
+}
+
+// hasPrefixFold reports whether s starts with prefix, ignoring case.
+func hasPrefixFold(s, prefix string) bool {
+	return strings.HasPrefix(strings.ToLower(s), strings.ToLower(prefix))
+}
@@ -0,0 +1,223 @@
+package posts
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestPaginationLinks(t *testing.T) {
+	u, _ := url.Parse("/posts?fields=id&limit=10")
+
+	tests := []struct {
+		name         string
+		page         pagination
+		expectedPrev string
+		expectedNext string
+		expectedLast string
+	}{
+		{
+			name:         "First Page",
+			page:         pagination{Limit: 10, Offset: 0, Total: 25},
+			expectedNext: "/posts?fields=id&limit=10&offset=10",
+			expectedLast: "/posts?fields=id&limit=10&offset=20",
+		},
+		{
+			name:         "Middle Page",
+			page:         pagination{Limit: 10, Offset: 10, Total: 25},
+			expectedPrev: "/posts?fields=id&limit=10&offset=0",
+			expectedNext: "/posts?fields=id&limit=10&offset=20",
+			expectedLast: "/posts?fields=id&limit=10&offset=20",
+		},
+		{
+			name:         "Last Page",
+			page:         pagination{Limit: 10, Offset: 20, Total: 25},
+			expectedPrev: "/posts?fields=id&limit=10&offset=10",
+			expectedLast: "/posts?fields=id&limit=10&offset=20",
+		},
+		{
+			name:         "Unaligned Offset",
+			page:         pagination{Limit: 10, Offset: 5, Total: 25},
+			expectedPrev: "/posts?fields=id&limit=10&offset=0",
+			expectedNext: "/posts?fields=id&limit=10&offset=15",
+			expectedLast: "/posts?fields=id&limit=10&offset=20",
+		},
+		{
+			name:         "Empty Collection",
+			page:         pagination{Limit: 10, Offset: 0, Total: 0},
+			expectedLast: "/posts?fields=id&limit=10&offset=0",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			links := paginationLinks(u, tc.page)
+
+			if links.First == nil || *links.First != "/posts?fields=id&limit=10&offset=0" {
+				t.Errorf("Expected first link /posts?fields=id&limit=10&offset=0, got %v", links.First)
+			}
+			assertLink(t, "prev", links.Prev, tc.expectedPrev)
+			assertLink(t, "next", links.Next, tc.expectedNext)
+			assertLink(t, "last", links.Last, tc.expectedLast)
+		})
+	}
+}
+
+func assertLink(t *testing.T, name string, link *string, expected string) {
+	t.Helper()
+
+	if expected == "" {
+		if link != nil {
+			t.Errorf("Expected no %s link, got %s", name, *link)
+		}
+		return
+	}
+	if link == nil || *link != expected {
+		t.Errorf("Expected %s link %s, got %v", name, expected, link)
+	}
+}
+
+func TestGetAllPostsPaginated(t *testing.T) {
+	mux := http.NewServeMux()
+	NewHandler(NewPostService(setupTestRepository())).RegisterRoutes(mux)
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectedIDs    []int
+		expectedNext   bool
+		expectedPrev   bool
+	}{
+		{name: "First Page", query: "?limit=1", expectedStatus: http.StatusOK, expectedIDs: []int{1}, expectedNext: true},
+		{name: "Last Page", query: "?limit=1&offset=1", expectedStatus: http.StatusOK, expectedIDs: []int{2}, expectedPrev: true},
+		{name: "Offset Past End", query: "?offset=5", expectedStatus: http.StatusOK, expectedIDs: []int{}, expectedPrev: true},
+		{name: "Invalid Limit", query: "?limit=0", expectedStatus: http.StatusBadRequest},
+		{name: "Invalid Offset", query: "?offset=-1", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/posts"+tc.query, nil))
+
+			if rr.Code != tc.expectedStatus {
+				t.Fatalf("Expected status %d, got %d", tc.expectedStatus, rr.Code)
+			}
+			if tc.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var response struct {
+				Data  []PostRead `json:"data"`
+				Total int        `json:"total"`
+				Links PageLinks  `json:"links"`
+			}
+			if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to unmarshal response: %v", err)
+			}
+			if response.Total != 2 {
+				t.Errorf("Expected total 2, got %d", response.Total)
+			}
+			if len(response.Data) != len(tc.expectedIDs) {
+				t.Fatalf("Expected %d posts, got %d", len(tc.expectedIDs), len(response.Data))
+			}
+			for i, id := range tc.expectedIDs {
+				if response.Data[i].ID != id {
+					t.Errorf("Expected post %d at position %d, got %d", id, i, response.Data[i].ID)
+				}
+			}
+			if (response.Links.Next != nil) != tc.expectedNext {
+				t.Errorf("Expected next link present=%v, got %v", tc.expectedNext, response.Links.Next)
+			}
+			if (response.Links.Prev != nil) != tc.expectedPrev {
+				t.Errorf("Expected prev link present=%v, got %v", tc.expectedPrev, response.Links.Prev)
+			}
+		})
+	}
+}
+
+func TestGetAllPostsPageLimits(t *testing.T) {
+	repo := setupTestRepository()
+	for i := 0; i < 3; i++ {
+		repo.Create(PostCreateUpdate{Title: "Title", Content: "Content", Author: "Author"})
+	}
+
+	tests := []struct {
+		name           string
+		rejectOverMax  bool
+		query          string
+		expectedStatus int
+		expectedCount  int
+		expectedLimit  string
+	}{
+		{name: "Omitted Limit Uses Default", query: "?offset=0", expectedStatus: http.StatusOK, expectedCount: 2, expectedLimit: "2"},
+		{name: "In Range Limit", query: "?limit=3", expectedStatus: http.StatusOK, expectedCount: 3, expectedLimit: "3"},
+		{name: "Over Max Limit Clamped", query: "?limit=50", expectedStatus: http.StatusOK, expectedCount: 3, expectedLimit: "3"},
+		{name: "Over Max Limit Rejected", rejectOverMax: true, query: "?limit=50", expectedStatus: http.StatusBadRequest},
+		{name: "In Range Limit With Rejection", rejectOverMax: true, query: "?limit=1", expectedStatus: http.StatusOK, expectedCount: 1, expectedLimit: "1"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			cfg := PaginationConfig{DefaultLimit: 2, MaxLimit: 3, RejectOverMax: tc.rejectOverMax}
+			NewHandler(NewPostService(repo), WithPagination(cfg)).RegisterRoutes(mux)
+
+			rr := httptest.NewRecorder()
+			mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/posts"+tc.query, nil))
+
+			if rr.Code != tc.expectedStatus {
+				t.Fatalf("Expected status %d, got %d", tc.expectedStatus, rr.Code)
+			}
+			if tc.expectedStatus != http.StatusOK {
+				if !strings.Contains(rr.Body.String(), "maximum page size of 3") {
+					t.Errorf("Expected the error to name the maximum, got %q", rr.Body.String())
+				}
+				return
+			}
+
+			var response struct {
+				Data  []PostRead `json:"data"`
+				Links PageLinks  `json:"links"`
+			}
+			if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to unmarshal response: %v", err)
+			}
+			if len(response.Data) != tc.expectedCount {
+				t.Errorf("Expected %d posts, got %d", tc.expectedCount, len(response.Data))
+			}
+			first, err := url.Parse(*response.Links.First)
+			if err != nil {
+				t.Fatalf("Failed to parse first link: %v", err)
+			}
+			if limit := first.Query().Get("limit"); limit != tc.expectedLimit {
+				t.Errorf("Expected links with limit %s, got %s", tc.expectedLimit, limit)
+			}
+		})
+	}
+}
+
+func TestWithPaginationDefaults(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      PaginationConfig
+		expected PaginationConfig
+	}{
+		{name: "Zero Values", cfg: PaginationConfig{}, expected: DefaultPaginationConfig()},
+		{name: "Default Above Max", cfg: PaginationConfig{DefaultLimit: 50, MaxLimit: 10}, expected: PaginationConfig{DefaultLimit: 10, MaxLimit: 10}},
+		{name: "Custom", cfg: PaginationConfig{DefaultLimit: 5, MaxLimit: 500, RejectOverMax: true}, expected: PaginationConfig{DefaultLimit: 5, MaxLimit: 500, RejectOverMax: true}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			h := NewHandler(nil, WithPagination(tc.cfg))
+			if h.pagination != tc.expected {
+				t.Errorf("Expected %+v, got %+v", tc.expected, h.pagination)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package posts
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+var errUnknownQueryParam = errors.New("unknown query parameters")
+
+// listQueryParams are the query parameters GET /posts understands.
+var listQueryParams = []string{"createdAfter", "createdBefore", "envelope", "fields", "limit", "offset", "pretty", "render", "sort", "stats", "view"}
+
+// checkQueryParams returns an error naming, in alphabetical order, every query parameter
+// of r that is not among known. It always returns nil unless strict is set, so that
+// lenient routes keep ignoring parameters they do not understand.
+func checkQueryParams(r *http.Request, strict bool, known []string) error {
+	if !strict {
+		return nil
+	}
+	var unknown []string
+	for _, key := range slices.Sorted(maps.Keys(r.URL.Query())) {
+		if !slices.Contains(known, key) {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w %s; allowed parameters are %s", errUnknownQueryParam, strings.Join(unknown, ", "), strings.Join(known, ", "))
+}
@@ -0,0 +1,74 @@
+package posts
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCheckQueryParams(t *testing.T) {
+	tests := []struct {
+		name          string
+		query         string
+		strict        bool
+		expectedError string
+	}{
+		{name: "Known Parameters", query: "?limit=10&offset=5", strict: true},
+		{name: "No Parameters", query: "", strict: true},
+		{name: "Unknown Parameter", query: "?limmit=10", strict: true, expectedError: "unknown query parameters limmit;"},
+		{name: "Unknown Parameters Sorted", query: "?zeta=1&limit=1&alpha=2", strict: true, expectedError: "unknown query parameters alpha, zeta;"},
+		{name: "Lenient", query: "?limmit=10", strict: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/posts"+tc.query, nil)
+
+			err := checkQueryParams(r, tc.strict, []string{"limit", "offset"})
+			if tc.expectedError == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, errUnknownQueryParam) {
+				t.Fatalf("Expected errUnknownQueryParam, got %v", err)
+			}
+			if !strings.HasPrefix(err.Error(), tc.expectedError) {
+				t.Errorf("Expected error starting with %q, got %q", tc.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestGetAllPostsStrictQuery(t *testing.T) {
+	tests := []struct {
+		name           string
+		strict         bool
+		query          string
+		expectedStatus int
+	}{
+		{name: "Known Parameter", strict: true, query: "?limit=1&pretty=true", expectedStatus: http.StatusOK},
+		{name: "Unknown Parameter", strict: true, query: "?limmit=10", expectedStatus: http.StatusBadRequest},
+		{name: "Unknown Parameter Ignored When Lenient", strict: false, query: "?limmit=10", expectedStatus: http.StatusOK},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			NewHandler(NewPostService(setupTestRepository()), WithStrictQuery(tc.strict)).RegisterRoutes(mux)
+
+			rr := httptest.NewRecorder()
+			mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/posts"+tc.query, nil))
+
+			if rr.Code != tc.expectedStatus {
+				t.Fatalf("Expected status %d, got %d", tc.expectedStatus, rr.Code)
+			}
+			if tc.expectedStatus == http.StatusBadRequest && !strings.Contains(rr.Body.String(), "limmit") {
+				t.Errorf("Expected the error to name the unknown parameter, got %q", rr.Body.String())
+			}
+		})
+	}
+}
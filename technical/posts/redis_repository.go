@@ -0,0 +1,602 @@
+package posts
+
+import (
+	"context"
+	"errors"
+	"github.com/redis/go-redis/v9"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	redisIDsKey    = "posts:ids"
+	redisNextIDKey = "posts:next_id"
+)
+
+// updatePostScript rewrites the editable fields of an existing post hash and returns
+// 0 without touching anything when the post does not exist.
+var updatePostScript = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 0 then
+	return 0
+end
+redis.call("HSET", KEYS[1], "title", ARGV[1], "content", ARGV[2], "author", ARGV[3], "status", ARGV[4], "tags", ARGV[5],
+	"image_url", ARGV[6], "updated_at", ARGV[7])
+return 1
+`)
+
+// upsertPostScript rewrites the editable fields of the post hash at KEYS[1] or, when it
+// does not exist, creates it and adds its ID to the set, raising the ID counter so that
+// later creates do not reuse the ID. It returns 1 if the post was created.
+var upsertPostScript = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 1 then
+	redis.call("HSET", KEYS[1], "title", ARGV[2], "content", ARGV[3], "author", ARGV[4], "status", ARGV[5], "tags", ARGV[6],
+		"image_url", ARGV[7], "updated_at", ARGV[8])
+	return 0
+end
+redis.call("HSET", KEYS[1], "id", ARGV[1], "title", ARGV[2], "content", ARGV[3], "author", ARGV[4], "status", ARGV[5],
+	"tags", ARGV[6], "image_url", ARGV[7], "views", 0, "created_at", ARGV[8], "updated_at", ARGV[8])
+redis.call("SADD", KEYS[2], ARGV[1])
+if tonumber(redis.call("GET", KEYS[3]) or "0") < tonumber(ARGV[1]) then
+	redis.call("SET", KEYS[3], ARGV[1])
+end
+return 1
+`)
+
+// incrementViewsScript increments the view counter of an existing post and returns -1
+// when the post does not exist, so that HINCRBY never creates a partial hash.
+var incrementViewsScript = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 0 then
+	return -1
+end
+return redis.call("HINCRBY", KEYS[1], "views", 1)
+`)
+
+// RedisRepository stores each post as a hash under post:{id}, keeps the set of IDs in
+// posts:ids and allocates IDs by incrementing posts:next_id, so that several API
+// instances can share one store.
+type RedisRepository struct {
+	client *redis.Client
+	now    func() time.Time
+}
+
+func NewRedisRepository(client *redis.Client) *RedisRepository {
+	return &RedisRepository{
+		client: client,
+		now:    time.Now,
+	}
+}
+
+func redisPostKey(id int) string {
+	return "post:" + strconv.Itoa(id)
+}
+
+func (r *RedisRepository) GetAll(ctx context.Context) ([]PostRead, error) {
+	members, err := r.client.SMembers(ctx, redisIDsKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, len(members))
+	for i, member := range members {
+		keys[i] = "post:" + member
+	}
+	return r.getPosts(ctx, keys)
+}
+
+// Count returns the size of the ID set.
+func (r *RedisRepository) Count(ctx context.Context) (int, error) {
+	n, err := r.client.SCard(ctx, redisIDsKey).Result()
+	return int(n), err
+}
+
+// List sorts the ID set and fetches only the hashes of the requested page. Filtering
+// by creation time and sorting by anything but ID need every post, so they fall back to
+// fetching them all.
+func (r *RedisRepository) List(ctx context.Context, params ListParams) ([]PostRead, int, error) {
+	if !params.Created.IsZero() || !params.Sort.byID() {
+		all, err := r.GetAll(ctx)
+		if err != nil {
+			return nil, 0, err
+		}
+		matching := slices.DeleteFunc(all, func(post PostRead) bool {
+			return !params.Created.Contains(post.CreatedAt)
+		})
+		sortPosts(matching, params.Sort)
+		start, end := pageBounds(params, len(matching))
+		return matching[start:end], len(matching), nil
+	}
+
+	ids, err := r.sortedIDs(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	start, end := pageBounds(params, len(ids))
+	keys := make([]string, 0, end-start)
+	for _, id := range ids[start:end] {
+		keys = append(keys, redisPostKey(id))
+	}
+	posts, err := r.getPosts(ctx, keys)
+	if err != nil {
+		return nil, 0, err
+	}
+	return posts, len(ids), nil
+}
+
+// sortedIDs returns the IDs of all posts in numeric order.
+func (r *RedisRepository) sortedIDs(ctx context.Context) ([]int, error) {
+	members, err := r.client.SMembers(ctx, redisIDsKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, len(members))
+	for i, member := range members {
+		if ids[i], err = strconv.Atoi(member); err != nil {
+			return nil, err
+		}
+	}
+	slices.Sort(ids)
+	return ids, nil
+}
+
+// redisIterateBatch is how many hashes Iterate fetches per pipeline.
+const redisIterateBatch = 100
+
+// Iterate fetches the posts a batch at a time in ID order.
+func (r *RedisRepository) Iterate(ctx context.Context, fn func(post PostRead) error) error {
+	ids, err := r.sortedIDs(ctx)
+	if err != nil {
+		return err
+	}
+
+	for batch := range slices.Chunk(ids, redisIterateBatch) {
+		keys := make([]string, len(batch))
+		for i, id := range batch {
+			keys[i] = redisPostKey(id)
+		}
+		posts, err := r.getPosts(ctx, keys)
+		if err != nil {
+			return err
+		}
+
+		for _, post := range posts {
+			if err := fn(post); err != nil {
+				if errors.Is(err, ErrStopIteration) {
+					return nil
+				}
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// getPosts fetches the post hashes at keys in one pipeline, in order.
+func (r *RedisRepository) getPosts(ctx context.Context, keys []string) ([]PostRead, error) {
+	cmds := make([]*redis.MapStringStringCmd, len(keys))
+	_, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, key := range keys {
+			cmds[i] = pipe.HGetAll(ctx, key)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	posts := make([]PostRead, 0, len(cmds))
+	for _, cmd := range cmds {
+		fields := cmd.Val()
+		// An ID whose hash is gone was deleted between SMEMBERS and HGETALL.
+		if len(fields) == 0 {
+			continue
+		}
+		post, err := postFromRedisHash(fields)
+		if err != nil {
+			return nil, err
+		}
+		posts = append(posts, post)
+	}
+	return posts, nil
+}
+
+func (r *RedisRepository) GetByID(id int) (PostRead, error) {
+	fields, err := r.client.HGetAll(context.Background(), redisPostKey(id)).Result()
+	if err != nil {
+		return PostRead{}, err
+	}
+	if len(fields) == 0 {
+		return PostRead{}, ErrPostNotFound
+	}
+	return postFromRedisHash(fields)
+}
+
+// GetByIDs fetches the hashes of all ids in one pipeline; getPosts skips those that
+// do not exist.
+func (r *RedisRepository) GetByIDs(ids []int) ([]PostRead, error) {
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = redisPostKey(id)
+	}
+	return r.getPosts(context.Background(), keys)
+}
+
+func (r *RedisRepository) Exists(id int) (bool, error) {
+	n, err := r.client.Exists(context.Background(), redisPostKey(id)).Result()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+func (r *RedisRepository) Create(data PostCreateUpdate) (PostRead, error) {
+	posts, err := r.CreateMany([]PostCreateUpdate{data})
+	if err != nil {
+		return PostRead{}, err
+	}
+	return posts[0], nil
+}
+
+// CreateMany reserves a block of IDs with a single INCRBY and writes all posts in one transaction.
+func (r *RedisRepository) CreateMany(data []PostCreateUpdate) ([]PostRead, error) {
+	if len(data) == 0 {
+		return []PostRead{}, nil
+	}
+	ctx := context.Background()
+
+	lastID, err := r.client.IncrBy(ctx, redisNextIDKey, int64(len(data))).Result()
+	if err != nil {
+		return nil, err
+	}
+	firstID := int(lastID) - len(data) + 1
+
+	now := r.now().UTC()
+	posts := make([]PostRead, len(data))
+	for i, d := range data {
+		posts[i] = PostRead{
+			ID:        firstID + i,
+			Title:     d.Title,
+			Content:   d.Content,
+			Author:    d.Author,
+			Status:    d.PostStatus(),
+			Tags:      d.Tags,
+			ImageURL:  d.ImageURL,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+	}
+
+	_, err = r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, post := range posts {
+			pipe.HSet(ctx, redisPostKey(post.ID), redisHashFromPost(post))
+			pipe.SAdd(ctx, redisIDsKey, post.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return posts, nil
+}
+
+func (r *RedisRepository) Update(id int, data PostCreateUpdate) (PostRead, error) {
+	ctx := context.Background()
+
+	updatedAt := r.now().UTC().Format(time.RFC3339Nano)
+	updated, err := updatePostScript.Run(ctx, r.client, []string{redisPostKey(id)},
+		data.Title, data.Content, data.Author, data.PostStatus(), strings.Join(data.Tags, ","), data.ImageURL, updatedAt).Int()
+	if err != nil {
+		return PostRead{}, err
+	}
+	if updated == 0 {
+		return PostRead{}, ErrPostNotFound
+	}
+	return r.GetByID(id)
+}
+
+func (r *RedisRepository) Upsert(id int, data PostCreateUpdate) (PostRead, bool, error) {
+	ctx := context.Background()
+
+	now := r.now().UTC().Format(time.RFC3339Nano)
+	created, err := upsertPostScript.Run(ctx, r.client, []string{redisPostKey(id), redisIDsKey, redisNextIDKey},
+		id, data.Title, data.Content, data.Author, data.PostStatus(), strings.Join(data.Tags, ","), data.ImageURL, now).Int()
+	if err != nil {
+		return PostRead{}, false, err
+	}
+
+	post, err := r.GetByID(id)
+	if err != nil {
+		return PostRead{}, false, err
+	}
+	return post, created == 1, nil
+}
+
+func (r *RedisRepository) Delete(id int) error {
+	ctx := context.Background()
+
+	var deleted *redis.IntCmd
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		deleted = pipe.Del(ctx, redisPostKey(id))
+		pipe.SRem(ctx, redisIDsKey, id)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if deleted.Val() == 0 {
+		return ErrPostNotFound
+	}
+	return nil
+}
+
+// UpdateIfUnmodified checks and updates the post inside a WATCH transaction, so a
+// concurrent change between the check and the write also fails the precondition.
+func (r *RedisRepository) UpdateIfUnmodified(id int, data PostCreateUpdate, since time.Time) (PostRead, error) {
+	ctx := context.Background()
+	key := redisPostKey(id)
+
+	modified := func(post PostRead) bool { return modifiedSince(post, since) }
+	if err := r.watchPrecondition(ctx, key, modified, r.writePostData(ctx, key, data)); err != nil {
+		return PostRead{}, err
+	}
+	return r.GetByID(id)
+}
+
+// UpdateIfMatch checks the ETag and updates the post inside a WATCH transaction, as
+// UpdateIfUnmodified does.
+func (r *RedisRepository) UpdateIfMatch(id int, data PostCreateUpdate, etag string) (PostRead, error) {
+	ctx := context.Background()
+	key := redisPostKey(id)
+
+	modified := func(post PostRead) bool { return postETag(post) != etag }
+	if err := r.watchPrecondition(ctx, key, modified, r.writePostData(ctx, key, data)); err != nil {
+		return PostRead{}, err
+	}
+	return r.GetByID(id)
+}
+
+// writePostData returns a transaction step that stores data as the post at key.
+func (r *RedisRepository) writePostData(ctx context.Context, key string, data PostCreateUpdate) func(redis.Pipeliner) {
+	return func(pipe redis.Pipeliner) {
+		pipe.HSet(ctx, key,
+			"title", data.Title,
+			"content", data.Content,
+			"author", data.Author,
+			"status", data.PostStatus(),
+			"tags", strings.Join(data.Tags, ","),
+			"image_url", data.ImageURL,
+			"updated_at", r.now().UTC().Format(time.RFC3339Nano),
+		)
+	}
+}
+
+func (r *RedisRepository) DeleteIfUnmodified(id int, since time.Time) error {
+	ctx := context.Background()
+	key := redisPostKey(id)
+
+	modified := func(post PostRead) bool { return modifiedSince(post, since) }
+	return r.watchPrecondition(ctx, key, modified, func(pipe redis.Pipeliner) {
+		pipe.Del(ctx, key)
+		pipe.SRem(ctx, redisIDsKey, id)
+	})
+}
+
+// watchPrecondition runs write in a transaction that only commits if the post at key
+// exists, is not reported as modified and is not changed concurrently.
+func (r *RedisRepository) watchPrecondition(ctx context.Context, key string, modified func(post PostRead) bool, write func(redis.Pipeliner)) error {
+	err := r.client.Watch(ctx, func(tx *redis.Tx) error {
+		fields, err := tx.HGetAll(ctx, key).Result()
+		if err != nil {
+			return err
+		}
+		if len(fields) == 0 {
+			return ErrPostNotFound
+		}
+		post, err := postFromRedisHash(fields)
+		if err != nil {
+			return err
+		}
+		if modified(post) {
+			return ErrPreconditionFailed
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			write(pipe)
+			return nil
+		})
+		return err
+	}, key)
+	if errors.Is(err, redis.TxFailedErr) {
+		return ErrPreconditionFailed
+	}
+	return err
+}
+
+// DeleteMany deletes the existing posts among ids in one transaction and returns the IDs that were deleted.
+func (r *RedisRepository) DeleteMany(ids []int) ([]int, error) {
+	ctx := context.Background()
+
+	cmds := make([]*redis.IntCmd, len(ids))
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, id := range ids {
+			cmds[i] = pipe.Del(ctx, redisPostKey(id))
+			pipe.SRem(ctx, redisIDsKey, id)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	deleted := make([]int, 0, len(ids))
+	for i, cmd := range cmds {
+		if cmd.Val() > 0 {
+			deleted = append(deleted, ids[i])
+		}
+	}
+	return deleted, nil
+}
+
+func (r *RedisRepository) IncrementViews(id int) (int, error) {
+	views, err := incrementViewsScript.Run(context.Background(), r.client, []string{redisPostKey(id)}).Int()
+	if err != nil {
+		return 0, err
+	}
+	if views < 0 {
+		return 0, ErrPostNotFound
+	}
+	return views, nil
+}
+
+func (r *RedisRepository) CountByAuthor(ctx context.Context) (map[string]int, error) {
+	posts, err := r.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int)
+	for _, post := range posts {
+		counts[post.Author] += 1
+	}
+	return counts, nil
+}
+
+func (r *RedisRepository) Authors(ctx context.Context) ([]string, error) {
+	posts, err := r.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	for _, post := range posts {
+		seen[post.Author] = true
+	}
+	return sortedAuthors(seen), nil
+}
+
+func (r *RedisRepository) GetRecent(ctx context.Context, n int) ([]PostRead, error) {
+	posts, err := r.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return mostRecent(posts, n), nil
+}
+
+func (r *RedisRepository) GetRandom() (PostRead, error) {
+	member, err := r.client.SRandMember(context.Background(), redisIDsKey).Result()
+	if errors.Is(err, redis.Nil) {
+		return PostRead{}, ErrPostNotFound
+	}
+	if err != nil {
+		return PostRead{}, err
+	}
+
+	id, err := strconv.Atoi(member)
+	if err != nil {
+		return PostRead{}, err
+	}
+	return r.GetByID(id)
+}
+
+func (r *RedisRepository) Neighbors(ctx context.Context, id int) (prev, next *PostRead, err error) {
+	ids, err := r.sortedIDs(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	i, found := slices.BinarySearch(ids, id)
+	if !found {
+		return nil, nil, ErrPostNotFound
+	}
+
+	var keys []string
+	if i > 0 {
+		keys = append(keys, redisPostKey(ids[i-1]))
+	}
+	if i+1 < len(ids) {
+		keys = append(keys, redisPostKey(ids[i+1]))
+	}
+	posts, err := r.getPosts(ctx, keys)
+	if err != nil {
+		return nil, nil, err
+	}
+	// A neighbor deleted since SMEMBERS is missing from posts and reported as none.
+	for _, post := range posts {
+		if post.ID < id {
+			prev = &post
+		} else {
+			next = &post
+		}
+	}
+	return prev, next, nil
+}
+
+func (r *RedisRepository) ExistsByTitleAndAuthor(title, author string) (bool, error) {
+	posts, err := r.GetAll(context.Background())
+	if err != nil {
+		return false, err
+	}
+
+	title, author = duplicateKey(title), duplicateKey(author)
+	for _, post := range posts {
+		if duplicateKey(post.Title) == title && duplicateKey(post.Author) == author {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func redisHashFromPost(post PostRead) map[string]any {
+	return map[string]any{
+		"id":         post.ID,
+		"title":      post.Title,
+		"content":    post.Content,
+		"author":     post.Author,
+		"status":     post.Status,
+		"tags":       strings.Join(post.Tags, ","),
+		"image_url":  post.ImageURL,
+		"views":      post.Views,
+		"created_at": post.CreatedAt.Format(time.RFC3339Nano),
+		"updated_at": post.UpdatedAt.Format(time.RFC3339Nano),
+	}
+}
+
+func postFromRedisHash(fields map[string]string) (PostRead, error) {
+	id, err := strconv.Atoi(fields["id"])
+	if err != nil {
+		return PostRead{}, err
+	}
+	views, err := strconv.Atoi(fields["views"])
+	if err != nil {
+		return PostRead{}, err
+	}
+	createdAt, err := time.Parse(time.RFC3339Nano, fields["created_at"])
+	if err != nil {
+		return PostRead{}, err
+	}
+	updatedAt, err := time.Parse(time.RFC3339Nano, fields["updated_at"])
+	if err != nil {
+		return PostRead{}, err
+	}
+
+	// Tags are stored comma-separated, which is safe since they cannot contain commas.
+	var tags []string
+	if fields["tags"] != "" {
+		tags = strings.Split(fields["tags"], ",")
+	}
+
+	return PostRead{
+		ID:        id,
+		Title:     fields["title"],
+		Content:   fields["content"],
+		Author:    fields["author"],
+		Status:    fields["status"],
+		Tags:      tags,
+		ImageURL:  fields["image_url"],
+		Views:     views,
+		CreatedAt: createdAt,
+		UpdatedAt: updatedAt,
+	}, nil
+}
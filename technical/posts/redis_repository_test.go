@@ -0,0 +1,513 @@
+package posts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"os"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+)
+
+// setupRedisRepository connects to the Redis server named by REDIS_TEST_ADDR and
+// flushes its current database, so it must point at a server dedicated to tests.
+func setupRedisRepository(t *testing.T) *RedisRepository {
+	t.Helper()
+
+	addr := os.Getenv("REDIS_TEST_ADDR")
+	if addr == "" {
+		t.Skip("REDIS_TEST_ADDR not set; skipping Redis integration test")
+	}
+
+	client := redis.NewClient(&redis.Options{Addr: addr})
+	t.Cleanup(func() { client.Close() })
+	if err := client.FlushDB(context.Background()).Err(); err != nil {
+		t.Fatalf("Failed to flush test Redis: %v", err)
+	}
+
+	repo := NewRedisRepository(client)
+	repo.now = func() time.Time {
+		return time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+	return repo
+}
+
+func TestRedisRepositoryCreateAndGet(t *testing.T) {
+	repo := setupRedisRepository(t)
+
+	created, err := repo.Create(PostCreateUpdate{Title: "Title", Content: "Content", Author: "Author", ImageURL: "https://example.com/cover.png"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if created.ID != 1 {
+		t.Errorf("Expected ID 1, got %d", created.ID)
+	}
+
+	post, err := repo.GetByID(created.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(post, created) {
+		t.Errorf("Expected %+v, got %+v", created, post)
+	}
+
+	exists, err := repo.Exists(created.ID)
+	if err != nil || !exists {
+		t.Errorf("Expected post to exist, got %v, %v", exists, err)
+	}
+
+	if _, err := repo.GetByID(99); !errors.Is(err, ErrPostNotFound) {
+		t.Errorf("Expected ErrPostNotFound, got %v", err)
+	}
+}
+
+func TestRedisRepositoryCreateManyAndGetAll(t *testing.T) {
+	repo := setupRedisRepository(t)
+
+	created, err := repo.CreateMany([]PostCreateUpdate{
+		{Title: "First", Content: "Content", Author: "Author"},
+		{Title: "Second", Content: "Content", Author: "Author"},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if created[0].ID != 1 || created[1].ID != 2 {
+		t.Errorf("Expected IDs 1 and 2, got %d and %d", created[0].ID, created[1].ID)
+	}
+
+	posts, err := repo.GetAll(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(posts) != 2 {
+		t.Errorf("Expected 2 posts, got %d", len(posts))
+	}
+	if count, err := repo.Count(context.Background()); err != nil || count != 2 {
+		t.Errorf("Expected a count of 2, got %d, %v", count, err)
+	}
+
+	counts, err := repo.CountByAuthor(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if counts["Author"] != 2 {
+		t.Errorf("Expected 2 posts by Author, got %d", counts["Author"])
+	}
+}
+
+func TestRedisRepositoryList(t *testing.T) {
+	repo := setupRedisRepository(t)
+
+	data := make([]PostCreateUpdate, 11)
+	for i := range data {
+		data[i] = PostCreateUpdate{Title: "Title", Content: "Content", Author: "Author"}
+	}
+	repo.CreateMany(data)
+
+	// IDs 9, 10 and 11 would sort before 2 as strings.
+	posts, total, err := repo.List(context.Background(), ListParams{Limit: 3, Offset: 8})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if total != 11 {
+		t.Errorf("Expected total 11, got %d", total)
+	}
+	if len(posts) != 3 || posts[0].ID != 9 || posts[2].ID != 11 {
+		t.Errorf("Expected posts 9 to 11, got %+v", posts)
+	}
+
+	if posts, _, _ := repo.List(context.Background(), ListParams{Limit: 3, Offset: 11}); len(posts) != 0 {
+		t.Errorf("Expected an empty page past the end, got %d posts", len(posts))
+	}
+}
+
+func TestRedisRepositoryListCreatedRange(t *testing.T) {
+	repo := setupRedisRepository(t)
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := range 3 {
+		repo.now = func() time.Time { return start.Add(time.Duration(i) * time.Hour) }
+		repo.Create(PostCreateUpdate{Title: "Title", Content: "Content", Author: "Author"})
+	}
+
+	posts, total, err := repo.List(context.Background(), ListParams{Created: TimeRange{After: start.Add(time.Hour)}})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if total != 2 || len(posts) != 2 || posts[0].ID != 2 || posts[1].ID != 3 {
+		t.Errorf("Expected posts 2 and 3 of 2, got %+v of %d", posts, total)
+	}
+
+	posts, total, _ = repo.List(context.Background(), ListParams{Limit: 1, Created: TimeRange{Before: start.Add(2 * time.Hour)}})
+	if total != 2 || len(posts) != 1 || posts[0].ID != 1 {
+		t.Errorf("Expected post 1 of 2, got %+v of %d", posts, total)
+	}
+}
+
+func TestRedisRepositoryListSorted(t *testing.T) {
+	repo := setupRedisRepository(t)
+	repo.CreateMany([]PostCreateUpdate{
+		{Title: "Title", Content: "Content", Author: "Bob"},
+		{Title: "Title", Content: "Content", Author: "Alice"},
+		{Title: "Title", Content: "Content", Author: "Bob"},
+	})
+
+	posts, total, err := repo.List(context.Background(), ListParams{Limit: 2, Sort: PostSort{Field: SortByAuthor, Desc: true}})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if total != 3 || len(posts) != 2 || posts[0].ID != 1 || posts[1].ID != 3 {
+		t.Errorf("Expected Bob's posts 1 and 3 of 3, got %+v of %d", posts, total)
+	}
+}
+
+func TestRedisRepositoryIterate(t *testing.T) {
+	repo := setupRedisRepository(t)
+
+	data := make([]PostCreateUpdate, redisIterateBatch+5)
+	for i := range data {
+		data[i] = PostCreateUpdate{Title: "Title", Content: "Content", Author: "Author"}
+	}
+	repo.CreateMany(data)
+
+	var ids []int
+	err := repo.Iterate(context.Background(), func(post PostRead) error {
+		ids = append(ids, post.ID)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(ids) != len(data) || !slices.IsSorted(ids) {
+		t.Errorf("Expected %d posts in ID order, got %v", len(data), ids)
+	}
+
+	visited := 0
+	err = repo.Iterate(context.Background(), func(post PostRead) error {
+		visited++
+		return ErrStopIteration
+	})
+	if err != nil || visited != 1 {
+		t.Errorf("Expected to stop after 1 post without error, got %d and %v", visited, err)
+	}
+}
+
+func TestRedisRepositoryUpdate(t *testing.T) {
+	repo := setupRedisRepository(t)
+
+	created, _ := repo.Create(PostCreateUpdate{Title: "Title", Content: "Content", Author: "Author"})
+	repo.IncrementViews(created.ID)
+
+	updatedAt := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	repo.now = func() time.Time { return updatedAt }
+
+	updated, err := repo.Update(created.ID, PostCreateUpdate{Title: "New Title", Content: "New Content", Author: "New Author", ImageURL: "https://example.com/cover.png"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if updated.Title != "New Title" || updated.Content != "New Content" || updated.Author != "New Author" || updated.ImageURL != "https://example.com/cover.png" {
+		t.Errorf("Expected updated fields, got %+v", updated)
+	}
+	if updated.Views != 1 {
+		t.Errorf("Expected views to be preserved, got %d", updated.Views)
+	}
+	if !updated.CreatedAt.Equal(created.CreatedAt) {
+		t.Errorf("Expected CreatedAt %v, got %v", created.CreatedAt, updated.CreatedAt)
+	}
+	if !updated.UpdatedAt.Equal(updatedAt) {
+		t.Errorf("Expected UpdatedAt %v, got %v", updatedAt, updated.UpdatedAt)
+	}
+
+	if _, err := repo.Update(99, PostCreateUpdate{Title: "Title", Content: "Content", Author: "Author"}); !errors.Is(err, ErrPostNotFound) {
+		t.Errorf("Expected ErrPostNotFound, got %v", err)
+	}
+	if exists, _ := repo.Exists(99); exists {
+		t.Error("Expected a failed update not to create the post")
+	}
+}
+
+func TestRedisRepositoryUpsert(t *testing.T) {
+	repo := setupRedisRepository(t)
+	data := PostCreateUpdate{Title: "Upserted", Content: "Content", Author: "Author", ImageURL: "https://example.com/cover.png"}
+
+	post, created, err := repo.Upsert(5, data)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !created || post.ID != 5 || post.Title != "Upserted" || post.ImageURL != data.ImageURL {
+		t.Errorf("Expected post 5 to be created, got created=%v %+v", created, post)
+	}
+
+	repo.IncrementViews(5)
+	post, created, err = repo.Upsert(5, PostCreateUpdate{Title: "Replaced", Content: "Content", Author: "Author"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if created || post.Title != "Replaced" || post.Views != 1 || post.ImageURL != "" {
+		t.Errorf("Expected post 5 to be replaced keeping its views, got created=%v %+v", created, post)
+	}
+
+	next, _ := repo.Create(data)
+	if next.ID != 6 {
+		t.Errorf("Expected the next created ID to follow the upserted one, got %d", next.ID)
+	}
+}
+
+func TestRedisRepositoryConditionalWrites(t *testing.T) {
+	repo := setupRedisRepository(t)
+
+	created, _ := repo.Create(PostCreateUpdate{Title: "Title", Content: "Content", Author: "Author"})
+	before := created.UpdatedAt.Add(-time.Hour)
+	data := PostCreateUpdate{Title: "New Title", Content: "New Content", Author: "Author"}
+
+	if _, err := repo.UpdateIfUnmodified(created.ID, data, before); !errors.Is(err, ErrPreconditionFailed) {
+		t.Errorf("Expected ErrPreconditionFailed, got %v", err)
+	}
+	if err := repo.DeleteIfUnmodified(created.ID, before); !errors.Is(err, ErrPreconditionFailed) {
+		t.Errorf("Expected ErrPreconditionFailed, got %v", err)
+	}
+
+	updated, err := repo.UpdateIfUnmodified(created.ID, data, created.UpdatedAt)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if updated.Title != "New Title" {
+		t.Errorf("Expected title New Title, got %s", updated.Title)
+	}
+	if err := repo.DeleteIfUnmodified(created.ID, updated.UpdatedAt); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if err := repo.DeleteIfUnmodified(created.ID, updated.UpdatedAt); !errors.Is(err, ErrPostNotFound) {
+		t.Errorf("Expected ErrPostNotFound, got %v", err)
+	}
+}
+
+func TestRedisRepositoryUpdateIfMatch(t *testing.T) {
+	repo := setupRedisRepository(t)
+
+	created, _ := repo.Create(PostCreateUpdate{Title: "Title", Content: "Content", Author: "Author", Tags: []string{"go"}})
+	stored, _ := repo.GetByID(created.ID)
+	data := PostCreateUpdate{Title: "New Title", Content: "New Content", Author: "Author"}
+
+	if postETag(created) != postETag(stored) {
+		t.Errorf("Expected the created and stored post to have the same ETag")
+	}
+	if _, err := repo.UpdateIfMatch(created.ID, data, `"stale"`); !errors.Is(err, ErrPreconditionFailed) {
+		t.Errorf("Expected ErrPreconditionFailed, got %v", err)
+	}
+	if _, err := repo.UpdateIfMatch(99, data, postETag(stored)); !errors.Is(err, ErrPostNotFound) {
+		t.Errorf("Expected ErrPostNotFound, got %v", err)
+	}
+
+	updated, err := repo.UpdateIfMatch(created.ID, data, postETag(stored))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if updated.Title != "New Title" {
+		t.Errorf("Expected title New Title, got %s", updated.Title)
+	}
+	if _, err := repo.UpdateIfMatch(created.ID, data, postETag(stored)); !errors.Is(err, ErrPreconditionFailed) {
+		t.Errorf("Expected the old ETag to be stale after the update, got %v", err)
+	}
+}
+
+func TestRedisRepositoryNeighbors(t *testing.T) {
+	repo := setupRedisRepository(t)
+
+	var ids []int
+	for i := 0; i < 4; i++ {
+		created, _ := repo.Create(PostCreateUpdate{Title: fmt.Sprintf("Post %d", i), Content: "Content", Author: "Author"})
+		ids = append(ids, created.ID)
+	}
+	// Deleting the second post leaves a gap that Neighbors must skip.
+	repo.Delete(ids[1])
+
+	tests := []struct {
+		name         string
+		id           int
+		expectedPrev int
+		expectedNext int
+		expectedErr  error
+	}{
+		{name: "Middle Post", id: ids[2], expectedPrev: ids[0], expectedNext: ids[3]},
+		{name: "First Post", id: ids[0], expectedPrev: 0, expectedNext: ids[2]},
+		{name: "Last Post", id: ids[3], expectedPrev: ids[2], expectedNext: 0},
+		{name: "Deleted Post", id: ids[1], expectedErr: ErrPostNotFound},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			prev, next, err := repo.Neighbors(context.Background(), tc.id)
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("Expected error %v, got %v", tc.expectedErr, err)
+			}
+			if got := neighborID(prev); got != tc.expectedPrev {
+				t.Errorf("Expected previous post %d, got %d", tc.expectedPrev, got)
+			}
+			if got := neighborID(next); got != tc.expectedNext {
+				t.Errorf("Expected next post %d, got %d", tc.expectedNext, got)
+			}
+		})
+	}
+}
+
+func TestRedisRepositoryDelete(t *testing.T) {
+	repo := setupRedisRepository(t)
+
+	created, _ := repo.Create(PostCreateUpdate{Title: "Title", Content: "Content", Author: "Author"})
+
+	if err := repo.Delete(created.ID); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := repo.GetByID(created.ID); !errors.Is(err, ErrPostNotFound) {
+		t.Errorf("Expected ErrPostNotFound after delete, got %v", err)
+	}
+	if posts, _ := repo.GetAll(context.Background()); len(posts) != 0 {
+		t.Errorf("Expected no posts after delete, got %d", len(posts))
+	}
+	if err := repo.Delete(created.ID); !errors.Is(err, ErrPostNotFound) {
+		t.Errorf("Expected ErrPostNotFound on second delete, got %v", err)
+	}
+}
+
+func TestRedisRepositoryDeleteMany(t *testing.T) {
+	repo := setupRedisRepository(t)
+
+	repo.CreateMany([]PostCreateUpdate{
+		{Title: "First", Content: "Content", Author: "Author"},
+		{Title: "Second", Content: "Content", Author: "Author"},
+	})
+
+	deleted, err := repo.DeleteMany([]int{2, 99, 1})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !slices.Equal(deleted, []int{2, 1}) {
+		t.Errorf("Expected deleted [2 1], got %v", deleted)
+	}
+	if posts, _ := repo.GetAll(context.Background()); len(posts) != 0 {
+		t.Errorf("Expected no posts left, got %d", len(posts))
+	}
+}
+
+func TestRedisRepositoryGetByIDs(t *testing.T) {
+	repo := setupRedisRepository(t)
+
+	repo.CreateMany([]PostCreateUpdate{
+		{Title: "First", Content: "Content", Author: "Author"},
+		{Title: "Second", Content: "Content", Author: "Author"},
+	})
+
+	posts, err := repo.GetByIDs([]int{2, 99, 1})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(posts) != 2 || posts[0].Title != "Second" || posts[1].Title != "First" {
+		t.Errorf("Expected posts 2 and 1 in that order, got %+v", posts)
+	}
+}
+
+func TestRedisRepositoryIncrementViews(t *testing.T) {
+	repo := setupRedisRepository(t)
+
+	created, _ := repo.Create(PostCreateUpdate{Title: "Title", Content: "Content", Author: "Author"})
+
+	for expected := 1; expected <= 2; expected++ {
+		views, err := repo.IncrementViews(created.ID)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if views != expected {
+			t.Errorf("Expected views %d, got %d", expected, views)
+		}
+	}
+
+	if _, err := repo.IncrementViews(99); !errors.Is(err, ErrPostNotFound) {
+		t.Errorf("Expected ErrPostNotFound, got %v", err)
+	}
+	if exists, _ := repo.Exists(99); exists {
+		t.Error("Expected incrementing a missing post not to create it")
+	}
+}
+
+func TestRedisRepositoryQueries(t *testing.T) {
+	repo := setupRedisRepository(t)
+
+	if _, err := repo.GetRandom(); !errors.Is(err, ErrPostNotFound) {
+		t.Errorf("Expected ErrPostNotFound from an empty repository, got %v", err)
+	}
+
+	authors, err := repo.Authors(context.Background())
+	if err != nil || authors == nil || len(authors) != 0 {
+		t.Errorf("Expected no authors in an empty repository, got %v, %v", authors, err)
+	}
+
+	repo.CreateMany([]PostCreateUpdate{
+		{Title: "First", Content: "Content", Author: "Jane Doe"},
+		{Title: "Second", Content: "Content", Author: "Jane Doe"},
+	})
+
+	authors, err = repo.Authors(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !slices.Equal(authors, []string{"Jane Doe"}) {
+		t.Errorf("Expected authors [Jane Doe], got %v", authors)
+	}
+
+	recent, err := repo.GetRecent(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(recent) != 1 || recent[0].ID != 2 {
+		t.Errorf("Expected post 2 as most recent, got %+v", recent)
+	}
+
+	random, err := repo.GetRandom()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if random.ID != 1 && random.ID != 2 {
+		t.Errorf("Expected post 1 or 2, got %d", random.ID)
+	}
+
+	duplicate, err := repo.ExistsByTitleAndAuthor(" first ", "JANE DOE")
+	if err != nil || !duplicate {
+		t.Errorf("Expected a duplicate to be found, got %v, %v", duplicate, err)
+	}
+}
+
+func TestRedisRepositoryTags(t *testing.T) {
+	repo := setupRedisRepository(t)
+
+	created, err := repo.Create(PostCreateUpdate{Title: "Title", Content: "Content", Author: "Author", Tags: []string{"go", "redis"}})
+	if err != nil {
+		t.Fatalf("Failed to create post: %v", err)
+	}
+	post, err := repo.GetByID(created.ID)
+	if err != nil {
+		t.Fatalf("Failed to get post: %v", err)
+	}
+	if !slices.Equal(post.Tags, []string{"go", "redis"}) {
+		t.Errorf("Expected tags [go redis], got %v", post.Tags)
+	}
+
+	updated, err := repo.Update(created.ID, PostCreateUpdate{Title: "Title", Content: "Content", Author: "Author"})
+	if err != nil {
+		t.Fatalf("Failed to update post: %v", err)
+	}
+	if updated.Tags != nil {
+		t.Errorf("Expected the update to clear the tags, got %v", updated.Tags)
+	}
+
+	upserted, _, err := repo.Upsert(created.ID, PostCreateUpdate{Title: "Title", Content: "Content", Author: "Author", Tags: []string{"api"}})
+	if err != nil {
+		t.Fatalf("Failed to upsert post: %v", err)
+	}
+	if !slices.Equal(upserted.Tags, []string{"api"}) {
+		t.Errorf("Expected tags [api], got %v", upserted.Tags)
+	}
+}
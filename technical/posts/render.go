@@ -0,0 +1,41 @@
+package posts
+
+import (
+	"bytes"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"net/http"
+)
+
+var (
+	markdown       = goldmark.New()
+	renderedPolicy = bluemonday.UGCPolicy()
+)
+
+// wantsRenderedHTML reports whether the client asked for ContentHTML via ?render=html.
+func wantsRenderedHTML(r *http.Request) bool {
+	return r.URL.Query().Get("render") == "html"
+}
+
+// renderMarkdown converts Markdown content to HTML and sanitizes the result, since
+// Markdown permits raw HTML and link targets that the stored content policy never sees.
+func renderMarkdown(content string) string {
+	var buf bytes.Buffer
+	if err := markdown.Convert([]byte(content), &buf); err != nil {
+		return ""
+	}
+	return renderedPolicy.Sanitize(buf.String())
+}
+
+// withRenderedHTML fills ContentHTML on a copy of posts when the request asks for it.
+func withRenderedHTML(r *http.Request, posts []PostRead) []PostRead {
+	if !wantsRenderedHTML(r) {
+		return posts
+	}
+	rendered := make([]PostRead, len(posts))
+	for i, post := range posts {
+		post.ContentHTML = renderMarkdown(post.Content)
+		rendered[i] = post
+	}
+	return rendered
+}
@@ -0,0 +1,122 @@
+package posts
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		expected    []string
+		notExpected []string
+	}{
+		{
+			name:     "Heading",
+			content:  "# Title",
+			expected: []string{"<h1>Title</h1>"},
+		},
+		{
+			name:     "Link",
+			content:  "[site](https://example.com)",
+			expected: []string{`<a href="https://example.com" rel="nofollow">site</a>`},
+		},
+		{
+			name:        "Javascript Link Removed",
+			content:     "[click](javascript:alert(1))",
+			notExpected: []string{"javascript:"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			html := renderMarkdown(tc.content)
+			for _, s := range tc.expected {
+				if !strings.Contains(html, s) {
+					t.Errorf("Expected rendered HTML to contain %q, got %q", s, html)
+				}
+			}
+			for _, s := range tc.notExpected {
+				if strings.Contains(html, s) {
+					t.Errorf("Expected rendered HTML not to contain %q, got %q", s, html)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderQueryParam(t *testing.T) {
+	mockService := &MockService{
+		GetPostByIDFn: func(id int) (PostRead, error) {
+			return PostRead{ID: id, Title: "Post", Content: "## Heading\n\n[link](https://example.com)", Author: "Author"}, nil
+		},
+		GetAllPostsFn: func() ([]PostRead, error) {
+			return []PostRead{{ID: 1, Title: "Post", Content: "# One", Author: "Author"}}, nil
+		},
+	}
+	mux := http.NewServeMux()
+	NewHandler(mockService).RegisterRoutes(mux)
+
+	tests := []struct {
+		name             string
+		url              string
+		expectedRendered bool
+		expectedHTML     string
+	}{
+		{
+			name:             "Single Post Rendered",
+			url:              "/posts/1?render=html",
+			expectedRendered: true,
+			expectedHTML:     "<h2>Heading</h2>\n<p><a href=\"https://example.com\" rel=\"nofollow\">link</a></p>\n",
+		},
+		{
+			name:             "Collection Rendered",
+			url:              "/posts?render=html",
+			expectedRendered: true,
+			expectedHTML:     "<h1>One</h1>\n",
+		},
+		{
+			name: "Single Post Without Param",
+			url:  "/posts/1",
+		},
+		{
+			name: "Collection Without Param",
+			url:  "/posts",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tc.url, nil))
+
+			if rr.Code != http.StatusOK {
+				t.Fatalf("Expected status %d, got %d", http.StatusOK, rr.Code)
+			}
+
+			body := strings.TrimSpace(rr.Body.String())
+			var post map[string]any
+			if strings.HasPrefix(body, "[") {
+				var posts []map[string]any
+				if err := json.Unmarshal(rr.Body.Bytes(), &posts); err != nil {
+					t.Fatalf("Failed to unmarshal response: %v", err)
+				}
+				post = posts[0]
+			} else if err := json.Unmarshal(rr.Body.Bytes(), &post); err != nil {
+				t.Fatalf("Failed to unmarshal response: %v", err)
+			}
+
+			html, present := post["content_html"]
+			if present != tc.expectedRendered {
+				t.Fatalf("Expected content_html present=%v, got %v", tc.expectedRendered, present)
+			}
+			if tc.expectedRendered && html != tc.expectedHTML {
+				t.Errorf("Expected content_html %q, got %q", tc.expectedHTML, html)
+			}
+		})
+	}
+}
@@ -1,68 +1,420 @@
 package posts
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"maps"
+	"math/rand"
 	"os"
 	"slices"
+	"strings"
 	"sync"
+	"time"
 )
 
 var (
-	ErrPostNotFound = errors.New("post not found")
+	ErrPostNotFound  = errors.New("post not found")
+	ErrDuplicatePost = errors.New("a post with this title and author already exists")
+	ErrNoAppendLog   = errors.New("repository has no append log")
+	// ErrNoSnapshotFile is returned by Flush for a repository that was not loaded from a file.
+	ErrNoSnapshotFile = errors.New("repository has no snapshot file")
+	// ErrCapacityExceeded is returned when a create would take the repository past its maximum number of posts.
+	ErrCapacityExceeded = errors.New("repository is at capacity")
+	// ErrDuplicatePostID is returned when a data file holds more than one post with the same ID.
+	ErrDuplicatePostID = errors.New("duplicate post ID")
+	// ErrStopIteration is returned by an Iterate callback to stop early without an error.
+	ErrStopIteration = errors.New("stop iteration")
 )
 
+// ListParams selects a page of posts in ID order. Limit 0 means no limit.
+type ListParams struct {
+	Limit  int
+	Offset int
+	// Created, unless zero, restricts the posts to those created within it.
+	Created TimeRange
+	// Sort orders the posts before the page is taken; the zero value is ID order.
+	Sort PostSort
+}
+
+// Repository stores posts. The methods that scan every post take a context and give
+// up with its error once it is done.
 type Repository interface {
-	GetAll() ([]PostRead, error)
+	GetAll(ctx context.Context) ([]PostRead, error)
+	// List returns up to params.Limit posts in params.Sort order starting at
+	// params.Offset, along with the total number of posts matching params.
+	List(ctx context.Context, params ListParams) (posts []PostRead, total int, err error)
+	// Iterate calls fn for each post in ID order without collecting them all first. It
+	// stops at the first error from fn, returning it unless it is ErrStopIteration.
+	// Posts created or deleted during the iteration may or may not be visited.
+	Iterate(ctx context.Context, fn func(post PostRead) error) error
 	GetByID(id int) (PostRead, error)
+	// GetByIDs returns the posts with the given IDs in the order of ids, leaving out
+	// the IDs of posts that do not exist.
+	GetByIDs(ids []int) ([]PostRead, error)
+	Exists(id int) (bool, error)
 	Create(data PostCreateUpdate) (PostRead, error)
+	CreateMany(data []PostCreateUpdate) ([]PostRead, error)
 	Update(id int, data PostCreateUpdate) (PostRead, error)
+	// Upsert replaces the post with the given ID, or creates it under that ID if it
+	// does not exist, and reports whether it was created.
+	Upsert(id int, data PostCreateUpdate) (post PostRead, created bool, err error)
 	Delete(id int) error
+	DeleteMany(ids []int) (deleted []int, err error)
+	// UpdateIfUnmodified and DeleteIfUnmodified fail with ErrPreconditionFailed,
+	// changing nothing, if the post was updated after since.
+	UpdateIfUnmodified(id int, data PostCreateUpdate, since time.Time) (PostRead, error)
+	// UpdateIfMatch fails with ErrPreconditionFailed, changing nothing, unless the
+	// post's current ETag is etag.
+	UpdateIfMatch(id int, data PostCreateUpdate, etag string) (PostRead, error)
+	DeleteIfUnmodified(id int, since time.Time) error
+	IncrementViews(id int) (int, error)
+	CountByAuthor(ctx context.Context) (map[string]int, error)
+	Authors(ctx context.Context) ([]string, error)
+	GetRecent(ctx context.Context, n int) ([]PostRead, error)
+	GetRandom() (PostRead, error)
+	// Neighbors returns the posts with the next smaller and next larger IDs than id, or
+	// nil at either end. It fails with ErrPostNotFound if there is no post id.
+	Neighbors(ctx context.Context, id int) (prev, next *PostRead, err error)
+	ExistsByTitleAndAuthor(title, author string) (bool, error)
 }
 
 type MapRepository struct {
 	posts  map[int]PostRead
 	nextID int
 	mutex  sync.RWMutex
+	now    func() time.Time
+
+	// maxPosts caps the number of stored posts; 0 means unlimited.
+	maxPosts int
+
+	// rand, when set, replaces the global source for GetRandom. *rand.Rand is not
+	// safe for concurrent use, so it has its own mutex rather than relying on the read lock.
+	rand      *rand.Rand
+	randMutex sync.Mutex
+
+	// log, when set by OpenMapRepository, receives every mutation before it is applied.
+	log *appendLog
+	// snapshotPath is the file the repository was loaded from, which Reload rereads
+	// and Compact rewrites.
+	snapshotPath string
+	// snapshotModTime is the modification time of the snapshot file when the repository
+	// last read or wrote it, and fileIDs holds the IDs found in it after a later edit.
+	snapshotModTime time.Time
+	fileIDs         map[int]bool
+
+	// audit receives the changed fields of every update.
+	audit AuditSink
+	// ids chooses the IDs of new posts; nil means sequential.
+	ids IDGenerator
+
+	// dirty is set by every mutation and cleared when Flush writes the snapshot.
+	dirty bool
+	// snapshotWrites counts how often the snapshot file has been rewritten.
+	snapshotWrites int
+	// autoSave, when started, flushes dirty state in the background.
+	autoSave *autoSaver
+
+	// inTx marks the copy WithTx hands to its closure, which collects its mutations in
+	// txRecords instead of logging them.
+	inTx      bool
+	txRecords []logRecord
 }
 
-func NewMapRepository() *MapRepository {
-	data, err := os.ReadFile("blog_data.json")
+// mapSnapshot is the on-disk format read by LoadMapRepository and written by Compact.
+type mapSnapshot struct {
+	Posts  []PostRead `json:"posts"`
+	NextID int        `json:"next_id,omitempty"`
+}
+
+func NewMapRepository(maxPosts int) *MapRepository {
+	repo, err := LoadMapRepository("blog_data.json", maxPosts)
 	if err != nil {
 		panic(err)
 	}
+	return repo
+}
+
+// SetRandSource makes GetRandom draw from src, so that its choices can be reproduced.
+func (r *MapRepository) SetRandSource(src rand.Source) {
+	r.randMutex.Lock()
+	defer r.randMutex.Unlock()
+	r.rand = rand.New(src)
+}
+
+// SetAuditSink makes every update report the fields it changed to sink.
+func (r *MapRepository) SetAuditSink(sink AuditSink) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.audit = sink
+}
+
+// SetIDGenerator makes new posts take their IDs from ids.
+func (r *MapRepository) SetIDGenerator(ids IDGenerator) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.ids = ids
+}
 
-	var jsonData struct {
-		Posts []PostRead `json:"posts"`
+// LoadMapRepository builds a MapRepository from the JSON file at path that holds at most
+// maxPosts posts, or any number when maxPosts is 0. Posts already in the file are always loaded.
+func LoadMapRepository(path string, maxPosts int) (*MapRepository, error) {
+	repo := &MapRepository{
+		mutex:        sync.RWMutex{},
+		now:          time.Now,
+		maxPosts:     maxPosts,
+		snapshotPath: path,
+		audit:        NopAuditSink{},
 	}
-	if err := json.Unmarshal(data, &jsonData); err != nil {
-		panic(err)
+
+	modTime := fileModTime(path)
+	posts, nextID, err := readMapSnapshot(path, repo.now().UTC())
+	if err != nil {
+		return nil, err
 	}
-	posts := jsonData.Posts
+	repo.posts = posts
+	repo.nextID = nextID
+	repo.snapshotModTime = modTime
+	return repo, nil
+}
 
+// NewMapRepositoryWithPosts builds a MapRepository holding posts without reading a file,
+// for demos and tests. Posts are filled in as if loaded by LoadMapRepository, new posts
+// get IDs after the largest given one, and posts sharing an ID are rejected with
+// ErrDuplicatePostID. The repository has no snapshot file, so Flush and Reload fail.
+func NewMapRepositoryWithPosts(posts []PostRead) (*MapRepository, error) {
 	repo := &MapRepository{
-		posts:  make(map[int]PostRead),
-		mutex:  sync.RWMutex{},
-		nextID: 1,
+		mutex: sync.RWMutex{},
+		now:   time.Now,
+		audit: NopAuditSink{},
+	}
+
+	indexed, nextID, err := indexPosts(posts, repo.now().UTC(), "the given posts")
+	if err != nil {
+		return nil, err
+	}
+	repo.posts = indexed
+	repo.nextID = nextID
+	return repo, nil
+}
+
+// fileModTime returns the modification time of the file at path, or the zero time if it
+// cannot be stat'ed.
+func fileModTime(path string) time.Time {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
 	}
+	return info.ModTime()
+}
+
+// checkSnapshotEdits notices posts added to the snapshot file by hand since the repository
+// last read or wrote it. Their IDs count as taken and nextID moves past them, so that a
+// Create before the next Reload cannot hand out an ID the file already uses. A file that
+// cannot be read is left for Reload to report. It must be called with the write lock held.
+func (r *MapRepository) checkSnapshotEdits() {
+	if r.snapshotPath == "" {
+		return
+	}
+	modTime := fileModTime(r.snapshotPath)
+	if modTime.IsZero() || modTime.Equal(r.snapshotModTime) {
+		return
+	}
+	posts, nextID, err := readMapSnapshot(r.snapshotPath, r.now().UTC())
+	if err != nil {
+		return
+	}
+
+	r.snapshotModTime = modTime
+	r.fileIDs = make(map[int]bool, len(posts))
+	for id := range posts {
+		r.fileIDs[id] = true
+	}
+	r.nextID = max(r.nextID, nextID)
+}
+
+// readMapSnapshot reads the posts in the JSON file at path and the next ID to hand out;
+// see indexPosts.
+func readMapSnapshot(path string, loadedAt time.Time) (map[int]PostRead, int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	return decodeMapSnapshot(data, loadedAt, path)
+}
+
+// decodeMapSnapshot is readMapSnapshot for data already read from source, which names
+// it in errors.
+func decodeMapSnapshot(data []byte, loadedAt time.Time, source string) (map[int]PostRead, int, error) {
+	var snapshot mapSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, 0, err
+	}
+
+	posts, nextID, err := indexPosts(snapshot.Posts, loadedAt, source)
+	if err != nil {
+		return nil, 0, err
+	}
+	// A compacted snapshot remembers the next ID so that IDs of deleted posts are not reused.
+	return posts, max(nextID, snapshot.NextID), nil
+}
 
+// indexPosts maps the posts from source by ID, filling in fields that older files lack,
+// and returns the next ID to hand out after them. Posts that repeat an ID are rejected
+// rather than letting the later one silently replace the earlier one.
+func indexPosts(list []PostRead, loadedAt time.Time, source string) (map[int]PostRead, int, error) {
+	posts := make(map[int]PostRead, len(list))
 	maxID := 0
-	for _, post := range posts {
-		repo.posts[post.ID] = post
+	for _, post := range list {
+		// Posts written before timestamps were tracked are treated as created at load time.
+		if post.CreatedAt.IsZero() {
+			post.CreatedAt = loadedAt
+		}
+		if post.UpdatedAt.IsZero() {
+			post.UpdatedAt = post.CreatedAt
+		}
+		// Posts written before statuses existed were all public.
+		if post.Status == "" {
+			post.Status = StatusPublished
+		}
+		if _, ok := posts[post.ID]; ok {
+			return nil, 0, fmt.Errorf("%w %d in %s", ErrDuplicatePostID, post.ID, source)
+		}
+		posts[post.ID] = post
 		if post.ID > maxID {
 			maxID = post.ID
 		}
 	}
-	repo.nextID = maxID + 1
-	return repo
+	return posts, maxID + 1, nil
 }
 
-func (r *MapRepository) GetAll() ([]PostRead, error) {
+// Reload replaces the posts with those in the file the repository was loaded from, so
+// that edits made to it while the server runs take effect. The file wins over anything
+// changed since it was loaded, and a mutation log, if any, is emptied since it no longer
+// applies. IDs already handed out are never handed out again.
+func (r *MapRepository) Reload() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	modTime := fileModTime(r.snapshotPath)
+	posts, nextID, err := readMapSnapshot(r.snapshotPath, r.now().UTC())
+	if err != nil {
+		return err
+	}
+	if r.log != nil {
+		if err := r.log.Truncate(); err != nil {
+			return err
+		}
+	}
+
+	r.posts = posts
+	r.nextID = max(nextID, r.nextID)
+	r.snapshotModTime = modTime
+	r.fileIDs = nil
+	return nil
+}
+
+// scanCheckInterval is how many posts a scan visits between checks for cancellation.
+const scanCheckInterval = 1024
+
+// scan calls fn for every post, stopping with ctx's error as soon as a periodic check
+// finds it done. It must be called with the lock held.
+func (r *MapRepository) scan(ctx context.Context, fn func(post PostRead)) error {
+	visited := 0
+	for _, post := range r.posts {
+		if visited%scanCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+		visited++
+		fn(post)
+	}
+	return nil
+}
+
+func (r *MapRepository) GetAll(ctx context.Context) ([]PostRead, error) {
 	r.mutex.RLock()
 	defer r.mutex.RUnlock()
 
-	return slices.Collect(maps.Values(r.posts)), nil
+	return r.collect(ctx)
+}
+
+// collect copies every post into a slice. It must be called with the lock held.
+func (r *MapRepository) collect(ctx context.Context) ([]PostRead, error) {
+	posts := make([]PostRead, 0, len(r.posts))
+	err := r.scan(ctx, func(post PostRead) {
+		posts = append(posts, post)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return posts, nil
+}
+
+func (r *MapRepository) List(ctx context.Context, params ListParams) ([]PostRead, int, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	matching := make([]PostRead, 0, len(r.posts))
+	err := r.scan(ctx, func(post PostRead) {
+		if params.Created.Contains(post.CreatedAt) {
+			matching = append(matching, post)
+		}
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	sortPosts(matching, params.Sort)
+	start, end := pageBounds(params, len(matching))
+	return slices.Clone(matching[start:end]), len(matching), nil
+}
+
+// Iterate snapshots the IDs under the read lock and then looks each post up on its own,
+// so fn runs without the lock held and may call back into the repository.
+func (r *MapRepository) Iterate(ctx context.Context, fn func(post PostRead) error) error {
+	r.mutex.RLock()
+	ids := slices.Sorted(maps.Keys(r.posts))
+	r.mutex.RUnlock()
+
+	for _, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		r.mutex.RLock()
+		post, ok := r.posts[id]
+		r.mutex.RUnlock()
+		if !ok {
+			continue
+		}
+
+		if err := fn(post); err != nil {
+			if errors.Is(err, ErrStopIteration) {
+				return nil
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// pageBounds clamps the page selected by params to a collection of total items.
+func pageBounds(params ListParams, total int) (start, end int) {
+	start = min(max(params.Offset, 0), total)
+	end = total
+	if params.Limit > 0 {
+		end = min(start+params.Limit, total)
+	}
+	return start, end
+}
+
+func (r *MapRepository) Count(ctx context.Context) (int, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return len(r.posts), nil
 }
 
 func (r *MapRepository) GetByID(id int) (PostRead, error) {
@@ -76,36 +428,200 @@ func (r *MapRepository) GetByID(id int) (PostRead, error) {
 	return PostRead{}, ErrPostNotFound
 }
 
+// GetByUID returns the post whose UID is uid, as given to it by a UIDGenerator.
+func (r *MapRepository) GetByUID(uid string) (PostRead, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	for _, post := range r.posts {
+		if uid != "" && post.UID == uid {
+			return post, nil
+		}
+	}
+	return PostRead{}, ErrPostNotFound
+}
+
+func (r *MapRepository) GetByIDs(ids []int) ([]PostRead, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	posts := make([]PostRead, 0, len(ids))
+	for _, id := range ids {
+		if post, ok := r.posts[id]; ok {
+			posts = append(posts, post)
+		}
+	}
+	return posts, nil
+}
+
+func (r *MapRepository) Exists(id int) (bool, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	_, ok := r.posts[id]
+	return ok, nil
+}
+
 func (r *MapRepository) Create(data PostCreateUpdate) (PostRead, error) {
+	posts, err := r.CreateMany([]PostCreateUpdate{data})
+	if err != nil {
+		return PostRead{}, err
+	}
+	return posts[0], nil
+}
+
+// CreateMany creates all posts under a single write lock, so that with sequential IDs they
+// get consecutive ones. It creates none of them if that would exceed the repository's capacity.
+func (r *MapRepository) CreateMany(data []PostCreateUpdate) ([]PostRead, error) {
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 
-	createdPost := PostRead{
-		ID:      r.nextID,
-		Title:   data.Title,
-		Content: data.Content,
-		Author:  data.Author,
+	if r.maxPosts > 0 && len(r.posts)+len(data) > r.maxPosts {
+		return nil, ErrCapacityExceeded
 	}
-	r.posts[r.nextID] = createdPost
-	r.nextID += 1
-	return createdPost, nil
+
+	r.checkSnapshotEdits()
+	ids := r.ids
+	if ids == nil {
+		ids = SequentialIDGenerator{}
+	}
+	uids, _ := ids.(UIDGenerator)
+	next := r.nextID
+	pending := make(map[int]bool, len(data))
+	taken := func(id int) bool {
+		_, ok := r.posts[id]
+		return ok || pending[id] || r.fileIDs[id]
+	}
+
+	now := r.now().UTC()
+	createdPosts := make([]PostRead, len(data))
+	records := make([]logRecord, len(data))
+	for i, item := range data {
+		id := ids.NextID(next, taken)
+		pending[id] = true
+		next = max(next, id+1)
+
+		createdPosts[i] = PostRead{
+			ID:        id,
+			UID:       newUID(uids),
+			Title:     item.Title,
+			Content:   item.Content,
+			Author:    item.Author,
+			Status:    item.PostStatus(),
+			Tags:      item.Tags,
+			ImageURL:  item.ImageURL,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		records[i] = logRecord{Op: logOpCreate, ID: createdPosts[i].ID, Post: &createdPosts[i]}
+	}
+	if err := r.appendToLog(records...); err != nil {
+		return nil, err
+	}
+
+	for _, post := range createdPosts {
+		r.posts[post.ID] = post
+	}
+	r.nextID = next
+	return createdPosts, nil
 }
 
 func (r *MapRepository) Update(id int, data PostCreateUpdate) (PostRead, error) {
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 
-	_, ok := r.posts[id]
+	existingPost, ok := r.posts[id]
+	if !ok {
+		return PostRead{}, ErrPostNotFound
+	}
+	return r.update(existingPost, data)
+}
+
+func (r *MapRepository) UpdateIfUnmodified(id int, data PostCreateUpdate, since time.Time) (PostRead, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	existingPost, ok := r.posts[id]
+	if !ok {
+		return PostRead{}, ErrPostNotFound
+	}
+	if modifiedSince(existingPost, since) {
+		return PostRead{}, ErrPreconditionFailed
+	}
+	return r.update(existingPost, data)
+}
+
+func (r *MapRepository) UpdateIfMatch(id int, data PostCreateUpdate, etag string) (PostRead, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	existingPost, ok := r.posts[id]
 	if !ok {
 		return PostRead{}, ErrPostNotFound
 	}
+	if postETag(existingPost) != etag {
+		return PostRead{}, ErrPreconditionFailed
+	}
+	return r.update(existingPost, data)
+}
+
+func (r *MapRepository) Upsert(id int, data PostCreateUpdate) (PostRead, bool, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if existingPost, ok := r.posts[id]; ok {
+		post, err := r.update(existingPost, data)
+		return post, false, err
+	}
+
+	if r.maxPosts > 0 && len(r.posts) >= r.maxPosts {
+		return PostRead{}, false, ErrCapacityExceeded
+	}
+	now := r.now().UTC()
+	uids, _ := r.ids.(UIDGenerator)
+	post := PostRead{
+		ID:        id,
+		UID:       newUID(uids),
+		Title:     data.Title,
+		Content:   data.Content,
+		Author:    data.Author,
+		Status:    data.PostStatus(),
+		Tags:      data.Tags,
+		ImageURL:  data.ImageURL,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := r.appendToLog(logRecord{Op: logOpCreate, ID: id, Post: &post}); err != nil {
+		return PostRead{}, false, err
+	}
+	r.posts[id] = post
+	// IDs handed out by Create must not collide with one chosen by the client.
+	r.nextID = max(r.nextID, id+1)
+	return post, true, nil
+}
+
+// update must be called with the write lock held.
+func (r *MapRepository) update(existingPost PostRead, data PostCreateUpdate) (PostRead, error) {
 	updatedPost := PostRead{
-		ID:      id,
-		Title:   data.Title,
-		Content: data.Content,
-		Author:  data.Author,
+		ID:        existingPost.ID,
+		UID:       existingPost.UID,
+		Title:     data.Title,
+		Content:   data.Content,
+		Author:    data.Author,
+		Status:    data.PostStatus(),
+		Tags:      data.Tags,
+		ImageURL:  data.ImageURL,
+		Views:     existingPost.Views,
+		CreatedAt: existingPost.CreatedAt,
+		UpdatedAt: r.now().UTC(),
+	}
+	if err := r.appendToLog(logRecord{Op: logOpUpdate, ID: updatedPost.ID, Post: &updatedPost}); err != nil {
+		return PostRead{}, err
+	}
+	r.posts[updatedPost.ID] = updatedPost
+	if r.audit != nil {
+		r.audit.Record(newUpdateAudit(existingPost, updatedPost))
 	}
-	r.posts[id] = updatedPost
 	return updatedPost, nil
 }
 
@@ -113,6 +629,213 @@ func (r *MapRepository) Delete(id int) error {
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 
+	if _, ok := r.posts[id]; !ok {
+		return nil
+	}
+	return r.delete(id)
+}
+
+func (r *MapRepository) DeleteIfUnmodified(id int, since time.Time) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	post, ok := r.posts[id]
+	if !ok {
+		return ErrPostNotFound
+	}
+	if modifiedSince(post, since) {
+		return ErrPreconditionFailed
+	}
+	return r.delete(id)
+}
+
+// delete must be called with the write lock held.
+func (r *MapRepository) delete(id int) error {
+	if err := r.appendToLog(logRecord{Op: logOpDelete, ID: id}); err != nil {
+		return err
+	}
 	delete(r.posts, id)
 	return nil
 }
+
+// DeleteMany deletes the existing posts among ids under a single write lock and
+// returns the IDs that were deleted.
+func (r *MapRepository) DeleteMany(ids []int) ([]int, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	deleted := make([]int, 0, len(ids))
+	records := make([]logRecord, 0, len(ids))
+	for _, id := range ids {
+		if _, ok := r.posts[id]; ok && !slices.Contains(deleted, id) {
+			deleted = append(deleted, id)
+			records = append(records, logRecord{Op: logOpDelete, ID: id})
+		}
+	}
+	if len(records) > 0 {
+		if err := r.appendToLog(records...); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, id := range deleted {
+		delete(r.posts, id)
+	}
+	return deleted, nil
+}
+
+func (r *MapRepository) IncrementViews(id int) (int, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	post, ok := r.posts[id]
+	if !ok {
+		return 0, ErrPostNotFound
+	}
+	post.Views += 1
+	if err := r.appendToLog(logRecord{Op: logOpUpdate, ID: id, Post: &post}); err != nil {
+		return 0, err
+	}
+	r.posts[id] = post
+	return post.Views, nil
+}
+
+func (r *MapRepository) CountByAuthor(ctx context.Context) (map[string]int, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	counts := make(map[string]int)
+	err := r.scan(ctx, func(post PostRead) {
+		counts[post.Author] += 1
+	})
+	if err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// Authors returns the distinct authors of the stored posts in alphabetical order.
+func (r *MapRepository) Authors(ctx context.Context) ([]string, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	seen := make(map[string]bool)
+	err := r.scan(ctx, func(post PostRead) {
+		seen[post.Author] = true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sortedAuthors(seen), nil
+}
+
+// sortedAuthors returns the keys of seen sorted, and an empty slice rather than nil.
+func sortedAuthors(seen map[string]bool) []string {
+	authors := slices.Sorted(maps.Keys(seen))
+	if authors == nil {
+		authors = []string{}
+	}
+	return authors
+}
+
+// GetRecent returns up to n posts, newest first.
+func (r *MapRepository) GetRecent(ctx context.Context, n int) ([]PostRead, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	posts, err := r.collect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return mostRecent(posts, n), nil
+}
+
+// mostRecent sorts posts newest first by CreatedAt, the higher ID winning ties, and keeps the first n.
+func mostRecent(posts []PostRead, n int) []PostRead {
+	slices.SortFunc(posts, func(a, b PostRead) int {
+		if c := b.CreatedAt.Compare(a.CreatedAt); c != 0 {
+			return c
+		}
+		return b.ID - a.ID
+	})
+	if n < len(posts) {
+		posts = posts[:n]
+	}
+	return posts
+}
+
+// GetRandom returns a uniformly chosen post, or ErrPostNotFound if there are none.
+func (r *MapRepository) GetRandom() (PostRead, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	if len(r.posts) == 0 {
+		return PostRead{}, ErrPostNotFound
+	}
+
+	// Map iteration order is random, so pick from the sorted IDs to keep a seeded source deterministic.
+	ids := slices.Sorted(maps.Keys(r.posts))
+	return r.posts[ids[r.intn(len(ids))]], nil
+}
+
+func (r *MapRepository) Neighbors(ctx context.Context, id int) (prev, next *PostRead, err error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+	if _, ok := r.posts[id]; !ok {
+		return nil, nil, ErrPostNotFound
+	}
+
+	// IDs are positive, so 0 stands for no neighbor.
+	prevID, nextID := 0, 0
+	for other := range r.posts {
+		if other < id && other > prevID {
+			prevID = other
+		}
+		if other > id && (nextID == 0 || other < nextID) {
+			nextID = other
+		}
+	}
+	return r.postOrNil(prevID), r.postOrNil(nextID), nil
+}
+
+// postOrNil returns a copy of post id, or nil if there is none. The lock must be held.
+func (r *MapRepository) postOrNil(id int) *PostRead {
+	post, ok := r.posts[id]
+	if !ok {
+		return nil
+	}
+	return &post
+}
+
+func (r *MapRepository) intn(n int) int {
+	r.randMutex.Lock()
+	defer r.randMutex.Unlock()
+
+	if r.rand == nil {
+		return rand.Intn(n)
+	}
+	return r.rand.Intn(n)
+}
+
+// ExistsByTitleAndAuthor reports whether a post has the given title and author,
+// ignoring case and differences in whitespace.
+func (r *MapRepository) ExistsByTitleAndAuthor(title, author string) (bool, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	title, author = duplicateKey(title), duplicateKey(author)
+	for _, post := range r.posts {
+		if duplicateKey(post.Title) == title && duplicateKey(post.Author) == author {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func duplicateKey(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
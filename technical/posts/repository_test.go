@@ -1,14 +1,25 @@
 package posts
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func TestMapRepositoryGetAll(t *testing.T) {
 	repo := setupTestRepository()
 
-	posts, err := repo.GetAll()
+	posts, err := repo.GetAll(context.Background())
 	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
 	}
@@ -92,6 +103,31 @@ func TestMapRepositoryGetByID(t *testing.T) {
 	}
 }
 
+func TestMapRepositoryExists(t *testing.T) {
+	repo := setupTestRepository()
+
+	tests := []struct {
+		name     string
+		id       int
+		expected bool
+	}{
+		{name: "Present ID", id: 1, expected: true},
+		{name: "Absent ID", id: 999, expected: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			exists, err := repo.Exists(tc.id)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if exists != tc.expected {
+				t.Errorf("Expected Exists(%d) to be %v, got %v", tc.id, tc.expected, exists)
+			}
+		})
+	}
+}
+
 func TestMapRepositoryCreate(t *testing.T) {
 	repo := setupTestRepository()
 
@@ -132,6 +168,438 @@ func TestMapRepositoryCreate(t *testing.T) {
 	}
 }
 
+func TestMapRepositoryCreateMany(t *testing.T) {
+	repo := setupTestRepository()
+
+	createdPosts, err := repo.CreateMany([]PostCreateUpdate{
+		{Title: "First", Content: "First Content", Author: "First Author"},
+		{Title: "Second", Content: "Second Content", Author: "Second Author"},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(createdPosts) != 2 {
+		t.Fatalf("Expected 2 created posts, got %d", len(createdPosts))
+	}
+	for i, expectedID := range []int{3, 4} {
+		if createdPosts[i].ID != expectedID {
+			t.Errorf("Expected post ID %d, got %d", expectedID, createdPosts[i].ID)
+		}
+		if _, err := repo.GetByID(expectedID); err != nil {
+			t.Errorf("Expected post %d to be retrievable, got %v", expectedID, err)
+		}
+	}
+}
+
+func TestMapRepositoryList(t *testing.T) {
+	repo := setupTestRepository()
+	repo.CreateMany([]PostCreateUpdate{
+		{Title: "Third", Content: "Content", Author: "Author"},
+		{Title: "Fourth", Content: "Content", Author: "Author"},
+		{Title: "Fifth", Content: "Content", Author: "Author"},
+	})
+
+	tests := []struct {
+		name        string
+		params      ListParams
+		expectedIDs []int
+	}{
+		{name: "First Page", params: ListParams{Limit: 2, Offset: 0}, expectedIDs: []int{1, 2}},
+		{name: "Middle Page", params: ListParams{Limit: 2, Offset: 2}, expectedIDs: []int{3, 4}},
+		{name: "Partial Last Page", params: ListParams{Limit: 2, Offset: 4}, expectedIDs: []int{5}},
+		{name: "Offset At End", params: ListParams{Limit: 2, Offset: 5}, expectedIDs: []int{}},
+		{name: "Offset Past End", params: ListParams{Limit: 2, Offset: 50}, expectedIDs: []int{}},
+		{name: "Limit Past End", params: ListParams{Limit: 50, Offset: 0}, expectedIDs: []int{1, 2, 3, 4, 5}},
+		{name: "No Limit", params: ListParams{Limit: 0, Offset: 1}, expectedIDs: []int{2, 3, 4, 5}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			posts, total, err := repo.List(context.Background(), tc.params)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if total != 5 {
+				t.Errorf("Expected total 5, got %d", total)
+			}
+			ids := make([]int, len(posts))
+			for i, post := range posts {
+				ids[i] = post.ID
+			}
+			if !slices.Equal(ids, tc.expectedIDs) {
+				t.Errorf("Expected IDs %v, got %v", tc.expectedIDs, ids)
+			}
+		})
+	}
+}
+
+func TestMapRepositoryListCreatedRange(t *testing.T) {
+	repo := setupTestRepository()
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		params      ListParams
+		expectedIDs []int
+	}{
+		{name: "Open", params: ListParams{}, expectedIDs: []int{1, 2}},
+		{name: "After Is Inclusive", params: ListParams{Created: TimeRange{After: createdAt.Add(time.Hour)}}, expectedIDs: []int{2}},
+		{name: "Before Is Exclusive", params: ListParams{Created: TimeRange{Before: createdAt.Add(time.Hour)}}, expectedIDs: []int{1}},
+		{name: "Bounded", params: ListParams{Created: TimeRange{After: createdAt, Before: createdAt.Add(2 * time.Hour)}}, expectedIDs: []int{1, 2}},
+		{name: "Paged Within Range", params: ListParams{Limit: 1, Offset: 1, Created: TimeRange{After: createdAt}}, expectedIDs: []int{2}},
+		{name: "Empty Range", params: ListParams{Created: TimeRange{After: createdAt.Add(24 * time.Hour)}}, expectedIDs: []int{}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			posts, total, err := repo.List(context.Background(), tc.params)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			ids := make([]int, len(posts))
+			for i, post := range posts {
+				ids[i] = post.ID
+			}
+			if !slices.Equal(ids, tc.expectedIDs) {
+				t.Errorf("Expected IDs %v, got %v", tc.expectedIDs, ids)
+			}
+			if tc.params.Limit == 0 && total != len(tc.expectedIDs) {
+				t.Errorf("Expected total %d, got %d", len(tc.expectedIDs), total)
+			}
+		})
+	}
+}
+
+func TestMapRepositoryScansHonorCancellation(t *testing.T) {
+	repo := setupTestRepository()
+	data := make([]PostCreateUpdate, 10*scanCheckInterval)
+	for i := range data {
+		data[i] = PostCreateUpdate{Title: fmt.Sprintf("Post %d", i), Content: "Content", Author: "Author"}
+	}
+	if _, err := repo.CreateMany(data); err != nil {
+		t.Fatalf("Failed to fill repository: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	scans := []struct {
+		name string
+		scan func() error
+	}{
+		{name: "GetAll", scan: func() error { _, err := repo.GetAll(ctx); return err }},
+		{name: "List", scan: func() error { _, _, err := repo.List(ctx, ListParams{Limit: 10}); return err }},
+		{name: "CountByAuthor", scan: func() error { _, err := repo.CountByAuthor(ctx); return err }},
+		{name: "GetRecent", scan: func() error { _, err := repo.GetRecent(ctx, 5); return err }},
+		{name: "Neighbors", scan: func() error { _, _, err := repo.Neighbors(ctx, 1); return err }},
+	}
+
+	for _, tc := range scans {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.scan(); !errors.Is(err, context.Canceled) {
+				t.Errorf("Expected context.Canceled, got %v", err)
+			}
+		})
+	}
+
+	if posts, err := repo.GetAll(context.Background()); err != nil || len(posts) != len(data)+2 {
+		t.Errorf("Expected %d posts with a live context, got %d and %v", len(data)+2, len(posts), err)
+	}
+}
+
+func TestMapRepositoryIterate(t *testing.T) {
+	repo := setupTestRepository()
+	repo.CreateMany([]PostCreateUpdate{
+		{Title: "Third", Content: "Content", Author: "Author"},
+		{Title: "Fourth", Content: "Content", Author: "Author"},
+	})
+	callbackErr := errors.New("callback failed")
+
+	tests := []struct {
+		name          string
+		ctx           func() context.Context
+		stopAfter     int
+		failAfter     int
+		expectedIDs   []int
+		expectedError error
+	}{
+		{name: "Full", ctx: context.Background, expectedIDs: []int{1, 2, 3, 4}},
+		{name: "Early Stop", ctx: context.Background, stopAfter: 2, expectedIDs: []int{1, 2}},
+		{name: "Callback Error", ctx: context.Background, failAfter: 3, expectedIDs: []int{1, 2, 3}, expectedError: callbackErr},
+		{
+			name: "Cancelled",
+			ctx: func() context.Context {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				return ctx
+			},
+			expectedIDs:   []int{},
+			expectedError: context.Canceled,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ids := []int{}
+			err := repo.Iterate(tc.ctx(), func(post PostRead) error {
+				ids = append(ids, post.ID)
+				if len(ids) == tc.stopAfter {
+					return ErrStopIteration
+				}
+				if len(ids) == tc.failAfter {
+					return callbackErr
+				}
+				return nil
+			})
+
+			if !errors.Is(err, tc.expectedError) {
+				t.Errorf("Expected error %v, got %v", tc.expectedError, err)
+			}
+			if !slices.Equal(ids, tc.expectedIDs) {
+				t.Errorf("Expected IDs %v, got %v", tc.expectedIDs, ids)
+			}
+		})
+	}
+}
+
+func TestMapRepositoryIterateStopsOnCancellationMidway(t *testing.T) {
+	repo := setupTestRepository()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	visited := 0
+	err := repo.Iterate(ctx, func(post PostRead) error {
+		visited++
+		cancel()
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if visited != 1 {
+		t.Errorf("Expected 1 post before the cancellation was noticed, got %d", visited)
+	}
+}
+
+func TestMapRepositoryIterateAllowsWritesFromCallback(t *testing.T) {
+	repo := setupTestRepository()
+
+	err := repo.Iterate(context.Background(), func(post PostRead) error {
+		_, err := repo.IncrementViews(post.ID)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if post, _ := repo.GetByID(2); post.Views != 1 {
+		t.Errorf("Expected the callback's write to be applied, got %d views", post.Views)
+	}
+}
+
+func TestMapRepositoryUpsert(t *testing.T) {
+	repo := setupTestRepository()
+	data := PostCreateUpdate{Title: "Upserted", Content: "Content", Author: "Author"}
+
+	post, created, err := repo.Upsert(1, data)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if created || post.Title != "Upserted" {
+		t.Errorf("Expected post 1 to be replaced, got created=%v %+v", created, post)
+	}
+
+	post, created, err = repo.Upsert(10, data)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !created || post.ID != 10 || post.CreatedAt.IsZero() {
+		t.Errorf("Expected post 10 to be created, got created=%v %+v", created, post)
+	}
+
+	next, _ := repo.Create(data)
+	if next.ID != 11 {
+		t.Errorf("Expected the next created ID to follow the upserted one, got %d", next.ID)
+	}
+}
+
+func TestLoadMapRepositoryDuplicateIDs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "blog_data.json")
+	content := `{"posts": [
+		{"id": 1, "title": "First", "content": "Content", "author": "Author"},
+		{"id": 2, "title": "Second", "content": "Content", "author": "Author"},
+		{"id": 1, "title": "Again", "content": "Content", "author": "Author"}
+	]}`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("Failed to write data file: %v", err)
+	}
+
+	_, err := LoadMapRepository(path, 0)
+	if !errors.Is(err, ErrDuplicatePostID) {
+		t.Fatalf("Expected ErrDuplicatePostID, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "duplicate post ID 1") {
+		t.Errorf("Expected the error to name the ID, got %q", err)
+	}
+}
+
+func TestMapRepositoryReloadRejectsDuplicateIDs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "blog_data.json")
+	if err := os.WriteFile(path, []byte(`{"posts": [{"id": 1, "title": "Title", "content": "Content", "author": "Author"}]}`), 0o644); err != nil {
+		t.Fatalf("Failed to write data file: %v", err)
+	}
+	repo, err := LoadMapRepository(path, 0)
+	if err != nil {
+		t.Fatalf("Failed to load repository: %v", err)
+	}
+
+	content := `{"posts": [
+		{"id": 2, "title": "Title", "content": "Content", "author": "Author"},
+		{"id": 2, "title": "Title", "content": "Content", "author": "Author"}
+	]}`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("Failed to write data file: %v", err)
+	}
+	if err := repo.Reload(); !errors.Is(err, ErrDuplicatePostID) {
+		t.Fatalf("Expected ErrDuplicatePostID, got %v", err)
+	}
+	if posts, _ := repo.GetAll(context.Background()); len(posts) != 1 || posts[0].ID != 1 {
+		t.Errorf("Expected the loaded posts to be kept, got %+v", posts)
+	}
+}
+
+func TestMapRepositoryReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "blog_data.json")
+	writeFile := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("Failed to write data file: %v", err)
+		}
+	}
+
+	writeFile(`{"posts": [{"id": 1, "title": "Title 1", "content": "Content 1", "author": "Author"}]}`)
+	repo, err := LoadMapRepository(path, 0)
+	if err != nil {
+		t.Fatalf("Failed to load repository: %v", err)
+	}
+	repo.Create(PostCreateUpdate{Title: "Title 2", Content: "Content 2", Author: "Author"})
+	repo.Create(PostCreateUpdate{Title: "Title 3", Content: "Content 3", Author: "Author"})
+
+	writeFile(`{"posts": [
+		{"id": 1, "title": "Edited", "content": "Content 1", "author": "Author"},
+		{"id": 2, "title": "Added", "content": "Content 2", "author": "Author"}
+	]}`)
+	if err := repo.Reload(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	posts, _ := repo.GetAll(context.Background())
+	slices.SortFunc(posts, func(a, b PostRead) int { return a.ID - b.ID })
+	if len(posts) != 2 || posts[0].Title != "Edited" || posts[1].Title != "Added" {
+		t.Errorf("Expected the reloaded file contents, got %+v", posts)
+	}
+
+	created, _ := repo.Create(PostCreateUpdate{Title: "Title 4", Content: "Content 4", Author: "Author"})
+	if created.ID != 4 {
+		t.Errorf("Expected ID 3, already handed out before the reload, not to be reused, got ID %d", created.ID)
+	}
+
+	writeFile(`{"posts": [`)
+	if err := repo.Reload(); err == nil {
+		t.Error("Expected an error for an invalid file")
+	}
+	if posts, _ := repo.GetAll(context.Background()); len(posts) != 3 {
+		t.Errorf("Expected a failed reload to keep the current posts, got %d", len(posts))
+	}
+}
+
+func TestMapRepositoryCreateAvoidsIDsAddedToFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "blog_data.json")
+	writeFile := func(content string, modTime time.Time) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("Failed to write data file: %v", err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatalf("Failed to set modification time: %v", err)
+		}
+	}
+
+	loadedAt := time.Now().Add(-time.Hour)
+	writeFile(`{"posts": [{"id": 1, "title": "Title 1", "content": "Content 1", "author": "Author"}]}`, loadedAt)
+	repo, err := LoadMapRepository(path, 0)
+	if err != nil {
+		t.Fatalf("Failed to load repository: %v", err)
+	}
+
+	// Someone adds posts at and above the next ID while the server runs.
+	writeFile(`{"posts": [
+		{"id": 1, "title": "Title 1", "content": "Content 1", "author": "Author"},
+		{"id": 2, "title": "Added 2", "content": "Content 2", "author": "Author"},
+		{"id": 3, "title": "Added 3", "content": "Content 3", "author": "Author"}
+	]}`, loadedAt.Add(time.Minute))
+
+	created, err := repo.Create(PostCreateUpdate{Title: "Created", Content: "Content", Author: "Author"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if created.ID != 4 {
+		t.Errorf("Expected ID 4, past the IDs added to the file, got %d", created.ID)
+	}
+	post, err := repo.GetByID(created.ID)
+	if err != nil {
+		t.Fatalf("Expected the created post to be retrievable, got %v", err)
+	}
+	if post.Title != "Created" {
+		t.Errorf("Expected title %q, got %q", "Created", post.Title)
+	}
+
+	// Random IDs must avoid the file's IDs as well, not just move past them.
+	repo.SetIDGenerator(idGeneratorFunc(func(next int, taken func(id int) bool) int {
+		for id := 2; ; id++ {
+			if !taken(id) {
+				return id
+			}
+		}
+	}))
+	created, _ = repo.Create(PostCreateUpdate{Title: "Created", Content: "Content", Author: "Author"})
+	if created.ID != 5 {
+		t.Errorf("Expected ID 5, the first ID used by neither the repository nor the file, got %d", created.ID)
+	}
+}
+
+func TestMapRepositoryCapacity(t *testing.T) {
+	repo := setupTestRepository()
+	repo.maxPosts = 4
+
+	if _, err := repo.CreateMany([]PostCreateUpdate{
+		{Title: "Third", Content: "Content", Author: "Author"},
+		{Title: "Fourth", Content: "Content", Author: "Author"},
+		{Title: "Fifth", Content: "Content", Author: "Author"},
+	}); !errors.Is(err, ErrCapacityExceeded) {
+		t.Errorf("Expected ErrCapacityExceeded for a batch over the limit, got %v", err)
+	}
+	if len(repo.posts) != 2 {
+		t.Errorf("Expected a rejected batch to create nothing, got %d posts", len(repo.posts))
+	}
+
+	for _, title := range []string{"Third", "Fourth"} {
+		if _, err := repo.Create(PostCreateUpdate{Title: title, Content: "Content", Author: "Author"}); err != nil {
+			t.Fatalf("Expected create up to the limit to succeed, got %v", err)
+		}
+	}
+	if _, err := repo.Create(PostCreateUpdate{Title: "Fifth", Content: "Content", Author: "Author"}); !errors.Is(err, ErrCapacityExceeded) {
+		t.Errorf("Expected ErrCapacityExceeded, got %v", err)
+	}
+
+	if err := repo.Delete(3); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := repo.Create(PostCreateUpdate{Title: "Fifth", Content: "Content", Author: "Author"}); err != nil {
+		t.Errorf("Expected create after a delete to succeed, got %v", err)
+	}
+}
+
 func TestMapRepositoryUpdate(t *testing.T) {
 	repo := setupTestRepository()
 
@@ -255,26 +723,427 @@ func TestMapRepositoryDelete(t *testing.T) {
 	}
 }
 
-func setupTestRepository() *MapRepository {
-	repo := &MapRepository{
-		posts:  make(map[int]PostRead),
-		mutex:  sync.RWMutex{},
-		nextID: 3,
+func TestMapRepositoryIncrementViews(t *testing.T) {
+	repo := setupTestRepository()
+
+	for i := 1; i <= 3; i++ {
+		views, err := repo.IncrementViews(1)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if views != i {
+			t.Errorf("Expected %d views, got %d", i, views)
+		}
+	}
+
+	updatedPost, err := repo.Update(1, PostCreateUpdate{
+		Title:   "Updated Post",
+		Content: "Updated Content",
+		Author:  "Updated Author",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if updatedPost.Views != 3 {
+		t.Errorf("Expected update to keep 3 views, got %d", updatedPost.Views)
+	}
+
+	_, err = repo.IncrementViews(999)
+	if err != ErrPostNotFound {
+		t.Errorf("Expected ErrPostNotFound, got %v", err)
+	}
+}
+
+func TestMapRepositoryTimestamps(t *testing.T) {
+	repo := setupTestRepository()
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	repo.now = func() time.Time { return now }
+
+	createdPost, err := repo.Create(PostCreateUpdate{Title: "New Post", Content: "New Content", Author: "New Author"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !createdPost.CreatedAt.Equal(now) || !createdPost.UpdatedAt.Equal(now) {
+		t.Errorf("Expected timestamps %v, got %v and %v", now, createdPost.CreatedAt, createdPost.UpdatedAt)
+	}
+
+	now = now.Add(time.Hour)
+	updatedPost, err := repo.Update(createdPost.ID, PostCreateUpdate{Title: "Updated Post", Content: "Updated Content", Author: "New Author"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !updatedPost.CreatedAt.Equal(createdPost.CreatedAt) {
+		t.Errorf("Expected CreatedAt to be preserved, got %v", updatedPost.CreatedAt)
+	}
+	if !updatedPost.UpdatedAt.Equal(now) {
+		t.Errorf("Expected UpdatedAt %v, got %v", now, updatedPost.UpdatedAt)
+	}
+}
+
+func TestMapRepositoryCountByAuthor(t *testing.T) {
+	repo := setupTestRepository()
+	repo.posts[3] = PostRead{ID: 3, Title: "Test Post 3", Content: "Test Content 3", Author: "Test Author 1"}
+
+	counts, err := repo.CountByAuthor(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := map[string]int{"Test Author 1": 2, "Test Author 2": 1}
+	if !maps.Equal(counts, expected) {
+		t.Errorf("Expected counts %v, got %v", expected, counts)
+	}
+}
+
+func TestMapRepositoryAuthors(t *testing.T) {
+	tests := []struct {
+		name     string
+		posts    []PostRead
+		expected []string
+	}{
+		{
+			name: "Duplicates Collapsed And Sorted",
+			posts: []PostRead{
+				{ID: 1, Author: "Zoe"},
+				{ID: 2, Author: "Adam"},
+				{ID: 3, Author: "Zoe"},
+				{ID: 4, Author: "Mia"},
+				{ID: 5, Author: "Adam"},
+			},
+			expected: []string{"Adam", "Mia", "Zoe"},
+		},
+		{
+			name:     "Empty Repository",
+			expected: []string{},
+		},
 	}
 
-	repo.posts[1] = PostRead{
-		ID:      1,
-		Title:   "Test Post 1",
-		Content: "Test Content 1",
-		Author:  "Test Author 1",
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := setupTestRepository()
+			repo.posts = make(map[int]PostRead)
+			for _, post := range tc.posts {
+				repo.posts[post.ID] = post
+			}
+
+			authors, err := repo.Authors(context.Background())
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if authors == nil {
+				t.Fatal("Expected an empty slice, got nil")
+			}
+			if !slices.Equal(authors, tc.expected) {
+				t.Errorf("Expected authors %v, got %v", tc.expected, authors)
+			}
+		})
 	}
+}
+
+func TestMapRepositoryDeleteMany(t *testing.T) {
+	repo := setupTestRepository()
 
-	repo.posts[2] = PostRead{
-		ID:      2,
-		Title:   "Test Post 2",
-		Content: "Test Content 2",
-		Author:  "Test Author 2",
+	deleted, err := repo.DeleteMany([]int{2, 99, 1})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !slices.Equal(deleted, []int{2, 1}) {
+		t.Errorf("Expected deleted [2 1], got %v", deleted)
+	}
+	if len(repo.posts) != 0 {
+		t.Errorf("Expected no posts left, got %d", len(repo.posts))
 	}
+}
 
+func TestMapRepositoryGetByIDs(t *testing.T) {
+	repo := setupTestRepository()
+
+	posts, err := repo.GetByIDs([]int{2, 99, 1})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(posts) != 2 || posts[0].ID != 2 || posts[1].ID != 1 {
+		t.Errorf("Expected posts 2 and 1 in that order, got %+v", posts)
+	}
+}
+
+func TestMapRepositoryGetRecent(t *testing.T) {
+	repo := setupTestRepository()
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	repo.posts[3] = PostRead{ID: 3, Title: "Test Post 3", Author: "Test Author 3", CreatedAt: createdAt.Add(2 * time.Hour)}
+	repo.posts[4] = PostRead{ID: 4, Title: "Test Post 4", Author: "Test Author 4", CreatedAt: createdAt}
+
+	tests := []struct {
+		name        string
+		n           int
+		expectedIDs []int
+	}{
+		{name: "Newest First", n: 2, expectedIDs: []int{3, 2}},
+		{name: "ID Breaks Ties", n: 4, expectedIDs: []int{3, 2, 4, 1}},
+		{name: "More Than Available", n: 10, expectedIDs: []int{3, 2, 4, 1}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			posts, err := repo.GetRecent(context.Background(), tc.n)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			ids := make([]int, len(posts))
+			for i, post := range posts {
+				ids[i] = post.ID
+			}
+			if !slices.Equal(ids, tc.expectedIDs) {
+				t.Errorf("Expected IDs %v, got %v", tc.expectedIDs, ids)
+			}
+		})
+	}
+}
+
+func TestMapRepositoryGetRandom(t *testing.T) {
+	t.Run("Seeded Source", func(t *testing.T) {
+		repo := setupTestRepository()
+		repo.posts[3] = PostRead{ID: 3, Title: "Test Post 3", Author: "Test Author 3"}
+
+		// rand.New(rand.NewSource(42)).Intn(3) is 2, which selects the third ID in sorted order.
+		repo.SetRandSource(rand.NewSource(42))
+		post, err := repo.GetRandom()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if post.ID != 3 {
+			t.Errorf("Expected post 3, got %d", post.ID)
+		}
+	})
+
+	t.Run("Empty Repository", func(t *testing.T) {
+		repo := &MapRepository{posts: make(map[int]PostRead), nextID: 1, now: time.Now}
+
+		if _, err := repo.GetRandom(); !errors.Is(err, ErrPostNotFound) {
+			t.Errorf("Expected ErrPostNotFound, got %v", err)
+		}
+	})
+}
+
+// TestMapRepositoryConcurrentAccess mixes writers working on their own posts with
+// readers scanning the whole map. It is meant to be run with -race, which reports
+// any method that touches the map without holding the lock.
+func TestMapRepositoryNeighbors(t *testing.T) {
+	repo := setupTestRepository()
+	for _, id := range []int{5, 9} {
+		repo.posts[id] = PostRead{ID: id, Title: fmt.Sprintf("Post %d", id)}
+	}
+
+	tests := []struct {
+		name         string
+		id           int
+		expectedPrev int
+		expectedNext int
+		expectedErr  error
+	}{
+		{name: "Middle Post", id: 5, expectedPrev: 2, expectedNext: 9},
+		{name: "First Post", id: 1, expectedPrev: 0, expectedNext: 2},
+		{name: "Last Post", id: 9, expectedPrev: 5, expectedNext: 0},
+		{name: "Missing Post", id: 7, expectedErr: ErrPostNotFound},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			prev, next, err := repo.Neighbors(context.Background(), tc.id)
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("Expected error %v, got %v", tc.expectedErr, err)
+			}
+			if got := neighborID(prev); got != tc.expectedPrev {
+				t.Errorf("Expected previous post %d, got %d", tc.expectedPrev, got)
+			}
+			if got := neighborID(next); got != tc.expectedNext {
+				t.Errorf("Expected next post %d, got %d", tc.expectedNext, got)
+			}
+		})
+	}
+}
+
+// neighborID returns the ID of post, or 0 for no post.
+func neighborID(post *PostRead) int {
+	if post == nil {
+		return 0
+	}
+	return post.ID
+}
+
+func TestMapRepositoryConcurrentAccess(t *testing.T) {
+	repo := setupTestRepository()
+	initial := len(repo.posts)
+
+	const writers = 8
+	const postsPerWriter = 50
+
+	var wg sync.WaitGroup
+	var deleted atomic.Int64
+	createdIDs := make([][]int, writers)
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < postsPerWriter; i++ {
+				post, err := repo.Create(PostCreateUpdate{Title: fmt.Sprintf("Post %d-%d", w, i), Content: "Content", Author: "Author"})
+				if err != nil {
+					t.Errorf("Expected no error creating, got %v", err)
+					return
+				}
+				createdIDs[w] = append(createdIDs[w], post.ID)
+
+				if _, err := repo.GetByID(post.ID); err != nil {
+					t.Errorf("Expected to read back post %d, got %v", post.ID, err)
+				}
+				if _, err := repo.Update(post.ID, PostCreateUpdate{Title: "Updated", Content: "Content", Author: "Author"}); err != nil {
+					t.Errorf("Expected no error updating post %d, got %v", post.ID, err)
+				}
+				if _, err := repo.IncrementViews(1); err != nil {
+					t.Errorf("Expected no error incrementing views, got %v", err)
+				}
+				if i%2 == 1 {
+					if err := repo.Delete(post.ID); err != nil {
+						t.Errorf("Expected no error deleting post %d, got %v", post.ID, err)
+					}
+					deleted.Add(1)
+				}
+			}
+		}()
+	}
+
+	const readers = 4
+	const scansPerReader = 50
+
+	for r := 0; r < readers; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < scansPerReader; i++ {
+				repo.GetAll(context.Background())
+				repo.List(context.Background(), ListParams{Limit: 10})
+				repo.CountByAuthor(context.Background())
+				repo.GetRecent(context.Background(), 5)
+				repo.ExistsByTitleAndAuthor("Updated", "Author")
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	ids := slices.Concat(createdIDs...)
+	if len(ids) != writers*postsPerWriter {
+		t.Fatalf("Expected %d successful creates, got %d", writers*postsPerWriter, len(ids))
+	}
+	slices.Sort(ids)
+	if len(slices.Compact(ids)) != writers*postsPerWriter {
+		t.Error("Expected every create to get a distinct ID")
+	}
+
+	posts, _ := repo.GetAll(context.Background())
+	expected := initial + writers*postsPerWriter - int(deleted.Load())
+	if len(posts) != expected {
+		t.Errorf("Expected %d posts, got %d", expected, len(posts))
+	}
+	for _, post := range posts {
+		if post.ID > 2 && post.Title != "Updated" {
+			t.Errorf("Expected post %d to be updated, got title %q", post.ID, post.Title)
+		}
+	}
+	if post, _ := repo.GetByID(1); post.Views != writers*postsPerWriter {
+		t.Errorf("Expected %d views, got %d", writers*postsPerWriter, post.Views)
+	}
+}
+
+func BenchmarkMapRepositoryGetByIDParallel(b *testing.B) {
+	repo := setupTestRepository()
+	for i := 0; i < 1000; i++ {
+		repo.Create(PostCreateUpdate{Title: fmt.Sprintf("Post %d", i), Content: "Content", Author: "Author"})
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		id := 1
+		for pb.Next() {
+			if _, err := repo.GetByID(id); err != nil {
+				b.Fatalf("Expected no error, got %v", err)
+			}
+			id = id%1000 + 1
+		}
+	})
+}
+
+func TestNewMapRepositoryWithPosts(t *testing.T) {
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	repo, err := NewMapRepositoryWithPosts([]PostRead{
+		{ID: 7, Title: "Seventh", Content: "Content", Author: "Jane Doe", CreatedAt: createdAt},
+		{ID: 3, Title: "Third", Content: "Content", Author: "Jane Doe", Status: StatusDraft},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	seventh, err := repo.GetByID(7)
+	if err != nil || seventh.Title != "Seventh" {
+		t.Errorf("Expected the seeded post 7, got %+v (%v)", seventh, err)
+	}
+	if !seventh.UpdatedAt.Equal(createdAt) || seventh.Status != StatusPublished {
+		t.Errorf("Expected missing fields to be filled in as when loading a file, got %+v", seventh)
+	}
+	if third, _ := repo.GetByID(3); third.Status != StatusDraft || third.CreatedAt.IsZero() {
+		t.Errorf("Expected the seeded post 3 to keep its status and get a creation time, got %+v", third)
+	}
+
+	created, err := repo.Create(PostCreateUpdate{Title: "New", Content: "Content", Author: "Jane Doe"})
+	if err != nil {
+		t.Fatalf("Failed to create post: %v", err)
+	}
+	if created.ID != 8 {
+		t.Errorf("Expected the next ID after the largest seeded one, 8, got %d", created.ID)
+	}
+}
+
+func TestNewMapRepositoryWithPostsRejectsDuplicateIDs(t *testing.T) {
+	_, err := NewMapRepositoryWithPosts([]PostRead{{ID: 1, Title: "A"}, {ID: 1, Title: "B"}})
+	if !errors.Is(err, ErrDuplicatePostID) {
+		t.Errorf("Expected ErrDuplicatePostID, got %v", err)
+	}
+}
+
+func TestNewMapRepositoryWithPostsEmpty(t *testing.T) {
+	repo, err := NewMapRepositoryWithPosts(nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if created, _ := repo.Create(PostCreateUpdate{Title: "First", Content: "Content", Author: "Jane Doe"}); created.ID != 1 {
+		t.Errorf("Expected the first post to get ID 1, got %d", created.ID)
+	}
+}
+
+func setupTestRepository() *MapRepository {
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	repo, err := NewMapRepositoryWithPosts([]PostRead{
+		{
+			ID:        1,
+			Title:     "Test Post 1",
+			Content:   "Test Content 1",
+			Author:    "Test Author 1",
+			CreatedAt: createdAt,
+			UpdatedAt: createdAt,
+		},
+		{
+			ID:        2,
+			Title:     "Test Post 2",
+			Content:   "Test Content 2",
+			Author:    "Test Author 2",
+			CreatedAt: createdAt.Add(time.Hour),
+			UpdatedAt: createdAt.Add(time.Hour),
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
 	return repo
 }
@@ -0,0 +1,327 @@
+package posts
+
+import (
+	"context"
+	"errors"
+	"github.com/go-playground/validator/v10"
+	"time"
+)
+
+const (
+	defaultRetryAttempts   = 3
+	defaultRetryBackoff    = 50 * time.Millisecond
+	defaultRetryMaxBackoff = time.Second
+)
+
+// permanentRepositoryErrors are the errors IsTransientError never retries: repeating the
+// call would fail the same way.
+var permanentRepositoryErrors = []error{
+	context.Canceled,
+	context.DeadlineExceeded,
+	ErrPostNotFound,
+	ErrDuplicatePost,
+	ErrDuplicatePostID,
+	ErrCapacityExceeded,
+	ErrPreconditionFailed,
+	ErrInvalidSnapshot,
+	InvalidPostIDError,
+	ErrReadOnly,
+}
+
+// RetryPolicy says which failed calls a RetryingRepository repeats, how often and how
+// long it waits in between.
+type RetryPolicy struct {
+	// MaxAttempts is how many times a call is made in all; 1 or less never retries.
+	MaxAttempts int
+	// InitialBackoff is the wait before the first retry. Each further retry waits twice
+	// as long as the one before, up to MaxBackoff.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// Retryable reports whether a call that failed with err may succeed when repeated;
+	// nil means IsTransientError.
+	Retryable func(err error) bool
+}
+
+// DefaultRetryPolicy makes up to 3 attempts, 50ms and then 100ms apart, retrying the
+// errors IsTransientError accepts.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    defaultRetryAttempts,
+		InitialBackoff: defaultRetryBackoff,
+		MaxBackoff:     defaultRetryMaxBackoff,
+		Retryable:      IsTransientError,
+	}
+}
+
+// backoff returns how long to wait before retry number attempt, counting from 1.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	wait := p.InitialBackoff
+	for i := 1; i < attempt && wait < p.MaxBackoff; i++ {
+		wait *= 2
+	}
+	return min(wait, p.MaxBackoff)
+}
+
+// IsTransientError reports whether a repository call that failed with err might succeed
+// if made again. Errors that describe the request rather than the backend, such as
+// ErrPostNotFound, validation errors and the caller's context ending, are not transient.
+func IsTransientError(err error) bool {
+	if err == nil {
+		return false
+	}
+	for _, permanent := range permanentRepositoryErrors {
+		if errors.Is(err, permanent) {
+			return false
+		}
+	}
+	var validationErrors validator.ValidationErrors
+	return !errors.As(err, &validationErrors)
+}
+
+// RetryingRepository passes every call through to repo and repeats those that fail with
+// a retryable error, backing off exponentially between attempts. Writes are retried
+// too, so a backend that fails a write after applying it may see it twice; Create and
+// IncrementViews are the calls that are not idempotent.
+type RetryingRepository struct {
+	repo   Repository
+	policy RetryPolicy
+}
+
+// retryingTransactor is the RetryingRepository of a repository that is a Transactor, so
+// that wrapping it keeps transactions available.
+type retryingTransactor struct {
+	*RetryingRepository
+}
+
+// NewRetryingRepository wraps repo so that its failed calls are retried as policy says.
+// The result is a Transactor if repo is one. Other optional interfaces, such as Reloader
+// and io.Closer, are not passed on and should be used on repo itself.
+func NewRetryingRepository(repo Repository, policy RetryPolicy) Repository {
+	if policy.Retryable == nil {
+		policy.Retryable = IsTransientError
+	}
+	retrying := &RetryingRepository{repo: repo, policy: policy}
+	if _, ok := repo.(Transactor); ok {
+		return retryingTransactor{retrying}
+	}
+	return retrying
+}
+
+// finalError wraps an error that do must return without retrying, whatever the policy
+// says about it.
+type finalError struct {
+	err error
+}
+
+func (e finalError) Error() string { return e.err.Error() }
+
+// do calls fn until it succeeds, fails with an error that is not retryable or has been
+// called policy.MaxAttempts times, and returns its last error. It gives up with ctx's
+// error if ctx is done while waiting between attempts.
+func (r *RetryingRepository) do(ctx context.Context, fn func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if final, ok := err.(finalError); ok {
+			return final.err
+		}
+		if err == nil || attempt >= r.policy.MaxAttempts || !r.policy.Retryable(err) {
+			return err
+		}
+
+		timer := time.NewTimer(r.policy.backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (r *RetryingRepository) GetAll(ctx context.Context) (posts []PostRead, err error) {
+	err = r.do(ctx, func() error {
+		posts, err = r.repo.GetAll(ctx)
+		return err
+	})
+	return posts, err
+}
+
+func (r *RetryingRepository) List(ctx context.Context, params ListParams) (posts []PostRead, total int, err error) {
+	err = r.do(ctx, func() error {
+		posts, total, err = r.repo.List(ctx, params)
+		return err
+	})
+	return posts, total, err
+}
+
+// Iterate is only retried if it fails before fn has seen a post, so that fn is never
+// called twice for the same post.
+func (r *RetryingRepository) Iterate(ctx context.Context, fn func(post PostRead) error) error {
+	visited := false
+	return r.do(ctx, func() error {
+		err := r.repo.Iterate(ctx, func(post PostRead) error {
+			visited = true
+			return fn(post)
+		})
+		if err != nil && visited {
+			return finalError{err}
+		}
+		return err
+	})
+}
+
+func (r *RetryingRepository) GetByID(id int) (post PostRead, err error) {
+	err = r.do(context.Background(), func() error {
+		post, err = r.repo.GetByID(id)
+		return err
+	})
+	return post, err
+}
+
+func (r *RetryingRepository) GetByIDs(ids []int) (posts []PostRead, err error) {
+	err = r.do(context.Background(), func() error {
+		posts, err = r.repo.GetByIDs(ids)
+		return err
+	})
+	return posts, err
+}
+
+func (r *RetryingRepository) Exists(id int) (exists bool, err error) {
+	err = r.do(context.Background(), func() error {
+		exists, err = r.repo.Exists(id)
+		return err
+	})
+	return exists, err
+}
+
+func (r *RetryingRepository) Create(data PostCreateUpdate) (post PostRead, err error) {
+	err = r.do(context.Background(), func() error {
+		post, err = r.repo.Create(data)
+		return err
+	})
+	return post, err
+}
+
+func (r *RetryingRepository) CreateMany(data []PostCreateUpdate) (posts []PostRead, err error) {
+	err = r.do(context.Background(), func() error {
+		posts, err = r.repo.CreateMany(data)
+		return err
+	})
+	return posts, err
+}
+
+func (r *RetryingRepository) Update(id int, data PostCreateUpdate) (post PostRead, err error) {
+	err = r.do(context.Background(), func() error {
+		post, err = r.repo.Update(id, data)
+		return err
+	})
+	return post, err
+}
+
+func (r *RetryingRepository) Upsert(id int, data PostCreateUpdate) (post PostRead, created bool, err error) {
+	err = r.do(context.Background(), func() error {
+		post, created, err = r.repo.Upsert(id, data)
+		return err
+	})
+	return post, created, err
+}
+
+func (r *RetryingRepository) Delete(id int) error {
+	return r.do(context.Background(), func() error {
+		return r.repo.Delete(id)
+	})
+}
+
+func (r *RetryingRepository) DeleteMany(ids []int) (deleted []int, err error) {
+	err = r.do(context.Background(), func() error {
+		deleted, err = r.repo.DeleteMany(ids)
+		return err
+	})
+	return deleted, err
+}
+
+func (r *RetryingRepository) UpdateIfUnmodified(id int, data PostCreateUpdate, since time.Time) (post PostRead, err error) {
+	err = r.do(context.Background(), func() error {
+		post, err = r.repo.UpdateIfUnmodified(id, data, since)
+		return err
+	})
+	return post, err
+}
+
+func (r *RetryingRepository) UpdateIfMatch(id int, data PostCreateUpdate, etag string) (post PostRead, err error) {
+	err = r.do(context.Background(), func() error {
+		post, err = r.repo.UpdateIfMatch(id, data, etag)
+		return err
+	})
+	return post, err
+}
+
+func (r *RetryingRepository) DeleteIfUnmodified(id int, since time.Time) error {
+	return r.do(context.Background(), func() error {
+		return r.repo.DeleteIfUnmodified(id, since)
+	})
+}
+
+func (r *RetryingRepository) IncrementViews(id int) (views int, err error) {
+	err = r.do(context.Background(), func() error {
+		views, err = r.repo.IncrementViews(id)
+		return err
+	})
+	return views, err
+}
+
+func (r *RetryingRepository) CountByAuthor(ctx context.Context) (counts map[string]int, err error) {
+	err = r.do(ctx, func() error {
+		counts, err = r.repo.CountByAuthor(ctx)
+		return err
+	})
+	return counts, err
+}
+
+func (r *RetryingRepository) Authors(ctx context.Context) (authors []string, err error) {
+	err = r.do(ctx, func() error {
+		authors, err = r.repo.Authors(ctx)
+		return err
+	})
+	return authors, err
+}
+
+func (r *RetryingRepository) GetRecent(ctx context.Context, n int) (posts []PostRead, err error) {
+	err = r.do(ctx, func() error {
+		posts, err = r.repo.GetRecent(ctx, n)
+		return err
+	})
+	return posts, err
+}
+
+func (r *RetryingRepository) GetRandom() (post PostRead, err error) {
+	err = r.do(context.Background(), func() error {
+		post, err = r.repo.GetRandom()
+		return err
+	})
+	return post, err
+}
+
+func (r *RetryingRepository) Neighbors(ctx context.Context, id int) (prev, next *PostRead, err error) {
+	err = r.do(ctx, func() error {
+		prev, next, err = r.repo.Neighbors(ctx, id)
+		return err
+	})
+	return prev, next, err
+}
+
+func (r *RetryingRepository) ExistsByTitleAndAuthor(title, author string) (exists bool, err error) {
+	err = r.do(context.Background(), func() error {
+		exists, err = r.repo.ExistsByTitleAndAuthor(title, author)
+		return err
+	})
+	return exists, err
+}
+
+// WithTx retries the transaction as a whole, running fn again from the start. The calls
+// fn makes on the transaction are not retried one by one.
+func (r retryingTransactor) WithTx(fn func(tx Repository) error) error {
+	return r.do(context.Background(), func() error {
+		return r.repo.(Transactor).WithTx(fn)
+	})
+}
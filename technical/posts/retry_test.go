@@ -0,0 +1,180 @@
+package posts
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+var errTransient = errors.New("connection reset")
+
+// testRetryPolicy retries quickly so that the tests do not wait on real backoffs.
+func testRetryPolicy() RetryPolicy {
+	policy := DefaultRetryPolicy()
+	policy.InitialBackoff = time.Millisecond
+	policy.MaxBackoff = 2 * time.Millisecond
+	return policy
+}
+
+func TestRetryingRepository(t *testing.T) {
+	tests := []struct {
+		name           string
+		failures       []error
+		expectedErr    error
+		expectedCalls  int
+		expectedPostID int
+	}{
+		{name: "Fails Twice Then Succeeds", failures: []error{errTransient, errTransient}, expectedCalls: 3, expectedPostID: 7},
+		{name: "No Failure", expectedCalls: 1, expectedPostID: 7},
+		{name: "Not Found Is Not Retried", failures: []error{ErrPostNotFound}, expectedErr: ErrPostNotFound, expectedCalls: 1},
+		{name: "Gives Up After Max Attempts", failures: []error{errTransient, errTransient, errTransient, errTransient}, expectedErr: errTransient, expectedCalls: 3},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			calls := 0
+			repo := NewRetryingRepository(&MockRepository{
+				GetByIDFn: func(id int) (PostRead, error) {
+					calls++
+					if calls <= len(tc.failures) {
+						return PostRead{}, tc.failures[calls-1]
+					}
+					return PostRead{ID: id}, nil
+				},
+			}, testRetryPolicy())
+
+			post, err := repo.GetByID(7)
+
+			if !errors.Is(err, tc.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tc.expectedErr, err)
+			}
+			if calls != tc.expectedCalls {
+				t.Errorf("Expected %d calls, got %d", tc.expectedCalls, calls)
+			}
+			if post.ID != tc.expectedPostID {
+				t.Errorf("Expected post ID %d, got %d", tc.expectedPostID, post.ID)
+			}
+		})
+	}
+}
+
+func TestRetryingRepositoryHonorsCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	policy := testRetryPolicy()
+	policy.InitialBackoff = time.Hour
+	policy.MaxBackoff = time.Hour
+	repo := NewRetryingRepository(&MockRepository{
+		GetAllFn: func() ([]PostRead, error) {
+			calls++
+			cancel()
+			return nil, errTransient
+		},
+	}, policy)
+
+	_, err := repo.GetAll(ctx)
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected 1 call, got %d", calls)
+	}
+}
+
+func TestRetryingRepositoryCustomPredicate(t *testing.T) {
+	calls := 0
+	policy := testRetryPolicy()
+	policy.Retryable = func(err error) bool { return errors.Is(err, ErrPostNotFound) }
+	repo := NewRetryingRepository(&MockRepository{
+		DeleteFn: func(id int) error {
+			calls++
+			if calls == 1 {
+				return ErrPostNotFound
+			}
+			return errTransient
+		},
+	}, policy)
+
+	err := repo.Delete(1)
+
+	if !errors.Is(err, errTransient) {
+		t.Errorf("Expected the error the predicate rejects, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected 2 calls, got %d", calls)
+	}
+}
+
+func TestRetryingRepositoryIterate(t *testing.T) {
+	calls := 0
+	visited := 0
+	repo := NewRetryingRepository(&MockRepository{
+		IterateFn: func(fn func(post PostRead) error) error {
+			calls++
+			if calls == 1 {
+				return errTransient
+			}
+			if err := fn(PostRead{ID: 1}); err != nil {
+				return err
+			}
+			return errTransient
+		},
+	}, testRetryPolicy())
+
+	err := repo.Iterate(context.Background(), func(post PostRead) error {
+		visited++
+		return nil
+	})
+
+	if !errors.Is(err, errTransient) {
+		t.Errorf("Expected the failure after a post was visited to be returned, got %v", err)
+	}
+	if calls != 2 || visited != 1 {
+		t.Errorf("Expected 2 calls visiting 1 post, got %d calls visiting %d", calls, visited)
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: 10 * time.Millisecond, MaxBackoff: 35 * time.Millisecond}
+	expected := []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 35 * time.Millisecond, 35 * time.Millisecond}
+
+	for i, want := range expected {
+		if got := policy.backoff(i + 1); got != want {
+			t.Errorf("Expected backoff %v before retry %d, got %v", want, i+1, got)
+		}
+	}
+}
+
+func TestIsTransientError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{name: "Backend Error", err: errTransient, expected: true},
+		{name: "Not Found", err: ErrPostNotFound, expected: false},
+		{name: "Wrapped Duplicate", err: errors.Join(errors.New("create"), ErrDuplicatePost), expected: false},
+		{name: "Precondition Failed", err: ErrPreconditionFailed, expected: false},
+		{name: "Deadline", err: context.DeadlineExceeded, expected: false},
+		{name: "Nil", err: nil, expected: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := IsTransientError(tc.err); got != tc.expected {
+				t.Errorf("Expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestRetryingRepositoryKeepsTransactions(t *testing.T) {
+	if _, ok := NewRetryingRepository(&MockRepository{}, testRetryPolicy()).(Transactor); ok {
+		t.Error("Expected a repository without transactions not to become a Transactor")
+	}
+	if _, ok := NewRetryingRepository(setupTestRepository(), testRetryPolicy()).(Transactor); !ok {
+		t.Error("Expected the wrapped MapRepository to remain a Transactor")
+	}
+}
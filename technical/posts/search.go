@@ -0,0 +1,61 @@
+package posts
+
+import (
+	"errors"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var (
+	// ErrEmptySearch is returned by SearchPosts for a query without any text.
+	ErrEmptySearch          = errors.New("search query must not be empty")
+	errInvalidStrictAccents = errors.New("strictAccents must be true or false")
+)
+
+// SearchQuery selects the posts SearchPosts returns: those whose title or content
+// contains Text, ignoring case.
+type SearchQuery struct {
+	Text string
+	// StrictAccents makes accented letters match only themselves. By default accents are
+	// ignored, so that "cafe" matches "café" and the other way round.
+	StrictAccents bool
+}
+
+// matches reports whether post's title or content contains the text of q, which must
+// already be normalized by q.normalize.
+func (q SearchQuery) matches(post PostRead, text string) bool {
+	return strings.Contains(q.normalize(post.Title), text) || strings.Contains(q.normalize(post.Content), text)
+}
+
+// normalize lower-cases s and, unless StrictAccents is set, strips its diacritics by
+// decomposing it to NFD and dropping the combining marks.
+func (q SearchQuery) normalize(s string) string {
+	s = strings.ToLower(s)
+	if q.StrictAccents {
+		// Composed and decomposed forms of the same letter still compare equal.
+		return norm.NFC.String(s)
+	}
+	folded, _, err := transform.String(transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC), s)
+	if err != nil {
+		return s
+	}
+	return folded
+}
+
+// parseSearchQuery reads the q and strictAccents query parameters of GET /posts/search.
+func parseSearchQuery(r *http.Request) (SearchQuery, error) {
+	query := SearchQuery{Text: r.URL.Query().Get("q")}
+	if raw := r.URL.Query().Get("strictAccents"); raw != "" {
+		strict, err := strconv.ParseBool(raw)
+		if err != nil {
+			return SearchQuery{}, errInvalidStrictAccents
+		}
+		query.StrictAccents = strict
+	}
+	return query, nil
+}
@@ -0,0 +1,98 @@
+package posts
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// setupSearchRepository returns the test repository with posts written with and without
+// accents, and one whose accent is a combining mark rather than a composed letter.
+func setupSearchRepository() *MapRepository {
+	repo := setupTestRepository()
+	repo.Create(PostCreateUpdate{Title: "Café culture", Content: "Where to drink coffee", Author: "Jane Doe"})
+	repo.Create(PostCreateUpdate{Title: "Naive questions", Content: "Resume writing tips", Author: "Jane Doe"})
+	repo.Create(PostCreateUpdate{Title: "Decomposed", Content: "A cafe\u0301 in Paris", Author: "Jane Doe"})
+	return repo
+}
+
+func TestSearchPosts(t *testing.T) {
+	tests := []struct {
+		name          string
+		query         SearchQuery
+		expectedIDs   []int
+		expectedError error
+	}{
+		{name: "Unaccented Query Matches Accented Content", query: SearchQuery{Text: "cafe"}, expectedIDs: []int{3, 5}},
+		{name: "Accented Query Matches Unaccented Title", query: SearchQuery{Text: "Naïve"}, expectedIDs: []int{4}},
+		{name: "Accented Query Matches Unaccented Content", query: SearchQuery{Text: "résumé"}, expectedIDs: []int{4}},
+		{name: "Case Insensitive", query: SearchQuery{Text: "CAFÉ CULTURE"}, expectedIDs: []int{3}},
+		{name: "Strict Accents Unaccented Query", query: SearchQuery{Text: "cafe", StrictAccents: true}, expectedIDs: nil},
+		{name: "Strict Accents Accented Query", query: SearchQuery{Text: "café", StrictAccents: true}, expectedIDs: []int{3, 5}},
+		{name: "Strict Accents Stays Case Insensitive", query: SearchQuery{Text: "CAFÉ", StrictAccents: true}, expectedIDs: []int{3, 5}},
+		{name: "No Match", query: SearchQuery{Text: "tea"}, expectedIDs: nil},
+		{name: "Empty", query: SearchQuery{Text: "  "}, expectedError: ErrEmptySearch},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			service := NewPostService(setupSearchRepository())
+
+			posts, err := service.SearchPosts(context.Background(), tc.query)
+
+			if !errors.Is(err, tc.expectedError) {
+				t.Fatalf("Expected error %v, got %v", tc.expectedError, err)
+			}
+			if len(posts) != len(tc.expectedIDs) {
+				t.Fatalf("Expected posts %v, got %+v", tc.expectedIDs, posts)
+			}
+			for i, post := range posts {
+				if post.ID != tc.expectedIDs[i] {
+					t.Errorf("Expected post %d at position %d, got %d", tc.expectedIDs[i], i, post.ID)
+				}
+			}
+		})
+	}
+}
+
+func TestSearchPostsHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		url            string
+		expectedStatus int
+		expectedCount  int
+	}{
+		{name: "Accent Insensitive", url: "/posts/search?q=cafe", expectedStatus: http.StatusOK, expectedCount: 2},
+		{name: "Strict Accents", url: "/posts/search?q=cafe&strictAccents=true", expectedStatus: http.StatusOK, expectedCount: 0},
+		{name: "Missing Query", url: "/posts/search", expectedStatus: http.StatusBadRequest},
+		{name: "Invalid Strict Accents", url: "/posts/search?q=cafe&strictAccents=maybe", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			NewHandler(NewPostService(setupSearchRepository())).RegisterRoutes(mux)
+
+			rr := httptest.NewRecorder()
+			mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tc.url, nil))
+
+			if rr.Code != tc.expectedStatus {
+				t.Fatalf("Expected status %d, got %d", tc.expectedStatus, rr.Code)
+			}
+			if tc.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var posts []PostRead
+			if err := json.Unmarshal(rr.Body.Bytes(), &posts); err != nil {
+				t.Fatalf("Expected an array, got %s", rr.Body.String())
+			}
+			if len(posts) != tc.expectedCount {
+				t.Errorf("Expected %d posts, got %d", tc.expectedCount, len(posts))
+			}
+		})
+	}
+}
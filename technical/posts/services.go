@@ -1,68 +1,841 @@
 package posts
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"github.com/microcosm-cc/bluemonday"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"io"
+	"slices"
+	"strings"
+	"time"
 )
 
-var InvalidPostIDError = errors.New("invalid post ID")
+var (
+	InvalidPostIDError = errors.New("invalid post ID")
+	ErrNoPostIDs       = errors.New("no post IDs given")
+	ErrTooManyPostIDs  = fmt.Errorf("more than %d post IDs given", maxBatchGetIDs)
+	ErrReadOnly        = errors.New("the API is read-only")
+)
+
+const maxRecentPosts = 50
+
+// maxBatchGetIDs caps the number of distinct IDs GetPostsByIDs looks up at once.
+const maxBatchGetIDs = 1000
+
+// maxPatchAttempts bounds how often PatchPost redoes a patch without If-Match that lost
+// a race with a concurrent update.
+const maxPatchAttempts = 3
 
 type Service interface {
-	GetAllPosts() ([]PostRead, error)
-	GetPostByID(id int) (PostRead, error)
-	CreatePost(req PostCreateUpdate) (PostRead, error)
-	UpdatePost(id int, req PostCreateUpdate) (PostRead, error)
-	DeletePost(id int) error
+	GetAllPosts(ctx context.Context) ([]PostRead, error)
+	ListPosts(ctx context.Context, params ListParams) (posts []PostRead, total int, err error)
+	IteratePosts(ctx context.Context, fn func(post PostRead) error) error
+	GetPostByID(ctx context.Context, id int) (PostRead, error)
+	GetPostsByIDs(ctx context.Context, ids []int) ([]PostRead, error)
+	CreatePost(ctx context.Context, req PostCreateUpdate) (PostRead, error)
+	CreatePostIdempotent(ctx context.Context, key string, req PostCreateUpdate) (PostRead, error)
+	UpdatePost(ctx context.Context, id int, req PostCreateUpdate) (PostRead, error)
+	UpdatePostIfUnmodified(ctx context.Context, id int, req PostCreateUpdate, since time.Time) (PostRead, error)
+	UpsertPost(ctx context.Context, id int, req PostCreateUpdate) (post PostRead, created bool, err error)
+	PatchPost(ctx context.Context, id int, patch PostPatch, ifMatch string) (PostRead, error)
+	DeletePost(ctx context.Context, id int) error
+	DeletePostIfUnmodified(ctx context.Context, id int, since time.Time) error
+	DeletePosts(ctx context.Context, ids []int) (BulkDeleteResult, error)
+	PatchPosts(ctx context.Context, ids []int, patch PostPatch) (BulkPatchResult, error)
+	ImportPosts(ctx context.Context, r io.Reader, atomic bool) (ImportResult, error)
+	IncrementViews(ctx context.Context, id int) (int, error)
+	Stats(ctx context.Context) (PostStats, error)
+	Authors(ctx context.Context) ([]string, error)
+	GetRecentPosts(ctx context.Context, n int) ([]PostRead, error)
+	GetRandomPost(ctx context.Context) (PostRead, error)
+	GetPostNeighbors(ctx context.Context, id int) (PostNeighbors, error)
+	SearchPosts(ctx context.Context, query SearchQuery) ([]PostRead, error)
+	ValidatePost(ctx context.Context, req PostCreateUpdate) error
 }
 
 type PostService struct {
-	repo Repository
+	repo        Repository
+	tracer      trace.Tracer
+	idempotency *idempotencyCache
+	sanitizer   Sanitizer
+	publisher   EventPublisher
+	validate    *validator.Validate
+
+	checkDuplicates bool
+	readOnly        bool
+}
+
+// Sanitizer removes unsafe markup from post content. *bluemonday.Policy implements it.
+type Sanitizer interface {
+	Sanitize(s string) string
+}
+
+type ServiceOption func(*PostService)
+
+// WithTracerProvider makes the service start its spans from tp instead of the global provider.
+func WithTracerProvider(tp trace.TracerProvider) ServiceOption {
+	return func(s *PostService) {
+		s.tracer = tp.Tracer(tracerName)
+	}
+}
+
+// WithIdempotencyTTL sets how long the result of a create is remembered per idempotency key.
+func WithIdempotencyTTL(ttl time.Duration) ServiceOption {
+	return func(s *PostService) {
+		s.idempotency = newIdempotencyCache(ttl, s.idempotency.maxEntries)
+	}
+}
+
+// WithIdempotencyMaxEntries caps how many idempotency keys are remembered at once.
+// When the cap is reached the key closest to expiry is forgotten first.
+func WithIdempotencyMaxEntries(maxEntries int) ServiceOption {
+	return func(s *PostService) {
+		s.idempotency = newIdempotencyCache(s.idempotency.ttl, maxEntries)
+	}
+}
+
+// WithSanitizer replaces the default content sanitization policy, e.g. with
+// bluemonday.StrictPolicy() to strip all markup.
+func WithSanitizer(sanitizer Sanitizer) ServiceOption {
+	return func(s *PostService) {
+		s.sanitizer = sanitizer
+	}
+}
+
+// WithValidator replaces the validator used for post data, e.g. one from NewValidator
+// with extra rules registered.
+func WithValidator(v *validator.Validate) ServiceOption {
+	return func(s *PostService) {
+		s.validate = v
+	}
+}
+
+// WithDuplicateCheck enables or disables rejecting creates whose title and author
+// match an existing post. It is enabled by default.
+func WithDuplicateCheck(enabled bool) ServiceOption {
+	return func(s *PostService) {
+		s.checkDuplicates = enabled
+	}
+}
+
+// WithEventPublisher makes the service report every stored change to publisher.
+func WithEventPublisher(publisher EventPublisher) ServiceOption {
+	return func(s *PostService) {
+		s.publisher = publisher
+	}
 }
 
-func NewPostService(repo Repository) *PostService {
-	return &PostService{
-		repo: repo,
+// WithReadOnly makes every method that would change posts, including IncrementViews,
+// fail with ErrReadOnly, whichever API it is called through.
+func WithReadOnly(enabled bool) ServiceOption {
+	return func(s *PostService) {
+		s.readOnly = enabled
 	}
 }
 
-func (s *PostService) GetAllPosts() ([]PostRead, error) {
-	return s.repo.GetAll()
+func NewPostService(repo Repository, opts ...ServiceOption) *PostService {
+	s := &PostService{
+		repo:        repo,
+		tracer:      otel.Tracer(tracerName),
+		idempotency: newIdempotencyCache(defaultIdempotencyTTL, defaultIdempotencyMaxEntries),
+		sanitizer:   bluemonday.UGCPolicy(),
+		validate:    defaultValidator,
+
+		checkDuplicates: true,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
-func (s *PostService) GetPostByID(id int) (PostRead, error) {
+func (s *PostService) GetAllPosts(ctx context.Context) (posts []PostRead, err error) {
+	_, span := startServiceSpan(ctx, s.tracer, "GetAllPosts")
+	defer func() { endSpan(span, err) }()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetAll(ctx)
+}
+
+// ListPosts returns the page of posts selected by params in ID order, along with the
+// total number of posts matching params.
+func (s *PostService) ListPosts(ctx context.Context, params ListParams) (posts []PostRead, total int, err error) {
+	_, span := startServiceSpan(ctx, s.tracer, "ListPosts")
+	defer func() { endSpan(span, err) }()
+
+	if err := ctx.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return s.repo.List(ctx, params)
+}
+
+// IteratePosts calls fn for each post in ID order; see Repository.Iterate.
+func (s *PostService) IteratePosts(ctx context.Context, fn func(post PostRead) error) (err error) {
+	_, span := startServiceSpan(ctx, s.tracer, "IteratePosts")
+	defer func() { endSpan(span, err) }()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return s.repo.Iterate(ctx, fn)
+}
+
+func (s *PostService) GetPostByID(ctx context.Context, id int) (post PostRead, err error) {
+	_, span := startServiceSpan(ctx, s.tracer, "GetPostByID", postIDAttribute(id))
+	defer func() { endSpan(span, err) }()
+
+	if err := ctx.Err(); err != nil {
+		return PostRead{}, err
+	}
+
 	if id <= 0 {
-		return PostRead{}, errors.New("invalid post ID")
+		return PostRead{}, InvalidPostIDError
 	}
 	return s.repo.GetByID(id)
 }
 
-func (s *PostService) CreatePost(data PostCreateUpdate) (PostRead, error) {
-	if err := data.Validate(); err != nil {
+// GetPostsByIDs returns the listed posts that exist, in the order their IDs are first
+// listed. Repeated IDs are looked up once, and at most maxBatchGetIDs distinct IDs may
+// be given.
+func (s *PostService) GetPostsByIDs(ctx context.Context, ids []int) (posts []PostRead, err error) {
+	_, span := startServiceSpan(ctx, s.tracer, "GetPostsByIDs")
+	defer func() { endSpan(span, err) }()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(ids) == 0 {
+		return nil, ErrNoPostIDs
+	}
+	seen := make(map[int]bool, len(ids))
+	unique := make([]int, 0, len(ids))
+	for _, id := range ids {
+		if id <= 0 {
+			return nil, InvalidPostIDError
+		}
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	if len(unique) > maxBatchGetIDs {
+		return nil, ErrTooManyPostIDs
+	}
+	return s.repo.GetByIDs(unique)
+}
+
+func (s *PostService) CreatePost(ctx context.Context, data PostCreateUpdate) (post PostRead, err error) {
+	_, span := startServiceSpan(ctx, s.tracer, "CreatePost")
+	defer func() { endSpan(span, err) }()
+
+	if err := ctx.Err(); err != nil {
+		return PostRead{}, err
+	}
+	if s.readOnly {
+		return PostRead{}, ErrReadOnly
+	}
+
+	data, err = s.prepareCreate(data)
+	if err != nil {
+		return PostRead{}, err
+	}
+
+	// The check and the create are separate repository calls, so two concurrent
+	// identical requests can still both succeed; this guards against double-submits
+	// rather than guaranteeing uniqueness.
+	if s.checkDuplicates {
+		duplicate, err := s.repo.ExistsByTitleAndAuthor(data.Title, data.Author)
+		if err != nil {
+			return PostRead{}, err
+		}
+		if duplicate {
+			return PostRead{}, ErrDuplicatePost
+		}
+	}
+
+	post, err = s.repo.Create(data)
+	if err != nil {
+		return PostRead{}, err
+	}
+	s.publish(postChanged(EventPostCreated, post))
+	return post, nil
+}
+
+// ValidatePost checks data exactly as CreatePost would, without storing anything.
+func (s *PostService) ValidatePost(ctx context.Context, data PostCreateUpdate) (err error) {
+	_, span := startServiceSpan(ctx, s.tracer, "ValidatePost")
+	defer func() { endSpan(span, err) }()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	_, err = s.prepareCreate(data)
+	return err
+}
+
+// prepareCreate normalizes and validates the request for a new post and returns the prepared data.
+func (s *PostService) prepareCreate(data PostCreateUpdate) (PostCreateUpdate, error) {
+	data = s.preparePostData(data)
+	if err := validatePost(s.validate, data, data.PostStatus()); err != nil {
+		return PostCreateUpdate{}, newValidationError(err)
+	}
+	return data, nil
+}
+
+// CreatePostIdempotent creates a post once per key; repeated calls with the same key
+// return the originally created post until the key expires. Reusing a key with a
+// different body fails with ErrIdempotencyKeyReused.
+func (s *PostService) CreatePostIdempotent(ctx context.Context, key string, data PostCreateUpdate) (PostRead, error) {
+	if key == "" {
+		return s.CreatePost(ctx, data)
+	}
+	return s.idempotency.do(ctx, key, idempotencyFingerprint(data), func() (PostRead, error) {
+		return s.CreatePost(ctx, data)
+	})
+}
+
+func (s *PostService) UpdatePost(ctx context.Context, id int, data PostCreateUpdate) (post PostRead, err error) {
+	_, span := startServiceSpan(ctx, s.tracer, "UpdatePost", postIDAttribute(id))
+	defer func() { endSpan(span, err) }()
+
+	data, err = s.prepareUpdate(ctx, id, data)
+	if err != nil {
+		return PostRead{}, err
+	}
+
+	exists, err := s.repo.Exists(id)
+	if err != nil {
 		return PostRead{}, err
 	}
+	if !exists {
+		return PostRead{}, ErrPostNotFound
+	}
 
-	return s.repo.Create(data)
+	post, err = s.repo.Update(id, data)
+	if err != nil {
+		return PostRead{}, err
+	}
+	s.publish(postChanged(EventPostUpdated, post))
+	return post, nil
 }
 
-func (s *PostService) UpdatePost(id int, data PostCreateUpdate) (PostRead, error) {
-	if id <= 0 {
-		return PostRead{}, InvalidPostIDError
+// UpdatePostIfUnmodified updates the post only if it has not been updated after since,
+// failing with ErrPreconditionFailed otherwise.
+func (s *PostService) UpdatePostIfUnmodified(ctx context.Context, id int, data PostCreateUpdate, since time.Time) (post PostRead, err error) {
+	_, span := startServiceSpan(ctx, s.tracer, "UpdatePostIfUnmodified", postIDAttribute(id))
+	defer func() { endSpan(span, err) }()
+
+	data, err = s.prepareUpdate(ctx, id, data)
+	if err != nil {
+		return PostRead{}, err
 	}
 
-	if err := data.Validate(); err != nil {
+	post, err = s.repo.UpdateIfUnmodified(id, data, since)
+	if err != nil {
 		return PostRead{}, err
 	}
+	s.publish(postChanged(EventPostUpdated, post))
+	return post, nil
+}
+
+// UpsertPost replaces the post with the given ID or, unlike UpdatePost, creates it
+// under that ID when it does not exist, reporting whether it was created.
+func (s *PostService) UpsertPost(ctx context.Context, id int, data PostCreateUpdate) (post PostRead, created bool, err error) {
+	_, span := startServiceSpan(ctx, s.tracer, "UpsertPost", postIDAttribute(id))
+	defer func() { endSpan(span, err) }()
+
+	data, err = s.prepareUpdate(ctx, id, data)
+	if err != nil {
+		return PostRead{}, false, err
+	}
+
+	post, created, err = s.repo.Upsert(id, data)
+	if err != nil {
+		return PostRead{}, false, err
+	}
+	if created {
+		s.publish(postChanged(EventPostCreated, post))
+	} else {
+		s.publish(postChanged(EventPostUpdated, post))
+	}
+	return post, created, nil
+}
+
+// PatchPost sets the fields present in patch on the post. With ifMatch, an If-Match
+// header value, it fails with ErrPreconditionFailed unless that matches the post's
+// current ETag; without it the patch is applied to whatever version is current, and
+// redone should another update slip in between reading and writing the post.
+func (s *PostService) PatchPost(ctx context.Context, id int, patch PostPatch, ifMatch string) (post PostRead, err error) {
+	_, span := startServiceSpan(ctx, s.tracer, "PatchPost", postIDAttribute(id))
+	defer func() { endSpan(span, err) }()
+
+	if err := s.checkUpdate(ctx, id); err != nil {
+		return PostRead{}, err
+	}
+	for attempt := 1; ; attempt++ {
+		current, err := s.repo.GetByID(id)
+		if err != nil {
+			return PostRead{}, err
+		}
+		etag := postETag(current)
+		if ifMatch != "" && !etagMatches(ifMatch, etag) {
+			return PostRead{}, ErrPreconditionFailed
+		}
+
+		data, err := s.preparePatch(ctx, current, patch)
+		if err != nil {
+			return PostRead{}, err
+		}
+		post, err = s.repo.UpdateIfMatch(id, data, etag)
+		if errors.Is(err, ErrPreconditionFailed) && ifMatch == "" && attempt < maxPatchAttempts {
+			continue
+		}
+		if err != nil {
+			return PostRead{}, err
+		}
+		s.publish(postChanged(EventPostUpdated, post))
+		return post, nil
+	}
+}
+
+// prepareUpdate checks the request for an update of post id and returns the prepared data.
+func (s *PostService) prepareUpdate(ctx context.Context, id int, data PostCreateUpdate) (PostCreateUpdate, error) {
+	if err := s.checkUpdate(ctx, id); err != nil {
+		return PostCreateUpdate{}, err
+	}
+
+	data = s.preparePostData(data)
+	if err := validatePost(s.validate, data, data.PostStatus()); err != nil {
+		return PostCreateUpdate{}, newValidationError(err)
+	}
+	return data, nil
+}
+
+// preparePatch checks patch applied to current and returns the prepared data. An
+// author the patch leaves as it is is not validated again.
+func (s *PostService) preparePatch(ctx context.Context, current PostRead, patch PostPatch) (PostCreateUpdate, error) {
+	if err := s.checkUpdate(ctx, current.ID); err != nil {
+		return PostCreateUpdate{}, err
+	}
+
+	data := s.preparePostData(patch.apply(current))
+	if err := validatePostUpdate(s.validate, data, data.PostStatus(), current.Author); err != nil {
+		return PostCreateUpdate{}, newValidationError(err)
+	}
+	return data, nil
+}
+
+// checkUpdate reports whether post id may be updated at all, before its data is looked at.
+func (s *PostService) checkUpdate(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if s.readOnly {
+		return ErrReadOnly
+	}
+
+	if id <= 0 {
+		return InvalidPostIDError
+	}
+	return nil
+}
+
+func (s *PostService) DeletePost(ctx context.Context, id int) (err error) {
+	_, span := startServiceSpan(ctx, s.tracer, "DeletePost", postIDAttribute(id))
+	defer func() { endSpan(span, err) }()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if s.readOnly {
+		return ErrReadOnly
+	}
+
+	if id <= 0 {
+		return InvalidPostIDError
+	}
+	// Deleting a missing post succeeds, but only an actual removal is announced.
+	exists, err := s.repo.Exists(id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Delete(id); err != nil {
+		return err
+	}
+	if exists {
+		s.publish(postDeleted(id))
+	}
+	return nil
+}
+
+// DeletePostIfUnmodified deletes the post only if it has not been updated after since,
+// failing with ErrPreconditionFailed otherwise.
+func (s *PostService) DeletePostIfUnmodified(ctx context.Context, id int, since time.Time) (err error) {
+	_, span := startServiceSpan(ctx, s.tracer, "DeletePostIfUnmodified", postIDAttribute(id))
+	defer func() { endSpan(span, err) }()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if s.readOnly {
+		return ErrReadOnly
+	}
+
+	if id <= 0 {
+		return InvalidPostIDError
+	}
+	if err := s.repo.DeleteIfUnmodified(id, since); err != nil {
+		return err
+	}
+	s.publish(postDeleted(id))
+	return nil
+}
+
+// DeletePosts deletes every listed post that exists and reports which IDs were
+// deleted and which were not found. Repeated IDs are reported once.
+func (s *PostService) DeletePosts(ctx context.Context, ids []int) (result BulkDeleteResult, err error) {
+	_, span := startServiceSpan(ctx, s.tracer, "DeletePosts")
+	defer func() { endSpan(span, err) }()
+
+	if err := ctx.Err(); err != nil {
+		return BulkDeleteResult{}, err
+	}
+	if s.readOnly {
+		return BulkDeleteResult{}, ErrReadOnly
+	}
+
+	if len(ids) == 0 {
+		return BulkDeleteResult{}, ErrNoPostIDs
+	}
+	unique := make([]int, 0, len(ids))
+	for _, id := range ids {
+		if id <= 0 {
+			return BulkDeleteResult{}, InvalidPostIDError
+		}
+		if !slices.Contains(unique, id) {
+			unique = append(unique, id)
+		}
+	}
+
+	deleted, err := s.repo.DeleteMany(unique)
+	if err != nil {
+		return BulkDeleteResult{}, err
+	}
+
+	for _, id := range deleted {
+		s.publish(postDeleted(id))
+	}
+
+	result = BulkDeleteResult{Deleted: emptyIfNil(deleted), NotFound: []int{}}
+	for _, id := range unique {
+		if !slices.Contains(deleted, id) {
+			result.NotFound = append(result.NotFound, id)
+		}
+	}
+	return result, nil
+}
+
+// PatchPosts sets the fields present in patch on every listed post that exists and
+// reports which IDs were not found. Should any patched post fail validation, none is
+// changed; when the repository is a Transactor the posts are also patched atomically
+// with respect to other writers. Repeated IDs are patched once.
+func (s *PostService) PatchPosts(ctx context.Context, ids []int, patch PostPatch) (result BulkPatchResult, err error) {
+	_, span := startServiceSpan(ctx, s.tracer, "PatchPosts")
+	defer func() { endSpan(span, err) }()
+
+	if err := ctx.Err(); err != nil {
+		return BulkPatchResult{}, err
+	}
+	if s.readOnly {
+		return BulkPatchResult{}, ErrReadOnly
+	}
+
+	if len(ids) == 0 {
+		return BulkPatchResult{}, ErrNoPostIDs
+	}
+	unique := make([]int, 0, len(ids))
+	for _, id := range ids {
+		if id <= 0 {
+			return BulkPatchResult{}, InvalidPostIDError
+		}
+		if !slices.Contains(unique, id) {
+			unique = append(unique, id)
+		}
+	}
+
+	apply := func(repo Repository) error {
+		result = BulkPatchResult{Updated: []PostRead{}, NotFound: []int{}}
+		updates := make(map[int]PostCreateUpdate, len(unique))
+		for _, id := range unique {
+			current, err := repo.GetByID(id)
+			if errors.Is(err, ErrPostNotFound) {
+				result.NotFound = append(result.NotFound, id)
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			data, err := s.preparePatch(ctx, current, patch)
+			if err != nil {
+				return fmt.Errorf("post %d: %w", id, err)
+			}
+			updates[id] = data
+		}
+
+		for _, id := range unique {
+			data, ok := updates[id]
+			if !ok {
+				continue
+			}
+			post, err := repo.Update(id, data)
+			if err != nil {
+				return err
+			}
+			result.Updated = append(result.Updated, post)
+		}
+		return nil
+	}
+	if transactor, ok := s.repo.(Transactor); ok {
+		err = transactor.WithTx(apply)
+	} else {
+		err = apply(s.repo)
+	}
+	if err != nil {
+		return BulkPatchResult{}, err
+	}
+
+	for _, post := range result.Updated {
+		s.publish(postChanged(EventPostUpdated, post))
+	}
+	return result, nil
+}
+
+func (s *PostService) IncrementViews(ctx context.Context, id int) (views int, err error) {
+	_, span := startServiceSpan(ctx, s.tracer, "IncrementViews", postIDAttribute(id))
+	defer func() { endSpan(span, err) }()
+
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	if s.readOnly {
+		return 0, ErrReadOnly
+	}
+
+	if id <= 0 {
+		return 0, InvalidPostIDError
+	}
+	return s.repo.IncrementViews(id)
+}
+
+// Stats returns the number of posts per author, most prolific first, with ties broken by author name.
+func (s *PostService) Stats(ctx context.Context) (stats PostStats, err error) {
+	_, span := startServiceSpan(ctx, s.tracer, "Stats")
+	defer func() { endSpan(span, err) }()
+
+	if err := ctx.Err(); err != nil {
+		return PostStats{}, err
+	}
 
-	_, err := s.repo.GetByID(id)
+	counts, err := s.repo.CountByAuthor(ctx)
 	if err != nil {
+		return PostStats{}, err
+	}
+
+	stats.Authors = make([]AuthorStats, 0, len(counts))
+	for author, count := range counts {
+		stats.Authors = append(stats.Authors, AuthorStats{Author: author, Count: count})
+		stats.Total += count
+	}
+	slices.SortFunc(stats.Authors, func(a, b AuthorStats) int {
+		if a.Count != b.Count {
+			return b.Count - a.Count
+		}
+		return strings.Compare(a.Author, b.Author)
+	})
+	return stats, nil
+}
+
+// Authors returns the distinct authors of all posts in alphabetical order.
+func (s *PostService) Authors(ctx context.Context) (authors []string, err error) {
+	_, span := startServiceSpan(ctx, s.tracer, "Authors")
+	defer func() { endSpan(span, err) }()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return s.repo.Authors(ctx)
+}
+
+// GetRecentPosts returns the n most recently created posts, with n clamped to [1, maxRecentPosts].
+func (s *PostService) GetRecentPosts(ctx context.Context, n int) (posts []PostRead, err error) {
+	_, span := startServiceSpan(ctx, s.tracer, "GetRecentPosts")
+	defer func() { endSpan(span, err) }()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetRecent(ctx, min(max(n, 1), maxRecentPosts))
+}
+
+func (s *PostService) GetRandomPost(ctx context.Context) (post PostRead, err error) {
+	_, span := startServiceSpan(ctx, s.tracer, "GetRandomPost")
+	defer func() { endSpan(span, err) }()
+
+	if err := ctx.Err(); err != nil {
 		return PostRead{}, err
 	}
 
-	return s.repo.Update(id, data)
+	return s.repo.GetRandom()
 }
 
-func (s *PostService) DeletePost(id int) error {
+// GetPostNeighbors returns the posts before and after post id in ID order, which is
+// also the order they were created in.
+func (s *PostService) GetPostNeighbors(ctx context.Context, id int) (neighbors PostNeighbors, err error) {
+	_, span := startServiceSpan(ctx, s.tracer, "GetPostNeighbors", postIDAttribute(id))
+	defer func() { endSpan(span, err) }()
+
+	if err := ctx.Err(); err != nil {
+		return PostNeighbors{}, err
+	}
+
 	if id <= 0 {
-		return errors.New("invalid post ID")
+		return PostNeighbors{}, InvalidPostIDError
+	}
+	prev, next, err := s.repo.Neighbors(ctx, id)
+	if err != nil {
+		return PostNeighbors{}, err
 	}
-	return s.repo.Delete(id)
+	return PostNeighbors{Prev: prev, Next: next}, nil
+}
+
+// SearchPosts returns, in ID order, the posts whose title or content contains
+// query.Text; see SearchQuery.
+func (s *PostService) SearchPosts(ctx context.Context, query SearchQuery) (posts []PostRead, err error) {
+	_, span := startServiceSpan(ctx, s.tracer, "SearchPosts")
+	defer func() { endSpan(span, err) }()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	text := query.normalize(strings.TrimSpace(query.Text))
+	if text == "" {
+		return nil, ErrEmptySearch
+	}
+	err = s.repo.Iterate(ctx, func(post PostRead) error {
+		if query.matches(post, text) {
+			posts = append(posts, post)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return posts, nil
+}
+
+// ImportPosts creates a post for every valid CSV row and reports the invalid ones by
+// line number. With atomic set, any invalid row aborts the import and nothing is created.
+func (s *PostService) ImportPosts(ctx context.Context, r io.Reader, atomic bool) (result ImportResult, err error) {
+	_, span := startServiceSpan(ctx, s.tracer, "ImportPosts")
+	defer func() { endSpan(span, err) }()
+
+	if err := ctx.Err(); err != nil {
+		return ImportResult{}, err
+	}
+	if s.readOnly {
+		return ImportResult{}, ErrReadOnly
+	}
+
+	rows, rowErrors, err := parseImportCSV(r)
+	if err != nil {
+		return ImportResult{}, err
+	}
+
+	valid := make([]PostCreateUpdate, 0, len(rows))
+	// seen holds the title and author of the rows accepted so far, so that a row
+	// repeating an earlier one is rejected just as a second CreatePost would be.
+	seen := make(map[[2]string]bool, len(rows))
+	for _, row := range rows {
+		data := s.preparePostData(row.data)
+		if err := validatePost(s.validate, data, data.PostStatus()); err != nil {
+			rowErrors = append(rowErrors, ImportRowError{Line: row.line, Error: importErrorMessage(err)})
+			continue
+		}
+		if s.checkDuplicates {
+			key := [2]string{data.Title, data.Author}
+			duplicate := seen[key]
+			if !duplicate {
+				duplicate, err = s.repo.ExistsByTitleAndAuthor(data.Title, data.Author)
+				if err != nil {
+					return ImportResult{}, err
+				}
+			}
+			if duplicate {
+				rowErrors = append(rowErrors, ImportRowError{Line: row.line, Error: ErrDuplicatePost.Error()})
+				continue
+			}
+			seen[key] = true
+		}
+		valid = append(valid, data)
+	}
+	slices.SortFunc(rowErrors, func(a, b ImportRowError) int {
+		return a.Line - b.Line
+	})
+
+	if rowErrors == nil {
+		rowErrors = []ImportRowError{}
+	}
+	result = ImportResult{CreatedIDs: []int{}, Errors: rowErrors}
+	if len(valid) == 0 || (atomic && len(rowErrors) > 0) {
+		return result, nil
+	}
+
+	created, err := s.repo.CreateMany(valid)
+	if err != nil {
+		return ImportResult{}, err
+	}
+	for _, post := range created {
+		result.CreatedIDs = append(result.CreatedIDs, post.ID)
+		s.publish(postChanged(EventPostCreated, post))
+	}
+	return result, nil
+}
+
+func (s *PostService) publish(event PostEvent) {
+	if s.publisher != nil {
+		s.publisher.Publish(event)
+	}
+}
+
+// preparePostData normalizes data and sanitizes its content ahead of validation,
+// so that content consisting only of unsafe markup fails the required rule.
+func (s *PostService) preparePostData(data PostCreateUpdate) PostCreateUpdate {
+	data = normalizePostData(data)
+	data.Content = strings.TrimSpace(s.sanitizer.Sanitize(data.Content))
+	return data
+}
+
+// normalizePostData trims the fields and collapses internal whitespace runs in
+// Title and Author so that visually identical posts are stored identically. Tags are
+// lowercased and de-duplicated.
+func normalizePostData(data PostCreateUpdate) PostCreateUpdate {
+	data.Title = strings.Join(strings.Fields(data.Title), " ")
+	data.Author = strings.Join(strings.Fields(data.Author), " ")
+	data.Content = strings.TrimSpace(data.Content)
+	data.Tags = normalizeTags(data.Tags)
+	data.ImageURL = strings.TrimSpace(data.ImageURL)
+	return data
 }
@@ -1,38 +1,134 @@
 package posts
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"github.com/microcosm-cc/bluemonday"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 )
 
 type MockRepository struct {
-	GetAllFn  func() ([]PostRead, error)
-	GetByIDFn func(id int) (PostRead, error)
-	CreateFn  func(data PostCreateUpdate) (PostRead, error)
-	UpdateFn  func(id int, data PostCreateUpdate) (PostRead, error)
-	DeleteFn  func(id int) error
+	GetAllFn                 func() ([]PostRead, error)
+	GetByIDFn                func(id int) (PostRead, error)
+	GetByIDsFn               func(ids []int) ([]PostRead, error)
+	CreateFn                 func(data PostCreateUpdate) (PostRead, error)
+	UpdateFn                 func(id int, data PostCreateUpdate) (PostRead, error)
+	DeleteFn                 func(id int) error
+	IncrementViewsFn         func(id int) (int, error)
+	CreateManyFn             func(data []PostCreateUpdate) ([]PostRead, error)
+	CountByAuthorFn          func() (map[string]int, error)
+	AuthorsFn                func() ([]string, error)
+	ExistsFn                 func(id int) (bool, error)
+	GetRecentFn              func(n int) ([]PostRead, error)
+	GetRandomFn              func() (PostRead, error)
+	NeighborsFn              func(id int) (*PostRead, *PostRead, error)
+	ExistsByTitleAndAuthorFn func(title, author string) (bool, error)
+	DeleteManyFn             func(ids []int) ([]int, error)
+	UpdateIfUnmodifiedFn     func(id int, data PostCreateUpdate, since time.Time) (PostRead, error)
+	UpdateIfMatchFn          func(id int, data PostCreateUpdate, etag string) (PostRead, error)
+	DeleteIfUnmodifiedFn     func(id int, since time.Time) error
+	ListFn                   func(params ListParams) ([]PostRead, int, error)
+	IterateFn                func(fn func(post PostRead) error) error
+	UpsertFn                 func(id int, data PostCreateUpdate) (PostRead, bool, error)
 }
 
-func (m *MockRepository) GetAll() ([]PostRead, error) {
+func (m *MockRepository) GetAll(ctx context.Context) ([]PostRead, error) {
 	return m.GetAllFn()
 }
 
+func (m *MockRepository) Iterate(ctx context.Context, fn func(post PostRead) error) error {
+	return m.IterateFn(fn)
+}
+
+func (m *MockRepository) List(ctx context.Context, params ListParams) ([]PostRead, int, error) {
+	return m.ListFn(params)
+}
+
 func (m *MockRepository) GetByID(id int) (PostRead, error) {
 	return m.GetByIDFn(id)
 }
 
+func (m *MockRepository) GetByIDs(ids []int) ([]PostRead, error) {
+	return m.GetByIDsFn(ids)
+}
+
+func (m *MockRepository) Exists(id int) (bool, error) {
+	return m.ExistsFn(id)
+}
+
 func (m *MockRepository) Create(data PostCreateUpdate) (PostRead, error) {
 	return m.CreateFn(data)
 }
 
+func (m *MockRepository) CreateMany(data []PostCreateUpdate) ([]PostRead, error) {
+	return m.CreateManyFn(data)
+}
+
+func (m *MockRepository) CountByAuthor(ctx context.Context) (map[string]int, error) {
+	return m.CountByAuthorFn()
+}
+
+func (m *MockRepository) Authors(ctx context.Context) ([]string, error) {
+	return m.AuthorsFn()
+}
+
+func (m *MockRepository) GetRecent(ctx context.Context, n int) ([]PostRead, error) {
+	return m.GetRecentFn(n)
+}
+
+func (m *MockRepository) GetRandom() (PostRead, error) {
+	return m.GetRandomFn()
+}
+
+func (m *MockRepository) Neighbors(ctx context.Context, id int) (*PostRead, *PostRead, error) {
+	return m.NeighborsFn(id)
+}
+
+func (m *MockRepository) ExistsByTitleAndAuthor(title, author string) (bool, error) {
+	return m.ExistsByTitleAndAuthorFn(title, author)
+}
+
 func (m *MockRepository) Update(id int, data PostCreateUpdate) (PostRead, error) {
 	return m.UpdateFn(id, data)
 }
 
+func (m *MockRepository) Upsert(id int, data PostCreateUpdate) (PostRead, bool, error) {
+	return m.UpsertFn(id, data)
+}
+
 func (m *MockRepository) Delete(id int) error {
 	return m.DeleteFn(id)
 }
 
+func (m *MockRepository) DeleteMany(ids []int) ([]int, error) {
+	return m.DeleteManyFn(ids)
+}
+
+func (m *MockRepository) UpdateIfUnmodified(id int, data PostCreateUpdate, since time.Time) (PostRead, error) {
+	return m.UpdateIfUnmodifiedFn(id, data, since)
+}
+
+func (m *MockRepository) UpdateIfMatch(id int, data PostCreateUpdate, etag string) (PostRead, error) {
+	return m.UpdateIfMatchFn(id, data, etag)
+}
+
+func (m *MockRepository) DeleteIfUnmodified(id int, since time.Time) error {
+	return m.DeleteIfUnmodifiedFn(id, since)
+}
+
+func (m *MockRepository) IncrementViews(id int) (int, error) {
+	return m.IncrementViewsFn(id)
+}
+
+func noDuplicates(title, author string) (bool, error) {
+	return false, nil
+}
+
 var testPostsData = []PostRead{
 	{ID: 1, Title: "Test Post 1", Content: "Content 1", Author: "Author 1"},
 	{ID: 2, Title: "Test Post 2", Content: "Content 2", Author: "Author 2"},
@@ -71,7 +167,7 @@ func TestServiceGetAllPosts(t *testing.T) {
 
 			service := NewPostService(mockRepo)
 
-			posts, err := service.GetAllPosts()
+			posts, err := service.GetAllPosts(context.Background())
 
 			if tc.expectedError && err == nil {
 				t.Error("Expected an error but got none")
@@ -103,6 +199,28 @@ func TestServiceGetAllPosts(t *testing.T) {
 	}
 }
 
+func TestServiceListPostsDelegatesToRepository(t *testing.T) {
+	var received ListParams
+	mockRepo := &MockRepository{
+		ListFn: func(params ListParams) ([]PostRead, int, error) {
+			received = params
+			return []PostRead{{ID: 3}}, 7, nil
+		},
+	}
+	service := NewPostService(mockRepo)
+
+	posts, total, err := service.ListPosts(context.Background(), ListParams{Limit: 1, Offset: 2})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if received != (ListParams{Limit: 1, Offset: 2}) {
+		t.Errorf("Expected params {Limit:1 Offset:2}, got %+v", received)
+	}
+	if len(posts) != 1 || posts[0].ID != 3 || total != 7 {
+		t.Errorf("Expected post 3 of 7, got %+v of %d", posts, total)
+	}
+}
+
 func TestServiceGetPostByID(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -157,7 +275,7 @@ func TestServiceGetPostByID(t *testing.T) {
 
 			service := NewPostService(mockRepo)
 
-			post, err := service.GetPostByID(tc.id)
+			post, err := service.GetPostByID(context.Background(), tc.id)
 
 			if tc.expectedError && err == nil {
 				t.Error("Expected an error but got none")
@@ -246,12 +364,13 @@ func TestServiceCreatePost(t *testing.T) {
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
 			mockRepo := &MockRepository{
-				CreateFn: tc.mockCreateFn,
+				CreateFn:                 tc.mockCreateFn,
+				ExistsByTitleAndAuthorFn: noDuplicates,
 			}
 
 			service := NewPostService(mockRepo)
 
-			post, err := service.CreatePost(tc.postData)
+			post, err := service.CreatePost(context.Background(), tc.postData)
 
 			if tc.expectedError && err == nil {
 				t.Error("Expected an error but got none")
@@ -292,7 +411,7 @@ func TestServiceUpdatePost(t *testing.T) {
 		name          string
 		id            int
 		postData      PostCreateUpdate
-		mockGetByIDFn func(id int) (PostRead, error)
+		mockExistsFn  func(id int) (bool, error)
 		mockUpdateFn  func(id int, data PostCreateUpdate) (PostRead, error)
 		expectedPost  *PostRead
 		expectedError bool
@@ -301,8 +420,8 @@ func TestServiceUpdatePost(t *testing.T) {
 			name:     "Success",
 			id:       1,
 			postData: validPostData,
-			mockGetByIDFn: func(id int) (PostRead, error) {
-				return testPostsData[0], nil
+			mockExistsFn: func(id int) (bool, error) {
+				return true, nil
 			},
 			mockUpdateFn: func(id int, data PostCreateUpdate) (PostRead, error) {
 				return PostRead{
@@ -324,8 +443,8 @@ func TestServiceUpdatePost(t *testing.T) {
 			name:     "Invalid ID",
 			id:       0,
 			postData: validPostData,
-			mockGetByIDFn: func(id int) (PostRead, error) {
-				return PostRead{}, nil
+			mockExistsFn: func(id int) (bool, error) {
+				return true, nil
 			},
 			mockUpdateFn: func(id int, data PostCreateUpdate) (PostRead, error) {
 				return PostRead{}, nil
@@ -340,8 +459,8 @@ func TestServiceUpdatePost(t *testing.T) {
 				Title:  "Updated Post",
 				Author: "Updated Author",
 			},
-			mockGetByIDFn: func(id int) (PostRead, error) {
-				return PostRead{}, nil
+			mockExistsFn: func(id int) (bool, error) {
+				return true, nil
 			},
 			mockUpdateFn: func(id int, data PostCreateUpdate) (PostRead, error) {
 				return PostRead{}, nil
@@ -353,8 +472,21 @@ func TestServiceUpdatePost(t *testing.T) {
 			name:     "Post Not Found",
 			id:       999,
 			postData: validPostData,
-			mockGetByIDFn: func(id int) (PostRead, error) {
-				return PostRead{}, ErrPostNotFound
+			mockExistsFn: func(id int) (bool, error) {
+				return false, nil
+			},
+			mockUpdateFn: func(id int, data PostCreateUpdate) (PostRead, error) {
+				return PostRead{}, nil
+			},
+			expectedPost:  nil,
+			expectedError: true,
+		},
+		{
+			name:     "Exists Error",
+			id:       1,
+			postData: validPostData,
+			mockExistsFn: func(id int) (bool, error) {
+				return false, errors.New("repository error")
 			},
 			mockUpdateFn: func(id int, data PostCreateUpdate) (PostRead, error) {
 				return PostRead{}, nil
@@ -366,8 +498,8 @@ func TestServiceUpdatePost(t *testing.T) {
 			name:     "Repository Error",
 			id:       1,
 			postData: validPostData,
-			mockGetByIDFn: func(id int) (PostRead, error) {
-				return testPostsData[0], nil
+			mockExistsFn: func(id int) (bool, error) {
+				return true, nil
 			},
 			mockUpdateFn: func(id int, data PostCreateUpdate) (PostRead, error) {
 				return PostRead{}, errors.New("repository error")
@@ -380,13 +512,13 @@ func TestServiceUpdatePost(t *testing.T) {
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
 			mockRepo := &MockRepository{
-				GetByIDFn: tc.mockGetByIDFn,
-				UpdateFn:  tc.mockUpdateFn,
+				ExistsFn: tc.mockExistsFn,
+				UpdateFn: tc.mockUpdateFn,
 			}
 
 			service := NewPostService(mockRepo)
 
-			post, err := service.UpdatePost(tc.id, tc.postData)
+			post, err := service.UpdatePost(context.Background(), tc.id, tc.postData)
 
 			if tc.expectedError && err == nil {
 				t.Error("Expected an error but got none")
@@ -416,6 +548,276 @@ func TestServiceUpdatePost(t *testing.T) {
 	}
 }
 
+func TestServiceUpsertPost(t *testing.T) {
+	data := PostCreateUpdate{Title: "Upserted Post", Content: "Upserted Content", Author: "Jane Doe"}
+
+	tests := []struct {
+		name            string
+		id              int
+		expectedCreated bool
+		expectedViews   int
+		expectedError   error
+	}{
+		{name: "Existing ID", id: 1, expectedCreated: false, expectedViews: 5},
+		{name: "New ID", id: 10, expectedCreated: true, expectedViews: 0},
+		{name: "Invalid ID", id: 0, expectedError: InvalidPostIDError},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := setupTestRepository()
+			repo.posts[1] = PostRead{ID: 1, Title: "Test Post 1", Content: "Test Content 1", Author: "Test Author 1", Views: 5}
+			service := NewPostService(repo)
+
+			post, created, err := service.UpsertPost(context.Background(), tc.id, data)
+			if tc.expectedError != nil {
+				if !errors.Is(err, tc.expectedError) {
+					t.Errorf("Expected error %v, got %v", tc.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if created != tc.expectedCreated {
+				t.Errorf("Expected created %v, got %v", tc.expectedCreated, created)
+			}
+			if post.ID != tc.id || post.Title != data.Title {
+				t.Errorf("Expected post %d titled %q, got %+v", tc.id, data.Title, post)
+			}
+			if post.Views != tc.expectedViews {
+				t.Errorf("Expected views %d, got %d", tc.expectedViews, post.Views)
+			}
+		})
+	}
+}
+
+func TestServiceUpsertPostValidates(t *testing.T) {
+	service := NewPostService(&MockRepository{})
+
+	_, _, err := service.UpsertPost(context.Background(), 1, PostCreateUpdate{Title: "", Content: "Content", Author: "Jane Doe"})
+	var validationError ValidationError
+	if !errors.As(err, &validationError) {
+		t.Errorf("Expected a ValidationError, got %v", err)
+	}
+}
+
+func TestServiceReadOnly(t *testing.T) {
+	// The mock has no functions set, so reaching the repository would panic.
+	service := NewPostService(&MockRepository{}, WithReadOnly(true))
+	ctx := context.Background()
+	data := PostCreateUpdate{Title: "Title", Content: "Content", Author: "Jane Doe"}
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{name: "Create", call: func() error { _, err := service.CreatePost(ctx, data); return err }},
+		{name: "Create Idempotent", call: func() error { _, err := service.CreatePostIdempotent(ctx, "key", data); return err }},
+		{name: "Update", call: func() error { _, err := service.UpdatePost(ctx, 1, data); return err }},
+		{name: "Update If Unmodified", call: func() error { _, err := service.UpdatePostIfUnmodified(ctx, 1, data, time.Now()); return err }},
+		{name: "Upsert", call: func() error { _, _, err := service.UpsertPost(ctx, 1, data); return err }},
+		{name: "Delete", call: func() error { return service.DeletePost(ctx, 1) }},
+		{name: "Delete If Unmodified", call: func() error { return service.DeletePostIfUnmodified(ctx, 1, time.Now()) }},
+		{name: "Delete Many", call: func() error { _, err := service.DeletePosts(ctx, []int{1}); return err }},
+		{name: "Patch Many", call: func() error { _, err := service.PatchPosts(ctx, []int{1}, PostPatch{}); return err }},
+		{name: "Import", call: func() error { _, err := service.ImportPosts(ctx, strings.NewReader(""), false); return err }},
+		{name: "Increment Views", call: func() error { _, err := service.IncrementViews(ctx, 1); return err }},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.call(); !errors.Is(err, ErrReadOnly) {
+				t.Errorf("Expected ErrReadOnly, got %v", err)
+			}
+		})
+	}
+
+	if err := service.ValidatePost(ctx, data); err != nil {
+		t.Errorf("Expected validation to remain available, got %v", err)
+	}
+}
+
+func TestServicePatchPost(t *testing.T) {
+	base := PostRead{ID: 1, Title: "Title", Content: "Content", Author: "Jane Doe", Status: StatusPublished}
+	title := "Patched"
+
+	t.Run("Retries Without If-Match", func(t *testing.T) {
+		attempts := 0
+		service := NewPostService(&MockRepository{
+			GetByIDFn: func(id int) (PostRead, error) {
+				return base, nil
+			},
+			UpdateIfMatchFn: func(id int, data PostCreateUpdate, etag string) (PostRead, error) {
+				attempts++
+				if etag != postETag(base) {
+					t.Errorf("Expected the ETag of the post the patch was applied to, got %s", etag)
+				}
+				if attempts == 1 {
+					return PostRead{}, ErrPreconditionFailed
+				}
+				return PostRead{ID: id, Title: data.Title, Content: data.Content, Author: data.Author}, nil
+			},
+		})
+
+		post, err := service.PatchPost(context.Background(), 1, PostPatch{Title: &title}, "")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if attempts != 2 {
+			t.Errorf("Expected 2 attempts, got %d", attempts)
+		}
+		if post.Title != "Patched" || post.Content != "Content" {
+			t.Errorf("Expected only the title to change, got %+v", post)
+		}
+	})
+
+	t.Run("No Retry With If-Match", func(t *testing.T) {
+		attempts := 0
+		service := NewPostService(&MockRepository{
+			GetByIDFn: func(id int) (PostRead, error) {
+				return base, nil
+			},
+			UpdateIfMatchFn: func(id int, data PostCreateUpdate, etag string) (PostRead, error) {
+				attempts++
+				return PostRead{}, ErrPreconditionFailed
+			},
+		})
+
+		_, err := service.PatchPost(context.Background(), 1, PostPatch{Title: &title}, postETag(base))
+		if !errors.Is(err, ErrPreconditionFailed) {
+			t.Errorf("Expected ErrPreconditionFailed, got %v", err)
+		}
+		if attempts != 1 {
+			t.Errorf("Expected 1 attempt, got %d", attempts)
+		}
+	})
+
+	t.Run("Validates Patched Post", func(t *testing.T) {
+		empty := ""
+		service := NewPostService(&MockRepository{
+			GetByIDFn: func(id int) (PostRead, error) {
+				return base, nil
+			},
+		})
+
+		_, err := service.PatchPost(context.Background(), 1, PostPatch{Title: &empty}, "")
+		var validationError ValidationError
+		if !errors.As(err, &validationError) {
+			t.Errorf("Expected a ValidationError, got %v", err)
+		}
+	})
+}
+
+func TestServicePatchPostsWithoutTransactor(t *testing.T) {
+	posts := map[int]PostRead{
+		1: {ID: 1, Title: "First", Content: "Content", Author: "Jane Doe", Status: StatusPublished},
+		2: {ID: 2, Title: "Second", Content: "", Author: "Jane Doe", Status: StatusDraft},
+	}
+	updates := 0
+	service := NewPostService(&MockRepository{
+		GetByIDFn: func(id int) (PostRead, error) {
+			post, ok := posts[id]
+			if !ok {
+				return PostRead{}, ErrPostNotFound
+			}
+			return post, nil
+		},
+		UpdateFn: func(id int, data PostCreateUpdate) (PostRead, error) {
+			updates++
+			return PostRead{ID: id, Title: data.Title, Content: data.Content, Author: data.Author, Status: data.Status}, nil
+		},
+	})
+
+	// Publishing post 2 fails because it has no content, so post 1 must not be written either.
+	published := StatusPublished
+	_, err := service.PatchPosts(context.Background(), []int{1, 2}, PostPatch{Status: &published})
+	var validationError ValidationError
+	if !errors.As(err, &validationError) {
+		t.Errorf("Expected a ValidationError, got %v", err)
+	}
+	if updates != 0 {
+		t.Errorf("Expected no post to be updated, got %d updates", updates)
+	}
+
+	result, err := service.PatchPosts(context.Background(), []int{1, 3}, PostPatch{Status: &published})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(result.Updated) != 1 || result.Updated[0].ID != 1 || !slices.Equal(result.NotFound, []int{3}) {
+		t.Errorf("Expected post 1 updated and post 3 not found, got %+v", result)
+	}
+}
+
+func TestServiceGetPostsByIDs(t *testing.T) {
+	tooMany := make([]int, maxBatchGetIDs+1)
+	for i := range tooMany {
+		tooMany[i] = i + 1
+	}
+
+	tests := []struct {
+		name           string
+		ids            []int
+		expectedLookup []int
+		expectedError  error
+	}{
+		{name: "Duplicates Looked Up Once", ids: []int{3, 1, 3, 2, 1}, expectedLookup: []int{3, 1, 2}},
+		{name: "Duplicates Do Not Count Towards Cap", ids: append(tooMany[:maxBatchGetIDs:maxBatchGetIDs], 1), expectedLookup: tooMany[:maxBatchGetIDs]},
+		{name: "Too Many", ids: tooMany, expectedError: ErrTooManyPostIDs},
+		{name: "No IDs", ids: nil, expectedError: ErrNoPostIDs},
+		{name: "Invalid ID", ids: []int{1, -1}, expectedError: InvalidPostIDError},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var lookup []int
+			service := NewPostService(&MockRepository{
+				GetByIDsFn: func(ids []int) ([]PostRead, error) {
+					lookup = ids
+					return []PostRead{}, nil
+				},
+			})
+
+			_, err := service.GetPostsByIDs(context.Background(), tc.ids)
+			if !errors.Is(err, tc.expectedError) {
+				t.Fatalf("Expected error %v, got %v", tc.expectedError, err)
+			}
+			if !slices.Equal(lookup, tc.expectedLookup) {
+				t.Errorf("Expected lookup of %v, got %v", tc.expectedLookup, lookup)
+			}
+		})
+	}
+}
+
+func TestServiceInvalidPostID(t *testing.T) {
+	service := NewPostService(&MockRepository{
+		GetByIDFn: func(id int) (PostRead, error) {
+			return PostRead{}, ErrPostNotFound
+		},
+		DeleteFn: func(id int) error {
+			return ErrPostNotFound
+		},
+	})
+
+	tests := []struct {
+		name string
+		call func(id int) error
+	}{
+		{name: "Get", call: func(id int) error { _, err := service.GetPostByID(context.Background(), id); return err }},
+		{name: "Delete", call: func(id int) error { return service.DeletePost(context.Background(), id) }},
+	}
+
+	for _, tc := range tests {
+		for _, id := range []int{0, -5} {
+			t.Run(fmt.Sprintf("%s %d", tc.name, id), func(t *testing.T) {
+				if err := tc.call(id); !errors.Is(err, InvalidPostIDError) {
+					t.Errorf("Expected InvalidPostIDError, got %v", err)
+				}
+			})
+		}
+	}
+}
+
 func TestServiceDeletePost(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -452,12 +854,15 @@ func TestServiceDeletePost(t *testing.T) {
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
 			mockRepo := &MockRepository{
+				ExistsFn: func(id int) (bool, error) {
+					return true, nil
+				},
 				DeleteFn: tc.mockDeleteFn,
 			}
 
 			service := NewPostService(mockRepo)
 
-			err := service.DeletePost(tc.id)
+			err := service.DeletePost(context.Background(), tc.id)
 
 			if tc.expectedError && err == nil {
 				t.Error("Expected an error but got none")
@@ -469,3 +874,487 @@ func TestServiceDeletePost(t *testing.T) {
 		})
 	}
 }
+
+func TestServiceIncrementViews(t *testing.T) {
+	tests := []struct {
+		name            string
+		id              int
+		mockIncrementFn func(id int) (int, error)
+		expectedViews   int
+		expectedError   error
+	}{
+		{
+			name: "Success",
+			id:   1,
+			mockIncrementFn: func(id int) (int, error) {
+				return 3, nil
+			},
+			expectedViews: 3,
+		},
+		{
+			name: "Invalid ID",
+			id:   0,
+			mockIncrementFn: func(id int) (int, error) {
+				return 0, nil
+			},
+			expectedError: InvalidPostIDError,
+		},
+		{
+			name: "Post Not Found",
+			id:   999,
+			mockIncrementFn: func(id int) (int, error) {
+				return 0, ErrPostNotFound
+			},
+			expectedError: ErrPostNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := &MockRepository{
+				IncrementViewsFn: tc.mockIncrementFn,
+			}
+
+			service := NewPostService(mockRepo)
+
+			views, err := service.IncrementViews(context.Background(), tc.id)
+
+			if !errors.Is(err, tc.expectedError) {
+				t.Errorf("Expected error %v, got %v", tc.expectedError, err)
+			}
+			if views != tc.expectedViews {
+				t.Errorf("Expected %d views, got %d", tc.expectedViews, views)
+			}
+		})
+	}
+}
+
+func TestServiceNormalizesPostData(t *testing.T) {
+	tests := []struct {
+		name            string
+		postData        PostCreateUpdate
+		expectedTitle   string
+		expectedContent string
+		expectedAuthor  string
+		expectedError   bool
+	}{
+		{
+			name: "Padded Fields",
+			postData: PostCreateUpdate{
+				Title:   "  New Post  ",
+				Content: "\n  New Content\n\n  Second line  \n",
+				Author:  " New Author ",
+			},
+			expectedTitle:   "New Post",
+			expectedContent: "New Content\n\n  Second line",
+			expectedAuthor:  "New Author",
+		},
+		{
+			name: "Doubly Spaced Title",
+			postData: PostCreateUpdate{
+				Title:   "New   Post \t Title",
+				Content: "New Content",
+				Author:  "New  Author",
+			},
+			expectedTitle:   "New Post Title",
+			expectedContent: "New Content",
+			expectedAuthor:  "New Author",
+		},
+		{
+			name: "Whitespace Only Title",
+			postData: PostCreateUpdate{
+				Title:   "   ",
+				Content: "New Content",
+				Author:  "New Author",
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var stored PostCreateUpdate
+			mockRepo := &MockRepository{
+				CreateFn: func(data PostCreateUpdate) (PostRead, error) {
+					stored = data
+					return PostRead{ID: 1, Title: data.Title, Content: data.Content, Author: data.Author}, nil
+				},
+				ExistsByTitleAndAuthorFn: noDuplicates,
+				ExistsFn: func(id int) (bool, error) {
+					return true, nil
+				},
+				UpdateFn: func(id int, data PostCreateUpdate) (PostRead, error) {
+					stored = data
+					return PostRead{ID: id, Title: data.Title, Content: data.Content, Author: data.Author}, nil
+				},
+			}
+
+			service := NewPostService(mockRepo)
+
+			for _, operation := range []string{"create", "update"} {
+				stored = PostCreateUpdate{}
+				var err error
+				if operation == "create" {
+					_, err = service.CreatePost(context.Background(), tc.postData)
+				} else {
+					_, err = service.UpdatePost(context.Background(), 1, tc.postData)
+				}
+
+				if tc.expectedError {
+					if err == nil {
+						t.Errorf("Expected an error on %s but got none", operation)
+					}
+					continue
+				}
+				if err != nil {
+					t.Fatalf("Expected no error on %s but got: %v", operation, err)
+				}
+
+				if stored.Title != tc.expectedTitle {
+					t.Errorf("Expected stored title %q on %s, got %q", tc.expectedTitle, operation, stored.Title)
+				}
+				if stored.Content != tc.expectedContent {
+					t.Errorf("Expected stored content %q on %s, got %q", tc.expectedContent, operation, stored.Content)
+				}
+				if stored.Author != tc.expectedAuthor {
+					t.Errorf("Expected stored author %q on %s, got %q", tc.expectedAuthor, operation, stored.Author)
+				}
+			}
+		})
+	}
+}
+
+func TestServiceStats(t *testing.T) {
+	mockRepo := &MockRepository{
+		CountByAuthorFn: func() (map[string]int, error) {
+			return map[string]int{"Bob": 2, "Alice": 2, "Carol": 5, "Dave": 1}, nil
+		},
+	}
+
+	service := NewPostService(mockRepo)
+
+	stats, err := service.Stats(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error but got: %v", err)
+	}
+
+	if stats.Total != 10 {
+		t.Errorf("Expected total 10, got %d", stats.Total)
+	}
+	expected := []AuthorStats{
+		{Author: "Carol", Count: 5},
+		{Author: "Alice", Count: 2},
+		{Author: "Bob", Count: 2},
+		{Author: "Dave", Count: 1},
+	}
+	if !slices.Equal(stats.Authors, expected) {
+		t.Errorf("Expected authors %v, got %v", expected, stats.Authors)
+	}
+}
+
+func TestServiceGetRecentPostsClampsN(t *testing.T) {
+	tests := []struct {
+		name      string
+		n         int
+		expectedN int
+	}{
+		{name: "Within Range", n: 5, expectedN: 5},
+		{name: "Excessive", n: 1000, expectedN: maxRecentPosts},
+		{name: "Zero", n: 0, expectedN: 1},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var requested int
+			mockRepo := &MockRepository{
+				GetRecentFn: func(n int) ([]PostRead, error) {
+					requested = n
+					return nil, nil
+				},
+			}
+			service := NewPostService(mockRepo)
+
+			if _, err := service.GetRecentPosts(context.Background(), tc.n); err != nil {
+				t.Fatalf("Expected no error but got: %v", err)
+			}
+			if requested != tc.expectedN {
+				t.Errorf("Expected repository to be asked for %d posts, got %d", tc.expectedN, requested)
+			}
+		})
+	}
+}
+
+func TestServiceCreatePostDuplicateCheck(t *testing.T) {
+	existing := PostRead{ID: 1, Title: "Hello World", Content: "Original content", Author: "Jane Doe"}
+
+	tests := []struct {
+		name          string
+		opts          []ServiceOption
+		postData      PostCreateUpdate
+		expectedError error
+	}{
+		{
+			name:          "Duplicate",
+			postData:      PostCreateUpdate{Title: "Hello World", Content: "Original content", Author: "Jane Doe"},
+			expectedError: ErrDuplicatePost,
+		},
+		{
+			name:          "Duplicate After Normalization",
+			postData:      PostCreateUpdate{Title: " hello   world ", Content: "Other content", Author: "JANE DOE"},
+			expectedError: ErrDuplicatePost,
+		},
+		{
+			name:     "Near Duplicate With Different Title",
+			postData: PostCreateUpdate{Title: "Hello World Again", Content: "Original content", Author: "Jane Doe"},
+		},
+		{
+			name:     "Duplicate With Check Disabled",
+			opts:     []ServiceOption{WithDuplicateCheck(false)},
+			postData: PostCreateUpdate{Title: "Hello World", Content: "Original content", Author: "Jane Doe"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &MapRepository{posts: map[int]PostRead{1: existing}, nextID: 2, now: time.Now}
+			service := NewPostService(repo, tc.opts...)
+
+			_, err := service.CreatePost(context.Background(), tc.postData)
+
+			if !errors.Is(err, tc.expectedError) {
+				t.Errorf("Expected error %v, got %v", tc.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestServiceCreateDraftWithEmptyContent(t *testing.T) {
+	tests := []struct {
+		name           string
+		postData       PostCreateUpdate
+		expectedStatus string
+		expectedError  bool
+	}{
+		{
+			name:           "Draft",
+			postData:       PostCreateUpdate{Title: "Draft Post", Author: "Jane Doe", Status: StatusDraft},
+			expectedStatus: StatusDraft,
+		},
+		{
+			name:          "Published",
+			postData:      PostCreateUpdate{Title: "Published Post", Author: "Jane Doe", Status: StatusPublished},
+			expectedError: true,
+		},
+		{
+			name:          "Status Defaults To Published",
+			postData:      PostCreateUpdate{Title: "Published Post", Author: "Jane Doe"},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &MapRepository{posts: make(map[int]PostRead), nextID: 1, now: time.Now}
+			service := NewPostService(repo)
+
+			post, err := service.CreatePost(context.Background(), tc.postData)
+
+			if tc.expectedError {
+				if err == nil {
+					t.Error("Expected an error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error but got: %v", err)
+			}
+			if post.Status != tc.expectedStatus {
+				t.Errorf("Expected status %s, got %s", tc.expectedStatus, post.Status)
+			}
+		})
+	}
+}
+
+func TestServiceValidationError(t *testing.T) {
+	service := NewPostService(&MockRepository{})
+
+	_, err := service.CreatePost(context.Background(), PostCreateUpdate{Author: "Jane Doe"})
+
+	var validationError ValidationError
+	if !errors.As(err, &validationError) {
+		t.Fatalf("Expected a ValidationError, got %v", err)
+	}
+	expected := ValidationError{
+		{Field: "Title", Rule: "required", Message: "Field validation for 'Title' failed on the 'required' tag"},
+		{Field: "Content", Rule: "required", Message: "Field validation for 'Content' failed on the 'required' tag"},
+	}
+	if !slices.Equal(validationError, expected) {
+		t.Errorf("Expected errors %v, got %v", expected, validationError)
+	}
+}
+
+func TestServiceSanitizesContent(t *testing.T) {
+	tests := []struct {
+		name            string
+		sanitizer       Sanitizer
+		content         string
+		expectedContent string
+		expectedError   bool
+	}{
+		{
+			name:            "Script Stripped",
+			content:         `Hello <script>alert("xss")</script>world`,
+			expectedContent: "Hello world",
+		},
+		{
+			name:            "Event Handler Stripped",
+			content:         `<a href="https://example.com" onclick="steal()">link</a>`,
+			expectedContent: `<a href="https://example.com" rel="nofollow">link</a>`,
+		},
+		{
+			name:            "Bold Kept",
+			content:         "Some <b>bold</b> text",
+			expectedContent: "Some <b>bold</b> text",
+		},
+		{
+			name:            "Strict Policy Strips Bold",
+			sanitizer:       bluemonday.StrictPolicy(),
+			content:         "Some <b>bold</b> text",
+			expectedContent: "Some bold text",
+		},
+		{
+			name:          "Only Script Fails Required",
+			content:       "<script>alert(1)</script>",
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var stored PostCreateUpdate
+			mockRepo := &MockRepository{
+				CreateFn: func(data PostCreateUpdate) (PostRead, error) {
+					stored = data
+					return PostRead{ID: 1, Title: data.Title, Content: data.Content, Author: data.Author}, nil
+				},
+				ExistsByTitleAndAuthorFn: noDuplicates,
+			}
+
+			var opts []ServiceOption
+			if tc.sanitizer != nil {
+				opts = append(opts, WithSanitizer(tc.sanitizer))
+			}
+			service := NewPostService(mockRepo, opts...)
+
+			_, err := service.CreatePost(context.Background(), PostCreateUpdate{
+				Title:   "New Post",
+				Content: tc.content,
+				Author:  "New Author",
+			})
+
+			if tc.expectedError {
+				if err == nil {
+					t.Error("Expected an error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error but got: %v", err)
+			}
+			if stored.Content != tc.expectedContent {
+				t.Errorf("Expected stored content %q, got %q", tc.expectedContent, stored.Content)
+			}
+		})
+	}
+}
+
+func TestServiceWithValidatorCustomRule(t *testing.T) {
+	v := NewValidator()
+	v.RegisterStructValidationCtx(func(ctx context.Context, sl validator.StructLevel) {
+		validatePostContent(ctx, sl)
+		data := sl.Current().Interface().(PostCreateUpdate)
+		if data.Title == strings.ToUpper(data.Title) {
+			sl.ReportError(data.Title, "Title", "Title", "no_shouting", "")
+		}
+	}, PostCreateUpdate{})
+
+	mockRepo := &MockRepository{
+		CreateFn: func(data PostCreateUpdate) (PostRead, error) {
+			return PostRead{ID: 1, Title: data.Title, Content: data.Content, Author: data.Author}, nil
+		},
+		ExistsByTitleAndAuthorFn: noDuplicates,
+	}
+	shouting := PostCreateUpdate{Title: "BREAKING NEWS", Content: "Content", Author: "Jane Doe"}
+
+	if _, err := NewPostService(mockRepo).CreatePost(context.Background(), shouting); err != nil {
+		t.Fatalf("Expected the default validator to accept the post, got %v", err)
+	}
+
+	service := NewPostService(mockRepo, WithValidator(v))
+	_, err := service.CreatePost(context.Background(), shouting)
+	var validationError ValidationError
+	if !errors.As(err, &validationError) {
+		t.Fatalf("Expected a validation error, got %v", err)
+	}
+	if len(validationError) != 1 || validationError[0].Rule != "no_shouting" {
+		t.Errorf("Expected a single no_shouting error, got %+v", validationError)
+	}
+
+	_, err = service.CreatePost(context.Background(), PostCreateUpdate{Title: "Breaking News", Author: "Jane Doe"})
+	if !errors.As(err, &validationError) || validationError[0].Field != "Content" {
+		t.Errorf("Expected the built-in content rule to still apply, got %v", err)
+	}
+}
+
+type recordingPublisher struct {
+	events []PostEvent
+}
+
+func (p *recordingPublisher) Publish(event PostEvent) {
+	p.events = append(p.events, event)
+}
+
+func TestServicePublishesEvents(t *testing.T) {
+	publisher := &recordingPublisher{}
+	service := NewPostService(setupTestRepository(), WithEventPublisher(publisher))
+	ctx := context.Background()
+	data := PostCreateUpdate{Title: "New Post", Content: "New Content", Author: "Jane Doe"}
+
+	created, err := service.CreatePost(ctx, data)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := service.UpdatePost(ctx, created.ID, data); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := service.UpdatePost(ctx, 99, data); !errors.Is(err, ErrPostNotFound) {
+		t.Fatalf("Expected ErrPostNotFound, got %v", err)
+	}
+	if _, err := service.DeletePosts(ctx, []int{created.ID, 99}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := service.DeletePost(ctx, 1); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := service.DeletePost(ctx, 99); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []PostEvent{
+		{Type: EventPostCreated, ID: created.ID},
+		{Type: EventPostUpdated, ID: created.ID},
+		{Type: EventPostDeleted, ID: created.ID},
+		{Type: EventPostDeleted, ID: 1},
+	}
+	if len(publisher.events) != len(expected) {
+		t.Fatalf("Expected %d events, got %+v", len(expected), publisher.events)
+	}
+	for i, event := range publisher.events {
+		if event.Type != expected[i].Type || event.ID != expected[i].ID {
+			t.Errorf("Expected event %d to be %s for post %d, got %+v", i, expected[i].Type, expected[i].ID, event)
+		}
+		if (event.Post == nil) != (event.Type == EventPostDeleted) {
+			t.Errorf("Expected only deleted events to omit the post, got %+v", event)
+		}
+	}
+}
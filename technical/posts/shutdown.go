@@ -0,0 +1,102 @@
+package posts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrDrainTimeout is returned by GracefulShutdown when requests were still running
+// once its timeout had passed.
+var ErrDrainTimeout = errors.New("requests still in flight after the shutdown timeout")
+
+// InFlightTracker counts the requests passing through its middleware that have not
+// finished yet. The zero value is ready to use.
+type InFlightTracker struct {
+	mutex  sync.Mutex
+	active int
+	// idle is closed when active drops to zero; it is nil while nothing has started.
+	idle chan struct{}
+}
+
+// Middleware counts each request from when it arrives until its handler returns.
+// Hijacked connections such as WebSockets outlive their handler and should not be
+// routed through it.
+func (t *InFlightTracker) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.start()
+		defer t.done()
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (t *InFlightTracker) start() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if t.active == 0 {
+		t.idle = make(chan struct{})
+	}
+	t.active++
+}
+
+func (t *InFlightTracker) done() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.active--
+	if t.active == 0 {
+		close(t.idle)
+	}
+}
+
+// Count returns the number of requests in flight.
+func (t *InFlightTracker) Count() int {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.active
+}
+
+// Wait blocks until no request is in flight or ctx is done, returning ctx's error in
+// the latter case.
+func (t *InFlightTracker) Wait(ctx context.Context) error {
+	t.mutex.Lock()
+	if t.active == 0 {
+		t.mutex.Unlock()
+		return nil
+	}
+	idle := t.idle
+	t.mutex.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// GracefulShutdown stops server from accepting connections and waits up to timeout for
+// the requests tracked by inFlight to finish. Should some still be running by then, it
+// closes the server, cutting them off, and returns ErrDrainTimeout.
+func GracefulShutdown(server *http.Server, inFlight *InFlightTracker, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	err := server.Shutdown(ctx)
+	if err == nil {
+		err = inFlight.Wait(ctx)
+	}
+	if err == nil {
+		return nil
+	}
+	remaining := inFlight.Count()
+	if closeErr := server.Close(); closeErr != nil {
+		return closeErr
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("%w: %d left", ErrDrainTimeout, remaining)
+	}
+	return err
+}
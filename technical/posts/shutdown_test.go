@@ -0,0 +1,84 @@
+package posts
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestGracefulShutdown(t *testing.T) {
+	tests := []struct {
+		name           string
+		requestTime    time.Duration
+		timeout        time.Duration
+		expectedErr    error
+		expectedServed bool
+	}{
+		{name: "Drains Within Timeout", requestTime: 100 * time.Millisecond, timeout: 2 * time.Second, expectedServed: true},
+		{name: "Forces Close After Timeout", requestTime: 2 * time.Second, timeout: 50 * time.Millisecond, expectedErr: ErrDrainTimeout},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var inFlight InFlightTracker
+			release := make(chan struct{})
+			server := &http.Server{Handler: inFlight.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				select {
+				case <-time.After(tc.requestTime):
+				case <-release:
+				}
+				w.WriteHeader(http.StatusOK)
+			}))}
+			defer close(release)
+
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatalf("Failed to listen: %v", err)
+			}
+			go server.Serve(listener)
+
+			served := make(chan error, 1)
+			go func() {
+				resp, err := http.Get("http://" + listener.Addr().String())
+				if err == nil {
+					resp.Body.Close()
+				}
+				served <- err
+			}()
+			for inFlight.Count() == 0 {
+				time.Sleep(time.Millisecond)
+			}
+
+			start := time.Now()
+			err = GracefulShutdown(server, &inFlight, tc.timeout)
+			elapsed := time.Since(start)
+
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("Expected error %v, got %v", tc.expectedErr, err)
+			}
+			if elapsed > tc.timeout+time.Second {
+				t.Errorf("Expected shutdown to finish within its timeout, took %v", elapsed)
+			}
+			if tc.expectedServed {
+				if elapsed < tc.requestTime/2 {
+					t.Errorf("Expected shutdown to wait for the request, returned after %v", elapsed)
+				}
+				if err := <-served; err != nil {
+					t.Errorf("Expected the request to complete, got %v", err)
+				}
+				if count := inFlight.Count(); count != 0 {
+					t.Errorf("Expected no request in flight, got %d", count)
+				}
+			}
+		})
+	}
+}
+
+func TestInFlightTrackerWaitWhenIdle(t *testing.T) {
+	var inFlight InFlightTracker
+	if err := inFlight.Wait(t.Context()); err != nil {
+		t.Errorf("Expected an idle tracker not to wait, got %v", err)
+	}
+}
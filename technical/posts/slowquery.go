@@ -0,0 +1,168 @@
+package posts
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// slowQueryRepository passes every call through to repo and logs those that take
+// longer than threshold, with the method name and its arguments. Post data is logged
+// by title and author only, so that content does not end up in the logs.
+type slowQueryRepository struct {
+	repo      Repository
+	threshold time.Duration
+	logger    *slog.Logger
+}
+
+// slowQueryTransactor is the slowQueryRepository of a repository that is a Transactor,
+// so that wrapping it keeps transactions available.
+type slowQueryTransactor struct {
+	*slowQueryRepository
+}
+
+// NewSlowQueryRepository wraps repo so that calls taking longer than threshold are
+// logged to logger as warnings; a nil logger means the default logger. The result is
+// a Transactor if repo is one. Other optional interfaces, such as Reloader and
+// io.Closer, are not passed on and should be used on repo itself.
+func NewSlowQueryRepository(repo Repository, threshold time.Duration, logger *slog.Logger) Repository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	logged := &slowQueryRepository{repo: repo, threshold: threshold, logger: logger}
+	if _, ok := repo.(Transactor); ok {
+		return slowQueryTransactor{logged}
+	}
+	return logged
+}
+
+// observe logs the call to method that started at start if it took too long. args are
+// alternating keys and values as taken by slog.
+func (r *slowQueryRepository) observe(method string, start time.Time, args ...any) {
+	elapsed := time.Since(start)
+	if elapsed <= r.threshold {
+		return
+	}
+	attrs := append([]any{"method", method, "duration", elapsed, "threshold", r.threshold}, args...)
+	r.logger.Warn("slow repository call", attrs...)
+}
+
+func (r *slowQueryRepository) GetAll(ctx context.Context) ([]PostRead, error) {
+	defer r.observe("GetAll", time.Now())
+	return r.repo.GetAll(ctx)
+}
+
+func (r *slowQueryRepository) List(ctx context.Context, params ListParams) ([]PostRead, int, error) {
+	defer r.observe("List", time.Now(), "params", params)
+	return r.repo.List(ctx, params)
+}
+
+// Iterate is timed as a whole, including the time spent in fn.
+func (r *slowQueryRepository) Iterate(ctx context.Context, fn func(post PostRead) error) error {
+	defer r.observe("Iterate", time.Now())
+	return r.repo.Iterate(ctx, fn)
+}
+
+func (r *slowQueryRepository) GetByID(id int) (PostRead, error) {
+	defer r.observe("GetByID", time.Now(), "id", id)
+	return r.repo.GetByID(id)
+}
+
+func (r *slowQueryRepository) GetByIDs(ids []int) ([]PostRead, error) {
+	defer r.observe("GetByIDs", time.Now(), "count", len(ids))
+	return r.repo.GetByIDs(ids)
+}
+
+func (r *slowQueryRepository) Exists(id int) (bool, error) {
+	defer r.observe("Exists", time.Now(), "id", id)
+	return r.repo.Exists(id)
+}
+
+func (r *slowQueryRepository) Create(data PostCreateUpdate) (PostRead, error) {
+	defer r.observe("Create", time.Now(), "title", data.Title, "author", data.Author)
+	return r.repo.Create(data)
+}
+
+func (r *slowQueryRepository) CreateMany(data []PostCreateUpdate) ([]PostRead, error) {
+	defer r.observe("CreateMany", time.Now(), "count", len(data))
+	return r.repo.CreateMany(data)
+}
+
+func (r *slowQueryRepository) Update(id int, data PostCreateUpdate) (PostRead, error) {
+	defer r.observe("Update", time.Now(), "id", id, "title", data.Title, "author", data.Author)
+	return r.repo.Update(id, data)
+}
+
+func (r *slowQueryRepository) Upsert(id int, data PostCreateUpdate) (PostRead, bool, error) {
+	defer r.observe("Upsert", time.Now(), "id", id, "title", data.Title, "author", data.Author)
+	return r.repo.Upsert(id, data)
+}
+
+func (r *slowQueryRepository) Delete(id int) error {
+	defer r.observe("Delete", time.Now(), "id", id)
+	return r.repo.Delete(id)
+}
+
+func (r *slowQueryRepository) DeleteMany(ids []int) ([]int, error) {
+	defer r.observe("DeleteMany", time.Now(), "ids", ids)
+	return r.repo.DeleteMany(ids)
+}
+
+func (r *slowQueryRepository) UpdateIfUnmodified(id int, data PostCreateUpdate, since time.Time) (PostRead, error) {
+	defer r.observe("UpdateIfUnmodified", time.Now(), "id", id, "title", data.Title, "author", data.Author, "since", since)
+	return r.repo.UpdateIfUnmodified(id, data, since)
+}
+
+func (r *slowQueryRepository) UpdateIfMatch(id int, data PostCreateUpdate, etag string) (PostRead, error) {
+	defer r.observe("UpdateIfMatch", time.Now(), "id", id, "title", data.Title, "author", data.Author, "etag", etag)
+	return r.repo.UpdateIfMatch(id, data, etag)
+}
+
+func (r *slowQueryRepository) DeleteIfUnmodified(id int, since time.Time) error {
+	defer r.observe("DeleteIfUnmodified", time.Now(), "id", id, "since", since)
+	return r.repo.DeleteIfUnmodified(id, since)
+}
+
+func (r *slowQueryRepository) IncrementViews(id int) (int, error) {
+	defer r.observe("IncrementViews", time.Now(), "id", id)
+	return r.repo.IncrementViews(id)
+}
+
+func (r *slowQueryRepository) CountByAuthor(ctx context.Context) (map[string]int, error) {
+	defer r.observe("CountByAuthor", time.Now())
+	return r.repo.CountByAuthor(ctx)
+}
+
+func (r *slowQueryRepository) Authors(ctx context.Context) ([]string, error) {
+	defer r.observe("Authors", time.Now())
+	return r.repo.Authors(ctx)
+}
+
+func (r *slowQueryRepository) GetRecent(ctx context.Context, n int) ([]PostRead, error) {
+	defer r.observe("GetRecent", time.Now(), "n", n)
+	return r.repo.GetRecent(ctx, n)
+}
+
+func (r *slowQueryRepository) GetRandom() (PostRead, error) {
+	defer r.observe("GetRandom", time.Now())
+	return r.repo.GetRandom()
+}
+
+func (r *slowQueryRepository) Neighbors(ctx context.Context, id int) (*PostRead, *PostRead, error) {
+	defer r.observe("Neighbors", time.Now(), "id", id)
+	return r.repo.Neighbors(ctx, id)
+}
+
+func (r *slowQueryRepository) ExistsByTitleAndAuthor(title, author string) (bool, error) {
+	defer r.observe("ExistsByTitleAndAuthor", time.Now(), "title", title, "author", author)
+	return r.repo.ExistsByTitleAndAuthor(title, author)
+}
+
+// WithTx is timed as a whole, including the time spent in fn; the calls fn makes on
+// the transaction are timed and logged as well.
+func (r slowQueryTransactor) WithTx(fn func(tx Repository) error) error {
+	defer r.observe("WithTx", time.Now())
+	return r.repo.(Transactor).WithTx(func(tx Repository) error {
+		return fn(&slowQueryRepository{repo: tx, threshold: r.threshold, logger: r.logger})
+	})
+}
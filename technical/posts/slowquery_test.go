@@ -0,0 +1,81 @@
+package posts
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSlowQueryRepository(t *testing.T) {
+	tests := []struct {
+		name        string
+		delay       time.Duration
+		expectedLog bool
+	}{
+		{name: "Slow Call", delay: 30 * time.Millisecond, expectedLog: true},
+		{name: "Fast Call", delay: 0, expectedLog: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := slog.New(slog.NewTextHandler(&buf, nil))
+			repo := NewSlowQueryRepository(&MockRepository{
+				GetByIDFn: func(id int) (PostRead, error) {
+					time.Sleep(tc.delay)
+					return PostRead{ID: id, Title: "Title"}, nil
+				},
+			}, 10*time.Millisecond, logger)
+
+			post, err := repo.GetByID(7)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if post.ID != 7 || post.Title != "Title" {
+				t.Errorf("Expected the underlying post, got %+v", post)
+			}
+
+			logged := buf.String()
+			if !tc.expectedLog {
+				if logged != "" {
+					t.Errorf("Expected nothing to be logged, got %q", logged)
+				}
+				return
+			}
+			for _, expected := range []string{"level=WARN", `msg="slow repository call"`, "method=GetByID", "id=7", "threshold=10ms"} {
+				if !strings.Contains(logged, expected) {
+					t.Errorf("Expected the log to contain %q, got %q", expected, logged)
+				}
+			}
+		})
+	}
+}
+
+func TestSlowQueryRepositoryKeepsTransactions(t *testing.T) {
+	if _, ok := NewSlowQueryRepository(&MockRepository{}, time.Second, nil).(Transactor); ok {
+		t.Error("Expected a repository without transactions not to become a Transactor")
+	}
+
+	var buf bytes.Buffer
+	repo := NewSlowQueryRepository(setupTestRepository(), 0, slog.New(slog.NewTextHandler(&buf, nil)))
+	transactor, ok := repo.(Transactor)
+	if !ok {
+		t.Fatal("Expected the wrapped MapRepository to remain a Transactor")
+	}
+
+	err := transactor.WithTx(func(tx Repository) error {
+		_, err := tx.Update(1, PostCreateUpdate{Title: "Updated", Content: "Updated Content", Author: "Jane Doe"})
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if post, _ := repo.GetByID(1); post.Title != "Updated" {
+		t.Errorf("Expected the transaction to be committed, got %+v", post)
+	}
+	if logged := buf.String(); !strings.Contains(logged, "method=Update") || !strings.Contains(logged, "method=WithTx") {
+		t.Errorf("Expected calls inside the transaction to be timed too, got %q", logged)
+	}
+}
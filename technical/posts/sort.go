@@ -0,0 +1,88 @@
+package posts
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// SortField names a field posts can be ordered by.
+type SortField string
+
+const (
+	SortByID        SortField = "id"
+	SortByTitle     SortField = "title"
+	SortByAuthor    SortField = "author"
+	SortByCreatedAt SortField = "createdAt"
+	SortByUpdatedAt SortField = "updatedAt"
+)
+
+var sortFields = []SortField{SortByID, SortByTitle, SortByAuthor, SortByCreatedAt, SortByUpdatedAt}
+
+// PostSort orders posts by Field, descending if Desc is set. Posts that tie on Field
+// are ordered by ascending ID, so the order never depends on how they are stored. The
+// zero PostSort orders by ascending ID.
+type PostSort struct {
+	Field SortField
+	Desc  bool
+}
+
+// ParsePostSort parses a sort such as "author" or "-createdAt", where a leading "-"
+// means descending. An empty string is the zero PostSort.
+func ParsePostSort(raw string) (PostSort, error) {
+	field, desc := strings.CutPrefix(raw, "-")
+	if raw == "" {
+		return PostSort{}, nil
+	}
+	for _, known := range sortFields {
+		if SortField(field) == known {
+			return PostSort{Field: known, Desc: desc}, nil
+		}
+	}
+	return PostSort{}, fmt.Errorf("sort must be one of id, title, author, createdAt or updatedAt, optionally prefixed with -, got %q", raw)
+}
+
+// byID reports whether s is the ascending ID order posts are stored and paged in.
+func (s PostSort) byID() bool {
+	return (s.Field == "" || s.Field == SortByID) && !s.Desc
+}
+
+// compare orders a and b by s.Field alone, ascending.
+func (s PostSort) compare(a, b PostRead) int {
+	switch s.Field {
+	case SortByTitle:
+		return strings.Compare(a.Title, b.Title)
+	case SortByAuthor:
+		return strings.Compare(a.Author, b.Author)
+	case SortByCreatedAt:
+		return a.CreatedAt.Compare(b.CreatedAt)
+	case SortByUpdatedAt:
+		return a.UpdatedAt.Compare(b.UpdatedAt)
+	default:
+		return a.ID - b.ID
+	}
+}
+
+// sortPosts orders posts in place as s says.
+func sortPosts(posts []PostRead, s PostSort) {
+	sort.SliceStable(posts, func(i, j int) bool {
+		c := s.compare(posts[i], posts[j])
+		if s.Desc {
+			c = -c
+		}
+		if c != 0 {
+			return c < 0
+		}
+		return posts[i].ID < posts[j].ID
+	})
+}
+
+// parseSort reads the sort query parameter, returning fallback when it is absent.
+func parseSort(r *http.Request, fallback PostSort) (PostSort, error) {
+	raw := r.URL.Query().Get("sort")
+	if raw == "" {
+		return fallback, nil
+	}
+	return ParsePostSort(raw)
+}
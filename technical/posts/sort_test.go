@@ -0,0 +1,133 @@
+package posts
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestParsePostSort(t *testing.T) {
+	tests := []struct {
+		name          string
+		raw           string
+		expected      PostSort
+		expectedError bool
+	}{
+		{name: "Empty", raw: "", expected: PostSort{}},
+		{name: "Ascending", raw: "author", expected: PostSort{Field: SortByAuthor}},
+		{name: "Descending", raw: "-createdAt", expected: PostSort{Field: SortByCreatedAt, Desc: true}},
+		{name: "Unknown Field", raw: "views", expectedError: true},
+		{name: "Only Minus", raw: "-", expectedError: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParsePostSort(tc.raw)
+			if (err != nil) != tc.expectedError {
+				t.Fatalf("Expected error %v, got %v", tc.expectedError, err)
+			}
+			if got != tc.expected {
+				t.Errorf("Expected %+v, got %+v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestSortPostsTieBreak(t *testing.T) {
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name        string
+		order       PostSort
+		expectedIDs []int
+	}{
+		{name: "Author Ties By ID", order: PostSort{Field: SortByAuthor}, expectedIDs: []int{2, 4, 5, 1, 3}},
+		{name: "Author Descending Ties Still By Ascending ID", order: PostSort{Field: SortByAuthor, Desc: true}, expectedIDs: []int{1, 3, 2, 4, 5}},
+		{name: "Newest First", order: PostSort{Field: SortByCreatedAt, Desc: true}, expectedIDs: []int{3, 5, 1, 2, 4}},
+		{name: "Zero Is ID Order", order: PostSort{}, expectedIDs: []int{1, 2, 3, 4, 5}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Listed out of ID order so that the tie-breaker, not the input order, decides.
+			posts := []PostRead{
+				{ID: 5, Author: "Alice", CreatedAt: day.Add(2 * time.Hour)},
+				{ID: 3, Author: "Bob", CreatedAt: day.Add(2 * time.Hour)},
+				{ID: 4, Author: "Alice", CreatedAt: day},
+				{ID: 1, Author: "Bob", CreatedAt: day.Add(time.Hour)},
+				{ID: 2, Author: "Alice", CreatedAt: day},
+			}
+
+			sortPosts(posts, tc.order)
+
+			for i, post := range posts {
+				if post.ID != tc.expectedIDs[i] {
+					t.Fatalf("Expected IDs %v, got post %d at position %d", tc.expectedIDs, post.ID, i)
+				}
+			}
+		})
+	}
+}
+
+func TestGetAllPostsSort(t *testing.T) {
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	repo := &MapRepository{posts: map[int]PostRead{
+		1: {ID: 1, Title: "A", Author: "Bob", CreatedAt: day},
+		2: {ID: 2, Title: "B", Author: "Alice", CreatedAt: day.Add(2 * time.Hour)},
+		3: {ID: 3, Title: "C", Author: "Bob", CreatedAt: day.Add(time.Hour)},
+	}, mutex: sync.RWMutex{}, nextID: 4, now: time.Now}
+
+	tests := []struct {
+		name           string
+		opts           []HandlerOption
+		url            string
+		expectedStatus int
+		expectedIDs    []int
+	}{
+		{name: "Default Is ID Order", url: "/posts", expectedStatus: http.StatusOK, expectedIDs: []int{1, 2, 3}},
+		{name: "Configured Default", opts: []HandlerOption{WithDefaultSort(PostSort{Field: SortByCreatedAt, Desc: true})}, url: "/posts", expectedStatus: http.StatusOK, expectedIDs: []int{2, 3, 1}},
+		{name: "Parameter Overrides Default", opts: []HandlerOption{WithDefaultSort(PostSort{Field: SortByCreatedAt, Desc: true})}, url: "/posts?sort=id", expectedStatus: http.StatusOK, expectedIDs: []int{1, 2, 3}},
+		{name: "Author Ties By ID", url: "/posts?sort=-author", expectedStatus: http.StatusOK, expectedIDs: []int{1, 3, 2}},
+		{name: "Sorted Before Paging", url: "/posts?sort=-createdAt&limit=2&offset=1", expectedStatus: http.StatusOK, expectedIDs: []int{3, 1}},
+		{name: "With Date Range", url: "/posts?sort=-createdAt&createdAfter=2024-01-01T00:30:00Z", expectedStatus: http.StatusOK, expectedIDs: []int{2, 3}},
+		{name: "Unknown Sort", url: "/posts?sort=views", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			NewHandler(NewPostService(repo), tc.opts...).RegisterRoutes(mux)
+
+			rr := httptest.NewRecorder()
+			mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tc.url, nil))
+
+			if rr.Code != tc.expectedStatus {
+				t.Fatalf("Expected status %d, got %d", tc.expectedStatus, rr.Code)
+			}
+			if tc.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var posts []PostRead
+			if err := json.Unmarshal(rr.Body.Bytes(), &posts); err != nil {
+				var page struct {
+					Data []PostRead `json:"data"`
+				}
+				if err := json.Unmarshal(rr.Body.Bytes(), &page); err != nil {
+					t.Fatalf("Failed to decode response: %v", err)
+				}
+				posts = page.Data
+			}
+			if len(posts) != len(tc.expectedIDs) {
+				t.Fatalf("Expected IDs %v, got %+v", tc.expectedIDs, posts)
+			}
+			for i, post := range posts {
+				if post.ID != tc.expectedIDs[i] {
+					t.Errorf("Expected IDs %v, got post %d at position %d", tc.expectedIDs, post.ID, i)
+				}
+			}
+		})
+	}
+}
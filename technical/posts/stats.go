@@ -0,0 +1,48 @@
+package posts
+
+import (
+	"net/http"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// wantsContentStats reports whether the client asked for WordCount and CharCount via ?stats=true.
+func wantsContentStats(r *http.Request) bool {
+	return r.URL.Query().Get("stats") == "true"
+}
+
+// countWords counts the runs of content separated by Unicode white space that contain
+// at least one letter or digit, so that a stray dash or emoji is not a word. Scripts
+// written without spaces between words count as one word per run.
+func countWords(content string) int {
+	words := 0
+	for _, field := range strings.Fields(content) {
+		if strings.IndexFunc(field, func(r rune) bool { return unicode.IsLetter(r) || unicode.IsNumber(r) }) >= 0 {
+			words++
+		}
+	}
+	return words
+}
+
+// withContentStats fills WordCount and CharCount from the content, counting characters
+// as runes rather than bytes.
+func withContentStats(post PostRead) PostRead {
+	words := countWords(post.Content)
+	chars := utf8.RuneCountInString(post.Content)
+	post.WordCount = &words
+	post.CharCount = &chars
+	return post
+}
+
+// withContentStatsAll fills the content stats on a copy of posts when the request asks for them.
+func withContentStatsAll(r *http.Request, posts []PostRead) []PostRead {
+	if !wantsContentStats(r) {
+		return posts
+	}
+	counted := make([]PostRead, len(posts))
+	for i, post := range posts {
+		counted[i] = withContentStats(post)
+	}
+	return counted
+}
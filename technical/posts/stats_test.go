@@ -0,0 +1,98 @@
+package posts
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWithContentStats(t *testing.T) {
+	tests := []struct {
+		name          string
+		content       string
+		expectedWords int
+		expectedChars int
+	}{
+		{name: "Empty", content: "", expectedWords: 0, expectedChars: 0},
+		{name: "Multiple Words", content: "Hello, brave new world", expectedWords: 4, expectedChars: 22},
+		{name: "Repeated White Space", content: "  Hello \t\n  world  ", expectedWords: 2, expectedChars: 19},
+		{name: "Multibyte", content: "Привет, мир! Ça va?", expectedWords: 4, expectedChars: 19},
+		{name: "Non-Breaking And Ideographic Spaces", content: "naïve café　日本語", expectedWords: 3, expectedChars: 14},
+		{name: "Punctuation Only Fields", content: "wait — what ... 42", expectedWords: 3, expectedChars: 18},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			post := withContentStats(PostRead{Content: tc.content})
+
+			if post.WordCount == nil || *post.WordCount != tc.expectedWords {
+				t.Errorf("Expected %d words, got %v", tc.expectedWords, post.WordCount)
+			}
+			if post.CharCount == nil || *post.CharCount != tc.expectedChars {
+				t.Errorf("Expected %d characters, got %v", tc.expectedChars, post.CharCount)
+			}
+		})
+	}
+}
+
+func TestContentStatsQuery(t *testing.T) {
+	repo := setupTestRepository()
+	repo.Create(PostCreateUpdate{Title: "Multibyte", Content: "Größe  und   Gewicht — ✓", Author: "Author"})
+	mux := http.NewServeMux()
+	NewHandler(NewPostService(repo)).RegisterRoutes(mux)
+
+	tests := []struct {
+		name          string
+		url           string
+		expectedStats bool
+		expectedWords int
+		expectedChars int
+	}{
+		{name: "Post Without Stats", url: "/posts/3"},
+		{name: "Post With Stats", url: "/posts/3?stats=true", expectedStats: true, expectedWords: 3, expectedChars: 24},
+		{name: "Excerpt Keeps Full Stats", url: "/posts/3?stats=true&excerpt=5", expectedStats: true, expectedWords: 3, expectedChars: 24},
+		{name: "Collection With Stats", url: "/posts?stats=true", expectedStats: true, expectedWords: 3, expectedChars: 24},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tc.url, nil))
+			if rr.Code != http.StatusOK {
+				t.Fatalf("Expected status %d, got %d", http.StatusOK, rr.Code)
+			}
+
+			var post PostRead
+			if tc.url == "/posts?stats=true" {
+				var posts []PostRead
+				if err := json.Unmarshal(rr.Body.Bytes(), &posts); err != nil {
+					t.Fatalf("Failed to unmarshal response: %v", err)
+				}
+				for _, p := range posts {
+					if p.WordCount == nil || p.CharCount == nil {
+						t.Errorf("Expected stats on post %d", p.ID)
+					}
+					if p.ID == 3 {
+						post = p
+					}
+				}
+			} else if err := json.Unmarshal(rr.Body.Bytes(), &post); err != nil {
+				t.Fatalf("Failed to unmarshal response: %v", err)
+			}
+
+			if !tc.expectedStats {
+				if post.WordCount != nil || post.CharCount != nil {
+					t.Errorf("Expected no stats, got %v words and %v characters", post.WordCount, post.CharCount)
+				}
+				return
+			}
+			if post.WordCount == nil || *post.WordCount != tc.expectedWords {
+				t.Errorf("Expected %d words, got %v", tc.expectedWords, post.WordCount)
+			}
+			if post.CharCount == nil || *post.CharCount != tc.expectedChars {
+				t.Errorf("Expected %d characters, got %v", tc.expectedChars, post.CharCount)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package posts
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+const stringIDsKey contextKey = "stringIDs"
+
+// stringIDPost is a PostRead whose ID is encoded as a JSON string, for clients such as
+// JavaScript that lose precision on large integers. Declaring ID first keeps it the
+// first key of the object and hides the numeric ID of the embedded post.
+type stringIDPost struct {
+	ID string `json:"id"`
+	PostRead
+}
+
+type stringIDSummary struct {
+	ID string `json:"id"`
+	PostSummary
+}
+
+type stringIDViews struct {
+	ID string `json:"id"`
+	PostViews
+}
+
+type stringIDNeighbors struct {
+	Prev *stringIDPost `json:"prev"`
+	Next *stringIDPost `json:"next"`
+}
+
+type stringIDBulkPatchResult struct {
+	Updated  []stringIDPost `json:"updated"`
+	NotFound []int          `json:"not_found"`
+}
+
+// wantsStringIDs reports whether the routes serving r encode post IDs as strings.
+func wantsStringIDs(r *http.Request) bool {
+	if r == nil {
+		return false
+	}
+	enabled, _ := r.Context().Value(stringIDsKey).(bool)
+	return enabled
+}
+
+func newStringIDPost(post PostRead) stringIDPost {
+	return stringIDPost{ID: strconv.Itoa(post.ID), PostRead: post}
+}
+
+func newStringIDPosts(posts []PostRead) []stringIDPost {
+	converted := make([]stringIDPost, len(posts))
+	for i, post := range posts {
+		converted[i] = newStringIDPost(post)
+	}
+	return converted
+}
+
+func newStringIDPostRef(post *PostRead) *stringIDPost {
+	if post == nil {
+		return nil
+	}
+	converted := newStringIDPost(*post)
+	return &converted
+}
+
+// stringIDProjection replaces the id of a post projected with ?fields= by its string form.
+func stringIDProjection(projected map[string]interface{}) map[string]interface{} {
+	if id, ok := projected["id"].(json.Number); ok {
+		projected["id"] = id.String()
+	}
+	return projected
+}
+
+// withStringIDs returns data with the IDs of the posts in it encoded as strings. Data
+// holding no posts, such as errors or lists of bare IDs, is returned unchanged.
+func withStringIDs(data interface{}) interface{} {
+	switch data := data.(type) {
+	case PostRead:
+		return newStringIDPost(data)
+	case []PostRead:
+		return newStringIDPosts(data)
+	case PostSummary:
+		return stringIDSummary{ID: strconv.Itoa(data.ID), PostSummary: data}
+	case []PostSummary:
+		converted := make([]stringIDSummary, len(data))
+		for i, summary := range data {
+			converted[i] = stringIDSummary{ID: strconv.Itoa(summary.ID), PostSummary: summary}
+		}
+		return converted
+	case map[string]interface{}:
+		return stringIDProjection(data)
+	case []map[string]interface{}:
+		for _, projected := range data {
+			stringIDProjection(projected)
+		}
+		return data
+	case PostViews:
+		return stringIDViews{ID: strconv.Itoa(data.ID), PostViews: data}
+	case PostNeighbors:
+		return stringIDNeighbors{Prev: newStringIDPostRef(data.Prev), Next: newStringIDPostRef(data.Next)}
+	case BulkPatchResult:
+		return stringIDBulkPatchResult{Updated: newStringIDPosts(data.Updated), NotFound: data.NotFound}
+	case PostList:
+		return PostList{Posts: withStringIDs(data.Posts)}
+	case PostPage:
+		data.Data = withStringIDs(data.Data)
+		return data
+	}
+	return data
+}
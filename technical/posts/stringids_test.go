@@ -0,0 +1,177 @@
+package posts
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestStringIDs(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		// id extracts the ID of the first post from the decoded response.
+		id func(body interface{}) interface{}
+	}{
+		{
+			name: "Single Post",
+			path: "/posts/1",
+			id:   func(body interface{}) interface{} { return body.(map[string]interface{})["id"] },
+		},
+		{
+			name: "Projected Post",
+			path: "/posts/1?fields=id,title",
+			id:   func(body interface{}) interface{} { return body.(map[string]interface{})["id"] },
+		},
+		{
+			name: "List",
+			path: "/posts",
+			id:   func(body interface{}) interface{} { return body.([]interface{})[0].(map[string]interface{})["id"] },
+		},
+		{
+			name: "Summaries",
+			path: "/posts?view=summary",
+			id:   func(body interface{}) interface{} { return body.([]interface{})[0].(map[string]interface{})["id"] },
+		},
+		{
+			name: "Envelope",
+			path: "/posts?envelope=true",
+			id: func(body interface{}) interface{} {
+				return body.(map[string]interface{})["posts"].([]interface{})[0].(map[string]interface{})["id"]
+			},
+		},
+		{
+			name: "Page",
+			path: "/posts?limit=1",
+			id: func(body interface{}) interface{} {
+				return body.(map[string]interface{})["data"].([]interface{})[0].(map[string]interface{})["id"]
+			},
+		},
+		{
+			name: "Neighbors",
+			path: "/posts/2/neighbors",
+			id: func(body interface{}) interface{} {
+				return body.(map[string]interface{})["prev"].(map[string]interface{})["id"]
+			},
+		},
+		{
+			name:   "Views",
+			method: http.MethodPost,
+			path:   "/posts/1/view",
+			id:     func(body interface{}) interface{} { return body.(map[string]interface{})["id"] },
+		},
+	}
+
+	for _, tc := range tests {
+		for _, stringIDs := range []bool{false, true} {
+			name := tc.name + " Numeric"
+			var expected interface{} = float64(1)
+			if stringIDs {
+				name = tc.name + " String"
+				expected = "1"
+			}
+			t.Run(name, func(t *testing.T) {
+				mux := http.NewServeMux()
+				NewHandler(NewPostService(setupTestRepository()), WithStringIDs(stringIDs)).RegisterRoutes(mux)
+
+				method := tc.method
+				if method == "" {
+					method = http.MethodGet
+				}
+				req := httptest.NewRequest(method, tc.path, nil)
+				rr := httptest.NewRecorder()
+				mux.ServeHTTP(rr, req)
+
+				if rr.Code != http.StatusOK {
+					t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+				}
+				var body interface{}
+				if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+					t.Fatalf("Failed to decode response: %v", err)
+				}
+				if id := tc.id(body); id != expected {
+					t.Errorf("Expected id %#v, got %#v", expected, id)
+				}
+			})
+		}
+	}
+}
+
+func TestStringIDsProjectionKeepsLargeIDs(t *testing.T) {
+	const id = 1<<53 + 1
+	repo, err := NewMapRepositoryWithPosts([]PostRead{{ID: id, Title: "Title", Content: "Content", Author: "Author"}})
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		stringIDs    bool
+		expectedBody string
+	}{
+		{name: "Numeric", stringIDs: false, expectedBody: `{"id":9007199254740993}`},
+		{name: "String", stringIDs: true, expectedBody: `{"id":"9007199254740993"}`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			NewHandler(NewPostService(repo), WithStringIDs(tc.stringIDs)).RegisterRoutes(mux)
+
+			req := httptest.NewRequest(http.MethodGet, "/posts/9007199254740993?fields=id", nil)
+			rr := httptest.NewRecorder()
+			mux.ServeHTTP(rr, req)
+
+			if rr.Code != http.StatusOK {
+				t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+			}
+			if body := strings.TrimSpace(rr.Body.String()); body != tc.expectedBody {
+				t.Errorf("Expected body %s, got %s", tc.expectedBody, body)
+			}
+		})
+	}
+}
+
+func TestStringIDsKeepFieldOrder(t *testing.T) {
+	mux := http.NewServeMux()
+	NewHandler(NewPostService(setupTestRepository()), WithStringIDs(true)).RegisterRoutes(mux)
+
+	req := httptest.NewRequest(http.MethodGet, "/posts/1", nil)
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+
+	if body := rr.Body.String(); !strings.HasPrefix(body, `{"id":"1","title":"Test Post 1",`) {
+		t.Errorf("Expected the string id to come first, got %s", body)
+	}
+	if count := strings.Count(rr.Body.String(), `"id"`); count != 1 {
+		t.Errorf("Expected a single id field, got %d", count)
+	}
+}
+
+func TestStringIDsExportNDJSON(t *testing.T) {
+	mux := http.NewServeMux()
+	NewHandler(NewPostService(setupTestRepository()), WithStringIDs(true)).RegisterRoutes(mux)
+
+	req := httptest.NewRequest(http.MethodGet, "/posts/export?format=ndjson", nil)
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+
+	lines := strings.Split(strings.TrimSpace(rr.Body.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %d: %s", len(lines), rr.Body.String())
+	}
+	for i, line := range lines {
+		var post struct {
+			ID json.RawMessage `json:"id"`
+		}
+		if err := json.Unmarshal([]byte(line), &post); err != nil {
+			t.Fatalf("Failed to decode line %d: %v", i+1, err)
+		}
+		if expected := `"` + string(rune('1'+i)) + `"`; string(post.ID) != expected {
+			t.Errorf("Expected id %s on line %d, got %s", expected, i+1, post.ID)
+		}
+	}
+}
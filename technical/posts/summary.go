@@ -0,0 +1,144 @@
+package posts
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// summaryExcerptLength is the number of runes of content a PostSummary keeps.
+const summaryExcerptLength = 200
+
+var (
+	errUnknownView    = errors.New("view must be summary or full")
+	errInvalidExcerpt = errors.New("excerpt must be a positive integer")
+	errUnknownExcerpt = errors.New("excerptMode must be chars or sentence")
+)
+
+// excerptMode selects where an excerpt of post content is cut.
+type excerptMode int
+
+const (
+	// excerptChars cuts content at exactly the requested number of runes.
+	excerptChars excerptMode = iota
+	// excerptSentence cuts content at the last sentence end within the requested number
+	// of runes, or at the last word boundary when there is none.
+	excerptSentence
+)
+
+// PostSummary is the shortened form of a post returned by GET /posts?view=summary.
+type PostSummary struct {
+	ID        int       `json:"id"`
+	Title     string    `json:"title"`
+	Author    string    `json:"author"`
+	Excerpt   string    `json:"excerpt"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// NewPostSummary returns the summary of post with its content cut to summaryExcerptLength runes.
+func NewPostSummary(post PostRead) PostSummary {
+	return PostSummary{
+		ID:        post.ID,
+		Title:     post.Title,
+		Author:    post.Author,
+		Excerpt:   excerpt(post.Content, summaryExcerptLength),
+		CreatedAt: post.CreatedAt,
+		UpdatedAt: post.UpdatedAt,
+	}
+}
+
+// excerpt returns the first n runes of s followed by an ellipsis, or s itself when it
+// has no more than n runes.
+func excerpt(s string, n int) string {
+	if truncated, ok := truncateRunes(s, n); ok {
+		return truncated + "…"
+	}
+	return s
+}
+
+// truncateRunes returns the first n runes of s and whether anything was cut off.
+func truncateRunes(s string, n int) (string, bool) {
+	if utf8.RuneCountInString(s) <= n {
+		return s, false
+	}
+	i := 0
+	for range n {
+		_, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+	}
+	return s[:i], true
+}
+
+// truncateSentence returns s cut to at most n runes and whether anything was cut off.
+// A cut content ends at its last '.', '!' or '?' within the limit; without one it ends
+// at the last whole word, and a single word longer than the limit is cut at n runes.
+func truncateSentence(s string, n int) (string, bool) {
+	truncated, ok := truncateRunes(s, n)
+	if !ok {
+		return s, false
+	}
+	if i := strings.LastIndexAny(truncated, ".!?"); i >= 0 {
+		return truncated[:i+1], true
+	}
+	// The limit falls between two words, so nothing has to be dropped.
+	if next, _ := utf8.DecodeRuneInString(s[len(truncated):]); unicode.IsSpace(next) {
+		return strings.TrimRightFunc(truncated, unicode.IsSpace), true
+	}
+	if i := strings.LastIndexFunc(truncated, unicode.IsSpace); i >= 0 {
+		return strings.TrimRightFunc(truncated[:i], unicode.IsSpace), true
+	}
+	return truncated, true
+}
+
+// truncate cuts s to at most n runes as mode says and reports whether anything was cut off.
+func (mode excerptMode) truncate(s string, n int) (string, bool) {
+	if mode == excerptSentence {
+		return truncateSentence(s, n)
+	}
+	return truncateRunes(s, n)
+}
+
+// parseExcerptMode returns the mode the excerptMode query parameter asks for, which is
+// excerptChars when it is absent.
+func parseExcerptMode(r *http.Request) (excerptMode, error) {
+	switch r.URL.Query().Get("excerptMode") {
+	case "", "chars":
+		return excerptChars, nil
+	case "sentence":
+		return excerptSentence, nil
+	default:
+		return excerptChars, errUnknownExcerpt
+	}
+}
+
+// parseExcerpt returns the number of runes the excerpt query parameter limits content
+// to, or 0 when it is absent.
+func parseExcerpt(r *http.Request) (int, error) {
+	raw := r.URL.Query().Get("excerpt")
+	if raw == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 1 {
+		return 0, errInvalidExcerpt
+	}
+	return n, nil
+}
+
+// wantsSummary reports whether the view query parameter asks for summaries, rejecting
+// values other than summary and full.
+func wantsSummary(r *http.Request) (bool, error) {
+	switch r.URL.Query().Get("view") {
+	case "", "full":
+		return false, nil
+	case "summary":
+		return true, nil
+	default:
+		return false, errUnknownView
+	}
+}
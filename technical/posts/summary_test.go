@@ -0,0 +1,199 @@
+package posts
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestExcerpt(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		n        int
+		expected string
+	}{
+		{name: "Short Content", content: "Hello", n: 10, expected: "Hello"},
+		{name: "Exact Length", content: "Hello", n: 5, expected: "Hello"},
+		{name: "Truncated", content: "Hello, world", n: 5, expected: "Hello…"},
+		{name: "Multibyte Runes", content: "Привет, мир", n: 6, expected: "Привет…"},
+		{name: "Empty", content: "", n: 5, expected: ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := excerpt(tc.content, tc.n)
+			if got != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, got)
+			}
+			if !utf8.ValidString(got) {
+				t.Errorf("Expected valid UTF-8, got %q", got)
+			}
+		})
+	}
+}
+
+func TestTruncateSentence(t *testing.T) {
+	tests := []struct {
+		name              string
+		content           string
+		n                 int
+		expected          string
+		expectedTruncated bool
+	}{
+		{name: "Mid Sentence", content: "First one. Second one! Third one goes on", n: 28, expected: "First one. Second one!", expectedTruncated: true},
+		{name: "Question Mark", content: "Is it done? Not quite yet", n: 15, expected: "Is it done?", expectedTruncated: true},
+		{name: "No Terminator", content: "no sentence ends here at all", n: 14, expected: "no sentence", expectedTruncated: true},
+		{name: "Limit At Word End", content: "no sentence ends here", n: 11, expected: "no sentence", expectedTruncated: true},
+		{name: "Single Long Word", content: "Supercalifragilistic", n: 5, expected: "Super", expectedTruncated: true},
+		{name: "Multibyte Runes", content: "Привет мир. Как дела", n: 15, expected: "Привет мир.", expectedTruncated: true},
+		{name: "Short Content", content: "Short. Text", n: 50, expected: "Short. Text"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, truncated := truncateSentence(tc.content, tc.n)
+			if got != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, got)
+			}
+			if truncated != tc.expectedTruncated {
+				t.Errorf("Expected truncated %v, got %v", tc.expectedTruncated, truncated)
+			}
+			if utf8.RuneCountInString(got) > tc.n {
+				t.Errorf("Expected at most %d runes, got %d", tc.n, utf8.RuneCountInString(got))
+			}
+		})
+	}
+}
+
+func TestNewPostSummary(t *testing.T) {
+	post := PostRead{ID: 1, Title: "Title", Author: "Author", Content: strings.Repeat("é", summaryExcerptLength+50)}
+
+	summary := NewPostSummary(post)
+
+	if got := utf8.RuneCountInString(summary.Excerpt); got != summaryExcerptLength+1 {
+		t.Errorf("Expected %d runes including the ellipsis, got %d", summaryExcerptLength+1, got)
+	}
+	if !strings.HasSuffix(summary.Excerpt, "…") {
+		t.Errorf("Expected the excerpt to end with an ellipsis, got %q", summary.Excerpt)
+	}
+	if summary.ID != post.ID || summary.Title != post.Title || summary.Author != post.Author {
+		t.Errorf("Expected ID, title and author to be copied, got %+v", summary)
+	}
+}
+
+func TestGetAllPostsSummaryView(t *testing.T) {
+	mux := http.NewServeMux()
+	NewHandler(NewPostService(setupTestRepository())).RegisterRoutes(mux)
+
+	tests := []struct {
+		name           string
+		url            string
+		expectedStatus int
+		expectedField  string
+	}{
+		{name: "Summary", url: "/posts?view=summary", expectedStatus: http.StatusOK, expectedField: "excerpt"},
+		{name: "Summary Page", url: "/posts?view=summary&limit=1", expectedStatus: http.StatusOK, expectedField: "excerpt"},
+		{name: "Full", url: "/posts?view=full", expectedStatus: http.StatusOK, expectedField: "content"},
+		{name: "Unknown View", url: "/posts?view=compact", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tc.url, nil))
+
+			if rr.Code != tc.expectedStatus {
+				t.Fatalf("Expected status %d, got %d", tc.expectedStatus, rr.Code)
+			}
+			if tc.expectedField == "" {
+				return
+			}
+
+			var posts []map[string]any
+			if strings.Contains(tc.url, "limit") {
+				var page struct {
+					Data []map[string]any `json:"data"`
+				}
+				if err := json.Unmarshal(rr.Body.Bytes(), &page); err != nil {
+					t.Fatalf("Expected a page, got %s", rr.Body.String())
+				}
+				posts = page.Data
+			} else if err := json.Unmarshal(rr.Body.Bytes(), &posts); err != nil {
+				t.Fatalf("Expected an array, got %s", rr.Body.String())
+			}
+
+			if len(posts) == 0 {
+				t.Fatal("Expected at least one post")
+			}
+			for _, post := range posts {
+				if _, ok := post[tc.expectedField]; !ok {
+					t.Errorf("Expected field %q, got %v", tc.expectedField, post)
+				}
+			}
+		})
+	}
+}
+
+func TestGetPostByIDExcerpt(t *testing.T) {
+	repo := setupTestRepository()
+	long, _ := repo.Create(PostCreateUpdate{Title: "Long", Content: "Ünïcödé content that goes on", Author: "Author"})
+	short, _ := repo.Create(PostCreateUpdate{Title: "Short", Content: "Brief", Author: "Author"})
+	prose, _ := repo.Create(PostCreateUpdate{Title: "Prose", Content: "It starts here. Then it keeps going", Author: "Author"})
+	mux := http.NewServeMux()
+	NewHandler(NewPostService(repo)).RegisterRoutes(mux)
+
+	tests := []struct {
+		name              string
+		url               string
+		expectedStatus    int
+		expectedContent   string
+		expectedTruncated bool
+	}{
+		{name: "Longer Than Limit", url: fmt.Sprintf("/posts/%d?excerpt=7", long.ID), expectedStatus: http.StatusOK, expectedContent: "Ünïcödé", expectedTruncated: true},
+		{name: "Shorter Than Limit", url: fmt.Sprintf("/posts/%d?excerpt=200", short.ID), expectedStatus: http.StatusOK, expectedContent: "Brief"},
+		{name: "Exactly The Limit", url: fmt.Sprintf("/posts/%d?excerpt=5", short.ID), expectedStatus: http.StatusOK, expectedContent: "Brief"},
+		{name: "No Excerpt", url: fmt.Sprintf("/posts/%d", long.ID), expectedStatus: http.StatusOK, expectedContent: long.Content},
+		{name: "Zero", url: fmt.Sprintf("/posts/%d?excerpt=0", long.ID), expectedStatus: http.StatusBadRequest},
+		{name: "Negative", url: fmt.Sprintf("/posts/%d?excerpt=-3", long.ID), expectedStatus: http.StatusBadRequest},
+		{name: "Not A Number", url: fmt.Sprintf("/posts/%d?excerpt=short", long.ID), expectedStatus: http.StatusBadRequest},
+		{name: "Sentence Mode", url: fmt.Sprintf("/posts/%d?excerpt=25&excerptMode=sentence", prose.ID), expectedStatus: http.StatusOK, expectedContent: "It starts here.", expectedTruncated: true},
+		{name: "Sentence Mode Without Terminator", url: fmt.Sprintf("/posts/%d?excerpt=10&excerptMode=sentence", long.ID), expectedStatus: http.StatusOK, expectedContent: "Ünïcödé", expectedTruncated: true},
+		{name: "Chars Mode", url: fmt.Sprintf("/posts/%d?excerpt=25&excerptMode=chars", prose.ID), expectedStatus: http.StatusOK, expectedContent: "It starts here. Then it k", expectedTruncated: true},
+		{name: "Unknown Mode", url: fmt.Sprintf("/posts/%d?excerpt=25&excerptMode=words", prose.ID), expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tc.url, nil))
+
+			if rr.Code != tc.expectedStatus {
+				t.Fatalf("Expected status %d, got %d", tc.expectedStatus, rr.Code)
+			}
+			if tc.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var post PostRead
+			if err := json.Unmarshal(rr.Body.Bytes(), &post); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if post.Content != tc.expectedContent {
+				t.Errorf("Expected content %q, got %q", tc.expectedContent, post.Content)
+			}
+			if post.Truncated != tc.expectedTruncated {
+				t.Errorf("Expected truncated %v, got %v", tc.expectedTruncated, post.Truncated)
+			}
+		})
+	}
+
+	stored, _ := repo.GetByID(long.ID)
+	if stored.Content != long.Content {
+		t.Errorf("Expected the stored content to be unchanged, got %q", stored.Content)
+	}
+}
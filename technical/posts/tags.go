@@ -0,0 +1,52 @@
+package posts
+
+import (
+	"github.com/go-playground/validator/v10"
+	"strings"
+)
+
+const (
+	maxTags         = 10
+	tagMinLength    = 1
+	tagMaxLength    = 30
+	tagCharsMessage = "lowercase letters, digits or hyphens"
+)
+
+// normalizeTags trims and lowercases tags and drops empty and repeated ones, keeping
+// the order in which they first appear.
+func normalizeTags(tags []string) []string {
+	if tags == nil {
+		return nil
+	}
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized
+}
+
+// validateTagCount limits the number of tags on a post.
+func validateTagCount(fl validator.FieldLevel) bool {
+	return fl.Field().Len() <= maxTags
+}
+
+// validateTag accepts tags of lowercase ASCII letters, digits and hyphens that fit the
+// allowed length range.
+func validateTag(fl validator.FieldLevel) bool {
+	tag := fl.Field().String()
+	if len(tag) < tagMinLength || len(tag) > tagMaxLength {
+		return false
+	}
+	for _, r := range tag {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,102 @@
+package posts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	tests := []struct {
+		name     string
+		tags     []string
+		expected []string
+	}{
+		{name: "No Tags", tags: nil, expected: nil},
+		{name: "Lowercased", tags: []string{"Go", "HTTP"}, expected: []string{"go", "http"}},
+		{name: "Duplicates Collapsed", tags: []string{"go", "Go", " GO ", "api"}, expected: []string{"go", "api"}},
+		{name: "Blank Tags Dropped", tags: []string{"go", "", "  "}, expected: []string{"go"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := normalizeTags(tc.tags)
+			if !slices.Equal(got, tc.expected) {
+				t.Errorf("Expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestTagValidation(t *testing.T) {
+	manyTags := make([]string, maxTags+1)
+	for i := range manyTags {
+		manyTags[i] = fmt.Sprintf("tag-%d", i)
+	}
+
+	tests := []struct {
+		name          string
+		tags          []string
+		expectedRule  string
+		expectedField string
+	}{
+		{name: "Valid Tags", tags: []string{"go", "web-api", "http2"}},
+		{name: "Maximum Tags", tags: manyTags[:maxTags]},
+		{name: "Too Many Tags", tags: manyTags, expectedRule: "tag_count", expectedField: "Tags"},
+		{name: "Over-Long Tag", tags: []string{"go", strings.Repeat("a", tagMaxLength+1)}, expectedRule: "tag", expectedField: "Tags[1]"},
+		{name: "Invalid Characters", tags: []string{"c++"}, expectedRule: "tag", expectedField: "Tags[0]"},
+		{name: "Non-ASCII Letters", tags: []string{"café"}, expectedRule: "tag", expectedField: "Tags[0]"},
+		{name: "Duplicates Within Limit", tags: append(slices.Clone(manyTags[:maxTags]), "TAG-0", "Tag-1")},
+	}
+
+	service := NewPostService(setupTestRepository())
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := service.ValidatePost(context.Background(), PostCreateUpdate{Title: "Title", Content: "Content", Author: "Author", Tags: tc.tags})
+
+			if tc.expectedRule == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+
+			var validationErr ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("Expected a ValidationError, got %v", err)
+			}
+			if validationErr[0].Rule != tc.expectedRule || validationErr[0].Field != tc.expectedField {
+				t.Errorf("Expected rule %s on %s, got %s on %s", tc.expectedRule, tc.expectedField, validationErr[0].Rule, validationErr[0].Field)
+			}
+			if validationErr[0].Message == "" || strings.Contains(validationErr[0].Message, "failed on the") {
+				t.Errorf("Expected a specific message, got %q", validationErr[0].Message)
+			}
+		})
+	}
+}
+
+func TestCreatePostStoresNormalizedTags(t *testing.T) {
+	service := NewPostService(setupTestRepository())
+
+	post, err := service.CreatePost(context.Background(), PostCreateUpdate{
+		Title:   "Tagged",
+		Content: "Content",
+		Author:  "Author",
+		Tags:    []string{"Go", "go", "API"},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []string{"go", "api"}
+	if !slices.Equal(post.Tags, expected) {
+		t.Errorf("Expected tags %v, got %v", expected, post.Tags)
+	}
+	stored, _ := service.GetPostByID(context.Background(), post.ID)
+	if !slices.Equal(stored.Tags, expected) {
+		t.Errorf("Expected stored tags %v, got %v", expected, stored.Tags)
+	}
+}
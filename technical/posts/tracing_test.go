@@ -0,0 +1,81 @@
+package posts
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestTracingGetPostByID(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	mux := http.NewServeMux()
+	NewHandler(NewPostService(setupTestRepository(), WithTracerProvider(tp))).RegisterRoutes(mux)
+	handler := TracingMiddleware(tp, "")(mux)
+
+	req := httptest.NewRequest(http.MethodGet, "/posts/1", nil)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("Expected 2 spans, got %d", len(spans))
+	}
+
+	serviceSpan, serverSpan := spans[0], spans[1]
+	if serverSpan.SpanKind != trace.SpanKindServer {
+		t.Errorf("Expected server span kind, got %v", serverSpan.SpanKind)
+	}
+	if serverSpan.Name != "GET /posts/{id}" {
+		t.Errorf("Expected span name GET /posts/{id}, got %s", serverSpan.Name)
+	}
+	if !slices.Contains(serverSpan.Attributes, attribute.String("url.path", "/posts/1")) {
+		t.Errorf("Expected url.path attribute on server span, got %v", serverSpan.Attributes)
+	}
+	if serviceSpan.Name != "PostService.GetPostByID" {
+		t.Errorf("Expected span name PostService.GetPostByID, got %s", serviceSpan.Name)
+	}
+	if serviceSpan.Parent.SpanID() != serverSpan.SpanContext.SpanID() {
+		t.Error("Expected service span to be a child of the server span")
+	}
+
+	found := false
+	for _, attr := range serviceSpan.Attributes {
+		if attr == attribute.Int("post.id", 1) {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected post.id attribute on service span, got %v", serviceSpan.Attributes)
+	}
+}
+
+func TestTracingRecordsServiceError(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	mux := http.NewServeMux()
+	NewHandler(NewPostService(setupTestRepository(), WithTracerProvider(tp))).RegisterRoutes(mux)
+	handler := TracingMiddleware(tp, "")(mux)
+
+	req := httptest.NewRequest(http.MethodGet, "/posts/999", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := exporter.GetSpans()
+	if len(spans) == 0 {
+		t.Fatal("Expected spans to be recorded")
+	}
+	if spans[0].Status.Code.String() != "Error" {
+		t.Errorf("Expected error status on service span, got %v", spans[0].Status.Code)
+	}
+}
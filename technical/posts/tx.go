@@ -0,0 +1,53 @@
+package posts
+
+import (
+	"maps"
+)
+
+// Transactor is implemented by repositories that can apply a sequence of operations
+// atomically. WithTx runs fn against a transactional view of the repository: if fn
+// returns nil every change it made is committed, otherwise none of them is applied
+// and its error is returned. fn must use only the Repository it is given.
+// MapRepository implements it; RedisRepository does not.
+type Transactor interface {
+	WithTx(fn func(tx Repository) error) error
+}
+
+// WithTx runs fn against a copy of the posts while holding the write lock, so that
+// readers see either none or all of its changes. On success the copy replaces the
+// posts, its mutations are appended to the log and its update audits are passed on
+// to the audit sink; should appending to the log fail, nothing is committed.
+func (r *MapRepository) WithTx(fn func(tx Repository) error) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.checkSnapshotEdits()
+	audits := &MemoryAuditSink{}
+	tx := &MapRepository{
+		posts:    maps.Clone(r.posts),
+		nextID:   r.nextID,
+		now:      r.now,
+		maxPosts: r.maxPosts,
+		audit:    audits,
+		ids:      r.ids,
+		fileIDs:  r.fileIDs,
+		inTx:     true,
+	}
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	if len(tx.txRecords) > 0 {
+		if err := r.appendToLog(tx.txRecords...); err != nil {
+			return err
+		}
+	}
+	r.posts = tx.posts
+	r.nextID = tx.nextID
+	if r.audit != nil {
+		for _, audit := range audits.Audits() {
+			r.audit.Record(audit)
+		}
+	}
+	return nil
+}
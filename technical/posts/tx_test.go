@@ -0,0 +1,146 @@
+package posts
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestMapRepositoryWithTxRollsBack(t *testing.T) {
+	errAbort := errors.New("abort")
+	data := PostCreateUpdate{Title: "Title", Content: "Content", Author: "Author"}
+
+	tests := []struct {
+		name string
+		fn   func(tx Repository) error
+	}{
+		{
+			name: "Error After Writes",
+			fn: func(tx Repository) error {
+				if _, err := tx.Create(data); err != nil {
+					return err
+				}
+				if _, err := tx.Update(1, data); err != nil {
+					return err
+				}
+				if err := tx.Delete(2); err != nil {
+					return err
+				}
+				return errAbort
+			},
+		},
+		{
+			name: "Failing Operation",
+			fn: func(tx Repository) error {
+				if _, err := tx.Create(data); err != nil {
+					return err
+				}
+				_, err := tx.Update(99, data)
+				return err
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := setupTestRepository()
+			audits := &MemoryAuditSink{}
+			repo.SetAuditSink(audits)
+			before, _ := repo.GetAll(context.Background())
+
+			if err := repo.WithTx(tc.fn); !errors.Is(err, errAbort) && !errors.Is(err, ErrPostNotFound) {
+				t.Fatalf("Expected the closure's error, got %v", err)
+			}
+
+			after, _ := repo.GetAll(context.Background())
+			if !reflect.DeepEqual(postsByID(before), postsByID(after)) {
+				t.Errorf("Expected the posts to be unchanged, got %+v", after)
+			}
+			if len(audits.Audits()) != 0 {
+				t.Errorf("Expected no audits for a rolled back update, got %+v", audits.Audits())
+			}
+			created, _ := repo.Create(data)
+			if created.ID != 3 {
+				t.Errorf("Expected ID 3 to remain free, got %d", created.ID)
+			}
+		})
+	}
+}
+
+func TestMapRepositoryWithTxCommits(t *testing.T) {
+	repo := setupTestRepository()
+	audits := &MemoryAuditSink{}
+	repo.SetAuditSink(audits)
+
+	err := repo.WithTx(func(tx Repository) error {
+		if _, err := tx.CreateMany([]PostCreateUpdate{
+			{Title: "Title 3", Content: "Content 3", Author: "Author"},
+			{Title: "Title 4", Content: "Content 4", Author: "Author"},
+		}); err != nil {
+			return err
+		}
+		if _, err := tx.Update(1, PostCreateUpdate{Title: "Updated", Content: "Content", Author: "Author"}); err != nil {
+			return err
+		}
+		// Reads inside the transaction see its own writes.
+		if post, err := tx.GetByID(3); err != nil || post.Title != "Title 3" {
+			t.Errorf("Expected post 3 inside the transaction, got %+v, %v", post, err)
+		}
+		return tx.Delete(2)
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	posts, _ := repo.GetAll(context.Background())
+	if len(posts) != 3 {
+		t.Errorf("Expected 3 posts, got %d", len(posts))
+	}
+	if post, _ := repo.GetByID(1); post.Title != "Updated" {
+		t.Errorf("Expected post 1 to be updated, got %q", post.Title)
+	}
+	if exists, _ := repo.Exists(2); exists {
+		t.Error("Expected post 2 to be deleted")
+	}
+	if len(audits.Audits()) != 1 {
+		t.Errorf("Expected 1 audit, got %d", len(audits.Audits()))
+	}
+	if created, _ := repo.Create(PostCreateUpdate{Title: "Title 5", Content: "Content 5", Author: "Author"}); created.ID != 5 {
+		t.Errorf("Expected ID 5 after the transaction, got %d", created.ID)
+	}
+}
+
+func TestMapRepositoryWithTxIsLogged(t *testing.T) {
+	snapshotPath, logPath := setupWALFiles(t)
+	repo, err := OpenMapRepository(snapshotPath, logPath, 0)
+	if err != nil {
+		t.Fatalf("Failed to open repository: %v", err)
+	}
+
+	var expected map[int]PostRead
+	err = repo.WithTx(func(tx Repository) error {
+		expected = mutateForRecovery(t, tx.(*MapRepository))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	repo.Close()
+
+	recovered, err := OpenMapRepository(snapshotPath, logPath, 0)
+	if err != nil {
+		t.Fatalf("Failed to reopen repository: %v", err)
+	}
+	defer recovered.Close()
+
+	assertRecovered(t, recovered, expected)
+}
+
+func postsByID(posts []PostRead) map[int]PostRead {
+	byID := make(map[int]PostRead, len(posts))
+	for _, post := range posts {
+		byID[post.ID] = post
+	}
+	return byID
+}
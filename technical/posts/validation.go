@@ -0,0 +1,218 @@
+package posts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	authorMinLength = 2
+	authorMaxLength = 64
+)
+
+// validateAuthorFormat accepts names made of letters, spaces and hyphens that
+// contain at least one letter and fit the allowed length range.
+func validateAuthorFormat(ctx context.Context, fl validator.FieldLevel) bool {
+	author := fl.Field().String()
+	if keptAuthor(ctx, author) {
+		return true
+	}
+	length := utf8.RuneCountInString(author)
+	if length < authorMinLength || length > authorMaxLength {
+		return false
+	}
+
+	hasLetter := false
+	for _, r := range author {
+		switch {
+		case unicode.IsLetter(r):
+			hasLetter = true
+		case r == ' ' || r == '-':
+		default:
+			return false
+		}
+	}
+	return hasLetter
+}
+
+// validateKnownAuthor accepts the authors in known, or any author when known is nil.
+func validateKnownAuthor(known map[string]struct{}) validator.FuncCtx {
+	return func(ctx context.Context, fl validator.FieldLevel) bool {
+		author := fl.Field().String()
+		if known == nil || keptAuthor(ctx, author) {
+			return true
+		}
+		_, ok := known[author]
+		return ok
+	}
+}
+
+// defaultValidator is used by services created without WithValidator.
+var defaultValidator = NewValidator()
+
+// ValidatorOption configures the post rules registered by NewValidator.
+type ValidatorOption func(*validatorConfig)
+
+type validatorConfig struct {
+	distinctTitleContent bool
+	// knownAuthors are the only authors accepted; nil accepts any well-formed author.
+	knownAuthors map[string]struct{}
+}
+
+// WithKnownAuthors restricts Author to the given names. Without names, any well-formed
+// author is accepted, as by default.
+func WithKnownAuthors(authors ...string) ValidatorOption {
+	return func(c *validatorConfig) {
+		if len(authors) == 0 {
+			c.knownAuthors = nil
+			return
+		}
+		c.knownAuthors = make(map[string]struct{}, len(authors))
+		for _, author := range authors {
+			c.knownAuthors[author] = struct{}{}
+		}
+	}
+}
+
+// WithDistinctTitleContent decides whether posts whose content only repeats the title,
+// ignoring surrounding whitespace, are rejected, as spam often does. It is off by default.
+func WithDistinctTitleContent(enabled bool) ValidatorOption {
+	return func(c *validatorConfig) {
+		c.distinctTitleContent = enabled
+	}
+}
+
+// NewValidator returns a validator with the post rules registered. Deployments can
+// register further tags on it and pass it to WithValidator; since a type has only one
+// struct-level rule, further rules on PostCreateUpdate replace the built-in ones.
+func NewValidator(opts ...ValidatorOption) *validator.Validate {
+	var cfg validatorConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	v := validator.New()
+	if err := v.RegisterValidationCtx("author", validateAuthorFormat); err != nil {
+		panic(err)
+	}
+	if err := v.RegisterValidationCtx("known_author", validateKnownAuthor(cfg.knownAuthors)); err != nil {
+		panic(err)
+	}
+	if err := v.RegisterValidation("tag_count", validateTagCount); err != nil {
+		panic(err)
+	}
+	if err := v.RegisterValidation("tag", validateTag); err != nil {
+		panic(err)
+	}
+	v.RegisterStructValidationCtx(func(ctx context.Context, sl validator.StructLevel) {
+		validatePostContent(ctx, sl)
+		if cfg.distinctTitleContent {
+			validateDistinctTitleContent(sl)
+		}
+	}, PostCreateUpdate{})
+	return v
+}
+
+// validatePost validates data as a post that will have the given status; only a draft may have empty Content.
+func validatePost(v *validator.Validate, data PostCreateUpdate, status string) error {
+	return v.StructCtx(context.WithValue(context.Background(), targetStatusKey, status), data)
+}
+
+// validatePostUpdate is validatePost for an update of a post whose stored author is
+// current. The author rules are only checked for a new author, so that posts stored
+// before the rules were tightened can still be patched.
+func validatePostUpdate(v *validator.Validate, data PostCreateUpdate, status, current string) error {
+	ctx := context.WithValue(context.Background(), targetStatusKey, status)
+	ctx = context.WithValue(ctx, storedAuthorKey, current)
+	return v.StructCtx(ctx, data)
+}
+
+const (
+	targetStatusKey contextKey = "targetStatus"
+	storedAuthorKey contextKey = "storedAuthor"
+)
+
+// keptAuthor reports whether author is the stored author of the post being validated.
+func keptAuthor(ctx context.Context, author string) bool {
+	stored, ok := ctx.Value(storedAuthorKey).(string)
+	return ok && stored == author
+}
+
+// validatePostContent requires Content unless the post is validated as a draft.
+func validatePostContent(ctx context.Context, sl validator.StructLevel) {
+	data := sl.Current().Interface().(PostCreateUpdate)
+	status, _ := ctx.Value(targetStatusKey).(string)
+	if status != StatusDraft && data.Content == "" {
+		sl.ReportError(data.Content, "Content", "Content", "required", "")
+	}
+}
+
+// validateDistinctTitleContent reports Content when it is the title over again.
+func validateDistinctTitleContent(sl validator.StructLevel) {
+	data := sl.Current().Interface().(PostCreateUpdate)
+	content := strings.TrimSpace(data.Content)
+	if content != "" && content == strings.TrimSpace(data.Title) {
+		sl.ReportError(data.Content, "Content", "Content", "distinct_from_title", "")
+	}
+}
+
+func validationMessage(fieldError validator.FieldError) string {
+	switch fieldError.Tag() {
+	case "author":
+		return fmt.Sprintf("Field '%s' must be %d-%d characters of letters, spaces or hyphens", fieldError.Field(), authorMinLength, authorMaxLength)
+	case "known_author":
+		return fmt.Sprintf("Field '%s' must be one of the known authors", fieldError.Field())
+	case "tag_count":
+		return fmt.Sprintf("Field '%s' must have at most %d tags", fieldError.Field(), maxTags)
+	case "tag":
+		return fmt.Sprintf("Field '%s' must be %d-%d %s", fieldError.Field(), tagMinLength, tagMaxLength, tagCharsMessage)
+	case "distinct_from_title":
+		return fmt.Sprintf("Field '%s' must not be the same as the title", fieldError.Field())
+	case "http_url":
+		return fmt.Sprintf("Field '%s' must be an http or https URL", fieldError.Field())
+	default:
+		return fmt.Sprintf("Field validation for '%s' failed on the '%s' tag", fieldError.Field(), fieldError.Tag())
+	}
+}
+
+// FieldError describes one validation rule a field broke.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// ValidationError is returned by the service when post data breaks one or more rules,
+// so that callers get the details without depending on the validator package.
+type ValidationError []FieldError
+
+func (e ValidationError) Error() string {
+	messages := make([]string, len(e))
+	for i, fieldError := range e {
+		messages[i] = fieldError.Message
+	}
+	return fmt.Sprintf("Validation failed: %s", strings.Join(messages, "; "))
+}
+
+// newValidationError translates validator's errors into a ValidationError and returns
+// any other error unchanged.
+func newValidationError(err error) error {
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return err
+	}
+	result := make(ValidationError, len(validationErrors))
+	for i, fieldError := range validationErrors {
+		result[i] = FieldError{
+			Field:   fieldError.Field(),
+			Rule:    fieldError.Tag(),
+			Message: validationMessage(fieldError),
+		}
+	}
+	return result
+}
@@ -0,0 +1,225 @@
+package posts
+
+import (
+	"errors"
+	"github.com/go-playground/validator/v10"
+	"strings"
+	"testing"
+)
+
+func TestAuthorValidation(t *testing.T) {
+	tests := []struct {
+		name        string
+		author      string
+		expectedTag string
+	}{
+		{name: "Simple Name", author: "Jane Doe", expectedTag: ""},
+		{name: "Hyphenated Name", author: "Anne-Marie Smith", expectedTag: ""},
+		{name: "Non-ASCII Letters", author: "José Müller", expectedTag: ""},
+		{name: "Name With Digits", author: "Author 1", expectedTag: "author"},
+		{name: "Control Characters", author: "Jane\tDoe", expectedTag: "author"},
+		{name: "Only Whitespace", author: "    ", expectedTag: "author"},
+		{name: "Only Hyphens", author: "--", expectedTag: "author"},
+		{name: "Too Short", author: "J", expectedTag: "author"},
+		{name: "Too Long", author: strings.Repeat("a", authorMaxLength+1), expectedTag: "author"},
+		{name: "Empty", author: "", expectedTag: "required"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			data := PostCreateUpdate{Title: "Title", Content: "Content", Author: tc.author}
+			err := validatePost(defaultValidator, data, data.PostStatus())
+
+			if tc.expectedTag == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+
+			var validationErrors validator.ValidationErrors
+			if !errors.As(err, &validationErrors) {
+				t.Fatalf("Expected validation errors, got %v", err)
+			}
+			if validationErrors[0].Tag() != tc.expectedTag {
+				t.Errorf("Expected tag %s, got %s", tc.expectedTag, validationErrors[0].Tag())
+			}
+		})
+	}
+}
+
+func TestKnownAuthorValidation(t *testing.T) {
+	v := NewValidator(WithKnownAuthors("Jane Doe", "John Smith"))
+
+	known := PostCreateUpdate{Title: "Title", Content: "Content", Author: "Jane Doe"}
+	if err := validatePost(v, known, known.PostStatus()); err != nil {
+		t.Errorf("Expected no error for a known author, got %v", err)
+	}
+
+	unknown := PostCreateUpdate{Title: "Title", Content: "Content", Author: "Someone Else"}
+	if err := validatePost(defaultValidator, unknown, unknown.PostStatus()); err != nil {
+		t.Errorf("Expected the default validator to accept any well-formed author, got %v", err)
+	}
+	var validationErrors validator.ValidationErrors
+	if !errors.As(validatePost(v, unknown, unknown.PostStatus()), &validationErrors) {
+		t.Fatal("Expected validation errors for an unknown author")
+	}
+	if validationErrors[0].Tag() != "known_author" {
+		t.Errorf("Expected tag known_author, got %s", validationErrors[0].Tag())
+	}
+
+	message := newValidationError(validationErrors).Error()
+	if !strings.Contains(message, "must be one of the known authors") {
+		t.Errorf("Expected a clear message, got %q", message)
+	}
+}
+
+func TestContentValidationDependsOnStatus(t *testing.T) {
+	tests := []struct {
+		name          string
+		data          PostCreateUpdate
+		status        string
+		expectedField string
+	}{
+		{
+			name:   "Draft With Empty Content",
+			data:   PostCreateUpdate{Title: "Title", Author: "Jane Doe"},
+			status: StatusDraft,
+		},
+		{
+			name:          "Published With Empty Content",
+			data:          PostCreateUpdate{Title: "Title", Author: "Jane Doe"},
+			status:        StatusPublished,
+			expectedField: "Content",
+		},
+		{
+			name:   "Published With Content",
+			data:   PostCreateUpdate{Title: "Title", Content: "Content", Author: "Jane Doe"},
+			status: StatusPublished,
+		},
+		{
+			name:          "Unknown Status",
+			data:          PostCreateUpdate{Title: "Title", Content: "Content", Author: "Jane Doe", Status: "archived"},
+			status:        StatusPublished,
+			expectedField: "Status",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validatePost(defaultValidator, tc.data, tc.status)
+
+			if tc.expectedField == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+
+			var validationErrors validator.ValidationErrors
+			if !errors.As(err, &validationErrors) {
+				t.Fatalf("Expected validation errors, got %v", err)
+			}
+			if len(validationErrors) != 1 || validationErrors[0].Field() != tc.expectedField {
+				t.Errorf("Expected a single error on %s, got %v", tc.expectedField, validationErrors)
+			}
+		})
+	}
+}
+
+func TestDistinctTitleContentValidation(t *testing.T) {
+	tests := []struct {
+		name          string
+		validator     *validator.Validate
+		data          PostCreateUpdate
+		expectedField string
+	}{
+		{
+			name:      "Normal Post",
+			validator: NewValidator(WithDistinctTitleContent(true)),
+			data:      PostCreateUpdate{Title: "Title", Content: "Content", Author: "Jane Doe"},
+		},
+		{
+			name:          "Identical Title And Content",
+			validator:     NewValidator(WithDistinctTitleContent(true)),
+			data:          PostCreateUpdate{Title: "Buy now", Content: "  Buy now\n", Author: "Jane Doe"},
+			expectedField: "Content",
+		},
+		{
+			name:      "Different Case",
+			validator: NewValidator(WithDistinctTitleContent(true)),
+			data:      PostCreateUpdate{Title: "Hello", Content: "hello", Author: "Jane Doe"},
+		},
+		{
+			name:      "Off By Default",
+			validator: defaultValidator,
+			data:      PostCreateUpdate{Title: "Buy now", Content: "Buy now", Author: "Jane Doe"},
+		},
+		{
+			name:      "Check Disabled",
+			validator: NewValidator(WithDistinctTitleContent(false)),
+			data:      PostCreateUpdate{Title: "Buy now", Content: "Buy now", Author: "Jane Doe"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := newValidationError(validatePost(tc.validator, tc.data, tc.data.PostStatus()))
+
+			if tc.expectedField == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+
+			var validationError ValidationError
+			if !errors.As(err, &validationError) {
+				t.Fatalf("Expected a ValidationError, got %v", err)
+			}
+			if len(validationError) != 1 || validationError[0].Field != tc.expectedField || validationError[0].Rule != "distinct_from_title" {
+				t.Errorf("Expected a distinct_from_title error on %s, got %v", tc.expectedField, validationError)
+			}
+			if !strings.Contains(validationError.Error(), "must not be the same as the title") {
+				t.Errorf("Expected a clear message, got %q", validationError.Error())
+			}
+		})
+	}
+}
+
+func TestImageURLValidation(t *testing.T) {
+	tests := []struct {
+		name        string
+		imageURL    string
+		expectedTag string
+	}{
+		{name: "Valid URL", imageURL: "https://example.com/covers/1.png", expectedTag: ""},
+		{name: "Plain HTTP", imageURL: "http://example.com/cover.jpg", expectedTag: ""},
+		{name: "Empty", imageURL: "", expectedTag: ""},
+		{name: "Not A URL", imageURL: "cover.png", expectedTag: "http_url"},
+		{name: "Missing Host", imageURL: "https://", expectedTag: "http_url"},
+		{name: "Script Scheme", imageURL: "javascript:alert(1)", expectedTag: "http_url"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			data := PostCreateUpdate{Title: "Title", Content: "Content", Author: "Jane Doe", ImageURL: tc.imageURL}
+			err := validatePost(defaultValidator, data, data.PostStatus())
+
+			if tc.expectedTag == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+
+			var validationErrors validator.ValidationErrors
+			if !errors.As(err, &validationErrors) {
+				t.Fatalf("Expected validation errors, got %v", err)
+			}
+			if validationErrors[0].Field() != "ImageURL" || validationErrors[0].Tag() != tc.expectedTag {
+				t.Errorf("Expected tag %s on ImageURL, got %s on %s", tc.expectedTag, validationErrors[0].Tag(), validationErrors[0].Field())
+			}
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package posts
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const (
+	logOpCreate = "create"
+	logOpUpdate = "update"
+	logOpDelete = "delete"
+)
+
+// logRecord is one line of the append log. Create and update records carry the
+// full post, so replaying a record that is already in the snapshot is harmless.
+type logRecord struct {
+	Op   string    `json:"op"`
+	ID   int       `json:"id"`
+	Post *PostRead `json:"post,omitempty"`
+}
+
+// appendLog is an append-only file of JSON lines, synced after every append.
+type appendLog struct {
+	file *os.File
+}
+
+// openAppendLog reads the records already in the log at path and opens it for appending,
+// creating it if needed. A final line without a newline is a write torn by a crash; it is
+// dropped and cut from the file so that later appends start on a clean line.
+func openAppendLog(path string) (*appendLog, []logRecord, error) {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, nil, err
+	}
+
+	var records []logRecord
+	valid := 0
+	for line := 1; valid < len(data); line++ {
+		end := bytes.IndexByte(data[valid:], '\n')
+		if end < 0 {
+			break
+		}
+		var record logRecord
+		if err := json.Unmarshal(data[valid:valid+end], &record); err != nil {
+			return nil, nil, fmt.Errorf("append log %s line %d: %w", path, line, err)
+		}
+		records = append(records, record)
+		valid += end + 1
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, nil, err
+	}
+	if valid < len(data) {
+		if err := file.Truncate(int64(valid)); err != nil {
+			file.Close()
+			return nil, nil, err
+		}
+	}
+	return &appendLog{file: file}, records, nil
+}
+
+// Append writes records in a single write and syncs the file before returning.
+func (l *appendLog) Append(records ...logRecord) error {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			return err
+		}
+	}
+	if _, err := l.file.Write(buf.Bytes()); err != nil {
+		return err
+	}
+	return l.file.Sync()
+}
+
+func (l *appendLog) Truncate() error {
+	if err := l.file.Truncate(0); err != nil {
+		return err
+	}
+	return l.file.Sync()
+}
+
+func (l *appendLog) Close() error {
+	return l.file.Close()
+}
+
+// OpenMapRepository loads the snapshot at snapshotPath, replays the append log at logPath
+// on top of it and keeps logging every mutation there, so that state survives a crash.
+// maxPosts is passed on to LoadMapRepository.
+func OpenMapRepository(snapshotPath, logPath string, maxPosts int) (*MapRepository, error) {
+	repo, err := LoadMapRepository(snapshotPath, maxPosts)
+	if err != nil {
+		return nil, err
+	}
+
+	log, records, err := openAppendLog(logPath)
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		repo.apply(record)
+	}
+	repo.log = log
+	return repo, nil
+}
+
+// apply replays a single log record; it must be called with the write lock held.
+func (r *MapRepository) apply(record logRecord) {
+	switch record.Op {
+	case logOpCreate, logOpUpdate:
+		if record.Post == nil {
+			return
+		}
+		r.posts[record.ID] = *record.Post
+		r.nextID = max(r.nextID, record.ID+1)
+	case logOpDelete:
+		delete(r.posts, record.ID)
+	}
+}
+
+// appendToLog records mutations before they are applied and marks the repository dirty
+// for the autosave. Writing to the log is a no-op without one; inside WithTx the
+// records are kept until the transaction commits. It must be called with the write
+// lock held.
+func (r *MapRepository) appendToLog(records ...logRecord) error {
+	if r.inTx {
+		r.txRecords = append(r.txRecords, records...)
+		return nil
+	}
+	r.dirty = true
+	if r.log == nil {
+		return nil
+	}
+	return r.log.Append(records...)
+}
+
+// Compact writes the current state to the snapshot file and empties the append log.
+// The snapshot is replaced atomically; should the process die before the log is
+// truncated, replaying the log over the new snapshot yields the same state.
+func (r *MapRepository) Compact() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.log == nil {
+		return ErrNoAppendLog
+	}
+
+	if err := r.writeSnapshot(); err != nil {
+		return err
+	}
+	return r.log.Truncate()
+}
+
+// writeSnapshot atomically replaces the snapshot file with the current state; it must
+// be called with the write lock held.
+func (r *MapRepository) writeSnapshot() error {
+	data, err := json.MarshalIndent(r.snapshot(), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(r.snapshotPath), filepath.Base(r.snapshotPath)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), r.snapshotPath); err != nil {
+		return err
+	}
+	r.snapshotModTime = fileModTime(r.snapshotPath)
+	r.fileIDs = nil
+	r.snapshotWrites++
+	return nil
+}
+
+// Close stops the autosave, if any, after a final flush and releases the append log.
+func (r *MapRepository) Close() error {
+	flushErr := r.stopAutoSave()
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.log == nil {
+		return flushErr
+	}
+	err := r.log.Close()
+	r.log = nil
+	return errors.Join(flushErr, err)
+}
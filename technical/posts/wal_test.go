@@ -0,0 +1,196 @@
+package posts
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func setupWALFiles(t *testing.T) (snapshotPath, logPath string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	snapshotPath = filepath.Join(dir, "blog_data.json")
+	logPath = filepath.Join(dir, "blog_data.log")
+	content := `{"posts": [{"id": 1, "title": "Title 1", "content": "Content 1", "author": "Author"}]}`
+	if err := os.WriteFile(snapshotPath, []byte(content), 0o644); err != nil {
+		t.Fatalf("Failed to write snapshot: %v", err)
+	}
+	return snapshotPath, logPath
+}
+
+// mutateForRecovery applies one of each kind of mutation and returns the state a
+// recovered repository is expected to have.
+func mutateForRecovery(t *testing.T, repo *MapRepository) map[int]PostRead {
+	t.Helper()
+
+	if _, err := repo.CreateMany([]PostCreateUpdate{
+		{Title: "Title 2", Content: "Content 2", Author: "Author", ImageURL: "https://example.com/2.png"},
+		{Title: "Title 3", Content: "Content 3", Author: "Author"},
+	}); err != nil {
+		t.Fatalf("Failed to create posts: %v", err)
+	}
+	if _, err := repo.Update(1, PostCreateUpdate{Title: "Updated", Content: "Updated", Author: "Author", ImageURL: "https://example.com/1.png"}); err != nil {
+		t.Fatalf("Failed to update post: %v", err)
+	}
+	if _, err := repo.IncrementViews(1); err != nil {
+		t.Fatalf("Failed to increment views: %v", err)
+	}
+	if err := repo.Delete(3); err != nil {
+		t.Fatalf("Failed to delete post: %v", err)
+	}
+
+	expected := make(map[int]PostRead)
+	for id, post := range repo.posts {
+		expected[id] = post
+	}
+	return expected
+}
+
+func assertRecovered(t *testing.T, repo *MapRepository, expected map[int]PostRead) {
+	t.Helper()
+
+	if len(repo.posts) != len(expected) {
+		t.Fatalf("Expected %d posts, got %d", len(expected), len(repo.posts))
+	}
+	for id, want := range expected {
+		got, ok := repo.posts[id]
+		if !ok {
+			t.Errorf("Expected post %d to be recovered", id)
+			continue
+		}
+		if got.Title != want.Title || got.ImageURL != want.ImageURL || got.Views != want.Views || !got.UpdatedAt.Equal(want.UpdatedAt) {
+			t.Errorf("Expected post %d to be %+v, got %+v", id, want, got)
+		}
+	}
+
+	created, err := repo.Create(PostCreateUpdate{Title: "Title 4", Content: "Content 4", Author: "Author"})
+	if err != nil {
+		t.Fatalf("Failed to create post: %v", err)
+	}
+	if created.ID != 4 {
+		t.Errorf("Expected the deleted ID 3 not to be reused, got ID %d", created.ID)
+	}
+}
+
+func TestMapRepositoryRecoversFromLog(t *testing.T) {
+	snapshotPath, logPath := setupWALFiles(t)
+
+	repo, err := OpenMapRepository(snapshotPath, logPath, 0)
+	if err != nil {
+		t.Fatalf("Failed to open repository: %v", err)
+	}
+	expected := mutateForRecovery(t, repo)
+
+	// Simulate a crash: the repository is abandoned without compaction.
+	repo.Close()
+
+	recovered, err := OpenMapRepository(snapshotPath, logPath, 0)
+	if err != nil {
+		t.Fatalf("Failed to reopen repository: %v", err)
+	}
+	defer recovered.Close()
+
+	assertRecovered(t, recovered, expected)
+}
+
+func TestMapRepositoryCompact(t *testing.T) {
+	snapshotPath, logPath := setupWALFiles(t)
+
+	repo, err := OpenMapRepository(snapshotPath, logPath, 0)
+	if err != nil {
+		t.Fatalf("Failed to open repository: %v", err)
+	}
+	expected := mutateForRecovery(t, repo)
+
+	if err := repo.Compact(); err != nil {
+		t.Fatalf("Failed to compact: %v", err)
+	}
+	repo.Close()
+
+	info, err := os.Stat(logPath)
+	if err != nil {
+		t.Fatalf("Failed to stat log: %v", err)
+	}
+	if info.Size() != 0 {
+		t.Errorf("Expected an empty log after compaction, got %d bytes", info.Size())
+	}
+
+	recovered, err := OpenMapRepository(snapshotPath, logPath, 0)
+	if err != nil {
+		t.Fatalf("Failed to reopen repository: %v", err)
+	}
+	defer recovered.Close()
+
+	assertRecovered(t, recovered, expected)
+}
+
+func TestMapRepositoryIgnoresTornLogWrite(t *testing.T) {
+	snapshotPath, logPath := setupWALFiles(t)
+
+	repo, err := OpenMapRepository(snapshotPath, logPath, 0)
+	if err != nil {
+		t.Fatalf("Failed to open repository: %v", err)
+	}
+	repo.Create(PostCreateUpdate{Title: "Title 2", Content: "Content 2", Author: "Author"})
+	repo.Close()
+
+	// A crash in the middle of an append leaves a partial line behind.
+	file, err := os.OpenFile(logPath, os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		t.Fatalf("Failed to open log: %v", err)
+	}
+	file.WriteString(`{"op":"create","id":3,"po`)
+	file.Close()
+
+	recovered, err := OpenMapRepository(snapshotPath, logPath, 0)
+	if err != nil {
+		t.Fatalf("Failed to reopen repository: %v", err)
+	}
+	if len(recovered.posts) != 2 {
+		t.Errorf("Expected 2 posts, got %d", len(recovered.posts))
+	}
+	recovered.Create(PostCreateUpdate{Title: "Title 3", Content: "Content 3", Author: "Author"})
+	recovered.Close()
+
+	reopened, err := OpenMapRepository(snapshotPath, logPath, 0)
+	if err != nil {
+		t.Fatalf("Expected the torn write to have been cut from the log, got %v", err)
+	}
+	defer reopened.Close()
+	if len(reopened.posts) != 3 {
+		t.Errorf("Expected 3 posts, got %d", len(reopened.posts))
+	}
+}
+
+func TestMapRepositoryReloadEmptiesLog(t *testing.T) {
+	snapshotPath, logPath := setupWALFiles(t)
+
+	repo, err := OpenMapRepository(snapshotPath, logPath, 0)
+	if err != nil {
+		t.Fatalf("Failed to open repository: %v", err)
+	}
+	repo.Create(PostCreateUpdate{Title: "Title 2", Content: "Content 2", Author: "Author"})
+	if err := repo.Reload(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	repo.Close()
+
+	reopened, err := OpenMapRepository(snapshotPath, logPath, 0)
+	if err != nil {
+		t.Fatalf("Failed to reopen repository: %v", err)
+	}
+	defer reopened.Close()
+	if len(reopened.posts) != 1 {
+		t.Errorf("Expected only the snapshot's post after reopening, got %d", len(reopened.posts))
+	}
+}
+
+func TestMapRepositoryCompactWithoutLog(t *testing.T) {
+	repo := setupTestRepository()
+
+	if err := repo.Compact(); !errors.Is(err, ErrNoAppendLog) {
+		t.Errorf("Expected ErrNoAppendLog, got %v", err)
+	}
+}
@@ -0,0 +1,129 @@
+package posts
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"nhooyr.io/websocket"
+	"sync"
+	"time"
+)
+
+const (
+	wsWriteTimeout = 5 * time.Second
+	// wsClientBuffer is how many events may queue for a client before it is
+	// considered too slow and disconnected.
+	wsClientBuffer = 16
+)
+
+// Hub broadcasts post events as JSON text frames to every client connected to
+// /ws/posts. It implements EventPublisher.
+type Hub struct {
+	mutex   sync.Mutex
+	clients map[*hubClient]struct{}
+}
+
+type hubClient struct {
+	messages chan []byte
+}
+
+func NewHub() *Hub {
+	return &Hub{
+		clients: make(map[*hubClient]struct{}),
+	}
+}
+
+// Publish queues event for every connected client without waiting for any of them;
+// a client whose queue is full is dropped so that it cannot hold up the others.
+func (h *Hub) Publish(event PostEvent) {
+	message, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	for client := range h.clients {
+		select {
+		case client.messages <- message:
+		default:
+			h.removeLocked(client)
+		}
+	}
+}
+
+// ServeHTTP handles GET /ws/posts
+// @Summary Stream post changes
+// @Description Upgrade to a WebSocket that receives a PostEvent JSON frame for every created, updated or deleted post
+// @Tags posts
+// @Success 101 {object} PostEvent "Switching to the WebSocket protocol"
+// @Failure 400 {object} string "Not a WebSocket handshake"
+// @Router /ws/posts [get]
+func (h *Hub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	conn, err := websocket.Accept(w, r, nil)
+	if err != nil {
+		// Accept has already written an error response.
+		return
+	}
+	defer conn.CloseNow()
+
+	client := h.add()
+	defer h.remove(client)
+
+	// Clients only listen: CloseRead discards anything they send and cancels ctx
+	// once they disconnect.
+	ctx := conn.CloseRead(r.Context())
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case message, ok := <-client.messages:
+			if !ok {
+				conn.Close(websocket.StatusPolicyViolation, "client too slow")
+				return
+			}
+			if err := h.write(ctx, conn, message); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (h *Hub) write(ctx context.Context, conn *websocket.Conn, message []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, wsWriteTimeout)
+	defer cancel()
+	return conn.Write(ctx, websocket.MessageText, message)
+}
+
+func (h *Hub) add() *hubClient {
+	client := &hubClient{messages: make(chan []byte, wsClientBuffer)}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.clients[client] = struct{}{}
+	return client
+}
+
+func (h *Hub) remove(client *hubClient) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.removeLocked(client)
+}
+
+// removeLocked closes the client's queue unless it was already removed; it must be
+// called with the mutex held.
+func (h *Hub) removeLocked(client *hubClient) {
+	if _, ok := h.clients[client]; !ok {
+		return
+	}
+	delete(h.clients, client)
+	close(client.messages)
+}
+
+// clientCount reports the number of connected clients.
+func (h *Hub) clientCount() int {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	return len(h.clients)
+}
@@ -0,0 +1,121 @@
+package posts
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"nhooyr.io/websocket"
+	"strings"
+	"testing"
+	"time"
+)
+
+// serveHub starts a test server for hub and returns its WebSocket URL.
+func serveHub(t *testing.T, hub *Hub) string {
+	t.Helper()
+
+	server := httptest.NewServer(hub)
+	t.Cleanup(server.Close)
+	return "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+func dialHub(t *testing.T, url string) *websocket.Conn {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	conn, _, err := websocket.Dial(ctx, url, nil)
+	if err != nil {
+		t.Fatalf("Failed to dial: %v", err)
+	}
+	t.Cleanup(func() { conn.CloseNow() })
+	return conn
+}
+
+func waitForClients(t *testing.T, hub *Hub, expected int) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for hub.clientCount() != expected {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected %d connected clients, got %d", expected, hub.clientCount())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func readEvent(t *testing.T, conn *websocket.Conn) PostEvent {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, data, err := conn.Read(ctx)
+	if err != nil {
+		t.Fatalf("Failed to read frame: %v", err)
+	}
+	var event PostEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		t.Fatalf("Failed to decode event: %v", err)
+	}
+	return event
+}
+
+func TestHubBroadcastsServiceChanges(t *testing.T) {
+	hub := NewHub()
+	url := serveHub(t, hub)
+	first := dialHub(t, url)
+	second := dialHub(t, url)
+	waitForClients(t, hub, 2)
+
+	service := NewPostService(setupTestRepository(), WithEventPublisher(hub))
+	created, err := service.CreatePost(context.Background(), PostCreateUpdate{Title: "New Post", Content: "New Content", Author: "Jane Doe"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := service.DeletePost(context.Background(), created.ID); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for _, conn := range []*websocket.Conn{first, second} {
+		event := readEvent(t, conn)
+		if event.Type != EventPostCreated || event.ID != created.ID || event.Post == nil || event.Post.Title != "New Post" {
+			t.Errorf("Expected a created event for post %d, got %+v", created.ID, event)
+		}
+		event = readEvent(t, conn)
+		if event.Type != EventPostDeleted || event.ID != created.ID || event.Post != nil {
+			t.Errorf("Expected a deleted event for post %d, got %+v", created.ID, event)
+		}
+	}
+}
+
+func TestHubRemovesDisconnectedClients(t *testing.T) {
+	hub := NewHub()
+	conn := dialHub(t, serveHub(t, hub))
+	waitForClients(t, hub, 1)
+
+	conn.Close(websocket.StatusNormalClosure, "")
+	waitForClients(t, hub, 0)
+
+	// Publishing with no clients left must not block or panic.
+	hub.Publish(postDeleted(1))
+}
+
+func TestHubDropsSlowClients(t *testing.T) {
+	hub := NewHub()
+	client := hub.add()
+
+	for i := 0; i <= wsClientBuffer; i++ {
+		hub.Publish(postDeleted(i))
+	}
+
+	if hub.clientCount() != 0 {
+		t.Errorf("Expected the slow client to be dropped, got %d clients", hub.clientCount())
+	}
+	queued := 0
+	for range client.messages {
+		queued++
+	}
+	if queued != wsClientBuffer {
+		t.Errorf("Expected %d queued messages, got %d", wsClientBuffer, queued)
+	}
+}